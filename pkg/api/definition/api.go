@@ -42,6 +42,9 @@ func GenerateRoutes(ws *restful.WebService, descriptors []Descriptor) *restful.W
 			for _, code := range handler.StatusCode {
 				builder.Returns(code.Code, code.Message, code.Sample)
 			}
+			if len(handler.Produces) > 0 {
+				builder.Produces(handler.Produces...)
+			}
 			for _, filter := range handler.Filters {
 				builder.Filter(filter)
 			}
@@ -37,8 +37,14 @@ type Key string
 const (
 	// KeyRequest is the key of request
 	KeyRequest Key = "Context.Request"
+	// KeyResponse is the key of response. Handlers can set response headers by it
+	KeyResponse Key = "Context.Response"
 )
 
+// AttributeContinue is the request attribute of continuation token. VerbList handlers set it
+// if there are more items after the returned items
+const AttributeContinue = "Context.Continue"
+
 // HandlerDecoration defines a decoration of handler
 // A handler is a function. The declaration of handler
 // should be compatible with the definition of specified Verb.
@@ -111,6 +117,7 @@ func NewHandlerDecoration(verb Verb, handler interface{}) *HandlerDecoration {
 // Handle handles a request
 func (hd *HandlerDecoration) Handle(request *restful.Request, resp *restful.Response) {
 	ctx := context.WithValue(context.Background(), KeyRequest, request)
+	ctx = context.WithValue(ctx, KeyResponse, resp)
 	result := hd.Value.Call([]reflect.Value{reflect.ValueOf(ctx)})
 	errValue := result[verbMapping[hd.Verb]-1]
 	if errValue.IsNil() {
@@ -123,23 +130,25 @@ func (hd *HandlerDecoration) Handle(request *restful.Request, resp *restful.Resp
 			if hd.Verb == VerbCreate {
 				statusCode = http.StatusCreated
 			}
-			// check obj type
-			obj := result[0]
+			// check obj type. The result may be declared as interface{}, so its dynamic type
+			// is checked.
+			obj := result[0].Interface()
 			// if obj is []byte, writes by resp.Write()
 			// otherwise resp.WriteHeaderAndEntity()
-			objType := obj.Type()
-			if (objType.Kind() == reflect.Array || objType.Kind() == reflect.Slice) &&
-				objType.Elem().AssignableTo(reflect.TypeOf(byte(0))) {
+			if data, ok := obj.([]byte); ok {
 				resp.WriteHeader(statusCode)
-				data := obj.Interface().([]byte)
 				resp.Write(data)
 			} else {
-				resp.WriteHeaderAndEntity(statusCode, obj.Interface())
+				resp.WriteHeaderAndEntity(statusCode, obj)
 			}
 			return
 		case VerbList:
 			total := int(result[0].Int())
-			resp.WriteHeaderAndEntity(http.StatusOK, models.NewListResponse(total, result[1].Interface()))
+			list := models.NewListResponse(total, result[1].Interface())
+			if token, ok := request.Attribute(AttributeContinue).(string); ok {
+				list.Metadata.Continue = token
+			}
+			resp.WriteHeaderAndEntity(http.StatusOK, list)
 			return
 		default:
 			// should not come here
@@ -149,6 +158,11 @@ func (hd *HandlerDecoration) Handle(request *restful.Request, resp *restful.Resp
 	// handle error
 	switch err := errValue.Interface().(type) {
 	case *errors.Error:
+		if err.Code == http.StatusNotModified {
+			// 304 must not have a body
+			resp.WriteHeader(err.Code)
+			return
+		}
 		resp.WriteHeaderAndEntity(err.Code, map[string]string{
 			"message": err.Message,
 			"reason":  err.Reason,
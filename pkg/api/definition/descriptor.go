@@ -44,6 +44,10 @@ type Handler struct {
 
 	// StatusCode describes all Status Codes from the handler
 	StatusCode []StatusCode
+
+	// Produces overrides media types of responses of the web service. It's used by handlers
+	// which respond with raw content
+	Produces []string
 }
 
 // Param describes detail infomation of a param
@@ -0,0 +1,16 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package definition
+
+const (
+	// MIMEAny matches all media types
+	MIMEAny = "*/*"
+	// MIMEText is the media type of plain text
+	MIMEText = "text/plain"
+	// MIMEMarkdown is the media type of Markdown
+	MIMEMarkdown = "text/markdown"
+	// MIMEHTML is the media type of HTML
+	MIMEHTML = "text/html"
+)
@@ -0,0 +1,51 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package definition
+
+import (
+	"io/ioutil"
+
+	"github.com/emicklei/go-restful"
+	"github.com/ghodss/yaml"
+)
+
+// MIMEYAML is the media type of YAML
+const MIMEYAML = "application/yaml"
+
+// entityYAMLAccess is a restful.EntityReaderWriter for YAML encoding. Entities are
+// converted by their json tags.
+type entityYAMLAccess struct {
+	contentType string
+}
+
+// NewEntityAccessorYAML creates a restful.EntityReaderWriter for YAML encoding
+func NewEntityAccessorYAML(contentType string) restful.EntityReaderWriter {
+	return &entityYAMLAccess{contentType: contentType}
+}
+
+// Read unmarshals the YAML body of request to v
+func (e *entityYAMLAccess) Read(req *restful.Request, v interface{}) error {
+	data, err := ioutil.ReadAll(req.Request.Body)
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(data, v)
+}
+
+// Write marshals v to YAML and writes it to response
+func (e *entityYAMLAccess) Write(resp *restful.Response, status int, v interface{}) error {
+	if v == nil {
+		resp.WriteHeader(status)
+		return nil
+	}
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return err
+	}
+	resp.Header().Set("Content-Type", e.contentType)
+	resp.WriteHeader(status)
+	_, err = resp.Write(data)
+	return err
+}
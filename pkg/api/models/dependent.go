@@ -0,0 +1,39 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package models
+
+import "github.com/caicloud/helm-registry/pkg/storage"
+
+// Dependent is an orchestrated version which was built from a package, or a version which
+// requires a package
+type Dependent struct {
+	// Space is the space of the dependent
+	Space string `json:"space"`
+	// Chart is the chart of the dependent
+	Chart string `json:"chart"`
+	// Version is the version number of the dependent
+	Version string `json:"version"`
+	// Reference is the package in the dependent
+	Reference storage.Reference `json:"reference"`
+}
+
+// OutdatedPackage is a package which has a newer version than the referenced one
+type OutdatedPackage struct {
+	storage.Reference
+	// Latest is the latest version of the package which is not yanked
+	Latest string `json:"latest"`
+}
+
+// OutdatedVersion is an orchestrated version whose packages have newer versions
+type OutdatedVersion struct {
+	// Space is the space of the orchestrated version
+	Space string `json:"space"`
+	// Chart is the chart of the orchestrated version
+	Chart string `json:"chart"`
+	// Version is the version number of the orchestrated version
+	Version string `json:"version"`
+	// Packages are outdated packages in the version
+	Packages []OutdatedPackage `json:"packages"`
+}
@@ -0,0 +1,16 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package models
+
+// File describes a file in a chart package
+type File struct {
+	// Path is the path of file relative to the chart directory. Files of subcharts are
+	// prefixed with charts/<name>/
+	Path string `json:"path"`
+	// Size is the size of file in bytes
+	Size int `json:"size"`
+	// ContentType is the media type of file
+	ContentType string `json:"contentType"`
+}
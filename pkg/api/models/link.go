@@ -4,6 +4,8 @@ Copyright 2017 caicloud authors. All rights reserved.
 
 package models
 
+import "github.com/caicloud/helm-registry/pkg/lint"
+
 // Link describes a normal self-link
 type Link struct {
 	// Name is object name
@@ -27,9 +29,11 @@ type ChartLink struct {
 	Version string `json:"version"`
 	// Link is the uri of object
 	Link string `json:"link"`
+	// Lint contains findings of linting the chart when it's stored
+	Lint []lint.Finding `json:"lint,omitempty"`
 }
 
 // NewChartLink creates a chart self-link
 func NewChartLink(space, chart, version, link string) *ChartLink {
-	return &ChartLink{space, chart, version, link}
+	return &ChartLink{Space: space, Chart: chart, Version: version, Link: link}
 }
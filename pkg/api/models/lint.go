@@ -0,0 +1,20 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package models
+
+import (
+	"github.com/caicloud/helm-registry/pkg/lint"
+	"github.com/caicloud/helm-registry/pkg/storage"
+)
+
+// LintResult is the result of linting a chart package in a space
+type LintResult struct {
+	// Mode is the lint mode of the space
+	Mode storage.LintMode `json:"mode"`
+	// Accepted means the chart package can be stored in the space
+	Accepted bool `json:"accepted"`
+	// Findings are problems found in the chart package
+	Findings []lint.Finding `json:"findings"`
+}
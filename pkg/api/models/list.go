@@ -10,6 +10,9 @@ import "reflect"
 type Metadata struct {
 	Total       int `json:"total"`
 	ItemsLength int `json:"itemsLength"`
+	// Continue is an opaque token to get items after current items. It's empty if
+	// there are no more items
+	Continue string `json:"continue,omitempty"`
 }
 
 // ListResponse describes a list
@@ -0,0 +1,33 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package models
+
+import (
+	"github.com/caicloud/helm-registry/pkg/lint"
+	"github.com/caicloud/helm-registry/pkg/orchestration"
+)
+
+// OrchestrationPreview is the result of orchestrating a chart without storing it
+type OrchestrationPreview struct {
+	// Space is the space where the chart would be stored
+	Space string `json:"space"`
+	// Chart is the name of chart
+	Chart string `json:"chart"`
+	// Version is the version number of chart
+	Version string `json:"version"`
+	// Accepted means the chart can be created by the same config
+	Accepted bool `json:"accepted"`
+	// Digest is the digest of the orchestrated package. It's empty if there are problems.
+	Digest string `json:"digest,omitempty"`
+	// Tree is the resolved dependency tree
+	Tree *orchestration.Node `json:"tree"`
+	// Values are the merged values which are stored with the orchestrated version. They're empty
+	// if charts can't be resolved
+	Values string `json:"values"`
+	// Problems prevent the chart from being created, e.g. missing packages and naming conflicts
+	Problems []orchestration.Problem `json:"problems"`
+	// Lint contains findings of linting the orchestrated chart
+	Lint []lint.Finding `json:"lint,omitempty"`
+}
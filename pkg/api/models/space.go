@@ -0,0 +1,19 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package models
+
+import "github.com/caicloud/helm-registry/pkg/storage"
+
+// Space describes a space and its properties
+type Space struct {
+	// Name is space name
+	Name string `json:"name"`
+	storage.SpaceProperties
+}
+
+// NewSpace creates a space with properties
+func NewSpace(name string, properties *storage.SpaceProperties) *Space {
+	return &Space{name, *properties}
+}
@@ -10,7 +10,11 @@ import (
 	"github.com/caicloud/helm-registry/pkg/api/definition"
 	"github.com/caicloud/helm-registry/pkg/api/models"
 	"github.com/caicloud/helm-registry/pkg/api/v1/handlers"
+	"github.com/caicloud/helm-registry/pkg/api/v1/types"
 	"github.com/caicloud/helm-registry/pkg/common"
+	"github.com/caicloud/helm-registry/pkg/diff"
+	"github.com/caicloud/helm-registry/pkg/lint"
+	"github.com/caicloud/helm-registry/pkg/storage"
 )
 
 func init() {
@@ -26,6 +30,8 @@ var charts = []definition.Descriptor{
 				HTTPMethod: http.MethodGet,
 				Handler:    definition.NewHandlerDecoration(definition.VerbList, handlers.ListCharts).Handle,
 				Doc:        "List all charts in space",
+				Note: `If detail is true, items are summaries of charts. A summary has name, the number of versions,
+last updated time and the latest version which is not yanked.`,
 				PathParams: []definition.Param{
 					{
 						Name:     "space",
@@ -49,6 +55,19 @@ var charts = []definition.Descriptor{
 						Required: false,
 						Default:  common.DefaultPagingLimit,
 					},
+					{
+						Name:     "continue",
+						Type:     "string",
+						Doc:      "continuation token from metadata of the previous page. start is ignored if it's specified",
+						Required: false,
+					},
+					{
+						Name:     "detail",
+						Type:     "boolean",
+						Doc:      "respond with summaries instead of names",
+						Required: false,
+						Default:  false,
+					},
 				},
 				StatusCode: []definition.StatusCode{
 					definition.StatusCode{Code: http.StatusOK, Message: "Success and respond with an array of chart names",
@@ -82,7 +101,7 @@ below is a sample:
             "independent":true,         // boolean, required
             "space":"space name",       // string, required
             "chart":"chart name",       // string, required
-            "version":"version number"  // string, required
+            "version":"version number"  // string, required. A tag of the chart is also accepted
         },
         "_config": {                    // key, required
             // root chart config
@@ -122,6 +141,10 @@ below is a sample:
         }
     }
 }
+The chart package is linted by the lint mode of the space. If the mode is error, a package with
+error findings is rejected with 422. Otherwise findings are in the lint field of the response.
+Values are validated by values.schema.json of the chart and its subcharts, and invalid values are
+rejected with 422. Please refer to fetching the schema of a version.
 `,
 				QueryParams: []definition.Param{
 					{
@@ -131,6 +154,14 @@ below is a sample:
 						Required: true,
 					},
 				},
+				HeaderParams: []definition.Param{
+					{
+						Name:     "X-Registry-Uploader",
+						Type:     "string",
+						Doc:      "uploader of the version. It's supplied by the client and not verified",
+						Required: false,
+					},
+				},
 				StatusCode: []definition.StatusCode{
 					definition.StatusCode{Code: http.StatusCreated, Message: "Create successfully",
 						Sample: &models.ChartLink{
@@ -138,7 +169,11 @@ below is a sample:
 							Chart:   "chartName",
 							Version: "1.0.0",
 							Link:    "/spaces/spaceName/charts/chartName/versions/1.0.0",
+							Lint: []lint.Finding{
+								{Severity: lint.SeverityInfo, Path: "Chart.yaml", Message: "icon is recommended"},
+							},
 						}},
+					definition.StatusCode{Code: http.StatusUnprocessableEntity, Message: "The chart package has lint errors or invalid values"},
 				},
 			},
 		},
@@ -150,6 +185,8 @@ below is a sample:
 				HTTPMethod: http.MethodDelete,
 				Handler:    definition.NewHandlerDecoration(definition.VerbDelete, handlers.DeleteChart).Handle,
 				Doc:        "Delete a chart and its all versions",
+				Note: `If any version of the chart is referenced by orchestrated versions or requirements of other charts,
+including yanked ones, the chart is not deleted unless force is true, and the error lists the referencing versions.`,
 				PathParams: []definition.Param{
 					{
 						Name:     "space",
@@ -164,8 +201,196 @@ below is a sample:
 						Required: true,
 					},
 				},
+				QueryParams: []definition.Param{
+					{
+						Name:     "force",
+						Type:     "boolean",
+						Doc:      "delete the chart even if its versions are referenced",
+						Required: false,
+						Default:  false,
+					},
+				},
 				StatusCode: []definition.StatusCode{
 					definition.StatusCode{Code: http.StatusNoContent, Message: "Delete successfully"},
+					definition.StatusCode{Code: http.StatusConflict, Message: "Versions of the chart are referenced by orchestrated versions or requirements"},
+				},
+			},
+		},
+	},
+	{
+		Path: "/spaces/{space}/charts/{chart}/move",
+		Handlers: []definition.Handler{
+			{
+				HTTPMethod: http.MethodPost,
+				Handler:    definition.NewHandlerDecoration(definition.VerbUpdate, handlers.MoveChart).Handle,
+				Doc:        "Move a chart and its all versions to another space",
+				Note: `The target space must exist and must not contain a chart with the same name. If any version of
+the chart is referenced by orchestrated versions of other charts, including yanked ones, the chart is not moved
+unless force is true, and the error lists the referencing versions. If it's moved, the references are moved with
+it, so rebuilding the orchestrated versions uses the moved chart. Requirements in packages can't be changed, so a
+chart with versions referenced by requirements is never moved. A Move event is recorded in the history of the chart.`,
+				PathParams: []definition.Param{
+					{
+						Name:     "space",
+						Type:     "string",
+						Doc:      "space name",
+						Required: true,
+					},
+					{
+						Name:     "chart",
+						Type:     "string",
+						Doc:      "chart name",
+						Required: true,
+					},
+				},
+				QueryParams: []definition.Param{
+					{
+						Name:     "target",
+						Type:     "string",
+						Doc:      "target space name",
+						Required: true,
+					},
+					{
+						Name:     "force",
+						Type:     "boolean",
+						Doc:      "move the chart even if its versions are referenced",
+						Required: false,
+						Default:  false,
+					},
+				},
+				StatusCode: []definition.StatusCode{
+					definition.StatusCode{Code: http.StatusOK, Message: "Move successfully",
+						Sample: &models.Link{
+							Name: "chartName",
+							Link: "/spaces/targetSpace/charts/chartName",
+						}},
+					definition.StatusCode{Code: http.StatusConflict, Message: "Versions of the chart are referenced by orchestrated versions or requirements"},
+				},
+			},
+		},
+	},
+	{
+		Path: "/spaces/{space}/charts/{chart}/history",
+		Handlers: []definition.Handler{
+			{
+				HTTPMethod: http.MethodGet,
+				Handler:    definition.NewHandlerDecoration(definition.VerbList, handlers.ListChartHistory).Handle,
+				Doc:        "List events in the history of a chart",
+				PathParams: []definition.Param{
+					{
+						Name:     "space",
+						Type:     "string",
+						Doc:      "space name",
+						Required: true,
+					},
+					{
+						Name:     "chart",
+						Type:     "string",
+						Doc:      "chart name",
+						Required: true,
+					},
+				},
+				QueryParams: []definition.Param{
+					{
+						Name:     "start",
+						Type:     "number",
+						Doc:      "Query start index",
+						Required: false,
+						Default:  0,
+					},
+					{
+						Name:     "limit",
+						Type:     "number",
+						Doc:      "Specify the number of records to return",
+						Required: false,
+						Default:  common.DefaultPagingLimit,
+					},
+					{
+						Name:     "continue",
+						Type:     "string",
+						Doc:      "continuation token from metadata of the previous page. start is ignored if it's specified",
+						Required: false,
+					},
+				},
+				StatusCode: []definition.StatusCode{
+					definition.StatusCode{Code: http.StatusOK, Message: "Success and respond with an array of events",
+						Sample: &models.ListResponse{
+							Metadata: models.Metadata{
+								Total:       10,
+								ItemsLength: 1,
+							},
+							Items: []*storage.Event{
+								{
+									Type:    storage.EventTypePromotion,
+									Version: "1.0.0",
+									Source:  "spaceName/chartName/1.0.0",
+								},
+							},
+						}},
+				},
+			},
+		},
+	},
+	{
+		Path: "/spaces/{space}/charts/{chart}/diff",
+		Handlers: []definition.Handler{
+			{
+				HTTPMethod: http.MethodGet,
+				Handler:    definition.NewHandlerDecoration(definition.VerbGet, handlers.DiffVersions).Handle,
+				Doc:        "Compare two versions of a chart",
+				Note: `Metadata and coalesced values are compared by keys. Templates, files and subcharts are compared
+by unified diffs. Specify toSpace to compare with a version in another space.`,
+				PathParams: []definition.Param{
+					{
+						Name:     "space",
+						Type:     "string",
+						Doc:      "space name",
+						Required: true,
+					},
+					{
+						Name:     "chart",
+						Type:     "string",
+						Doc:      "chart name",
+						Required: true,
+					},
+				},
+				QueryParams: []definition.Param{
+					{
+						Name:     "from",
+						Type:     "string",
+						Doc:      "old version number or tag",
+						Required: true,
+					},
+					{
+						Name:     "to",
+						Type:     "string",
+						Doc:      "new version number or tag",
+						Required: true,
+					},
+					{
+						Name:     "toSpace",
+						Type:     "string",
+						Doc:      "space of the new version. Default is the space of the old version",
+						Required: false,
+					},
+				},
+				StatusCode: []definition.StatusCode{
+					definition.StatusCode{Code: http.StatusOK, Message: "Success and respond with the difference",
+						Sample: &types.ChartDiff{
+							Metadata: []types.ValueChange{
+								{Path: "version", Type: diff.Changed, From: "1.3.0", To: "1.4.0"},
+							},
+							Values: []types.ValueChange{
+								{Path: "image.tag", Type: diff.Changed, From: "1.3", To: "1.4"},
+							},
+							Files: []types.FileChange{
+								{
+									Path: "templates/service.yaml",
+									Type: diff.Added,
+									Diff: "--- /dev/null\n+++ b/templates/service.yaml\n@@ -0,0 +1,1 @@\n+kind: Service\n",
+								},
+							},
+						}},
 				},
 			},
 		},
@@ -0,0 +1,200 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package descriptor
+
+import (
+	"net/http"
+
+	"github.com/caicloud/helm-registry/pkg/api/definition"
+	"github.com/caicloud/helm-registry/pkg/api/models"
+	"github.com/caicloud/helm-registry/pkg/api/v1/handlers"
+	"github.com/caicloud/helm-registry/pkg/common"
+	"github.com/caicloud/helm-registry/pkg/storage"
+)
+
+func init() {
+	registerDescriptors(dependents)
+}
+
+// dependents descriptors
+var dependents = []definition.Descriptor{
+	{
+		Path: "/spaces/{space}/charts/{chart}/dependents",
+		Handlers: []definition.Handler{
+			{
+				HTTPMethod: http.MethodGet,
+				Handler:    definition.NewHandlerDecoration(definition.VerbList, handlers.ListDependents).Handle,
+				Doc:        "List versions which were built from a chart or require it",
+				Note: `
+Orchestrated versions in all spaces are listed with the package which references the chart. A
+version is listed once for each reference, and the path of a reference is the path of chart in
+the orchestration config. Tags in configs are resolved when versions are created, so a reference
+always has a version number. Versions which require the chart in requirements.yaml are listed
+with references whose requirement is true, and the path is the name or alias of the dependency.
+A requirement references the chart if its repository is the space of the chart in this registry,
+e.g. http://registry/api/v1/spaces/library. It references the subchart packaged in the dependent,
+or else the latest version satisfying its range when the dependent is stored. If version is specified, only references of that version are listed.
+Versions orchestrated before references were recorded are not listed. Yanked dependents are
+excluded unless yanked is true. Dependents which can't be read, e.g. they're being stored, are
+skipped and reported by Warning headers.`,
+				PathParams: []definition.Param{
+					{
+						Name:     "space",
+						Type:     "string",
+						Doc:      "space name",
+						Required: true,
+					},
+					{
+						Name:     "chart",
+						Type:     "string",
+						Doc:      "chart name",
+						Required: true,
+					},
+				},
+				QueryParams: []definition.Param{
+					{
+						Name:     "start",
+						Type:     "number",
+						Doc:      "Query start index",
+						Required: false,
+						Default:  0,
+					},
+					{
+						Name:     "limit",
+						Type:     "number",
+						Doc:      "Specify the number of records to return",
+						Required: false,
+						Default:  common.DefaultPagingLimit,
+					},
+					{
+						Name:     "continue",
+						Type:     "string",
+						Doc:      "continuation token from metadata of the previous page. start is ignored if it's specified",
+						Required: false,
+					},
+					{
+						Name:     "version",
+						Type:     "string",
+						Doc:      "version number or tag of the chart",
+						Required: false,
+					},
+					{
+						Name:     "yanked",
+						Type:     "boolean",
+						Doc:      "include yanked dependents",
+						Required: false,
+						Default:  false,
+					},
+				},
+				StatusCode: []definition.StatusCode{
+					definition.StatusCode{Code: http.StatusOK, Message: "Success and respond with a array of dependents",
+						Sample: &models.ListResponse{
+							Metadata: models.Metadata{
+								Total:       1,
+								ItemsLength: 1,
+							},
+							Items: []*models.Dependent{
+								{
+									Space:   "apps",
+									Chart:   "shop",
+									Version: "1.0.0",
+									Reference: storage.Reference{
+										Path:    "db",
+										Space:   "library",
+										Chart:   "mysql",
+										Version: "0.3.0",
+										Digest:  "sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+									},
+								},
+							},
+						}},
+					definition.StatusCode{Code: http.StatusNotFound, Message: "The tag does not exist"},
+				},
+			},
+		},
+	},
+	{
+		Path: "/spaces/{space}/outdated",
+		Handlers: []definition.Handler{
+			{
+				HTTPMethod: http.MethodGet,
+				Handler:    definition.NewHandlerDecoration(definition.VerbList, handlers.ListOutdatedVersions).Handle,
+				Doc:        "List orchestrated versions whose packages have newer versions",
+				Note: `
+A package is outdated if its chart has a newer version which is not yanked. Only the latest
+orchestrated version of each chart is checked unless all is true, and yanked versions are never
+checked. Packages of an item are the outdated packages with their latest versions. Such a
+version can be upgraded by rebuilding. Versions which can't be read, e.g. they're being stored,
+are skipped and reported by Warning headers.`,
+				PathParams: []definition.Param{
+					{
+						Name:     "space",
+						Type:     "string",
+						Doc:      "space name",
+						Required: true,
+					},
+				},
+				QueryParams: []definition.Param{
+					{
+						Name:     "start",
+						Type:     "number",
+						Doc:      "Query start index",
+						Required: false,
+						Default:  0,
+					},
+					{
+						Name:     "limit",
+						Type:     "number",
+						Doc:      "Specify the number of records to return",
+						Required: false,
+						Default:  common.DefaultPagingLimit,
+					},
+					{
+						Name:     "continue",
+						Type:     "string",
+						Doc:      "continuation token from metadata of the previous page. start is ignored if it's specified",
+						Required: false,
+					},
+					{
+						Name:     "all",
+						Type:     "boolean",
+						Doc:      "check all versions instead of the latest version of each chart",
+						Required: false,
+						Default:  false,
+					},
+				},
+				StatusCode: []definition.StatusCode{
+					definition.StatusCode{Code: http.StatusOK, Message: "Success and respond with a array of outdated versions",
+						Sample: &models.ListResponse{
+							Metadata: models.Metadata{
+								Total:       1,
+								ItemsLength: 1,
+							},
+							Items: []*models.OutdatedVersion{
+								{
+									Space:   "apps",
+									Chart:   "shop",
+									Version: "1.0.0",
+									Packages: []models.OutdatedPackage{
+										{
+											Reference: storage.Reference{
+												Path:    "db",
+												Space:   "library",
+												Chart:   "mysql",
+												Version: "0.3.0",
+												Digest:  "sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+											},
+											Latest: "0.4.1",
+										},
+									},
+								},
+							},
+						}},
+					definition.StatusCode{Code: http.StatusNotFound, Message: "The space does not exist"},
+				},
+			},
+		},
+	},
+}
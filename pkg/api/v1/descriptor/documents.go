@@ -0,0 +1,278 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package descriptor
+
+import (
+	"net/http"
+
+	"github.com/caicloud/helm-registry/pkg/api/definition"
+	"github.com/caicloud/helm-registry/pkg/api/v1/handlers"
+	"github.com/emicklei/go-restful"
+)
+
+func init() {
+	registerDescriptors(documents)
+}
+
+// documents descriptors
+var documents = []definition.Descriptor{
+	{
+		Path: "/spaces/{space}/charts/{chart}/versions/{version}/readme",
+		Handlers: []definition.Handler{
+			{
+				HTTPMethod: http.MethodGet,
+				Handler:    definition.NewHandlerDecoration(definition.VerbGet, handlers.FetchReadme).Handle,
+				Doc:        "Fetch README.md of a version",
+				Note: `Respond with markdown by default, or with html rendered from the markdown if the request
+accepts text/html. Raw HTML in the markdown is escaped. The response has an ETag which is
+the digest of README.md.`,
+				PathParams: []definition.Param{
+					{
+						Name:     "space",
+						Type:     "string",
+						Doc:      "space name",
+						Required: true,
+					},
+					{
+						Name:     "chart",
+						Type:     "string",
+						Doc:      "chart name",
+						Required: true,
+					},
+					{
+						Name:     "version",
+						Type:     "string",
+						Doc:      "version number or tag",
+						Required: true,
+					},
+				},
+				HeaderParams: []definition.Param{
+					{
+						Name:     "Accept",
+						Type:     "string",
+						Doc:      "text/markdown or text/html",
+						Required: false,
+					},
+					{
+						Name:     "If-None-Match",
+						Type:     "string",
+						Doc:      "entity tags of cached responses",
+						Required: false,
+					},
+				},
+				StatusCode: []definition.StatusCode{
+					definition.StatusCode{Code: http.StatusOK, Message: "Success and respond with markdown or html"},
+					definition.StatusCode{Code: http.StatusNotModified, Message: "Not modified since the cached response"},
+					definition.StatusCode{Code: http.StatusNotFound, Message: "The chart has no README.md"},
+				},
+				Produces: []string{restful.MIME_JSON, restful.MIME_XML, definition.MIMEYAML,
+					definition.MIMEMarkdown, definition.MIMEHTML},
+			},
+		},
+	},
+	{
+		Path: "/spaces/{space}/charts/{chart}/versions/{version}/notes",
+		Handlers: []definition.Handler{
+			{
+				HTTPMethod: http.MethodGet,
+				Handler:    definition.NewHandlerDecoration(definition.VerbGet, handlers.FetchNotes).Handle,
+				Doc:        "Fetch templates/NOTES.txt of a version",
+				Note:       "The notes are not rendered. The response has an ETag which is the digest of NOTES.txt.",
+				PathParams: []definition.Param{
+					{
+						Name:     "space",
+						Type:     "string",
+						Doc:      "space name",
+						Required: true,
+					},
+					{
+						Name:     "chart",
+						Type:     "string",
+						Doc:      "chart name",
+						Required: true,
+					},
+					{
+						Name:     "version",
+						Type:     "string",
+						Doc:      "version number or tag",
+						Required: true,
+					},
+				},
+				HeaderParams: []definition.Param{
+					{
+						Name:     "If-None-Match",
+						Type:     "string",
+						Doc:      "entity tags of cached responses",
+						Required: false,
+					},
+				},
+				StatusCode: []definition.StatusCode{
+					definition.StatusCode{Code: http.StatusOK, Message: "Success and respond with plain text"},
+					definition.StatusCode{Code: http.StatusNotModified, Message: "Not modified since the cached response"},
+					definition.StatusCode{Code: http.StatusNotFound, Message: "The chart has no NOTES.txt"},
+				},
+				Produces: []string{restful.MIME_JSON, restful.MIME_XML, definition.MIMEYAML, definition.MIMEText},
+			},
+		},
+	},
+	{
+		Path: "/spaces/{space}/charts/{chart}/versions/{version}/icon",
+		Handlers: []definition.Handler{
+			{
+				HTTPMethod: http.MethodGet,
+				Handler:    definition.NewHandlerDecoration(definition.VerbGet, handlers.FetchIcon).Handle,
+				Doc:        "Fetch the icon of a version",
+				Note: `Only an icon file in the chart package is available, e.g. icon: icon.png or
+icon: file://icon.png in Chart.yaml. A remote icon is only referenced by metadata. Content-Type
+of the response is detected from the extension or content of the icon.`,
+				PathParams: []definition.Param{
+					{
+						Name:     "space",
+						Type:     "string",
+						Doc:      "space name",
+						Required: true,
+					},
+					{
+						Name:     "chart",
+						Type:     "string",
+						Doc:      "chart name",
+						Required: true,
+					},
+					{
+						Name:     "version",
+						Type:     "string",
+						Doc:      "version number or tag",
+						Required: true,
+					},
+				},
+				HeaderParams: []definition.Param{
+					{
+						Name:     "If-None-Match",
+						Type:     "string",
+						Doc:      "entity tags of cached responses",
+						Required: false,
+					},
+				},
+				StatusCode: []definition.StatusCode{
+					definition.StatusCode{Code: http.StatusOK, Message: "Success and respond with the icon"},
+					definition.StatusCode{Code: http.StatusNotModified, Message: "Not modified since the cached response"},
+					definition.StatusCode{Code: http.StatusNotFound, Message: "The chart package has no icon"},
+				},
+				Produces: []string{restful.MIME_JSON, restful.MIME_XML, definition.MIMEYAML, definition.MIMEAny},
+			},
+		},
+	},
+	{
+		Path: "/spaces/{space}/charts/{chart}/versions/{version}/schema",
+		Handlers: []definition.Handler{
+			{
+				HTTPMethod: http.MethodGet,
+				Handler:    definition.NewHandlerDecoration(definition.VerbGet, handlers.FetchSchema).Handle,
+				Doc:        "Fetch the json schema of values of a version",
+				Note: `The schema is composed from values.schema.json of the chart and its subcharts. A schema
+of subchart describes the values under the name of subchart. If the version is created by
+orchestration, schemas describe _config of each chart instead. Values are validated by the
+schema whenever the version is uploaded, updated or created by orchestration.`,
+				PathParams: []definition.Param{
+					{
+						Name:     "space",
+						Type:     "string",
+						Doc:      "space name",
+						Required: true,
+					},
+					{
+						Name:     "chart",
+						Type:     "string",
+						Doc:      "chart name",
+						Required: true,
+					},
+					{
+						Name:     "version",
+						Type:     "string",
+						Doc:      "version number or tag",
+						Required: true,
+					},
+				},
+				HeaderParams: []definition.Param{
+					{
+						Name:     "If-None-Match",
+						Type:     "string",
+						Doc:      "entity tags of cached responses",
+						Required: false,
+					},
+				},
+				StatusCode: []definition.StatusCode{
+					definition.StatusCode{Code: http.StatusOK, Message: "Success and respond with the json schema"},
+					definition.StatusCode{Code: http.StatusNotModified, Message: "Not modified since the cached response"},
+					definition.StatusCode{Code: http.StatusNotFound, Message: "Neither the chart nor its subcharts have values.schema.json"},
+				},
+			},
+		},
+	},
+	{
+		Path: "/spaces/{space}/charts/{chart}/versions/{version}/schema/inferred",
+		Handlers: []definition.Handler{
+			{
+				HTTPMethod: http.MethodGet,
+				Handler:    definition.NewHandlerDecoration(definition.VerbGet, handlers.InferSchema).Handle,
+				Doc:        "Infer a json schema from values of a version",
+				Note: `The schema is inferred from coalesced values, so values of subcharts are under their names,
+and it's available even if the chart has no values.schema.json. Objects have properties, arrays
+have items inferred from all of their items, and other values have types and defaults. Descriptions
+come from comments right above keys with the same indent, or comments at the end of lines, in
+values.yaml of the chart and its subcharts. Comments of a parent chart take precedence.
+Sample:
+{
+    "$schema": "http://json-schema.org/draft-07/schema#",
+    "type": "object",
+    "properties": {
+        "image": {
+            "type": "object",
+            "properties": {
+                "tag": {
+                    "description": "image tag",
+                    "type": "string",
+                    "default": "1.13"
+                }
+            }
+        }
+    }
+}`,
+				PathParams: []definition.Param{
+					{
+						Name:     "space",
+						Type:     "string",
+						Doc:      "space name",
+						Required: true,
+					},
+					{
+						Name:     "chart",
+						Type:     "string",
+						Doc:      "chart name",
+						Required: true,
+					},
+					{
+						Name:     "version",
+						Type:     "string",
+						Doc:      "version number or tag",
+						Required: true,
+					},
+				},
+				HeaderParams: []definition.Param{
+					{
+						Name:     "If-None-Match",
+						Type:     "string",
+						Doc:      "entity tags of cached responses",
+						Required: false,
+					},
+				},
+				StatusCode: []definition.StatusCode{
+					definition.StatusCode{Code: http.StatusOK, Message: "Success and respond with the inferred json schema"},
+					definition.StatusCode{Code: http.StatusNotModified, Message: "Not modified since the cached response"},
+				},
+			},
+		},
+	},
+}
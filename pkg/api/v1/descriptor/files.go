@@ -0,0 +1,142 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package descriptor
+
+import (
+	"net/http"
+
+	"github.com/caicloud/helm-registry/pkg/api/definition"
+	"github.com/caicloud/helm-registry/pkg/api/models"
+	"github.com/caicloud/helm-registry/pkg/api/v1/handlers"
+	"github.com/caicloud/helm-registry/pkg/common"
+	"github.com/emicklei/go-restful"
+)
+
+func init() {
+	registerDescriptors(files)
+}
+
+// files descriptors
+var files = []definition.Descriptor{
+	{
+		Path: "/spaces/{space}/charts/{chart}/versions/{version}/files",
+		Handlers: []definition.Handler{
+			{
+				HTTPMethod: http.MethodGet,
+				Handler:    definition.NewHandlerDecoration(definition.VerbList, handlers.ListFiles).Handle,
+				Doc:        "List files in the chart package of a version",
+				Note: `Files are sorted by paths which are relative to the top directory of the package. Files are
+listed as they are archived, and packages of subcharts are files in charts/.`,
+				PathParams: []definition.Param{
+					{
+						Name:     "space",
+						Type:     "string",
+						Doc:      "space name",
+						Required: true,
+					},
+					{
+						Name:     "chart",
+						Type:     "string",
+						Doc:      "chart name",
+						Required: true,
+					},
+					{
+						Name:     "version",
+						Type:     "string",
+						Doc:      "version number or tag",
+						Required: true,
+					},
+				},
+				QueryParams: []definition.Param{
+					{
+						Name:     "start",
+						Type:     "number",
+						Doc:      "Query start index",
+						Required: false,
+						Default:  0,
+					},
+					{
+						Name:     "limit",
+						Type:     "number",
+						Doc:      "Specify the number of records to return",
+						Required: false,
+						Default:  common.DefaultPagingLimit,
+					},
+					{
+						Name:     "continue",
+						Type:     "string",
+						Doc:      "continuation token from metadata of the previous page. start is ignored if it's specified",
+						Required: false,
+					},
+				},
+				StatusCode: []definition.StatusCode{
+					definition.StatusCode{Code: http.StatusOK, Message: "Success and respond with a array of files",
+						Sample: &models.ListResponse{
+							Metadata: models.Metadata{
+								Total:       3,
+								ItemsLength: 3,
+							},
+							Items: []*models.File{
+								{Path: "Chart.yaml", Size: 68, ContentType: definition.MIMEYAML},
+								{Path: "templates/deployment.yaml", Size: 512, ContentType: definition.MIMEYAML},
+								{Path: "values.yaml", Size: 128, ContentType: definition.MIMEYAML},
+							},
+						}},
+				},
+			},
+		},
+	},
+	{
+		Path: "/spaces/{space}/charts/{chart}/versions/{version}/files/{path:*}",
+		Handlers: []definition.Handler{
+			{
+				HTTPMethod: http.MethodGet,
+				Handler:    definition.NewHandlerDecoration(definition.VerbGet, handlers.FetchFile).Handle,
+				Doc:        "Fetch raw content of a file in the chart package of a version",
+				Note: `Content-Type of the response is detected from the extension or content of the file. The
+response has an ETag which is the digest of the file.`,
+				PathParams: []definition.Param{
+					{
+						Name:     "space",
+						Type:     "string",
+						Doc:      "space name",
+						Required: true,
+					},
+					{
+						Name:     "chart",
+						Type:     "string",
+						Doc:      "chart name",
+						Required: true,
+					},
+					{
+						Name:     "version",
+						Type:     "string",
+						Doc:      "version number or tag",
+						Required: true,
+					},
+					{
+						Name:     "path",
+						Type:     "string",
+						Doc:      "file path, e.g. templates/deployment.yaml",
+						Required: true,
+					},
+				},
+				HeaderParams: []definition.Param{
+					{
+						Name:     "If-None-Match",
+						Type:     "string",
+						Doc:      "entity tags of cached responses",
+						Required: false,
+					},
+				},
+				StatusCode: []definition.StatusCode{
+					definition.StatusCode{Code: http.StatusOK, Message: "Success and respond with raw content of the file"},
+					definition.StatusCode{Code: http.StatusNotModified, Message: "Not modified since the cached response"},
+				},
+				Produces: []string{restful.MIME_JSON, restful.MIME_XML, definition.MIMEYAML, definition.MIMEAny},
+			},
+		},
+	},
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package descriptor
+
+import (
+	"net/http"
+
+	"github.com/caicloud/helm-registry/pkg/api/definition"
+	"github.com/caicloud/helm-registry/pkg/api/models"
+	"github.com/caicloud/helm-registry/pkg/api/v1/handlers"
+	"github.com/caicloud/helm-registry/pkg/lint"
+	"github.com/caicloud/helm-registry/pkg/storage"
+)
+
+func init() {
+	registerDescriptors(lints)
+}
+
+// lints descriptors
+var lints = []definition.Descriptor{
+	{
+		Path: "/spaces/{space}/lint",
+		Handlers: []definition.Handler{
+			{
+				HTTPMethod: http.MethodPost,
+				Handler:    definition.NewHandlerDecoration(definition.VerbGet, handlers.LintChart).Handle,
+				Doc:        "Lint a chart package without storing it",
+				Note: `
+Lint a chart package as uploading it to the space does, but the package is not stored. Findings
+are reported even if the lint mode of the space is off. Accepted means whether the package can be
+uploaded to the space. Checks:
+  error:   Chart.yaml has a name and a valid SemVer version, values.yaml and templates can be
+           parsed, maintainers have names and valid emails, the package directory matches the name,
+           values.schema.json is valid and default values satisfy it
+  warning: the icon is a valid url or a file in the package, home is a valid url
+  info:    description, icon, maintainers, values.yaml and templates are recommended`,
+				PathParams: []definition.Param{
+					{
+						Name:     "space",
+						Type:     "string",
+						Doc:      "space name",
+						Required: true,
+					},
+				},
+				QueryParams: []definition.Param{
+					{
+						Name:     "chartfile",
+						Type:     "multipart/form-data",
+						Doc:      "An archive file of chart",
+						Required: true,
+					},
+				},
+				StatusCode: []definition.StatusCode{
+					definition.StatusCode{Code: http.StatusOK, Message: "Success and respond with findings",
+						Sample: &models.LintResult{
+							Mode:     storage.LintError,
+							Accepted: false,
+							Findings: []lint.Finding{
+								{Severity: lint.SeverityError, Path: "Chart.yaml", Message: `version "1.0" is not a valid SemVer: No Major.Minor.Patch elements found`},
+								{Severity: lint.SeverityInfo, Path: "Chart.yaml", Message: "icon is recommended"},
+							},
+						}},
+					definition.StatusCode{Code: http.StatusBadRequest, Message: "The chart package can't be loaded"},
+				},
+			},
+		},
+	},
+}
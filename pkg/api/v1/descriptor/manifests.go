@@ -28,6 +28,8 @@ var manifests = []definition.Descriptor{
 				HTTPMethod: http.MethodGet,
 				Handler:    definition.NewHandlerDecoration(definition.VerbList, handlers.ListMetadataInSpace).Handle,
 				Doc:        "List all metadata in a space",
+				Note: `Metadata of yanked versions is excluded unless yanked is true. Metadata can be filtered by
+query parameters, and only metadata which matches all filters is listed.`,
 				PathParams: []definition.Param{
 					{
 						Name:     "space",
@@ -51,6 +53,73 @@ var manifests = []definition.Descriptor{
 						Required: false,
 						Default:  common.DefaultPagingLimit,
 					},
+					{
+						Name:     "continue",
+						Type:     "string",
+						Doc:      "continuation token from metadata of the previous page. start is ignored if it's specified",
+						Required: false,
+					},
+					{
+						Name:     "yanked",
+						Type:     "boolean",
+						Doc:      "include yanked versions",
+						Required: false,
+						Default:  false,
+					},
+					{
+						Name:     "sort",
+						Type:     "string",
+						Doc:      "sort by name, version, created or updated. Prefix '-' means descending order. Default order is by name and version",
+						Required: false,
+					},
+					{
+						Name:     "prefix",
+						Type:     "string",
+						Doc:      "prefix of chart names",
+						Required: false,
+					},
+					{
+						Name:     "keyword",
+						Type:     "string",
+						Doc:      "case-insensitive keyword in chart names, descriptions or keywords",
+						Required: false,
+					},
+					{
+						Name:     "maintainer",
+						Type:     "string",
+						Doc:      "name or email of a maintainer",
+						Required: false,
+					},
+					{
+						Name:     "appVersion",
+						Type:     "string",
+						Doc:      "app version",
+						Required: false,
+					},
+					{
+						Name:     "annotation",
+						Type:     "string",
+						Doc:      "annotations in format key1=value1,key2. A key without value matches any value",
+						Required: false,
+					},
+					{
+						Name:     "version",
+						Type:     "string",
+						Doc:      "version constraints as helm requirements have, such as '>=1.0.0, <2.0.0 || ^3.1.0'. Prereleases only match constraints with prereleases",
+						Required: false,
+					},
+					{
+						Name:     "createdAfter",
+						Type:     "string",
+						Doc:      "RFC3339 time. Only versions created after the time are included",
+						Required: false,
+					},
+					{
+						Name:     "createdBefore",
+						Type:     "string",
+						Doc:      "RFC3339 time. Only versions created before the time are included",
+						Required: false,
+					},
 				},
 				StatusCode: []definition.StatusCode{
 					definition.StatusCode{Code: http.StatusOK, Message: "Success and respond with an array of metadata",
@@ -89,6 +158,8 @@ var manifests = []definition.Descriptor{
 				HTTPMethod: http.MethodGet,
 				Handler:    definition.NewHandlerDecoration(definition.VerbList, handlers.ListLatestMetadataInSpace).Handle,
 				Doc:        "List latest metadata in a space",
+				Note: `Yanked versions are never the latest. For each chart, the latest version which matches all
+filters is listed. A chart without such versions is excluded.`,
 				PathParams: []definition.Param{
 					{
 						Name:     "space",
@@ -112,6 +183,66 @@ var manifests = []definition.Descriptor{
 						Required: false,
 						Default:  common.DefaultPagingLimit,
 					},
+					{
+						Name:     "continue",
+						Type:     "string",
+						Doc:      "continuation token from metadata of the previous page. start is ignored if it's specified",
+						Required: false,
+					},
+					{
+						Name:     "sort",
+						Type:     "string",
+						Doc:      "sort by name, version, created or updated. Prefix '-' means descending order. Default order is by name and version",
+						Required: false,
+					},
+					{
+						Name:     "prefix",
+						Type:     "string",
+						Doc:      "prefix of chart names",
+						Required: false,
+					},
+					{
+						Name:     "keyword",
+						Type:     "string",
+						Doc:      "case-insensitive keyword in chart names, descriptions or keywords",
+						Required: false,
+					},
+					{
+						Name:     "maintainer",
+						Type:     "string",
+						Doc:      "name or email of a maintainer",
+						Required: false,
+					},
+					{
+						Name:     "appVersion",
+						Type:     "string",
+						Doc:      "app version",
+						Required: false,
+					},
+					{
+						Name:     "annotation",
+						Type:     "string",
+						Doc:      "annotations in format key1=value1,key2. A key without value matches any value",
+						Required: false,
+					},
+					{
+						Name:     "version",
+						Type:     "string",
+						Doc:      "version constraints as helm requirements have, such as '>=1.0.0, <2.0.0 || ^3.1.0'. Prereleases only match constraints with prereleases",
+						Required: false,
+					},
+					{
+						Name:     "createdAfter",
+						Type:     "string",
+						Doc:      "RFC3339 time. Only versions created after the time are included",
+						Required: false,
+					},
+					{
+						Name:     "createdBefore",
+						Type:     "string",
+						Doc:      "RFC3339 time. Only versions created before the time are included",
+						Required: false,
+					},
 				},
 				StatusCode: []definition.StatusCode{
 					definition.StatusCode{Code: http.StatusOK, Message: "Success and respond with an array of latest metadata",
@@ -150,6 +281,8 @@ var manifests = []definition.Descriptor{
 				HTTPMethod: http.MethodGet,
 				Handler:    definition.NewHandlerDecoration(definition.VerbList, handlers.ListMetadataInChart).Handle,
 				Doc:        "List all metadata in a chart",
+				Note: `Metadata of yanked versions is excluded unless yanked is true. Metadata can be filtered by
+query parameters, and only metadata which matches all filters is listed.`,
 				PathParams: []definition.Param{
 					{
 						Name:     "space",
@@ -179,6 +312,73 @@ var manifests = []definition.Descriptor{
 						Required: false,
 						Default:  common.DefaultPagingLimit,
 					},
+					{
+						Name:     "continue",
+						Type:     "string",
+						Doc:      "continuation token from metadata of the previous page. start is ignored if it's specified",
+						Required: false,
+					},
+					{
+						Name:     "yanked",
+						Type:     "boolean",
+						Doc:      "include yanked versions",
+						Required: false,
+						Default:  false,
+					},
+					{
+						Name:     "sort",
+						Type:     "string",
+						Doc:      "sort by name, version, created or updated. Prefix '-' means descending order. Default order is by name and version",
+						Required: false,
+					},
+					{
+						Name:     "prefix",
+						Type:     "string",
+						Doc:      "prefix of chart names",
+						Required: false,
+					},
+					{
+						Name:     "keyword",
+						Type:     "string",
+						Doc:      "case-insensitive keyword in chart names, descriptions or keywords",
+						Required: false,
+					},
+					{
+						Name:     "maintainer",
+						Type:     "string",
+						Doc:      "name or email of a maintainer",
+						Required: false,
+					},
+					{
+						Name:     "appVersion",
+						Type:     "string",
+						Doc:      "app version",
+						Required: false,
+					},
+					{
+						Name:     "annotation",
+						Type:     "string",
+						Doc:      "annotations in format key1=value1,key2. A key without value matches any value",
+						Required: false,
+					},
+					{
+						Name:     "version",
+						Type:     "string",
+						Doc:      "version constraints as helm requirements have, such as '>=1.0.0, <2.0.0 || ^3.1.0'. Prereleases only match constraints with prereleases",
+						Required: false,
+					},
+					{
+						Name:     "createdAfter",
+						Type:     "string",
+						Doc:      "RFC3339 time. Only versions created after the time are included",
+						Required: false,
+					},
+					{
+						Name:     "createdBefore",
+						Type:     "string",
+						Doc:      "RFC3339 time. Only versions created before the time are included",
+						Required: false,
+					},
 				},
 				StatusCode: []definition.StatusCode{
 					definition.StatusCode{Code: http.StatusOK, Message: "Success and respond with an array of metadata",
@@ -217,6 +417,7 @@ var manifests = []definition.Descriptor{
 				HTTPMethod: http.MethodGet,
 				Handler:    definition.NewHandlerDecoration(definition.VerbGet, handlers.GetLatestMetadataInChart).Handle,
 				Doc:        "Get metadata of the latest version in a chart",
+				Note:       `Yanked versions are never the latest.`,
 				PathParams: []definition.Param{
 					{
 						Name:     "space",
@@ -260,6 +461,8 @@ var manifests = []definition.Descriptor{
 				HTTPMethod: http.MethodGet,
 				Handler:    definition.NewHandlerDecoration(definition.VerbGet, handlers.FetchMetadata).Handle,
 				Doc:        "Get metadata of a version",
+				Note: `If the version is deprecated, the response has a Warning header. The response has an ETag and a
+Last-Modified header unless the version is specified by a tag.`,
 				PathParams: []definition.Param{
 					{
 						Name:     "space",
@@ -276,10 +479,30 @@ var manifests = []definition.Descriptor{
 					{
 						Name:     "version",
 						Type:     "string",
-						Doc:      "version number",
+						Doc:      "version number or tag",
 						Required: true,
 					},
 				},
+				HeaderParams: []definition.Param{
+					{
+						Name:     "Accept",
+						Type:     "string",
+						Doc:      "application/json or application/yaml. Default is application/json",
+						Required: false,
+					},
+					{
+						Name:     "If-None-Match",
+						Type:     "string",
+						Doc:      "entity tags of cached responses",
+						Required: false,
+					},
+					{
+						Name:     "If-Modified-Since",
+						Type:     "string",
+						Doc:      "last modified time of cached response. Ignored if If-None-Match is specified",
+						Required: false,
+					},
+				},
 				StatusCode: []definition.StatusCode{
 					definition.StatusCode{Code: http.StatusOK, Message: "Success and respond with a metadata of a version",
 						Sample: &storage.Metadata{
@@ -298,6 +521,7 @@ var manifests = []definition.Descriptor{
 								},
 							},
 						}},
+					definition.StatusCode{Code: http.StatusNotModified, Message: "Not modified since the cached response"},
 				},
 			},
 			{
@@ -305,7 +529,7 @@ var manifests = []definition.Descriptor{
 				Handler:    definition.NewHandlerDecoration(definition.VerbUpdate, handlers.UpdateMetadata).Handle,
 				Doc:        "Update metadata for a version",
 				Note: `The api only can update metadata of root chart. Must not modify name and version of metadata.
-							Pass json format metadata by request body.`,
+							Pass json or yaml format metadata by request body.`,
 				PathParams: []definition.Param{
 					{
 						Name:     "space",
@@ -322,10 +546,36 @@ var manifests = []definition.Descriptor{
 					{
 						Name:     "version",
 						Type:     "string",
-						Doc:      "version number",
+						Doc:      "version number or tag",
 						Required: true,
 					},
 				},
+				HeaderParams: []definition.Param{
+					{
+						Name:     "Content-Type",
+						Type:     "string",
+						Doc:      "application/json or application/yaml",
+						Required: false,
+					},
+					{
+						Name:     "Accept",
+						Type:     "string",
+						Doc:      "application/json or application/yaml. Default is application/json",
+						Required: false,
+					},
+					{
+						Name:     "X-Registry-Uploader",
+						Type:     "string",
+						Doc:      "uploader of the version. It's supplied by the client and not verified",
+						Required: false,
+					},
+					{
+						Name:     "If-Match",
+						Type:     "string",
+						Doc:      "entity tag which must match the current one",
+						Required: false,
+					},
+				},
 				StatusCode: []definition.StatusCode{
 					definition.StatusCode{Code: http.StatusOK, Message: "Success and respond with a metadata of a version",
 						Sample: &storage.Metadata{
@@ -344,6 +594,65 @@ var manifests = []definition.Descriptor{
 								},
 							},
 						}},
+					definition.StatusCode{Code: http.StatusPreconditionFailed, Message: "If-Match does not match the current entity tag"},
+					definition.StatusCode{Code: http.StatusUnprocessableEntity, Message: "Values don't satisfy the json schema, or values.yaml can't be edited without losing its content"},
+				},
+			},
+			{
+				HTTPMethod: http.MethodPatch,
+				Handler:    definition.NewHandlerDecoration(definition.VerbUpdate, handlers.PatchMetadata).Handle,
+				Doc:        "Patch metadata of a version",
+				Note: `The api only can patch metadata of root chart. Must not modify name and version of metadata.
+							Pass a JSON Merge Patch with content type application/merge-patch+json, or a JSON Patch
+							with content type application/json-patch+json by request body. The patch is applied atomically.
+							Only changed keys are edited in values.yaml, so its comments are kept. Changes which can't be
+							made without losing content of values.yaml, such as removing defaults of subcharts, are rejected.`,
+				PathParams: []definition.Param{
+					{
+						Name:     "space",
+						Type:     "string",
+						Doc:      "space name",
+						Required: true,
+					},
+					{
+						Name:     "chart",
+						Type:     "string",
+						Doc:      "chart name",
+						Required: true,
+					},
+					{
+						Name:     "version",
+						Type:     "string",
+						Doc:      "version number or tag",
+						Required: true,
+					},
+				},
+				HeaderParams: []definition.Param{
+					{
+						Name:     "Content-Type",
+						Type:     "string",
+						Doc:      "application/merge-patch+json or application/json-patch+json",
+						Required: true,
+					},
+					{
+						Name:     "X-Registry-Uploader",
+						Type:     "string",
+						Doc:      "uploader of the version. It's supplied by the client and not verified",
+						Required: false,
+					},
+					{
+						Name:     "If-Match",
+						Type:     "string",
+						Doc:      "entity tag which must match the current one",
+						Required: false,
+					},
+				},
+				StatusCode: []definition.StatusCode{
+					definition.StatusCode{Code: http.StatusOK, Message: "Success and respond with patched metadata of a version"},
+					definition.StatusCode{Code: http.StatusBadRequest, Message: "Malformed patch"},
+					definition.StatusCode{Code: http.StatusPreconditionFailed, Message: "If-Match does not match the current entity tag"},
+					definition.StatusCode{Code: http.StatusUnsupportedMediaType, Message: "Unsupported patch type"},
+					definition.StatusCode{Code: http.StatusUnprocessableEntity, Message: "The patch can't be applied. The message points to the failing operation"},
 				},
 			},
 		},
@@ -355,6 +664,8 @@ var manifests = []definition.Descriptor{
 				HTTPMethod: http.MethodGet,
 				Handler:    definition.NewHandlerDecoration(definition.VerbGet, handlers.FetchValues).Handle,
 				Doc:        "Get values of a version",
+				Note: `If the version is deprecated, the response has a Warning header. The response has an ETag and a
+Last-Modified header unless the version is specified by a tag.`,
 				PathParams: []definition.Param{
 					{
 						Name:     "space",
@@ -371,12 +682,33 @@ var manifests = []definition.Descriptor{
 					{
 						Name:     "version",
 						Type:     "string",
-						Doc:      "version number",
+						Doc:      "version number or tag",
 						Required: true,
 					},
 				},
+				HeaderParams: []definition.Param{
+					{
+						Name:     "Accept",
+						Type:     "string",
+						Doc:      "application/json for coalesced values, or application/yaml for the original values.yaml. Default is application/json",
+						Required: false,
+					},
+					{
+						Name:     "If-None-Match",
+						Type:     "string",
+						Doc:      "entity tags of cached responses",
+						Required: false,
+					},
+					{
+						Name:     "If-Modified-Since",
+						Type:     "string",
+						Doc:      "last modified time of cached response. Ignored if If-None-Match is specified",
+						Required: false,
+					},
+				},
 				StatusCode: []definition.StatusCode{
 					definition.StatusCode{Code: http.StatusOK, Message: "Success and respond with values of a version"},
+					definition.StatusCode{Code: http.StatusNotModified, Message: "Not modified since the cached response"},
 				},
 			},
 			{
@@ -384,7 +716,9 @@ var manifests = []definition.Descriptor{
 				Handler:    definition.NewHandlerDecoration(definition.VerbUpdate, handlers.UpdateValues).Handle,
 				Doc:        "Update values for a version",
 				Note: `The values only stores in root chart. If you want to set values of subcharts, use overriding values.
-							Pass json format metadata by request body.`,
+							Pass json or yaml format values by request body. Comments and the order of keys in yaml are kept.
+							Values in json only edit changed keys of values.yaml, so its comments are kept too.
+							Values are validated by the json schema of the version if it has one.`,
 				PathParams: []definition.Param{
 					{
 						Name:     "space",
@@ -401,12 +735,97 @@ var manifests = []definition.Descriptor{
 					{
 						Name:     "version",
 						Type:     "string",
-						Doc:      "version number",
+						Doc:      "version number or tag",
 						Required: true,
 					},
 				},
+				HeaderParams: []definition.Param{
+					{
+						Name:     "Content-Type",
+						Type:     "string",
+						Doc:      "application/json, or application/yaml which is stored as values.yaml as it is",
+						Required: false,
+					},
+					{
+						Name:     "Accept",
+						Type:     "string",
+						Doc:      "application/json for coalesced values, or application/yaml for the original values.yaml. Default is application/json",
+						Required: false,
+					},
+					{
+						Name:     "X-Registry-Uploader",
+						Type:     "string",
+						Doc:      "uploader of the version. It's supplied by the client and not verified",
+						Required: false,
+					},
+					{
+						Name:     "If-Match",
+						Type:     "string",
+						Doc:      "entity tag which must match the current one",
+						Required: false,
+					},
+				},
 				StatusCode: []definition.StatusCode{
 					definition.StatusCode{Code: http.StatusOK, Message: "Success and respond with values of a version"},
+					definition.StatusCode{Code: http.StatusPreconditionFailed, Message: "If-Match does not match the current entity tag"},
+					definition.StatusCode{Code: http.StatusUnprocessableEntity, Message: "Values don't satisfy the json schema, or values.yaml can't be edited without losing its content"},
+				},
+			},
+			{
+				HTTPMethod: http.MethodPatch,
+				Handler:    definition.NewHandlerDecoration(definition.VerbUpdate, handlers.PatchValues).Handle,
+				Doc:        "Patch values of a version",
+				Note: `The patch is applied to values which are returned by GET, and the result must be an object.
+							Pass a JSON Merge Patch with content type application/merge-patch+json, or a JSON Patch
+							with content type application/json-patch+json by request body. The patch is applied atomically.
+							Only changed keys are edited in values.yaml, so its comments are kept. Changes which can't be
+							made without losing content of values.yaml, such as removing defaults of subcharts, are rejected.`,
+				PathParams: []definition.Param{
+					{
+						Name:     "space",
+						Type:     "string",
+						Doc:      "space name",
+						Required: true,
+					},
+					{
+						Name:     "chart",
+						Type:     "string",
+						Doc:      "chart name",
+						Required: true,
+					},
+					{
+						Name:     "version",
+						Type:     "string",
+						Doc:      "version number or tag",
+						Required: true,
+					},
+				},
+				HeaderParams: []definition.Param{
+					{
+						Name:     "Content-Type",
+						Type:     "string",
+						Doc:      "application/merge-patch+json or application/json-patch+json",
+						Required: true,
+					},
+					{
+						Name:     "X-Registry-Uploader",
+						Type:     "string",
+						Doc:      "uploader of the version. It's supplied by the client and not verified",
+						Required: false,
+					},
+					{
+						Name:     "If-Match",
+						Type:     "string",
+						Doc:      "entity tag which must match the current one",
+						Required: false,
+					},
+				},
+				StatusCode: []definition.StatusCode{
+					definition.StatusCode{Code: http.StatusOK, Message: "Success and respond with patched values of a version"},
+					definition.StatusCode{Code: http.StatusBadRequest, Message: "Malformed patch"},
+					definition.StatusCode{Code: http.StatusPreconditionFailed, Message: "If-Match does not match the current entity tag"},
+					definition.StatusCode{Code: http.StatusUnsupportedMediaType, Message: "Unsupported patch type"},
+					definition.StatusCode{Code: http.StatusUnprocessableEntity, Message: "The patch can't be applied. The message points to the failing operation"},
 				},
 			},
 		},
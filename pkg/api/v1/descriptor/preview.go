@@ -0,0 +1,97 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package descriptor
+
+import (
+	"net/http"
+
+	"github.com/caicloud/helm-registry/pkg/api/definition"
+	"github.com/caicloud/helm-registry/pkg/api/models"
+	"github.com/caicloud/helm-registry/pkg/api/v1/handlers"
+	"github.com/caicloud/helm-registry/pkg/orchestration"
+	"github.com/emicklei/go-restful"
+)
+
+func init() {
+	registerDescriptors(previews)
+}
+
+// previews descriptors
+var previews = []definition.Descriptor{
+	{
+		Path: "/spaces/{space}/preview",
+		Handlers: []definition.Handler{
+			{
+				HTTPMethod: http.MethodPost,
+				Handler:    definition.NewHandlerDecoration(definition.VerbGet, handlers.PreviewChart).Handle,
+				Doc:        "Preview a chart orchestrated by a config without storing it",
+				Note: `
+The request body is the same orchestration config as creating a chart. The config is resolved
+and the chart is linted and validated as creating does, but problems are reported in the response
+instead of failing the request, e.g. missing packages, invalid or conflicting chart names, an
+existing version and invalid values. Accepted means the chart can be created by the config.
+The tree contains sources of charts, and digests of independent packages. Values are the merged
+values as they are stored with the version, in yaml. They're empty if charts can't be resolved.
+If format is archive, respond with the orchestrated chart package instead, or with 422 if there
+are problems.`,
+				PathParams: []definition.Param{
+					{
+						Name:     "space",
+						Type:     "string",
+						Doc:      "space name",
+						Required: true,
+					},
+				},
+				QueryParams: []definition.Param{
+					{
+						Name:     "format",
+						Type:     "string",
+						Doc:      "archive to download the chart package",
+						Required: false,
+					},
+				},
+				StatusCode: []definition.StatusCode{
+					definition.StatusCode{Code: http.StatusOK, Message: "Success and respond with the preview or the chart package",
+						Sample: &models.OrchestrationPreview{
+							Space:    "library",
+							Chart:    "app",
+							Version:  "1.0.0",
+							Accepted: false,
+							Tree: &orchestration.Node{
+								Name: "app",
+								Package: &orchestration.Package{
+									Independent: true,
+									Space:       "library",
+									Chart:       "web",
+									Version:     "1.2.0",
+								},
+								Digest: "sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+								Dependencies: []*orchestration.Node{
+									{
+										Name: "db",
+										Package: &orchestration.Package{
+											Independent: true,
+											Space:       "library",
+											Chart:       "mysql",
+											Version:     "0.3.0",
+										},
+										Digest: "sha256:4a2bb3a5c8b3e1ef3e0b7a4f5bc9e2ec5e8d1f0b3fd2d7f38a0e2c8f3e6e5b1a",
+									},
+								},
+							},
+							Values: "_config:\n  replicas: 2\ndb:\n  _config: {}\n  global: {}\n",
+							Problems: []orchestration.Problem{
+								{Message: "values can't be applied: _config.image: value is required"},
+							},
+						}},
+					definition.StatusCode{Code: http.StatusBadRequest, Message: "The config is malformed"},
+					definition.StatusCode{Code: http.StatusNotFound, Message: "The space does not exist"},
+					definition.StatusCode{Code: http.StatusUnprocessableEntity, Message: "The chart package is requested but there are problems"},
+				},
+				Produces: []string{restful.MIME_JSON, restful.MIME_XML, definition.MIMEYAML, definition.MIMEAny},
+			},
+		},
+	},
+}
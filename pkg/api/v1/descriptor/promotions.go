@@ -0,0 +1,156 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package descriptor
+
+import (
+	"net/http"
+
+	"github.com/caicloud/helm-registry/pkg/api/definition"
+	"github.com/caicloud/helm-registry/pkg/api/models"
+	"github.com/caicloud/helm-registry/pkg/api/v1/handlers"
+)
+
+func init() {
+	registerDescriptors(promotions)
+}
+
+// promotions descriptors
+var promotions = []definition.Descriptor{
+	{
+		Path: "/spaces/{space}/charts/{chart}/promote",
+		Handlers: []definition.Handler{
+			{
+				HTTPMethod: http.MethodPost,
+				Handler:    definition.NewHandlerDecoration(definition.VerbCreate, handlers.PromoteChart).Handle,
+				Doc:        "Promote versions of a chart to another space",
+				Note: `Copy packages of specified versions to the target space. If versions is not specified, all
+versions of the chart which are not yanked are promoted, and yanked versions are promoted only if they're
+specified. Packages are copied without modification, so their digests are preserved. Packages are linted by
+the lint mode of the target space, and findings are in the links. If any version exists in the target space
+or is rejected by linting, nothing is promoted.`,
+				PathParams: []definition.Param{
+					{
+						Name:     "space",
+						Type:     "string",
+						Doc:      "space name",
+						Required: true,
+					},
+					{
+						Name:     "chart",
+						Type:     "string",
+						Doc:      "chart name",
+						Required: true,
+					},
+				},
+				QueryParams: []definition.Param{
+					{
+						Name:     "target",
+						Type:     "string",
+						Doc:      "target space name",
+						Required: true,
+					},
+					{
+						Name:     "versions",
+						Type:     "string",
+						Doc:      "comma-separated version numbers to promote",
+						Required: false,
+					},
+					{
+						Name:     "history",
+						Type:     "boolean",
+						Doc:      "record promotions in the history of target chart",
+						Required: false,
+						Default:  false,
+					},
+				},
+				HeaderParams: []definition.Param{
+					{
+						Name:     "X-Registry-Uploader",
+						Type:     "string",
+						Doc:      "uploader of the version. It's supplied by the client and not verified",
+						Required: false,
+					},
+				},
+				StatusCode: []definition.StatusCode{
+					definition.StatusCode{Code: http.StatusCreated, Message: "Promote successfully",
+						Sample: []*models.ChartLink{
+							{
+								Space:   "targetSpace",
+								Chart:   "chartName",
+								Version: "1.0.0",
+								Link:    "/spaces/targetSpace/charts/chartName/versions/1.0.0",
+							},
+						}},
+					definition.StatusCode{Code: http.StatusUnprocessableEntity, Message: "Lint of the target space failed"},
+				},
+			},
+		},
+	},
+	{
+		Path: "/spaces/{space}/charts/{chart}/versions/{version}/promote",
+		Handlers: []definition.Handler{
+			{
+				HTTPMethod: http.MethodPost,
+				Handler:    definition.NewHandlerDecoration(definition.VerbCreate, handlers.PromoteVersion).Handle,
+				Doc:        "Promote a version of a chart to another space",
+				Note: `Copy the package of the version to the target space. The digest of package is preserved. The
+package is linted by the lint mode of the target space, and findings are in the link.`,
+				PathParams: []definition.Param{
+					{
+						Name:     "space",
+						Type:     "string",
+						Doc:      "space name",
+						Required: true,
+					},
+					{
+						Name:     "chart",
+						Type:     "string",
+						Doc:      "chart name",
+						Required: true,
+					},
+					{
+						Name:     "version",
+						Type:     "string",
+						Doc:      "version number or tag",
+						Required: true,
+					},
+				},
+				QueryParams: []definition.Param{
+					{
+						Name:     "target",
+						Type:     "string",
+						Doc:      "target space name",
+						Required: true,
+					},
+					{
+						Name:     "history",
+						Type:     "boolean",
+						Doc:      "record the promotion in the history of target chart",
+						Required: false,
+						Default:  false,
+					},
+				},
+				HeaderParams: []definition.Param{
+					{
+						Name:     "X-Registry-Uploader",
+						Type:     "string",
+						Doc:      "uploader of the version. It's supplied by the client and not verified",
+						Required: false,
+					},
+				},
+				StatusCode: []definition.StatusCode{
+					definition.StatusCode{Code: http.StatusCreated, Message: "Promote successfully",
+						Sample: &models.ChartLink{
+							Space:   "targetSpace",
+							Chart:   "chartName",
+							Version: "1.0.0",
+							Link:    "/spaces/targetSpace/charts/chartName/versions/1.0.0",
+						}},
+					definition.StatusCode{Code: http.StatusUnprocessableEntity, Message: "Lint of the target space failed"},
+				},
+			},
+		},
+	},
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package descriptor
+
+import (
+	"net/http"
+
+	"github.com/caicloud/helm-registry/pkg/api/definition"
+	"github.com/caicloud/helm-registry/pkg/api/v1/handlers"
+)
+
+func init() {
+	registerDescriptors(render)
+}
+
+// render descriptors
+var render = []definition.Descriptor{
+	{
+		Path: "/spaces/{space}/charts/{chart}/versions/{version}/render",
+		Handlers: []definition.Handler{
+			{
+				HTTPMethod: http.MethodPost,
+				Handler:    definition.NewHandlerDecoration(definition.VerbGet, handlers.RenderVersion).Handle,
+				Doc:        "Render templates of a version",
+				Note: `
+Render templates of a version and its subcharts as tiller does, without a cluster. Requirement
+conditions, tags and imported values are processed. The request body is an optional json or yaml
+config; below is a sample:
+{
+    "name":"release name",              // string, optional. Default is RELEASE-NAME
+    "namespace":"namespace",            // string, optional. Default is default
+    "values":{                          // object, optional. Values to override values.yaml
+        "image":{
+            "tag":"v2"
+        }
+    },
+    "kubeVersion":"v1.7.0",             // string, optional. Default is v1.7.0
+    "apiVersions":["apps/v1beta1"],     // array, optional. v1 is always supported
+    "isUpgrade":false                   // boolean, optional
+}
+By default the response has non-empty manifests sorted by paths of templates, and rendered
+NOTES.txt of the chart:
+{
+    "manifests":[
+        {
+            "path":"chart/templates/deployment.yaml",
+            "content":"..."
+        }
+    ],
+    "notes":"..."
+}
+If format is yaml, the response is a multi-document yaml. Each document starts with a comment
+of its template, e.g. # Source: chart/templates/deployment.yaml. Errors in templates, e.g. a
+required value is missing, are responded with 422. Rendering fails with 422 too if it takes more
+than 10 seconds or templates write more than 16MiB.`,
+				PathParams: []definition.Param{
+					{
+						Name:     "space",
+						Type:     "string",
+						Doc:      "space name",
+						Required: true,
+					},
+					{
+						Name:     "chart",
+						Type:     "string",
+						Doc:      "chart name",
+						Required: true,
+					},
+					{
+						Name:     "version",
+						Type:     "string",
+						Doc:      "version number or tag",
+						Required: true,
+					},
+				},
+				QueryParams: []definition.Param{
+					{
+						Name:     "format",
+						Type:     "string",
+						Doc:      "files or yaml",
+						Required: false,
+						Default:  "files",
+					},
+				},
+				StatusCode: []definition.StatusCode{
+					definition.StatusCode{Code: http.StatusOK, Message: "Success and respond with manifests"},
+					definition.StatusCode{Code: http.StatusBadRequest, Message: "The config or format is invalid"},
+					definition.StatusCode{Code: http.StatusNotFound, Message: "The version is not found"},
+					definition.StatusCode{Code: http.StatusUnprocessableEntity, Message: "Templates can't be rendered"},
+				},
+			},
+		},
+	},
+}
@@ -0,0 +1,273 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package descriptor
+
+import (
+	"net/http"
+
+	"github.com/caicloud/helm-registry/pkg/api/definition"
+	"github.com/caicloud/helm-registry/pkg/api/models"
+	"github.com/caicloud/helm-registry/pkg/api/v1/handlers"
+	"github.com/caicloud/helm-registry/pkg/api/v1/types"
+	"github.com/caicloud/helm-registry/pkg/common"
+	"github.com/caicloud/helm-registry/pkg/diff"
+	"github.com/caicloud/helm-registry/pkg/storage"
+)
+
+func init() {
+	registerDescriptors(revisions)
+}
+
+// revisions descriptors
+var revisions = []definition.Descriptor{
+	{
+		Path: "/spaces/{space}/charts/{chart}/versions/{version}/revisions",
+		Handlers: []definition.Handler{
+			{
+				HTTPMethod: http.MethodGet,
+				Handler:    definition.NewHandlerDecoration(definition.VerbList, handlers.ListRevisions).Handle,
+				Doc:        "List revisions of a version",
+				Note: `A new revision is created every time the chart package of the version is stored, e.g. by
+updating metadata or values. Revisions are in ascending order and the last one is the current revision.`,
+				PathParams: []definition.Param{
+					{
+						Name:     "space",
+						Type:     "string",
+						Doc:      "space name",
+						Required: true,
+					},
+					{
+						Name:     "chart",
+						Type:     "string",
+						Doc:      "chart name",
+						Required: true,
+					},
+					{
+						Name:     "version",
+						Type:     "string",
+						Doc:      "version number or tag",
+						Required: true,
+					},
+				},
+				QueryParams: []definition.Param{
+					{
+						Name:     "start",
+						Type:     "number",
+						Doc:      "Query start index",
+						Required: false,
+						Default:  0,
+					},
+					{
+						Name:     "limit",
+						Type:     "number",
+						Doc:      "Specify the number of records to return",
+						Required: false,
+						Default:  common.DefaultPagingLimit,
+					},
+					{
+						Name:     "continue",
+						Type:     "string",
+						Doc:      "continuation token from metadata of the previous page. start is ignored if it's specified",
+						Required: false,
+					},
+				},
+				StatusCode: []definition.StatusCode{
+					definition.StatusCode{Code: http.StatusOK, Message: "Success and respond with a array of revisions",
+						Sample: &models.ListResponse{
+							Metadata: models.Metadata{
+								Total:       2,
+								ItemsLength: 2,
+							},
+							Items: []*storage.Revision{
+								{
+									Revision: 1,
+									Author:   "admin",
+									Size:     1024,
+									Digest:   "sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+								},
+								{
+									Revision: 2,
+									Author:   "admin",
+									Size:     1025,
+									Digest:   "sha256:5feceb66ffc86f38d952786c6d696c79c2dbc239dd4e91b46729d73a27fb57e9",
+									Current:  true,
+								},
+							},
+						}},
+				},
+			},
+		},
+	},
+	{
+		Path: "/spaces/{space}/charts/{chart}/versions/{version}/revisions/{revision}",
+		Handlers: []definition.Handler{
+			{
+				HTTPMethod: http.MethodGet,
+				Handler:    definition.NewHandlerDecoration(definition.VerbGet, handlers.DownloadRevision).Handle,
+				Doc:        "Download the chart package of a revision",
+				Note:       "The response has an ETag which is the digest of the archive file.",
+				PathParams: []definition.Param{
+					{
+						Name:     "space",
+						Type:     "string",
+						Doc:      "space name",
+						Required: true,
+					},
+					{
+						Name:     "chart",
+						Type:     "string",
+						Doc:      "chart name",
+						Required: true,
+					},
+					{
+						Name:     "version",
+						Type:     "string",
+						Doc:      "version number or tag",
+						Required: true,
+					},
+					{
+						Name:     "revision",
+						Type:     "number",
+						Doc:      "revision number",
+						Required: true,
+					},
+				},
+				HeaderParams: []definition.Param{
+					{
+						Name:     "If-None-Match",
+						Type:     "string",
+						Doc:      "entity tags of cached responses",
+						Required: false,
+					},
+				},
+				StatusCode: []definition.StatusCode{
+					definition.StatusCode{Code: http.StatusOK, Message: "Download with an archive file of chart"},
+					definition.StatusCode{Code: http.StatusNotModified, Message: "Not modified since the cached response"},
+				},
+			},
+		},
+	},
+	{
+		Path: "/spaces/{space}/charts/{chart}/versions/{version}/revisions/{revision}/diff",
+		Handlers: []definition.Handler{
+			{
+				HTTPMethod: http.MethodGet,
+				Handler:    definition.NewHandlerDecoration(definition.VerbGet, handlers.DiffRevisions).Handle,
+				Doc:        "Compare a revision with another revision",
+				Note: `Metadata and coalesced values are compared by keys. Templates, files and subcharts are compared
+by unified diffs.`,
+				PathParams: []definition.Param{
+					{
+						Name:     "space",
+						Type:     "string",
+						Doc:      "space name",
+						Required: true,
+					},
+					{
+						Name:     "chart",
+						Type:     "string",
+						Doc:      "chart name",
+						Required: true,
+					},
+					{
+						Name:     "version",
+						Type:     "string",
+						Doc:      "version number or tag",
+						Required: true,
+					},
+					{
+						Name:     "revision",
+						Type:     "number",
+						Doc:      "revision number",
+						Required: true,
+					},
+				},
+				QueryParams: []definition.Param{
+					{
+						Name:     "to",
+						Type:     "number",
+						Doc:      "revision to compare with. Default is the current revision",
+						Required: false,
+					},
+				},
+				StatusCode: []definition.StatusCode{
+					definition.StatusCode{Code: http.StatusOK, Message: "Success and respond with the difference",
+						Sample: &types.ChartDiff{
+							Metadata: []types.ValueChange{
+								{Path: "description", Type: diff.Changed, From: "old", To: "new"},
+							},
+							Values: []types.ValueChange{
+								{Path: "image.tag", Type: diff.Changed, From: "1.0", To: "1.1"},
+							},
+							Files: []types.FileChange{},
+						}},
+				},
+			},
+		},
+	},
+	{
+		Path: "/spaces/{space}/charts/{chart}/versions/{version}/revisions/{revision}/rollback",
+		Handlers: []definition.Handler{
+			{
+				HTTPMethod: http.MethodPost,
+				Handler:    definition.NewHandlerDecoration(definition.VerbCreate, handlers.RollbackRevision).Handle,
+				Doc:        "Roll a version back to a revision",
+				Note: `The chart package of the revision is stored as a new revision, and a Rollback event is recorded
+in the history of the chart.`,
+				PathParams: []definition.Param{
+					{
+						Name:     "space",
+						Type:     "string",
+						Doc:      "space name",
+						Required: true,
+					},
+					{
+						Name:     "chart",
+						Type:     "string",
+						Doc:      "chart name",
+						Required: true,
+					},
+					{
+						Name:     "version",
+						Type:     "string",
+						Doc:      "version number or tag",
+						Required: true,
+					},
+					{
+						Name:     "revision",
+						Type:     "number",
+						Doc:      "revision number",
+						Required: true,
+					},
+				},
+				HeaderParams: []definition.Param{
+					{
+						Name:     "X-Registry-Uploader",
+						Type:     "string",
+						Doc:      "author of the new revision. It's supplied by the client and not verified",
+						Required: false,
+					},
+					{
+						Name:     "If-Match",
+						Type:     "string",
+						Doc:      "entity tag which must match the digest of the current chart package",
+						Required: false,
+					},
+				},
+				StatusCode: []definition.StatusCode{
+					definition.StatusCode{Code: http.StatusCreated, Message: "Success and respond with the new revision",
+						Sample: &storage.Revision{
+							Revision: 3,
+							Author:   "admin",
+							Size:     1024,
+							Digest:   "sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+							Current:  true,
+						}},
+					definition.StatusCode{Code: http.StatusPreconditionFailed, Message: "If-Match does not match the current entity tag"},
+				},
+			},
+		},
+	},
+}
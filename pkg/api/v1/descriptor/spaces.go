@@ -11,6 +11,7 @@ import (
 	"github.com/caicloud/helm-registry/pkg/api/models"
 	"github.com/caicloud/helm-registry/pkg/api/v1/handlers"
 	"github.com/caicloud/helm-registry/pkg/common"
+	"github.com/caicloud/helm-registry/pkg/storage"
 )
 
 func init() {
@@ -26,6 +27,8 @@ var spaces = []definition.Descriptor{
 				HTTPMethod: http.MethodGet,
 				Handler:    definition.NewHandlerDecoration(definition.VerbList, handlers.ListSpaces).Handle,
 				Doc:        "List all spaces in the registry",
+				Note: `If detail is true, items are summaries of spaces. A summary has name, description, owners,
+labels, visibility and the number of charts.`,
 				QueryParams: []definition.Param{
 					{
 						Name:     "start",
@@ -41,6 +44,31 @@ var spaces = []definition.Descriptor{
 						Required: false,
 						Default:  common.DefaultPagingLimit,
 					},
+					{
+						Name:     "continue",
+						Type:     "string",
+						Doc:      "continuation token from metadata of the previous page. start is ignored if it's specified",
+						Required: false,
+					},
+					{
+						Name:     "detail",
+						Type:     "boolean",
+						Doc:      "respond with summaries instead of names",
+						Required: false,
+						Default:  false,
+					},
+					{
+						Name:     "label",
+						Type:     "string",
+						Doc:      "comma-separated labels which spaces must have. e.g. team=dev,env",
+						Required: false,
+					},
+					{
+						Name:     "visibility",
+						Type:     "string",
+						Doc:      "visibility of spaces, public or private",
+						Required: false,
+					},
 				},
 				StatusCode: []definition.StatusCode{
 					definition.StatusCode{Code: http.StatusOK, Message: "Success and respond with a array of space names",
@@ -59,6 +87,9 @@ var spaces = []definition.Descriptor{
 				HTTPMethod: http.MethodPost,
 				Handler:    definition.NewHandlerDecoration(definition.VerbCreate, handlers.CreateSpace).Handle,
 				Doc:        "Create a space",
+				Note: `Properties of the space can be specified by a json body with content type application/json.
+If properties are not specified, the space is public, charts are linted in warn mode and the space
+has no description, owners and labels.`,
 				QueryParams: []definition.Param{
 					{
 						Name:     "space",
@@ -80,10 +111,68 @@ var spaces = []definition.Descriptor{
 	{
 		Path: "/spaces/{space}",
 		Handlers: []definition.Handler{
+			{
+				HTTPMethod: http.MethodGet,
+				Handler:    definition.NewHandlerDecoration(definition.VerbGet, handlers.GetSpace).Handle,
+				Doc:        "Get a space and its properties",
+				PathParams: []definition.Param{
+					{
+						Name:     "space",
+						Type:     "string",
+						Doc:      "space name",
+						Required: true,
+					},
+				},
+				StatusCode: []definition.StatusCode{
+					definition.StatusCode{Code: http.StatusOK, Message: "Success and respond with the space",
+						Sample: &models.Space{
+							Name: "spaceName",
+							SpaceProperties: storage.SpaceProperties{
+								Description: "charts of team dev",
+								Owners:      []string{"dev"},
+								Labels:      map[string]string{"team": "dev"},
+								Visibility:  storage.VisibilityPublic,
+								Lint:        storage.LintWarn,
+							},
+						}},
+				},
+			},
+			{
+				HTTPMethod: http.MethodPut,
+				Handler:    definition.NewHandlerDecoration(definition.VerbUpdate, handlers.UpdateSpace).Handle,
+				Doc:        "Update properties of a space",
+				Note: `Replace all properties of the space by a json body. The body has fields: description, owners,
+labels, visibility and lint. Visibility should be public or private, default to public. Lint is the
+lint mode of uploaded and orchestrated charts, and should be off, warn or error, default to warn.
+Charts with lint errors are rejected only if lint is error.`,
+				PathParams: []definition.Param{
+					{
+						Name:     "space",
+						Type:     "string",
+						Doc:      "space name",
+						Required: true,
+					},
+				},
+				StatusCode: []definition.StatusCode{
+					definition.StatusCode{Code: http.StatusOK, Message: "Update successfully",
+						Sample: &models.Space{
+							Name: "spaceName",
+							SpaceProperties: storage.SpaceProperties{
+								Description: "charts of team dev",
+								Owners:      []string{"dev"},
+								Labels:      map[string]string{"team": "dev"},
+								Visibility:  storage.VisibilityPublic,
+								Lint:        storage.LintWarn,
+							},
+						}},
+				},
+			},
 			{
 				HTTPMethod: http.MethodDelete,
 				Handler:    definition.NewHandlerDecoration(definition.VerbDelete, handlers.DeleteSpace).Handle,
 				Doc:        "Delete space",
+				Note: `If any version in the space is referenced by orchestrated versions or requirements in other spaces,
+including yanked ones, the space is not deleted unless force is true, and the error lists the referencing versions.`,
 				PathParams: []definition.Param{
 					{
 						Name:     "space",
@@ -92,8 +181,64 @@ var spaces = []definition.Descriptor{
 						Required: true,
 					},
 				},
+				QueryParams: []definition.Param{
+					{
+						Name:     "force",
+						Type:     "boolean",
+						Doc:      "delete the space even if its versions are referenced",
+						Required: false,
+						Default:  false,
+					},
+				},
 				StatusCode: []definition.StatusCode{
 					definition.StatusCode{Code: http.StatusNoContent, Message: "Delete successfully"},
+					definition.StatusCode{Code: http.StatusConflict, Message: "Versions in the space are referenced by orchestrated versions or requirements"},
+				},
+			},
+		},
+	},
+	{
+		Path: "/spaces/{space}/rename",
+		Handlers: []definition.Handler{
+			{
+				HTTPMethod: http.MethodPost,
+				Handler:    definition.NewHandlerDecoration(definition.VerbUpdate, handlers.RenameSpace).Handle,
+				Doc:        "Rename a space",
+				Note: `All charts in the space are moved with the space. The space with the new name must not exist.
+If any version in the space is referenced by orchestrated versions in other spaces, including yanked ones, the
+space is not renamed unless force is true, and the error lists the referencing versions. If it's renamed, the
+references are moved with it. Requirements in packages can't be changed, so a space with versions referenced by
+requirements is never renamed. A Move event is recorded in the history of every chart.`,
+				PathParams: []definition.Param{
+					{
+						Name:     "space",
+						Type:     "string",
+						Doc:      "space name",
+						Required: true,
+					},
+				},
+				QueryParams: []definition.Param{
+					{
+						Name:     "name",
+						Type:     "string",
+						Doc:      "new space name",
+						Required: true,
+					},
+					{
+						Name:     "force",
+						Type:     "boolean",
+						Doc:      "rename the space even if its versions are referenced",
+						Required: false,
+						Default:  false,
+					},
+				},
+				StatusCode: []definition.StatusCode{
+					definition.StatusCode{Code: http.StatusOK, Message: "Rename successfully",
+						Sample: &models.Link{
+							Name: "newSpaceName",
+							Link: "/spaces/newSpaceName",
+						}},
+					definition.StatusCode{Code: http.StatusConflict, Message: "Versions in the space are referenced by orchestrated versions or requirements"},
 				},
 			},
 		},
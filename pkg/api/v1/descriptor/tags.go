@@ -0,0 +1,193 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package descriptor
+
+import (
+	"net/http"
+
+	"github.com/caicloud/helm-registry/pkg/api/definition"
+	"github.com/caicloud/helm-registry/pkg/api/models"
+	"github.com/caicloud/helm-registry/pkg/api/v1/handlers"
+	"github.com/caicloud/helm-registry/pkg/common"
+	"github.com/caicloud/helm-registry/pkg/storage"
+)
+
+func init() {
+	registerDescriptors(tags)
+}
+
+// tags descriptors
+var tags = []definition.Descriptor{
+	{
+		Path: "/spaces/{space}/charts/{chart}/tags",
+		Handlers: []definition.Handler{
+			{
+				HTTPMethod: http.MethodGet,
+				Handler:    definition.NewHandlerDecoration(definition.VerbList, handlers.ListTags).Handle,
+				Doc:        "List tags of a chart",
+				PathParams: []definition.Param{
+					{
+						Name:     "space",
+						Type:     "string",
+						Doc:      "space name",
+						Required: true,
+					},
+					{
+						Name:     "chart",
+						Type:     "string",
+						Doc:      "chart name",
+						Required: true,
+					},
+				},
+				QueryParams: []definition.Param{
+					{
+						Name:     "start",
+						Type:     "number",
+						Doc:      "Query start index",
+						Required: false,
+						Default:  0,
+					},
+					{
+						Name:     "limit",
+						Type:     "number",
+						Doc:      "Specify the number of records to return",
+						Required: false,
+						Default:  common.DefaultPagingLimit,
+					},
+					{
+						Name:     "continue",
+						Type:     "string",
+						Doc:      "continuation token from metadata of the previous page. start is ignored if it's specified",
+						Required: false,
+					},
+				},
+				StatusCode: []definition.StatusCode{
+					definition.StatusCode{Code: http.StatusOK, Message: "Success and respond with an array of tags",
+						Sample: &models.ListResponse{
+							Metadata: models.Metadata{
+								Total:       10,
+								ItemsLength: 1,
+							},
+							Items: []*storage.Tag{
+								{
+									Name:    "stable",
+									Version: "1.0.0",
+								},
+							},
+						}},
+				},
+			},
+		},
+	},
+	{
+		Path: "/spaces/{space}/charts/{chart}/tags/{tag}",
+		Handlers: []definition.Handler{
+			{
+				HTTPMethod: http.MethodGet,
+				Handler:    definition.NewHandlerDecoration(definition.VerbGet, handlers.GetTag).Handle,
+				Doc:        "Get a tag of a chart",
+				PathParams: []definition.Param{
+					{
+						Name:     "space",
+						Type:     "string",
+						Doc:      "space name",
+						Required: true,
+					},
+					{
+						Name:     "chart",
+						Type:     "string",
+						Doc:      "chart name",
+						Required: true,
+					},
+					{
+						Name:     "tag",
+						Type:     "string",
+						Doc:      "tag name",
+						Required: true,
+					},
+				},
+				StatusCode: []definition.StatusCode{
+					definition.StatusCode{Code: http.StatusOK, Message: "Success and respond with the tag",
+						Sample: &storage.Tag{
+							Name:    "stable",
+							Version: "1.0.0",
+						}},
+				},
+			},
+			{
+				HTTPMethod: http.MethodPut,
+				Handler:    definition.NewHandlerDecoration(definition.VerbUpdate, handlers.SetTag).Handle,
+				Doc:        "Create a tag or move a tag to another version",
+				Note: `A tag is a mutable name which points to a version of the chart. A tag can be used anywhere a
+version number is accepted. Tag names have the same format as chart names. Moving a tag is atomic and
+recorded in the history of the chart.`,
+				PathParams: []definition.Param{
+					{
+						Name:     "space",
+						Type:     "string",
+						Doc:      "space name",
+						Required: true,
+					},
+					{
+						Name:     "chart",
+						Type:     "string",
+						Doc:      "chart name",
+						Required: true,
+					},
+					{
+						Name:     "tag",
+						Type:     "string",
+						Doc:      "tag name",
+						Required: true,
+					},
+				},
+				QueryParams: []definition.Param{
+					{
+						Name:     "version",
+						Type:     "string",
+						Doc:      "version number or tag which the tag points to",
+						Required: true,
+					},
+				},
+				StatusCode: []definition.StatusCode{
+					definition.StatusCode{Code: http.StatusOK, Message: "Update successfully",
+						Sample: &storage.Tag{
+							Name:    "stable",
+							Version: "1.0.0",
+						}},
+				},
+			},
+			{
+				HTTPMethod: http.MethodDelete,
+				Handler:    definition.NewHandlerDecoration(definition.VerbDelete, handlers.DeleteTag).Handle,
+				Doc:        "Delete a tag of a chart",
+				Note:       `Only the tag is deleted. The version which the tag points to is kept.`,
+				PathParams: []definition.Param{
+					{
+						Name:     "space",
+						Type:     "string",
+						Doc:      "space name",
+						Required: true,
+					},
+					{
+						Name:     "chart",
+						Type:     "string",
+						Doc:      "chart name",
+						Required: true,
+					},
+					{
+						Name:     "tag",
+						Type:     "string",
+						Doc:      "tag name",
+						Required: true,
+					},
+				},
+				StatusCode: []definition.StatusCode{
+					definition.StatusCode{Code: http.StatusNoContent, Message: "Delete successfully"},
+				},
+			},
+		},
+	},
+}
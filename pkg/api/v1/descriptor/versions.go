@@ -10,7 +10,9 @@ import (
 	"github.com/caicloud/helm-registry/pkg/api/definition"
 	"github.com/caicloud/helm-registry/pkg/api/models"
 	"github.com/caicloud/helm-registry/pkg/api/v1/handlers"
+	"github.com/caicloud/helm-registry/pkg/api/v1/types"
 	"github.com/caicloud/helm-registry/pkg/common"
+	"github.com/caicloud/helm-registry/pkg/storage"
 )
 
 func init() {
@@ -26,6 +28,8 @@ var versions = []definition.Descriptor{
 				HTTPMethod: http.MethodGet,
 				Handler:    definition.NewHandlerDecoration(definition.VerbList, handlers.ListVersions).Handle,
 				Doc:        "List all versions in a chart",
+				Note: `Yanked versions are excluded unless yanked is true. If detail is true, items are summaries
+of versions. A summary has version number, app version, description, state and record.`,
 				PathParams: []definition.Param{
 					{
 						Name:     "space",
@@ -55,6 +59,33 @@ var versions = []definition.Descriptor{
 						Required: false,
 						Default:  common.DefaultPagingLimit,
 					},
+					{
+						Name:     "continue",
+						Type:     "string",
+						Doc:      "continuation token from metadata of the previous page. start is ignored if it's specified",
+						Required: false,
+					},
+					{
+						Name:     "detail",
+						Type:     "boolean",
+						Doc:      "respond with summaries instead of names",
+						Required: false,
+						Default:  false,
+					},
+					{
+						Name:     "yanked",
+						Type:     "boolean",
+						Doc:      "include yanked versions",
+						Required: false,
+						Default:  false,
+					},
+					{
+						Name:     "sort",
+						Type:     "string",
+						Doc:      "sort by version, created or updated. Prefix '-' means descending order",
+						Required: false,
+						Default:  "version",
+					},
 				},
 				StatusCode: []definition.StatusCode{
 					definition.StatusCode{Code: http.StatusOK, Message: "Success and respond with a array of version numbers",
@@ -78,6 +109,8 @@ var versions = []definition.Descriptor{
 				HTTPMethod: http.MethodGet,
 				Handler:    definition.NewHandlerDecoration(definition.VerbGet, handlers.DownloadVersion).Handle,
 				Doc:        "Download a version of a chart",
+				Note: `If the version is deprecated, the response has a Warning header. The response has an ETag which
+is the digest of the archive file, and a Last-Modified header unless the version is specified by a tag.`,
 				PathParams: []definition.Param{
 					{
 						Name:     "space",
@@ -94,18 +127,35 @@ var versions = []definition.Descriptor{
 					{
 						Name:     "version",
 						Type:     "string",
-						Doc:      "version number",
+						Doc:      "version number or tag",
 						Required: true,
 					},
 				},
+				HeaderParams: []definition.Param{
+					{
+						Name:     "If-None-Match",
+						Type:     "string",
+						Doc:      "entity tags of cached responses",
+						Required: false,
+					},
+					{
+						Name:     "If-Modified-Since",
+						Type:     "string",
+						Doc:      "last modified time of cached response. Ignored if If-None-Match is specified",
+						Required: false,
+					},
+				},
 				StatusCode: []definition.StatusCode{
 					definition.StatusCode{Code: http.StatusOK, Message: "Download with an archive file of chart"},
+					definition.StatusCode{Code: http.StatusNotModified, Message: "Not modified since the cached response"},
 				},
 			},
 			{
 				HTTPMethod: http.MethodPut,
 				Handler:    definition.NewHandlerDecoration(definition.VerbUpdate, handlers.UpdateVersion).Handle,
 				Doc:        "Update a version of a chart",
+				Note: `The chart package is linted by the lint mode of the space as uploading does. Findings are in
+the lint field of the response.`,
 				PathParams: []definition.Param{
 					{
 						Name:     "space",
@@ -122,7 +172,7 @@ var versions = []definition.Descriptor{
 					{
 						Name:     "version",
 						Type:     "string",
-						Doc:      "version number",
+						Doc:      "version number or tag",
 						Required: true,
 					},
 				},
@@ -134,6 +184,20 @@ var versions = []definition.Descriptor{
 						Required: true,
 					},
 				},
+				HeaderParams: []definition.Param{
+					{
+						Name:     "X-Registry-Uploader",
+						Type:     "string",
+						Doc:      "uploader of the version. It's supplied by the client and not verified",
+						Required: false,
+					},
+					{
+						Name:     "If-Match",
+						Type:     "string",
+						Doc:      "entity tag which must match the current one",
+						Required: false,
+					},
+				},
 				StatusCode: []definition.StatusCode{
 					definition.StatusCode{Code: http.StatusOK, Message: "Update successfully",
 						Sample: &models.ChartLink{
@@ -142,12 +206,17 @@ var versions = []definition.Descriptor{
 							Version: "1.0.0",
 							Link:    "/spaces/spaceName/charts/chartName/versions/1.0.0",
 						}},
+					definition.StatusCode{Code: http.StatusPreconditionFailed, Message: "If-Match does not match the current entity tag"},
+					definition.StatusCode{Code: http.StatusUnprocessableEntity, Message: "The chart package has lint errors or invalid values"},
 				},
 			},
 			{
 				HTTPMethod: http.MethodDelete,
 				Handler:    definition.NewHandlerDecoration(definition.VerbDelete, handlers.DeleteVersion).Handle,
-				Doc:        "Delete a chart and all of its versions",
+				Doc:        "Delete a version",
+				Note: `If the version is referenced by orchestrated versions or requirements of any chart, including yanked
+ones, it's not deleted unless force is true, and the error lists the referencing versions. Rebuilding a version
+which references a deleted version fails.`,
 				PathParams: []definition.Param{
 					{
 						Name:     "space",
@@ -164,12 +233,258 @@ var versions = []definition.Descriptor{
 					{
 						Name:     "version",
 						Type:     "string",
-						Doc:      "version number",
+						Doc:      "version number or tag",
 						Required: true,
 					},
 				},
+				QueryParams: []definition.Param{
+					{
+						Name:     "force",
+						Type:     "boolean",
+						Doc:      "delete the version even if it is referenced",
+						Required: false,
+						Default:  false,
+					},
+				},
 				StatusCode: []definition.StatusCode{
 					definition.StatusCode{Code: http.StatusNoContent, Message: "Delete successfully"},
+					definition.StatusCode{Code: http.StatusConflict, Message: "The version is referenced by orchestrated versions or requirements"},
+				},
+			},
+		},
+	},
+	{
+		Path: "/spaces/{space}/charts/{chart}/versions/{version}/state",
+		Handlers: []definition.Handler{
+			{
+				HTTPMethod: http.MethodGet,
+				Handler:    definition.NewHandlerDecoration(definition.VerbGet, handlers.GetVersionState).Handle,
+				Doc:        "Get the state of a version",
+				PathParams: []definition.Param{
+					{
+						Name:     "space",
+						Type:     "string",
+						Doc:      "space name",
+						Required: true,
+					},
+					{
+						Name:     "chart",
+						Type:     "string",
+						Doc:      "chart name",
+						Required: true,
+					},
+					{
+						Name:     "version",
+						Type:     "string",
+						Doc:      "version number or tag",
+						Required: true,
+					},
+				},
+				StatusCode: []definition.StatusCode{
+					definition.StatusCode{Code: http.StatusOK, Message: "Success and respond with the state",
+						Sample: &storage.VersionState{
+							State:  storage.StateDeprecated,
+							Reason: "use 1.0.1 instead",
+						}},
+				},
+			},
+			{
+				HTTPMethod: http.MethodPut,
+				Handler:    definition.NewHandlerDecoration(definition.VerbUpdate, handlers.SetVersionState).Handle,
+				Doc:        "Set the state of a version",
+				Note: `A version is active by default. A deprecated version can be used normally, but responses of
+downloading, metadata and values have a Warning header. A yanked version can only be fetched by its exact
+version number or tags, and is excluded from latest metadata and listings by default.`,
+				PathParams: []definition.Param{
+					{
+						Name:     "space",
+						Type:     "string",
+						Doc:      "space name",
+						Required: true,
+					},
+					{
+						Name:     "chart",
+						Type:     "string",
+						Doc:      "chart name",
+						Required: true,
+					},
+					{
+						Name:     "version",
+						Type:     "string",
+						Doc:      "version number or tag",
+						Required: true,
+					},
+				},
+				QueryParams: []definition.Param{
+					{
+						Name:     "state",
+						Type:     "string",
+						Doc:      "active, deprecated or yanked",
+						Required: true,
+					},
+					{
+						Name:     "reason",
+						Type:     "string",
+						Doc:      "reason of the state",
+						Required: false,
+					},
+				},
+				StatusCode: []definition.StatusCode{
+					definition.StatusCode{Code: http.StatusOK, Message: "Update successfully",
+						Sample: &storage.VersionState{
+							State:  storage.StateDeprecated,
+							Reason: "use 1.0.1 instead",
+						}},
+				},
+			},
+		},
+	},
+	{
+		Path: "/spaces/{space}/charts/{chart}/versions/{version}/record",
+		Handlers: []definition.Handler{
+			{
+				HTTPMethod: http.MethodGet,
+				Handler:    definition.NewHandlerDecoration(definition.VerbGet, handlers.GetVersionRecord).Handle,
+				Doc:        "Get the record of a version",
+				Note: `The record is maintained by the registry. Created time, uploader and source are kept when the
+version is updated. The registry doesn't authenticate users, so uploaders and updaters are supplied by clients
+and not verified.`,
+				PathParams: []definition.Param{
+					{
+						Name:     "space",
+						Type:     "string",
+						Doc:      "space name",
+						Required: true,
+					},
+					{
+						Name:     "chart",
+						Type:     "string",
+						Doc:      "chart name",
+						Required: true,
+					},
+					{
+						Name:     "version",
+						Type:     "string",
+						Doc:      "version number or tag",
+						Required: true,
+					},
+				},
+				StatusCode: []definition.StatusCode{
+					definition.StatusCode{Code: http.StatusOK, Message: "Success and respond with the record",
+						Sample: &storage.Record{
+							Uploader: "admin",
+							Size:     1024,
+							Digest:   "sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+							Source:   storage.SourceUpload,
+						}},
+				},
+			},
+		},
+	},
+	{
+		Path: "/spaces/{space}/charts/{chart}/versions/{version}/orchestration",
+		Handlers: []definition.Handler{
+			{
+				HTTPMethod: http.MethodGet,
+				Handler:    definition.NewHandlerDecoration(definition.VerbGet, handlers.GetOrchestrationConfig).Handle,
+				Doc:        "Get the orchestration config which created a version",
+				Note: `The config is stored when the version is created by orchestration, and later updates of the
+version don't change it. It can be posted to create a chart again.`,
+				PathParams: []definition.Param{
+					{
+						Name:     "space",
+						Type:     "string",
+						Doc:      "space name",
+						Required: true,
+					},
+					{
+						Name:     "chart",
+						Type:     "string",
+						Doc:      "chart name",
+						Required: true,
+					},
+					{
+						Name:     "version",
+						Type:     "string",
+						Doc:      "version number or tag",
+						Required: true,
+					},
+				},
+				StatusCode: []definition.StatusCode{
+					definition.StatusCode{Code: http.StatusOK, Message: "Success and respond with the orchestration config",
+						Sample: &types.OrchestrationConfig{
+							Save: types.Save{Chart: "app", Version: "1.0.0", Desc: "an orchestrated chart"},
+							Configs: map[string]interface{}{
+								"package": map[string]interface{}{
+									"independent": true,
+									"space":       "library",
+									"chart":       "web",
+									"version":     "1.2.0",
+								},
+								"_config": map[string]interface{}{"replicas": 2},
+							},
+						}},
+					definition.StatusCode{Code: http.StatusNotFound, Message: "The version was not created by orchestration"},
+				},
+			},
+		},
+	},
+	{
+		Path: "/spaces/{space}/charts/{chart}/versions/{version}/rebuild",
+		Handlers: []definition.Handler{
+			{
+				HTTPMethod: http.MethodPost,
+				Handler:    definition.NewHandlerDecoration(definition.VerbCreate, handlers.RebuildVersion).Handle,
+				Doc:        "Rebuild an orchestrated version into a new version",
+				Note: `
+Create a new version in the same chart by the orchestration config of the version, as creating a
+chart by the config does. The new version stores the upgraded config. Independent packages are
+pinned to the packages which the version was built from, even if their tags were moved, and the
+request fails with the missing package if one was deleted. Versions of independent packages can
+be upgraded by paths of charts in the config, and the path of root chart is empty. If latest is
+true, other independent packages are upgraded to their latest versions which are not yanked.
+Request body:
+{
+    "version": "1.1.0",                 // string, required. Number of the new version
+    "description": "description",       // string, optional. The original one is kept by default
+    "upgrades": {                       // object, optional
+        "": "1.3.0",                    // version of the root package
+        "chartB": "2.0.0"               // version of the package of chartB
+    },
+    "latest": false                     // boolean, optional
+}`,
+				PathParams: []definition.Param{
+					{
+						Name:     "space",
+						Type:     "string",
+						Doc:      "space name",
+						Required: true,
+					},
+					{
+						Name:     "chart",
+						Type:     "string",
+						Doc:      "chart name",
+						Required: true,
+					},
+					{
+						Name:     "version",
+						Type:     "string",
+						Doc:      "version number or tag",
+						Required: true,
+					},
+				},
+				StatusCode: []definition.StatusCode{
+					definition.StatusCode{Code: http.StatusCreated, Message: "Success and respond with a link of the new version",
+						Sample: &models.ChartLink{
+							Space:   "library",
+							Chart:   "app",
+							Version: "1.1.0",
+							Link:    "/api/v1/spaces/library/charts/app/versions/1.1.0",
+						}},
+					definition.StatusCode{Code: http.StatusBadRequest, Message: "The rebuild config is invalid"},
+					definition.StatusCode{Code: http.StatusNotFound, Message: "The version was not created by orchestration, or a referenced package does not exist"},
+					definition.StatusCode{Code: http.StatusConflict, Message: "The new version exists"},
+					definition.StatusCode{Code: http.StatusUnprocessableEntity, Message: "The new chart has lint errors or invalid values"},
 				},
 			},
 		},
@@ -5,19 +5,27 @@ Copyright 2017 caicloud authors. All rights reserved.
 package handlers
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/caicloud/helm-registry/pkg/api/models"
+	"github.com/caicloud/helm-registry/pkg/api/v1/types"
 	"github.com/caicloud/helm-registry/pkg/common"
 	"github.com/caicloud/helm-registry/pkg/errors"
 	"github.com/caicloud/helm-registry/pkg/orchestration"
+	"github.com/caicloud/helm-registry/pkg/storage"
 	"gopkg.in/yaml.v2"
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
 )
 
-// ListCharts lists charts in specified space
-func ListCharts(ctx context.Context) (int, []string, error) {
+// ListCharts lists charts in specified space. If detail is true, returns summaries
+// of charts instead of names.
+func ListCharts(ctx context.Context) (int, interface{}, error) {
 	spaceName, err := getSpaceName(ctx)
 	if err != nil {
 		return 0, nil, err
@@ -26,12 +34,26 @@ func ListCharts(ctx context.Context) (int, []string, error) {
 	if err != nil {
 		return 0, nil, err
 	}
+	detail, err := isDetailRequest(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	if detail {
+		summaries, err := space.ChartSummaries(ctx)
+		if err != nil {
+			return 0, nil, err
+		}
+		return pageItems(ctx, summaries, nameOrder(func(i int) string {
+			return summaries[i].Name
+		}))
+	}
 	return listStrings(ctx, func() ([]string, error) {
 		return space.List(ctx)
 	})
 }
 
-// DeleteChart deletes specified chart
+// DeleteChart deletes specified chart. If any version of the chart is referenced by
+// orchestrated versions or requirements in other charts, it's deleted only if force is true.
 func DeleteChart(ctx context.Context) error {
 	spaceName, chartName, err := getSpaceAndChartName(ctx)
 	if err != nil {
@@ -41,7 +63,96 @@ func DeleteChart(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	return space.Delete(ctx, chartName)
+	force, err := getBoolQueryParameter(ctx, "force")
+	if err != nil {
+		return err
+	}
+	return space.Delete(ctx, chartName, force)
+}
+
+// MoveChart moves specified chart to the target space. If any version of the chart is
+// referenced by orchestrated versions in other charts, it's moved only if force is true. If
+// any version is referenced by requirements, it's never moved.
+func MoveChart(ctx context.Context) (*models.Link, error) {
+	spaceName, chartName, err := getSpaceAndChartName(ctx)
+	if err != nil {
+		return nil, err
+	}
+	target, err := getQueryParameter(ctx, "target")
+	if err != nil {
+		return nil, err
+	}
+	space, err := common.GetSpace(ctx, spaceName)
+	if err != nil {
+		return nil, err
+	}
+	force, err := getBoolQueryParameter(ctx, "force")
+	if err != nil {
+		return nil, err
+	}
+	chart, err := space.Move(ctx, chartName, target, force)
+	if err != nil {
+		return nil, err
+	}
+	prefix, err := getAPIPrefix(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewLink(chart.Name(), fmt.Sprintf("%s/spaces/%s/charts/%s", prefix, target, chart.Name())), nil
+}
+
+// ListChartHistory lists events in the history of specified chart
+func ListChartHistory(ctx context.Context) (int, interface{}, error) {
+	chart, err := getExistingChart(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	events, err := chart.History(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	return pageItems(ctx, events, indexOrder())
+}
+
+// DiffVersions compares two versions of specified chart. The new version is looked up
+// in toSpace if it is specified, so that versions in different spaces can be compared.
+func DiffVersions(ctx context.Context) (*types.ChartDiff, error) {
+	spaceName, chartName, err := getSpaceAndChartName(ctx)
+	if err != nil {
+		return nil, err
+	}
+	from, err := getQueryParameter(ctx, "from")
+	if err != nil {
+		return nil, err
+	}
+	to, err := getQueryParameter(ctx, "to")
+	if err != nil {
+		return nil, err
+	}
+	toSpaceName, err := getOptionalQueryParameter(ctx, "toSpace")
+	if err != nil {
+		return nil, err
+	}
+	if len(toSpaceName) <= 0 {
+		toSpaceName = spaceName
+	}
+	fromVersion, err := common.GetVersion(ctx, spaceName, chartName, from)
+	if err != nil {
+		return nil, err
+	}
+	toVersion, err := common.GetVersion(ctx, toSpaceName, chartName, to)
+	if err != nil {
+		return nil, err
+	}
+	fromData, err := fromVersion.GetContent(ctx)
+	if err != nil {
+		return nil, err
+	}
+	toData, err := toVersion.GetContent(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return diffPackages(fromData, toData)
 }
 
 // CreateChart creates a chart by a json config
@@ -50,6 +161,12 @@ func CreateChart(ctx context.Context) (*models.ChartLink, error) {
 	if err != nil {
 		return nil, err
 	}
+	return createOrchestratedChart(ctx, config)
+}
+
+// createOrchestratedChart creates a version by an orchestration config. The config is
+// stored with the version.
+func createOrchestratedChart(ctx context.Context, config *types.OrchestrationConfig) (*models.ChartLink, error) {
 	space, _, version, err := common.GetSpaceChartAndVersion(ctx, config.Save.Space, config.Save.Chart, config.Save.Version)
 	if err != nil {
 		return nil, err
@@ -60,15 +177,194 @@ func CreateChart(ctx context.Context) (*models.ChartLink, error) {
 	if version.Exists(ctx) {
 		return nil, errors.ErrorResourceExist.Format(config.Save.Path())
 	}
+	// keep the original config before it's separated
+	rawConfig, err := json.Marshal(config)
+	if err != nil {
+		return nil, errors.ErrorInternalUnknown.Format(err.Error())
+	}
 	configs, values, err := separateConfigs(config.Configs)
 	if err != nil {
 		return nil, err
 	}
 	// create chart
-	newChart, err := orchestration.Create(configs)
+	newChart, tree, err := orchestration.Create(configs)
+	if err != nil {
+		return nil, err
+	}
+	// archive chart
+	data, _, err := packOrchestratedChart(config, newChart, values)
+	if err != nil {
+		return nil, err
+	}
+	findings, err := lintPackage(ctx, space, config.Save.Path(), data)
+	if err != nil {
+		return nil, err
+	}
+	// save chart
+	options, err := getPutOptions(ctx, storage.SourceOrchestration)
+	if err != nil {
+		return nil, err
+	}
+	options.Config = rawConfig
+	options.References = orchestration.References(tree)
+	err = version.PutContent(ctx, data, options)
+	if err != nil {
+		return nil, err
+	}
+	// construct a chart self-link
+	prefix, err := getAPIPrefix(ctx)
 	if err != nil {
 		return nil, err
 	}
+	link := models.NewChartLink(config.Save.Space, config.Save.Chart, config.Save.Version,
+		fmt.Sprintf("%s/spaces/%s/charts/%s/versions/%s", prefix, config.Save.Space, config.Save.Chart, config.Save.Version))
+	link.Lint = findings
+	return link, nil
+}
+
+// GetOrchestrationConfig gets the orchestration config which created specified version
+func GetOrchestrationConfig(ctx context.Context) (config *types.OrchestrationConfig, err error) {
+	err = managerHelper(ctx, func(space storage.Space, chart storage.Chart, version storage.Version) error {
+		config, err = orchestrationConfig(ctx, space, version)
+		return err
+	})
+	return
+}
+
+// orchestrationConfig reads the stored orchestration config of a version
+func orchestrationConfig(ctx context.Context, space storage.Space, version storage.Version) (*types.OrchestrationConfig, error) {
+	data, err := version.OrchestrationConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	config := &types.OrchestrationConfig{}
+	if err = json.Unmarshal(data, config); err != nil {
+		return nil, errors.ErrorInternalTypeError.Format("orchestration config", "json", "unknown")
+	}
+	config.Save.Space = space.Name()
+	return config, nil
+}
+
+// RebuildVersion creates a new version by the orchestration config of specified version.
+// Independent packages are pinned to the packages which the version was built from, and they
+// can be upgraded to other versions.
+func RebuildVersion(ctx context.Context) (link *models.ChartLink, err error) {
+	rebuild, err := getRebuildConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var config *types.OrchestrationConfig
+	references := map[string]storage.Reference{}
+	err = managerHelper(ctx, func(space storage.Space, chart storage.Chart, version storage.Version) error {
+		config, err = orchestrationConfig(ctx, space, version)
+		if err != nil {
+			return err
+		}
+		record, err := version.Record(ctx)
+		if err != nil {
+			return err
+		}
+		for _, reference := range record.References {
+			references[reference.Path] = reference
+		}
+		// the new version is in the same chart even if the chart was moved
+		config.Save.Chart = chart.Name()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	config.Save.Version = rebuild.Version
+	if len(rebuild.Description) > 0 {
+		config.Save.Desc = rebuild.Description
+	}
+	upgraded := make(map[string]bool, len(rebuild.Upgrades))
+	if err = upgradePackages(ctx, config.Configs, "", rebuild, references, upgraded); err != nil {
+		return nil, err
+	}
+	for path := range rebuild.Upgrades {
+		if !upgraded[path] {
+			return nil, errors.ErrorInvalidParam.Format(fmt.Sprintf("upgrades[%q]", path), "no such chart in the orchestration config")
+		}
+	}
+	return createOrchestratedChart(ctx, config)
+}
+
+// upgradePackages upgrades versions of independent packages in configs of the chart at path.
+// Packages which are not upgraded are pinned to references by paths, and references locate
+// all packages even if they were moved. Upgraded paths are marked in upgraded.
+func upgradePackages(ctx context.Context, configs map[string]interface{}, path string, rebuild *types.RebuildConfig,
+	references map[string]storage.Reference, upgraded map[string]bool) error {
+	for key, value := range configs {
+		config, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		switch key {
+		case valuesConfigName:
+		case packageName:
+			independent, _ := config["independent"].(bool)
+			reference, pinned := references[path]
+			if independent && pinned {
+				config["space"] = reference.Space
+				config["chart"] = reference.Chart
+			}
+			version, ok := rebuild.Upgrades[path]
+			if ok {
+				if !independent {
+					return errors.ErrorInvalidParam.Format(fmt.Sprintf("upgrades[%q]", path), "the package is not independent")
+				}
+				config["version"] = version
+				upgraded[path] = true
+			} else if independent && rebuild.Latest {
+				spaceName, _ := config["space"].(string)
+				chartName, _ := config["chart"].(string)
+				metadata, err := getLatestMetadata(ctx, spaceName, chartName, nil)
+				if err != nil {
+					return err
+				}
+				if metadata == nil {
+					return errors.ErrorContentNotFound.Format(fmt.Sprintf("latest version of %s/%s", spaceName, chartName))
+				}
+				config["version"] = metadata.Version
+			} else if independent && pinned {
+				if err := checkReference(ctx, &reference); err != nil {
+					return err
+				}
+				config["version"] = reference.Version
+			}
+		default:
+			childPath := key
+			if len(path) > 0 {
+				childPath = path + "." + key
+			}
+			if err := upgradePackages(ctx, config, childPath, rebuild, references, upgraded); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkReference checks whether the referenced package exists
+func checkReference(ctx context.Context, reference *storage.Reference) error {
+	version, err := common.GetVersion(ctx, reference.Space, reference.Chart, reference.Version)
+	if err != nil {
+		return err
+	}
+	if !version.Exists(ctx) {
+		name := "root chart"
+		if len(reference.Path) > 0 {
+			name = strconv.Quote(reference.Path)
+		}
+		return errors.ErrorContentNotFound.Format(fmt.Sprintf("package %s/%s/%s of %s",
+			reference.Space, reference.Chart, reference.Version, name))
+	}
+	return nil
+}
+
+// archiveOrchestratedChart sets values and metadata of an orchestrated chart and archives it
+func archiveOrchestratedChart(config *types.OrchestrationConfig, newChart *chart.Chart, values map[string]interface{}) ([]byte, error) {
 	orchestration.ClearValues(newChart)
 	// set values
 	rawValues, err := yaml.Marshal(values)
@@ -80,23 +376,131 @@ func CreateChart(ctx context.Context) (*models.ChartLink, error) {
 	newChart.Metadata.Name = config.Save.Chart
 	newChart.Metadata.Version = config.Save.Version
 	newChart.Metadata.Description = config.Save.Desc
-	// archive chart
-	data, err := orchestration.Archive(newChart)
+	return orchestration.Archive(newChart)
+}
+
+// orchestratedPackage is the package of an orchestrated chart
+type orchestratedPackage struct {
+	// chart is loaded from the package as storing it does
+	chart *chart.Chart
+	// values are the merged values which are stored with the version
+	values chartutil.Values
+}
+
+// packOrchestratedChart archives an orchestrated chart as creating a version does, and loads
+// the package back as storing it does. Creating and previewing share it so that a preview
+// shows what is stored.
+func packOrchestratedChart(config *types.OrchestrationConfig, newChart *chart.Chart, values map[string]interface{}) ([]byte, *orchestratedPackage, error) {
+	data, err := archiveOrchestratedChart(config, newChart, values)
+	if err != nil {
+		return nil, nil, err
+	}
+	loaded, err := chartutil.LoadArchive(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, errors.ErrorInternalUnknown.Format(err.Error())
+	}
+	merged, err := storage.CoalesceValues(loaded)
+	if err != nil {
+		return nil, nil, errors.ErrorInvalidParam.Format("values", err.Error())
+	}
+	return data, &orchestratedPackage{chart: loaded, values: merged}, nil
+}
+
+// PreviewChart orchestrates a chart by a json config without storing it. Problems of the
+// config are reported instead of failing the request. It responds with the chart package
+// if format is archive and there is no problem.
+func PreviewChart(ctx context.Context) (result interface{}, err error) {
+	format, err := getOptionalQueryParameter(ctx, "format")
 	if err != nil {
 		return nil, err
 	}
-	// save chart
-	err = version.PutContent(ctx, data)
+	if len(format) > 0 && format != previewFormatArchive {
+		return nil, errors.ErrorParamValueError.Format("format", previewFormatArchive, format)
+	}
+	config, err := getChartConfig(ctx)
 	if err != nil {
 		return nil, err
 	}
-	// construct a chart self-link
-	path, err := getRequestPath(ctx)
+	space, _, version, err := common.GetSpaceChartAndVersion(ctx, config.Save.Space, config.Save.Chart, config.Save.Version)
+	if err != nil {
+		return nil, err
+	}
+	if !space.Exists(ctx) {
+		return nil, errors.ErrorContentNotFound.Format(config.Save.Space)
+	}
+	preview := &models.OrchestrationPreview{
+		Space:    config.Save.Space,
+		Chart:    config.Save.Chart,
+		Version:  config.Save.Version,
+		Problems: []orchestration.Problem{},
+	}
+	if version.Exists(ctx) {
+		preview.Problems = append(preview.Problems, orchestration.Problem{
+			Message: errors.ErrorResourceExist.Format(config.Save.Path()).Error(),
+		})
+	}
+	configs, values, err := separateConfigs(config.Configs)
 	if err != nil {
 		return nil, err
 	}
-	return models.NewChartLink(config.Save.Space, config.Save.Chart, config.Save.Version,
-		fmt.Sprintf("%s/%s/versions/%s", path, config.Save.Chart, config.Save.Version)), nil
+	newChart, tree, problems := orchestration.Resolve(configs)
+	tree.Name = config.Save.Chart
+	preview.Tree = tree
+	preview.Problems = append(preview.Problems, problems...)
+	var data []byte
+	if newChart != nil {
+		var pkg *orchestratedPackage
+		data, pkg, err = packOrchestratedChart(config, newChart, values)
+		if err != nil {
+			return nil, err
+		}
+		var rawValues []byte
+		if rawValues, err = yaml.Marshal(pkg.values); err != nil {
+			return nil, errors.ErrorInternalUnknown.Format(err.Error())
+		}
+		preview.Values = string(rawValues)
+		preview.Problems = append(preview.Problems, checkOrchestratedValues(pkg)...)
+		preview.Lint, err = lintPackage(ctx, space, config.Save.Path(), data)
+		if err != nil {
+			preview.Problems = append(preview.Problems, orchestration.Problem{Message: err.Error()})
+		}
+	}
+	preview.Accepted = len(preview.Problems) <= 0
+	if !preview.Accepted {
+		if format == previewFormatArchive {
+			return nil, errors.ErrorUnprocessableEntity.Format("config of "+config.Save.Path(), preview.Problems[0].Message)
+		}
+		return preview, nil
+	}
+	preview.Digest = storage.Digest(data)
+	if format != previewFormatArchive {
+		return preview, nil
+	}
+	err = addResponseHeader(ctx, "Content-Disposition",
+		fmt.Sprintf(`attachment; filename="%s-%s.tgz"`, config.Save.Chart, config.Save.Version))
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// previewFormatArchive responds with the orchestrated chart package
+const previewFormatArchive = "archive"
+
+// checkOrchestratedValues validates values of an orchestrated package by schemas of its
+// charts as storing the package does
+func checkOrchestratedValues(pkg *orchestratedPackage) []orchestration.Problem {
+	document, err := storage.ExtractSchema(pkg.chart, pkg.values)
+	if err != nil {
+		return []orchestration.Problem{{Message: errors.ErrorInvalidParam.Format(storage.SchemaName, err.Error()).Error()}}
+	}
+	if document == nil {
+		return nil
+	}
+	if err = storage.ValidateValues(document, pkg.values); err != nil {
+		return []orchestration.Problem{{Message: errors.ErrorUnprocessableEntity.Format("values", err.Error()).Error()}}
+	}
+	return nil
 }
 
 // UploadChart handles a request for storing a version of chart. Resource should not exist
@@ -117,10 +521,19 @@ func UploadChart(ctx context.Context) (*models.ChartLink, error) {
 	if err != nil {
 		return nil, err
 	}
+	resource := fmt.Sprintf("%s/%s/%s", space.Name(), chart.Name(), version.Number())
 	if version.Exists(ctx) {
-		return nil, errors.ErrorResourceExist.Format(fmt.Sprintf("%s/%s/%s", space.Name(), chart.Name(), version.Number()))
+		return nil, errors.ErrorResourceExist.Format(resource)
+	}
+	findings, err := lintPackage(ctx, space, resource, data)
+	if err != nil {
+		return nil, err
+	}
+	options, err := getPutOptions(ctx, storage.SourceUpload)
+	if err != nil {
+		return nil, err
 	}
-	err = version.PutContent(ctx, data)
+	err = version.PutContent(ctx, data, options)
 	if err != nil {
 		return nil, err
 	}
@@ -129,8 +542,10 @@ func UploadChart(ctx context.Context) (*models.ChartLink, error) {
 	if err != nil {
 		return nil, err
 	}
-	return models.NewChartLink(spaceName, metadata.Name, metadata.Version,
-		fmt.Sprintf("%s/%s/versions/%s", path, metadata.Name, metadata.Version)), nil
+	link := models.NewChartLink(spaceName, metadata.Name, metadata.Version,
+		fmt.Sprintf("%s/%s/versions/%s", path, metadata.Name, metadata.Version))
+	link.Lint = findings
+	return link, nil
 }
 
 // CreateOrUploadChart selects a handler to handle the request by request content type
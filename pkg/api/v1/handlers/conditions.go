@@ -0,0 +1,173 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/caicloud/helm-registry/pkg/errors"
+	"github.com/caicloud/helm-registry/pkg/storage"
+)
+
+// newETag creates a strong entity tag from an opaque value
+func newETag(value string) string {
+	return `"` + value + `"`
+}
+
+// dataETag creates a strong entity tag from the sha256 digest of data
+func dataETag(data []byte) string {
+	return newETag(fmt.Sprintf("sha256:%x", sha256.Sum256(data)))
+}
+
+// matchETag checks whether an entity tag matches a list of entity tags in If-None-Match.
+// Weak comparison is used as RFC 7232 requires.
+func matchETag(list string, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, tag := range strings.Split(list, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// metadataETag creates the entity tag of metadata
+func metadataETag(metadata *storage.Metadata) (string, error) {
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return "", errors.ErrorInternalUnknown.Format(err)
+	}
+	return dataETag(data), nil
+}
+
+// yamlETag creates the entity tag of the yaml representation from the entity tag of the
+// json representation
+func yamlETag(etag string) string {
+	return strings.TrimSuffix(etag, `"`) + `+yaml"`
+}
+
+// htmlETag creates the entity tag of the html representation from the entity tag of the
+// markdown representation
+func htmlETag(etag string) string {
+	return strings.TrimSuffix(etag, `"`) + `+html"`
+}
+
+// negotiatedETag returns the entity tag of the representation which is selected by Accept
+// of request. etag is the entity tag of the json representation.
+func negotiatedETag(ctx context.Context, etag string) string {
+	if acceptsYAML(ctx) {
+		return yamlETag(etag)
+	}
+	return etag
+}
+
+// hasPreconditions checks whether the request has If-Match
+func hasPreconditions(ctx context.Context) (bool, error) {
+	request, err := getRequestFromContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	return len(request.HeaderParameter("If-Match")) > 0, nil
+}
+
+// checkPreconditions evaluates If-Match of request with entity tags of current
+// representations. If-Match is satisfied if it matches any representation. No etags
+// means the resource does not exist. Strong comparison is used, so weak entity tags
+// never match. It returns ErrorPreconditionFailed if the precondition is not satisfied.
+// resource is a description of the resource.
+func checkPreconditions(ctx context.Context, resource string, etags ...string) error {
+	request, err := getRequestFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	match := request.HeaderParameter("If-Match")
+	if len(match) <= 0 {
+		return nil
+	}
+	for _, etag := range etags {
+		if len(etag) <= 0 {
+			continue
+		}
+		for _, tag := range strings.Split(match, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "*" || tag == etag {
+				return nil
+			}
+		}
+	}
+	return errors.ErrorPreconditionFailed.Format(resource, "If-Match")
+}
+
+// checkConditions sets ETag and Last-Modified of current representation to response, and
+// evaluates If-None-Match and If-Modified-Since of request. If-Modified-Since is ignored if
+// the request has If-None-Match. It returns ErrorNotModified if the client has the same
+// representation. resource is a description of the resource.
+func checkConditions(ctx context.Context, resource string, etag string, modified time.Time) error {
+	request, err := getRequestFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	response, err := getResponseFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	if len(etag) > 0 {
+		response.AddHeader("ETag", etag)
+	}
+	if !modified.IsZero() {
+		response.AddHeader("Last-Modified", modified.UTC().Format(http.TimeFormat))
+	}
+	method := request.Request.Method
+	if method != http.MethodGet && method != http.MethodHead {
+		return nil
+	}
+	if match := request.HeaderParameter("If-None-Match"); len(match) > 0 {
+		if len(etag) > 0 && matchETag(match, etag) {
+			return errors.ErrorNotModified.Format(resource)
+		}
+		return nil
+	}
+	if since := request.HeaderParameter("If-Modified-Since"); len(since) > 0 && !modified.IsZero() {
+		t, err := http.ParseTime(since)
+		if err == nil && !modified.Truncate(time.Second).After(t) {
+			return errors.ErrorNotModified.Format(resource)
+		}
+	}
+	return nil
+}
+
+// lastModified returns the last modified time of a version. It's the latest time of the
+// record and the state.
+func lastModified(record *storage.Record, state *storage.VersionState) time.Time {
+	var modified time.Time
+	if record != nil {
+		modified = record.Updated
+	}
+	if state != nil && state.Time != nil && state.Time.After(modified) {
+		modified = *state.Time
+	}
+	return modified
+}
+
+// versionModified returns modified if the request specifies version by its number. A version
+// which is resolved by a tag has no last modified time, because the tag can be moved to an
+// older version. Clients should use the entity tag instead.
+func versionModified(ctx context.Context, version storage.Version, modified time.Time) (time.Time, error) {
+	number, err := getVersionNumber(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if number != version.Number() {
+		return time.Time{}, nil
+	}
+	return modified, nil
+}
@@ -0,0 +1,251 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/caicloud/helm-registry/pkg/api/models"
+	"github.com/caicloud/helm-registry/pkg/common"
+	"github.com/caicloud/helm-registry/pkg/errors"
+	"github.com/caicloud/helm-registry/pkg/storage"
+)
+
+// warnSkippedVersion adds a warning header to response for a version which is skipped
+// because it can't be read, e.g. it's being stored.
+func warnSkippedVersion(ctx context.Context, spaceName, chartName, versionNumber string, cause error) error {
+	message := fmt.Sprintf("%s/%s/%s is skipped: %v", spaceName, chartName, versionNumber, cause)
+	return addResponseHeader(ctx, "Warning", "299 - "+strconv.Quote(message))
+}
+
+// dependentState returns the state of an orchestrated version. If the version can't be read,
+// it's reported by a warning and a nil state is returned.
+func dependentState(ctx context.Context, spaceName, chartName, versionNumber string) (*storage.VersionState, error) {
+	version, err := common.GetVersion(ctx, spaceName, chartName, versionNumber)
+	if err == nil {
+		var state *storage.VersionState
+		if state, err = version.State(ctx); err == nil {
+			return state, nil
+		}
+	}
+	return nil, warnSkippedVersion(ctx, spaceName, chartName, versionNumber, err)
+}
+
+// findDependents finds versions in all spaces which were built from specified chart or
+// require it. If versionNumber is not empty, only the version is matched. Versions which can't be
+// read are skipped and reported by warnings.
+func findDependents(ctx context.Context, spaceName, chartName, versionNumber string, yanked bool) ([]*models.Dependent, error) {
+	dependencies, err := common.MustGetSpaceManager().Dependencies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// states caches states of dependents. It's nil if a dependent is skipped
+	states := map[string]*storage.VersionState{}
+	dependents := []*models.Dependent{}
+	for _, d := range dependencies {
+		if d.Reference.Space != spaceName || d.Reference.Chart != chartName {
+			continue
+		}
+		if len(versionNumber) > 0 && d.Reference.Version != versionNumber {
+			continue
+		}
+		key := path.Join(d.Space, d.Chart, d.Version)
+		state, ok := states[key]
+		if !ok {
+			if state, err = dependentState(ctx, d.Space, d.Chart, d.Version); err != nil {
+				return nil, err
+			}
+			states[key] = state
+		}
+		if state == nil || (!yanked && state.Yanked()) {
+			continue
+		}
+		dependents = append(dependents, &models.Dependent{
+			Space:     d.Space,
+			Chart:     d.Chart,
+			Version:   d.Version,
+			Reference: d.Reference,
+		})
+	}
+	order := dependentOrder(dependents)
+	sort.SliceStable(dependents, func(i, j int) bool {
+		return order.compare(order.keys(i), order.keys(j)) < 0
+	})
+	return dependents, nil
+}
+
+// latestPackageVersion returns the latest version number of a package which is not yanked.
+// Versions which can't be read are skipped and reported by warnings. It's empty if there is
+// no such version.
+func latestPackageVersion(ctx context.Context, spaceName, chartName string) (string, error) {
+	chart, err := common.GetChart(ctx, spaceName, chartName)
+	if err != nil {
+		return "", err
+	}
+	if !chart.Exists(ctx) {
+		return "", nil
+	}
+	versionNumbers, err := chart.List(ctx)
+	if err != nil {
+		return "", err
+	}
+	for i := len(versionNumbers) - 1; i >= 0; i-- {
+		version, err := chart.Version(ctx, versionNumbers[i])
+		if err != nil {
+			return "", err
+		}
+		metadata, err := version.Metadata(ctx)
+		if err != nil {
+			if err = warnSkippedVersion(ctx, spaceName, chartName, versionNumbers[i], err); err != nil {
+				return "", err
+			}
+			continue
+		}
+		if !metadata.State.Yanked() {
+			return metadata.Version, nil
+		}
+	}
+	return "", nil
+}
+
+// dependentOrder creates an order of dependents
+func dependentOrder(dependents []*models.Dependent) *listOrder {
+	return &listOrder{
+		comparers: []keyComparer{strings.Compare, strings.Compare, compareVersionNumbers, strings.Compare},
+		keys: func(i int) []string {
+			d := dependents[i]
+			return []string{d.Space, d.Chart, d.Version, d.Reference.Path}
+		},
+	}
+}
+
+// ListDependents lists versions in all spaces which were built from specified chart or
+// require it. The version parameter matches only a version number or a tag of the chart.
+// Dependents which are yanked are listed only if yanked is true. Dependents which can't be
+// read are skipped and reported by warnings.
+func ListDependents(ctx context.Context) (int, interface{}, error) {
+	spaceName, chartName, err := getSpaceAndChartName(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	versionNumber, err := getQueryParameter(ctx, "version")
+	if err != nil && !errors.ErrorParamNotFound.Equal(err) {
+		return 0, nil, err
+	}
+	if len(versionNumber) > 0 {
+		version, err := common.GetVersion(ctx, spaceName, chartName, versionNumber)
+		if err != nil {
+			return 0, nil, err
+		}
+		versionNumber = version.Number()
+	}
+	yanked, err := includeYanked(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	dependents, err := findDependents(ctx, spaceName, chartName, versionNumber, yanked)
+	if err != nil {
+		return 0, nil, err
+	}
+	return pageItems(ctx, dependents, dependentOrder(dependents))
+}
+
+// ListOutdatedVersions lists orchestrated versions in specified space whose packages have
+// newer versions which are not yanked. Only the latest orchestrated version of each chart
+// is checked unless all is true. Yanked versions are never checked, and requirements are not
+// packages of orchestrated versions. Versions which can't be read are skipped and reported by
+// warnings.
+func ListOutdatedVersions(ctx context.Context) (int, interface{}, error) {
+	spaceName, err := getSpaceName(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	space, err := common.GetSpace(ctx, spaceName)
+	if err != nil {
+		return 0, nil, err
+	}
+	if !space.Exists(ctx) {
+		return 0, nil, errors.ErrorContentNotFound.Format(spaceName)
+	}
+	all, err := getBoolQueryParameter(ctx, "all")
+	if err != nil {
+		return 0, nil, err
+	}
+	dependencies, err := common.MustGetSpaceManager().Dependencies(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	// checked are versions to check. If all is false, they're the latest versions of charts
+	checked := []*models.OutdatedVersion{}
+	references := map[string][]storage.Reference{}
+	latestVersions := map[string]*models.OutdatedVersion{}
+	for _, d := range dependencies {
+		if d.Space != spaceName || d.Reference.Requirement {
+			continue
+		}
+		key := path.Join(d.Chart, d.Version)
+		if refs, ok := references[key]; ok {
+			references[key] = append(refs, d.Reference)
+			continue
+		}
+		references[key] = []storage.Reference{d.Reference}
+		state, err := dependentState(ctx, d.Space, d.Chart, d.Version)
+		if err != nil {
+			return 0, nil, err
+		}
+		if state == nil || state.Yanked() {
+			continue
+		}
+		version := &models.OutdatedVersion{Space: spaceName, Chart: d.Chart, Version: d.Version}
+		if !all {
+			current, ok := latestVersions[d.Chart]
+			if ok && compareVersionNumbers(d.Version, current.Version) <= 0 {
+				continue
+			}
+			latestVersions[d.Chart] = version
+		}
+		checked = append(checked, version)
+	}
+	// latest caches latest version numbers of packages. It's empty if a package has no
+	// version which is not yanked.
+	latest := map[string]string{}
+	result := make([]*models.OutdatedVersion, 0, len(checked))
+	for _, version := range checked {
+		if !all && latestVersions[version.Chart] != version {
+			continue
+		}
+		for _, reference := range references[path.Join(version.Chart, version.Version)] {
+			key := path.Join(reference.Space, reference.Chart)
+			number, ok := latest[key]
+			if !ok {
+				if number, err = latestPackageVersion(ctx, reference.Space, reference.Chart); err != nil {
+					return 0, nil, err
+				}
+				latest[key] = number
+			}
+			if len(number) > 0 && compareVersionNumbers(number, reference.Version) > 0 {
+				version.Packages = append(version.Packages, models.OutdatedPackage{Reference: reference, Latest: number})
+			}
+		}
+		if len(version.Packages) > 0 {
+			result = append(result, version)
+		}
+	}
+	order := &listOrder{
+		comparers: []keyComparer{strings.Compare, compareVersionNumbers},
+		keys: func(i int) []string {
+			return []string{result[i].Chart, result[i].Version}
+		},
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return order.compare(order.keys(i), order.keys(j)) < 0
+	})
+	return pageItems(ctx, result, order)
+}
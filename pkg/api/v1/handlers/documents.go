@@ -0,0 +1,130 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/caicloud/helm-registry/pkg/api/definition"
+	"github.com/caicloud/helm-registry/pkg/errors"
+	"github.com/caicloud/helm-registry/pkg/markdown"
+	"github.com/caicloud/helm-registry/pkg/storage"
+	"github.com/emicklei/go-restful"
+	"k8s.io/helm/pkg/chartutil"
+)
+
+// FetchReadme fetches README.md of specified version. It responds with html rendered from
+// the markdown if the request accepts html.
+func FetchReadme(ctx context.Context) (data []byte, err error) {
+	err = managerHelper(ctx, func(space storage.Space, chart storage.Chart, version storage.Version) error {
+		document, err := version.Document(ctx, storage.DocumentReadme)
+		if err != nil {
+			return err
+		}
+		if err = addResponseHeader(ctx, "Vary", "Accept"); err != nil {
+			return err
+		}
+		resource := fmt.Sprintf("readme of %s/%s/%s", space.Name(), chart.Name(), version.Number())
+		contentType, etag := definition.MIMEMarkdown, dataETag(document.Data)
+		data = document.Data
+		if acceptsHTML(ctx) {
+			contentType, etag = definition.MIMEHTML, htmlETag(etag)
+			data = markdown.ToHTML(document.Data)
+		}
+		if err = checkConditions(ctx, resource, etag, time.Time{}); err != nil {
+			return err
+		}
+		return addResponseHeader(ctx, "Content-Type", contentType+"; charset=utf-8")
+	})
+	return
+}
+
+// FetchNotes fetches templates/NOTES.txt of specified version
+func FetchNotes(ctx context.Context) (data []byte, err error) {
+	err = managerHelper(ctx, func(space storage.Space, chart storage.Chart, version storage.Version) error {
+		document, err := version.Document(ctx, storage.DocumentNotes)
+		if err != nil {
+			return err
+		}
+		err = checkConditions(ctx, fmt.Sprintf("notes of %s/%s/%s", space.Name(), chart.Name(), version.Number()),
+			dataETag(document.Data), time.Time{})
+		if err != nil {
+			return err
+		}
+		data = document.Data
+		return addResponseHeader(ctx, "Content-Type", definition.MIMEText+"; charset=utf-8")
+	})
+	return
+}
+
+// FetchIcon fetches the icon of specified version. Only icons in chart packages are available.
+func FetchIcon(ctx context.Context) (data []byte, err error) {
+	err = managerHelper(ctx, func(space storage.Space, chart storage.Chart, version storage.Version) error {
+		document, err := version.Document(ctx, storage.DocumentIcon)
+		if err != nil {
+			return err
+		}
+		err = checkConditions(ctx, fmt.Sprintf("icon of %s/%s/%s", space.Name(), chart.Name(), version.Number()),
+			dataETag(document.Data), time.Time{})
+		if err != nil {
+			return err
+		}
+		data = document.Data
+		return addResponseHeader(ctx, "Content-Type", fileContentType(document.Path, document.Data))
+	})
+	return
+}
+
+// FetchSchema fetches the json schema of values of specified version. The schema is composed
+// from values.schema.json of the chart and its subcharts.
+func FetchSchema(ctx context.Context) (data []byte, err error) {
+	err = managerHelper(ctx, func(space storage.Space, chart storage.Chart, version storage.Version) error {
+		document, err := version.Document(ctx, storage.DocumentSchema)
+		if err != nil {
+			return err
+		}
+		err = checkConditions(ctx, fmt.Sprintf("schema of %s/%s/%s", space.Name(), chart.Name(), version.Number()),
+			dataETag(document.Data), time.Time{})
+		if err != nil {
+			return err
+		}
+		data = document.Data
+		return addResponseHeader(ctx, "Content-Type", restful.MIME_JSON)
+	})
+	return
+}
+
+// InferSchema infers a json schema from coalesced values of specified version. It's useful
+// to build forms for charts without values.schema.json.
+func InferSchema(ctx context.Context) (data []byte, err error) {
+	err = managerHelper(ctx, func(space storage.Space, chart storage.Chart, version storage.Version) error {
+		content, err := version.GetContent(ctx)
+		if err != nil {
+			return err
+		}
+		resource := fmt.Sprintf("%s/%s/%s", space.Name(), chart.Name(), version.Number())
+		origin, err := chartutil.LoadArchive(bytes.NewReader(content))
+		if err != nil {
+			return errors.ErrorInternalTypeError.Format(resource, "chart", "unknown")
+		}
+		values, err := chartutil.CoalesceValues(origin, origin.Values)
+		if err != nil {
+			return errors.ErrorInternalUnknown.Format(err)
+		}
+		data, err = json.MarshalIndent(storage.InferSchema(origin, values), "", "  ")
+		if err != nil {
+			return errors.ErrorInternalUnknown.Format(err)
+		}
+		if err = checkConditions(ctx, "inferred schema of "+resource, dataETag(data), time.Time{}); err != nil {
+			return err
+		}
+		return addResponseHeader(ctx, "Content-Type", restful.MIME_JSON)
+	})
+	return
+}
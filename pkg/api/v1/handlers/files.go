@@ -0,0 +1,154 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package handlers
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/caicloud/helm-registry/pkg/api/definition"
+	"github.com/caicloud/helm-registry/pkg/api/models"
+	"github.com/caicloud/helm-registry/pkg/errors"
+	"github.com/caicloud/helm-registry/pkg/storage"
+)
+
+// textTypes are media types of files which are common in charts but unknown to mime
+var textTypes = map[string]string{
+	".yaml": definition.MIMEYAML,
+	".yml":  definition.MIMEYAML,
+	".tpl":  definition.MIMEText + "; charset=utf-8",
+	".txt":  definition.MIMEText + "; charset=utf-8",
+	".md":   definition.MIMEMarkdown + "; charset=utf-8",
+}
+
+// ListFiles lists files in the package of specified version
+func ListFiles(ctx context.Context) (int, interface{}, error) {
+	var files []*models.File
+	err := managerHelper(ctx, func(space storage.Space, chart storage.Chart, version storage.Version) error {
+		contents, err := loadFiles(ctx, chart, version)
+		if err != nil {
+			return err
+		}
+		files = make([]*models.File, 0, len(contents))
+		for name, data := range contents {
+			files = append(files, &models.File{
+				Path:        name,
+				Size:        len(data),
+				ContentType: fileContentType(name, data),
+			})
+		}
+		sort.Slice(files, func(i, j int) bool {
+			return files[i].Path < files[j].Path
+		})
+		return nil
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	return pageItems(ctx, files, nameOrder(func(i int) string {
+		return files[i].Path
+	}))
+}
+
+// FetchFile fetches raw content of a file in the package of specified version
+func FetchFile(ctx context.Context) (data []byte, err error) {
+	err = managerHelper(ctx, func(space storage.Space, chart storage.Chart, version storage.Version) error {
+		name, err := getPathParameter(ctx, "path")
+		if err != nil {
+			return err
+		}
+		name = strings.TrimPrefix(path.Clean("/"+name), "/")
+		contents, err := loadFiles(ctx, chart, version)
+		if err != nil {
+			return err
+		}
+		content, ok := contents[name]
+		if !ok {
+			return errors.ErrorContentNotFound.Format(fmt.Sprintf("file %s in %s/%s/%s", name, space.Name(), chart.Name(), version.Number()))
+		}
+		err = checkConditions(ctx, fmt.Sprintf("file %s in %s/%s/%s", name, space.Name(), chart.Name(), version.Number()),
+			dataETag(content), time.Time{})
+		if err != nil {
+			return err
+		}
+		data = content
+		return addResponseHeader(ctx, "Content-Type", fileContentType(name, content))
+	})
+	return
+}
+
+// loadFiles loads files in the package of version
+func loadFiles(ctx context.Context, chart storage.Chart, version storage.Version) (map[string][]byte, error) {
+	data, err := version.GetContent(ctx)
+	if err != nil {
+		return nil, err
+	}
+	files, err := archiveFiles(data)
+	if err != nil {
+		return nil, errors.ErrorInternalTypeError.Format(
+			fmt.Sprintf("%s/%s", chart.Name(), version.Number()), "chart", "unknown")
+	}
+	return files, nil
+}
+
+// archiveFiles reads regular files in a chart package as they are archived. Paths of
+// files are relative to the top directory of the package, as helm loads them. Packages
+// of subcharts in charts/ are files too.
+func archiveFiles(data []byte) (map[string][]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	files := make(map[string][]byte)
+	tr := tar.NewReader(reader)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return files, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg && header.Typeflag != tar.TypeRegA {
+			continue
+		}
+		// archives created on Windows may have backslashes
+		name := strings.Replace(header.Name, "\\", "/", -1)
+		parts := strings.SplitN(name, "/", 2)
+		if len(parts) < 2 || len(parts[1]) <= 0 {
+			continue
+		}
+		content, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		files[parts[1]] = content
+	}
+}
+
+// fileContentType returns the media type of a file by its extension, and detects it
+// from content if the extension is unknown
+func fileContentType(name string, data []byte) string {
+	ext := strings.ToLower(path.Ext(name))
+	if contentType, ok := textTypes[ext]; ok {
+		return contentType
+	}
+	if contentType := mime.TypeByExtension(ext); len(contentType) > 0 {
+		return contentType
+	}
+	return http.DetectContentType(data)
+}
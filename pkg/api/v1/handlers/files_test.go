@@ -0,0 +1,96 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package handlers
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"reflect"
+	"testing"
+)
+
+// archiveEntry is an entry of a test archive
+type archiveEntry struct {
+	name     string
+	typeflag byte
+	content  string
+}
+
+// newArchive creates a gzipped tar archive of entries
+func newArchive(t *testing.T, entries []archiveEntry) []byte {
+	buf := bytes.NewBuffer(nil)
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+	for _, e := range entries {
+		header := &tar.Header{Name: e.name, Mode: 0644, Size: int64(len(e.content)), Typeflag: e.typeflag}
+		if e.typeflag == tar.TypeSymlink {
+			header.Linkname, header.Size = e.content, 0
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if header.Size > 0 {
+			if _, err := tw.Write([]byte(e.content)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestArchiveFiles(t *testing.T) {
+	cases := []struct {
+		name    string
+		entries []archiveEntry
+		files   map[string]string
+	}{
+		{"chart", []archiveEntry{
+			{"web/", tar.TypeDir, ""},
+			{"web/Chart.yaml", tar.TypeReg, "name: web # comment\nversion: 1.0.0\n"},
+			{"web/templates/svc.yaml", tar.TypeReg, "kind: Service\n"},
+			{"web/charts/db-1.0.0.tgz", tar.TypeReg, "tgz"},
+		}, map[string]string{
+			"Chart.yaml":          "name: web # comment\nversion: 1.0.0\n",
+			"templates/svc.yaml":  "kind: Service\n",
+			"charts/db-1.0.0.tgz": "tgz",
+		}},
+		{"current directory", []archiveEntry{
+			{"./Chart.yaml", tar.TypeReg, "name: web\n"},
+			{"./values.yaml", tar.TypeReg, ""},
+		}, map[string]string{"Chart.yaml": "name: web\n", "values.yaml": ""}},
+		{"windows paths", []archiveEntry{
+			{"web\\templates\\svc.yaml", tar.TypeReg, "kind: Service\n"},
+		}, map[string]string{"templates/svc.yaml": "kind: Service\n"}},
+		{"not regular files", []archiveEntry{
+			{"README.md", tar.TypeReg, "top"},
+			{"web/link", tar.TypeSymlink, "Chart.yaml"},
+			{"web/Chart.yaml", tar.TypeReg, "name: web\n"},
+		}, map[string]string{"Chart.yaml": "name: web\n"}},
+	}
+	for _, c := range cases {
+		files, err := archiveFiles(newArchive(t, c.entries))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", c.name, err)
+			continue
+		}
+		result := make(map[string]string, len(files))
+		for name, content := range files {
+			result[name] = string(content)
+		}
+		if !reflect.DeepEqual(result, c.files) {
+			t.Errorf("%s: expected %v, got %v", c.name, c.files, result)
+		}
+	}
+	if _, err := archiveFiles([]byte("chart")); err == nil {
+		t.Errorf("expected an error of invalid packages")
+	}
+}
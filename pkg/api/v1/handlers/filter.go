@@ -0,0 +1,194 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package handlers
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/blang/semver"
+	"github.com/caicloud/helm-registry/pkg/errors"
+	"github.com/caicloud/helm-registry/pkg/semverutil"
+	"github.com/caicloud/helm-registry/pkg/storage"
+)
+
+// metadataFilter filters metadata of versions by query parameters
+type metadataFilter struct {
+	// prefix is the prefix of chart name
+	prefix string
+	// keyword is a case-insensitive substring of name, description or keywords
+	keyword string
+	// maintainer is the name or email of a maintainer
+	maintainer string
+	// appVersion is the app version
+	appVersion string
+	// annotations is a selector of annotations
+	annotations map[string]string
+	// versions is the range of version numbers
+	versions versionRange
+	// createdAfter and createdBefore limit the created time of versions
+	createdAfter  time.Time
+	createdBefore time.Time
+}
+
+// getMetadataFilter gets the filter of metadata from query parameters
+func getMetadataFilter(ctx context.Context) (*metadataFilter, error) {
+	filter := &metadataFilter{}
+	var err error
+	params := map[string]*string{
+		"prefix":     &filter.prefix,
+		"keyword":    &filter.keyword,
+		"maintainer": &filter.maintainer,
+		"appVersion": &filter.appVersion,
+	}
+	for name, value := range params {
+		if *value, err = getOptionalQueryParameter(ctx, name); err != nil {
+			return nil, err
+		}
+	}
+	if filter.annotations, err = getSelector(ctx, "annotation"); err != nil {
+		return nil, err
+	}
+	if filter.versions, err = getVersionRange(ctx); err != nil {
+		return nil, err
+	}
+	if filter.createdAfter, err = getTimeQueryParameter(ctx, "createdAfter"); err != nil {
+		return nil, err
+	}
+	if filter.createdBefore, err = getTimeQueryParameter(ctx, "createdBefore"); err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
+
+// Match checks whether metadata matches the filter
+func (f *metadataFilter) Match(md *storage.Metadata) bool {
+	if !strings.HasPrefix(md.Name, f.prefix) {
+		return false
+	}
+	if len(f.keyword) > 0 && !matchKeyword(md, f.keyword) {
+		return false
+	}
+	if len(f.maintainer) > 0 && !matchMaintainer(md, f.maintainer) {
+		return false
+	}
+	if len(f.appVersion) > 0 && md.AppVersion != f.appVersion {
+		return false
+	}
+	for key, value := range f.annotations {
+		v, ok := md.Annotations[key]
+		if !ok || (len(value) > 0 && v != value) {
+			return false
+		}
+	}
+	if !f.versions.Match(md.Version) {
+		return false
+	}
+	if !f.createdAfter.IsZero() || !f.createdBefore.IsZero() {
+		if md.Record == nil {
+			return false
+		}
+		if !f.createdAfter.IsZero() && !md.Record.Created.After(f.createdAfter) {
+			return false
+		}
+		if !f.createdBefore.IsZero() && !md.Record.Created.Before(f.createdBefore) {
+			return false
+		}
+	}
+	return true
+}
+
+// Filter returns metadata which matches the filter
+func (f *metadataFilter) Filter(metadata []*storage.Metadata) []*storage.Metadata {
+	result := make([]*storage.Metadata, 0, len(metadata))
+	for _, md := range metadata {
+		if f.Match(md) {
+			result = append(result, md)
+		}
+	}
+	return result
+}
+
+// matchKeyword checks whether name, description or keywords of metadata contain keyword
+func matchKeyword(md *storage.Metadata, keyword string) bool {
+	keyword = strings.ToLower(keyword)
+	contains := func(s string) bool {
+		return strings.Contains(strings.ToLower(s), keyword)
+	}
+	if contains(md.Name) || contains(md.Description) {
+		return true
+	}
+	for _, kw := range md.Keywords {
+		if contains(kw) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchMaintainer checks whether metadata has a maintainer with the name or email
+func matchMaintainer(md *storage.Metadata, maintainer string) bool {
+	for _, m := range md.Maintainers {
+		if m != nil && (m.Name == maintainer || strings.EqualFold(m.Email, maintainer)) {
+			return true
+		}
+	}
+	return false
+}
+
+// getOptionalQueryParameter gets a query parameter. It returns an empty string if the
+// parameter does not exist
+func getOptionalQueryParameter(ctx context.Context, name string) (string, error) {
+	value, err := getQueryParameter(ctx, name)
+	if err != nil && errors.ErrorParamNotFound.Equal(err) {
+		return "", nil
+	}
+	return value, err
+}
+
+// getTimeQueryParameter gets a RFC3339 time from query parameters. It returns zero time
+// if the parameter does not exist
+func getTimeQueryParameter(ctx context.Context, name string) (time.Time, error) {
+	value, err := getOptionalQueryParameter(ctx, name)
+	if err != nil || len(value) <= 0 {
+		return time.Time{}, err
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, errors.ErrorParamTypeError.Format(name, "RFC3339 time", value)
+	}
+	return t, nil
+}
+
+// versionRange is a range of version numbers in the syntax of version constraints of helm.
+// An empty range matches all versions.
+type versionRange semverutil.Constraints
+
+// getVersionRange gets the version range from query parameters
+func getVersionRange(ctx context.Context) (versionRange, error) {
+	const field = "version"
+	value, err := getOptionalQueryParameter(ctx, field)
+	if err != nil || len(value) <= 0 {
+		return nil, err
+	}
+	r, err := semverutil.ParseConstraints(value)
+	if err != nil {
+		return nil, errors.ErrorInvalidParam.Format(field, err)
+	}
+	return versionRange(r), nil
+}
+
+// Match checks whether the version number is in the range
+func (r versionRange) Match(number string) bool {
+	if len(r) <= 0 {
+		return true
+	}
+	version, err := semver.Parse(number)
+	if err != nil {
+		return false
+	}
+	return semverutil.Constraints(r).Check(version)
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package handlers
+
+import (
+	"context"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/caicloud/helm-registry/pkg/api/definition"
+	"github.com/caicloud/helm-registry/pkg/storage"
+	"github.com/emicklei/go-restful"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+// newRequestContext creates a context with a request of query
+func newRequestContext(query string) (context.Context, *restful.Request) {
+	request := restful.NewRequest(httptest.NewRequest("GET", "/?"+query, nil))
+	return context.WithValue(context.Background(), definition.KeyRequest, request), request
+}
+
+// newMetadata creates metadata of a version created at created
+func newMetadata(name, version string, created time.Time) *storage.Metadata {
+	md := &storage.Metadata{Record: &storage.Record{Created: created, Updated: created}}
+	md.Name = name
+	md.Version = version
+	return md
+}
+
+func TestMetadataFilter(t *testing.T) {
+	created := time.Date(2017, 6, 1, 0, 0, 0, 0, time.UTC)
+	web := newMetadata("web", "1.2.3", created)
+	web.Description = "A Web Server"
+	web.Keywords = []string{"http", "Nginx"}
+	web.AppVersion = "1.13"
+	web.Annotations = map[string]string{"team": "infra", "beta": ""}
+	web.Maintainers = []*chart.Maintainer{nil, {Name: "admin", Email: "Admin@example.com"}}
+	db := newMetadata("webdb", "2.0.0-beta.1", created.Add(time.Hour))
+	db.Record = nil
+	invalid := newMetadata("web", "latest", created)
+	metadata := []*storage.Metadata{web, db, invalid}
+
+	cases := []struct {
+		query    string
+		expected []*storage.Metadata
+	}{
+		{"", metadata},
+		{"prefix=web", metadata},
+		{"prefix=webd", []*storage.Metadata{db}},
+		{"keyword=server", []*storage.Metadata{web}},
+		{"keyword=NGINX", []*storage.Metadata{web}},
+		{"keyword=DB", []*storage.Metadata{db}},
+		{"maintainer=admin", []*storage.Metadata{web}},
+		{"maintainer=admin%40EXAMPLE.com", []*storage.Metadata{web}},
+		{"maintainer=Admin", nil},
+		{"appVersion=1.13", []*storage.Metadata{web}},
+		{"appVersion=1.1", nil},
+		{"annotation=team", []*storage.Metadata{web}},
+		{"annotation=team%3Dinfra,beta", []*storage.Metadata{web}},
+		{"annotation=team%3Dapp", nil},
+		{"version=1.2.3", []*storage.Metadata{web}},
+		// prereleases only satisfy constraints with prereleases
+		{"version=%3E%3D1.0.0", []*storage.Metadata{web}},
+		{"version=%3E%3D1.0.0+%3C2.0.0", []*storage.Metadata{web}},
+		{"version=%3E%3D1.0.0-0", []*storage.Metadata{web, db}},
+		{"version=%3C1.0.0+%7C%7C+%3E%3D2.0.0-0", []*storage.Metadata{db}},
+		{"version=%3E1.2.3", nil},
+		{"version=%21%3D1.2.3", []*storage.Metadata{db}},
+		{"version=%3C%3D1.2.3", []*storage.Metadata{web}},
+		{"version=~1.2.0", []*storage.Metadata{web}},
+		{"version=1.2.x", []*storage.Metadata{web}},
+		{"version=~1.1.0", nil},
+		{"version=%5E1.0.0", []*storage.Metadata{web}},
+		{"version=%5E0.1.0", nil},
+		{"createdAfter=2017-05-31T00:00:00Z", []*storage.Metadata{web, invalid}},
+		{"createdAfter=2017-06-01T00:00:00Z", nil},
+		{"createdBefore=2017-06-01T08:00:01%2B08:00", []*storage.Metadata{web, invalid}},
+		{"prefix=web&keyword=server&version=1.2.3&createdBefore=2017-07-01T00:00:00Z", []*storage.Metadata{web}},
+	}
+	for _, c := range cases {
+		ctx, _ := newRequestContext(c.query)
+		filter, err := getMetadataFilter(ctx)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", c.query, err)
+			continue
+		}
+		result := filter.Filter(metadata)
+		if len(result) != len(c.expected) {
+			t.Errorf("%q: expected %d versions, got %d", c.query, len(c.expected), len(result))
+			continue
+		}
+		for i := range result {
+			if result[i] != c.expected[i] {
+				t.Errorf("%q: expected %s %s, got %s %s", c.query,
+					c.expected[i].Name, c.expected[i].Version, result[i].Name, result[i].Version)
+			}
+		}
+	}
+}
+
+func TestMetadataFilterErrors(t *testing.T) {
+	cases := []struct {
+		query, message string
+	}{
+		{"annotation=%3Dinfra", "annotation"},
+		{"annotation=team,,beta", "annotation"},
+		{"createdAfter=2017-06-01", "createdAfter"},
+		{"createdBefore=yesterday", "createdBefore"},
+		{"version=1.0.0-01", `invalid constraint "1.0.0-01"`},
+		{"version=%3E%3E1.0.0", "invalid constraints"},
+		{"version=latest", "invalid constraints"},
+		{"version=%3E%3D", "invalid constraints"},
+		{"version=1.0.0+%7C%7C", "invalid constraints"},
+		{"version=%7C%7C1.0.0", "invalid constraints"},
+	}
+	for _, c := range cases {
+		ctx, _ := newRequestContext(c.query)
+		_, err := getMetadataFilter(ctx)
+		if err == nil {
+			t.Errorf("%q: expected an error", c.query)
+			continue
+		}
+		if !strings.Contains(err.Error(), c.message) {
+			t.Errorf("%q: expected an error with %q, got %q", c.query, c.message, err.Error())
+		}
+	}
+}
+
+func TestVersionRange(t *testing.T) {
+	cases := []struct {
+		value    string
+		versions map[string]bool
+	}{
+		{"~1.2.3", map[string]bool{"1.2.3": true, "1.2.10": true, "1.2.10-beta": false, "1.3.0": false, "1.2.2": false}},
+		{"^1.2.3", map[string]bool{"1.9.0": true, "2.0.0": false, "2.0.0-beta": false, "1.2.2": false}},
+		{"=1.0.0 || 3.0.0", map[string]bool{"1.0.0": true, "3.0.0": true, "2.0.0": false, "x": false}},
+	}
+	for _, c := range cases {
+		ctx, _ := newRequestContext("version=" + url.QueryEscape(c.value))
+		r, err := getVersionRange(ctx)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", c.value, err)
+			continue
+		}
+		for version, matched := range c.versions {
+			if r.Match(version) != matched {
+				t.Errorf("%q: expected %s to match: %v", c.value, version, matched)
+			}
+		}
+	}
+	if !versionRange(nil).Match("x") {
+		t.Errorf("empty range should match all versions")
+	}
+}
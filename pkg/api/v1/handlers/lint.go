@@ -0,0 +1,88 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package handlers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/caicloud/helm-registry/pkg/api/models"
+	"github.com/caicloud/helm-registry/pkg/common"
+	"github.com/caicloud/helm-registry/pkg/errors"
+	"github.com/caicloud/helm-registry/pkg/lint"
+	"github.com/caicloud/helm-registry/pkg/storage"
+)
+
+// LintChart lints an uploaded chart package without storing it. Findings are always
+// reported even if linting is off in the space.
+func LintChart(ctx context.Context) (*models.LintResult, error) {
+	spaceName, err := getSpaceName(ctx)
+	if err != nil {
+		return nil, err
+	}
+	data, err := getChartFileData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	space, err := common.GetSpace(ctx, spaceName)
+	if err != nil {
+		return nil, err
+	}
+	properties, err := lintProperties(ctx, space)
+	if err != nil {
+		return nil, err
+	}
+	report, err := lintArchive(data)
+	if err != nil {
+		return nil, err
+	}
+	return &models.LintResult{
+		Mode:     properties.Lint,
+		Accepted: properties.Lint != storage.LintError || len(report.Errors()) <= 0,
+		Findings: report.Findings,
+	}, nil
+}
+
+// lintPackage lints a chart package by the lint mode of space. It returns findings if the
+// package can be stored, or an error with error findings if the package is rejected.
+func lintPackage(ctx context.Context, space storage.Space, resource string, data []byte) ([]lint.Finding, error) {
+	properties, err := lintProperties(ctx, space)
+	if err != nil {
+		return nil, err
+	}
+	if properties.Lint == storage.LintOff {
+		return nil, nil
+	}
+	report, err := lintArchive(data)
+	if err != nil {
+		return nil, err
+	}
+	if errs := report.Errors(); properties.Lint == storage.LintError && len(errs) > 0 {
+		messages := make([]string, len(errs))
+		for i, finding := range errs {
+			messages[i] = finding.String()
+		}
+		return nil, errors.ErrorUnprocessableEntity.Format(resource, "lint failed: "+strings.Join(messages, "; "))
+	}
+	return report.Findings, nil
+}
+
+// lintProperties returns properties of space for linting. A space which doesn't exist has
+// default properties, because storing a chart package creates the space.
+func lintProperties(ctx context.Context, space storage.Space) (*storage.SpaceProperties, error) {
+	if !space.Exists(ctx) {
+		return storage.NewSpaceProperties(), nil
+	}
+	return space.Properties(ctx)
+}
+
+// lintArchive lints a chart package
+func lintArchive(data []byte) (*lint.Report, error) {
+	report, err := lint.Archive(data)
+	if err != nil {
+		return nil, errors.ErrorParamTypeError.Format(common.HTTPRequestUploadFileName, "chart", "unknown")
+	}
+	return report, nil
+}
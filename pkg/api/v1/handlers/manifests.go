@@ -7,27 +7,36 @@ package handlers
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
 
+	"github.com/caicloud/helm-registry/pkg/api/definition"
 	"github.com/caicloud/helm-registry/pkg/common"
 	"github.com/caicloud/helm-registry/pkg/errors"
 	"github.com/caicloud/helm-registry/pkg/orchestration"
 	"github.com/caicloud/helm-registry/pkg/storage"
+	"github.com/emicklei/go-restful"
 	"github.com/ghodss/yaml"
 	"k8s.io/helm/pkg/chartutil"
 )
 
-// ListMetadataInSpace lists all metadata in a space
-func ListMetadataInSpace(ctx context.Context) (int, []*storage.Metadata, error) {
+// ListMetadataInSpace lists all metadata in a space. Metadata is filtered and sorted by
+// query parameters.
+func ListMetadataInSpace(ctx context.Context) (int, interface{}, error) {
 	spaceName, err := getSpaceName(ctx)
 	if err != nil {
 		return 0, nil, err
 	}
-	start, limit, err := getPaging(ctx)
+	space, err := common.GetSpace(ctx, spaceName)
 	if err != nil {
 		return 0, nil, err
 	}
-	space, err := common.GetSpace(ctx, spaceName)
+	yanked, err := includeYanked(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	filter, err := getMetadataFilter(ctx)
 	if err != nil {
 		return 0, nil, err
 	}
@@ -35,22 +44,25 @@ func ListMetadataInSpace(ctx context.Context) (int, []*storage.Metadata, error)
 	if err != nil {
 		return 0, nil, err
 	}
-	total := len(metadata)
-	start, end := standardizeRange(total, start, limit)
-	return total, metadata[start:end], nil
+	if !yanked {
+		metadata = filterYankedMetadata(metadata)
+	}
+	metadata = filter.Filter(metadata)
+	return listMetadata(ctx, metadata)
 }
 
-// ListLatestMetadataInSpace lists all metadata of the latest version of charts in space
-func ListLatestMetadataInSpace(ctx context.Context) (int, []*storage.Metadata, error) {
+// ListLatestMetadataInSpace lists all metadata of the latest version of charts in space.
+// For each chart, the latest version which matches the filter is listed.
+func ListLatestMetadataInSpace(ctx context.Context) (int, interface{}, error) {
 	spaceName, err := getSpaceName(ctx)
 	if err != nil {
 		return 0, nil, err
 	}
-	start, limit, err := getPaging(ctx)
+	space, err := common.GetSpace(ctx, spaceName)
 	if err != nil {
 		return 0, nil, err
 	}
-	space, err := common.GetSpace(ctx, spaceName)
+	filter, err := getMetadataFilter(ctx)
 	if err != nil {
 		return 0, nil, err
 	}
@@ -60,29 +72,36 @@ func ListLatestMetadataInSpace(ctx context.Context) (int, []*storage.Metadata, e
 	}
 	metadata := make([]*storage.Metadata, 0, len(chartNames))
 	for _, chartName := range chartNames {
-		md, err := getLatestMetadata(ctx, spaceName, chartName)
+		if !strings.HasPrefix(chartName, filter.prefix) {
+			continue
+		}
+		md, err := getLatestMetadata(ctx, spaceName, chartName, filter)
 		if err != nil {
 			return 0, nil, err
 		}
-		metadata = append(metadata, md)
+		if md != nil {
+			metadata = append(metadata, md)
+		}
 	}
-
-	total := len(metadata)
-	start, end := standardizeRange(total, start, limit)
-	return total, metadata[start:end], nil
+	return listMetadata(ctx, metadata)
 }
 
-// ListMetadataInChart lists all metadata in a chart
-func ListMetadataInChart(ctx context.Context) (int, []*storage.Metadata, error) {
+// ListMetadataInChart lists all metadata in a chart. Metadata is filtered and sorted by
+// query parameters.
+func ListMetadataInChart(ctx context.Context) (int, interface{}, error) {
 	spaceName, chartName, err := getSpaceAndChartName(ctx)
 	if err != nil {
 		return 0, nil, err
 	}
-	start, limit, err := getPaging(ctx)
+	chart, err := common.GetChart(ctx, spaceName, chartName)
 	if err != nil {
 		return 0, nil, err
 	}
-	chart, err := common.GetChart(ctx, spaceName, chartName)
+	yanked, err := includeYanked(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	filter, err := getMetadataFilter(ctx)
 	if err != nil {
 		return 0, nil, err
 	}
@@ -91,9 +110,23 @@ func ListMetadataInChart(ctx context.Context) (int, []*storage.Metadata, error)
 	if err != nil {
 		return 0, nil, err
 	}
-	total := len(metadata)
-	start, end := standardizeRange(total, start, limit)
-	return total, metadata[start:end], nil
+	if !yanked {
+		metadata = filterYankedMetadata(metadata)
+	}
+	metadata = filter.Filter(metadata)
+	return listMetadata(ctx, metadata)
+}
+
+// listMetadata sorts metadata and selects a page of metadata
+func listMetadata(ctx context.Context, metadata []*storage.Metadata) (int, interface{}, error) {
+	if err := sortMetadata(ctx, metadata); err != nil {
+		return 0, nil, err
+	}
+	order, err := metadataOrder(ctx, metadata)
+	if err != nil {
+		return 0, nil, err
+	}
+	return pageItems(ctx, metadata, order)
 }
 
 // GetLatestMetadataInChart gets metadata of the latest version in a chart
@@ -102,25 +135,61 @@ func GetLatestMetadataInChart(ctx context.Context) (metadata *storage.Metadata,
 	if err != nil {
 		return nil, err
 	}
-	return getLatestMetadata(ctx, spaceName, chartName)
+	metadata, err = getLatestMetadata(ctx, spaceName, chartName, nil)
+	if err == nil && metadata == nil {
+		err = errors.ErrorContentNotFound.Format(fmt.Sprintf("available versions of %s/%s", spaceName, chartName))
+	}
+	return
 }
 
-// FetchMetadata fetches metadata of specified version
+// FetchMetadata fetches metadata of specified version. Metadata is in json or yaml by Accept.
+// The entity tag of response is the digest of the metadata
 func FetchMetadata(ctx context.Context) (metadata *storage.Metadata, err error) {
 	err = managerHelper(ctx, func(space storage.Space, chart storage.Chart, version storage.Version) error {
 		metadata, err = version.Metadata(ctx)
-		return err
+		if err != nil {
+			return err
+		}
+		if err = warnDeprecatedState(ctx, space, chart, version, metadata.State); err != nil {
+			return err
+		}
+		etag, err := metadataETag(metadata)
+		if err != nil {
+			return err
+		}
+		if err = addResponseHeader(ctx, "Vary", "Accept"); err != nil {
+			return err
+		}
+		modified, err := versionModified(ctx, version, lastModified(metadata.Record, metadata.State))
+		if err != nil {
+			return err
+		}
+		return checkConditions(ctx, fmt.Sprintf("metadata of %s/%s/%s", space.Name(), chart.Name(), version.Number()),
+			negotiatedETag(ctx, etag), modified)
 	})
 	return
 }
 
-// UpdateMetadata updates metadata
+// UpdateMetadata updates metadata. Metadata can be in json or yaml. If the request has If-Match,
+// metadata is updated only if it matches the entity tag of current metadata.
 func UpdateMetadata(ctx context.Context) (metadata *storage.Metadata, err error) {
 	err = managerHelper(ctx, func(space storage.Space, chart storage.Chart, version storage.Version) error {
 		md, err := getMetadata(ctx)
 		if err != nil {
 			return err
 		}
+		current, err := version.Metadata(ctx)
+		if err != nil {
+			return err
+		}
+		etag, err := metadataETag(current)
+		if err != nil {
+			return err
+		}
+		resource := fmt.Sprintf("metadata of %s/%s/%s", space.Name(), chart.Name(), version.Number())
+		if err = checkPreconditions(ctx, resource, etag, yamlETag(etag)); err != nil {
+			return err
+		}
 		data, err := version.GetContent(ctx)
 		if err != nil {
 			return err
@@ -137,38 +206,96 @@ func UpdateMetadata(ctx context.Context) (metadata *storage.Metadata, err error)
 			return errors.ErrorParamValueError.Format("version", origin.Metadata.Version, md.Version)
 		}
 		*origin.Metadata = md.Metadata
+		// the package must not be changed by others before storing
+		options, err := getPutOptions(ctx, storage.SourceUpload)
+		if err != nil {
+			return err
+		}
+		options.Digest = storage.Digest(data)
 		data, err = orchestration.Archive(origin)
 		if err != nil {
 			return err
 		}
-		err = version.PutContent(ctx, data)
+		err = version.PutContent(ctx, data, options)
 		if err != nil {
 			return err
 		}
-		metadata, err = storage.CoalesceMetadata(origin)
-		return err
+		metadata, err = version.Metadata(ctx)
+		if err != nil {
+			return err
+		}
+		etag, err = metadataETag(metadata)
+		if err != nil {
+			return err
+		}
+		return addResponseHeader(ctx, "ETag", negotiatedETag(ctx, etag))
 	})
 	return
 }
 
-// FetchValues fetches values of specified version
+// FetchValues fetches values of specified version. If the request accepts yaml, the original
+// values.yaml of the chart is returned. Otherwise coalesced values are returned in json.
+// The entity tag of response is the digest of the values
 func FetchValues(ctx context.Context) (data []byte, err error) {
 	err = managerHelper(ctx, func(space storage.Space, chart storage.Chart, version storage.Version) error {
-		data, err = version.Values(ctx)
-		return err
+		if acceptsYAML(ctx) {
+			content, err := version.GetContent(ctx)
+			if err != nil {
+				return err
+			}
+			origin, err := chartutil.LoadArchive(bytes.NewReader(content))
+			if err != nil {
+				return errors.ErrorInternalTypeError.Format(
+					fmt.Sprintf("%s/%s", chart.Name(), version.Number()), "chart", "unknown")
+			}
+			data = rawValues(origin)
+			if err = addResponseHeader(ctx, "Content-Type", definition.MIMEYAML); err != nil {
+				return err
+			}
+		} else {
+			data, err = version.Values(ctx)
+			if err != nil {
+				return err
+			}
+			if err = addResponseHeader(ctx, "Content-Type", restful.MIME_JSON); err != nil {
+				return err
+			}
+		}
+		if err = warnDeprecatedVersion(ctx, space, chart, version); err != nil {
+			return err
+		}
+		record, err := version.Record(ctx)
+		if err != nil {
+			return err
+		}
+		if err = addResponseHeader(ctx, "Vary", "Accept"); err != nil {
+			return err
+		}
+		modified, err := versionModified(ctx, version, lastModified(record, nil))
+		if err != nil {
+			return err
+		}
+		return checkConditions(ctx, fmt.Sprintf("values of %s/%s/%s", space.Name(), chart.Name(), version.Number()),
+			dataETag(data), modified)
 	})
 	return
 }
 
-// UpdateValues updates values
+// UpdateValues updates values. Values in yaml are stored as values.yaml as they are, so comments
+// and the order of keys are kept. Values in json only edit changed keys of values.yaml, and are
+// unprocessable if values.yaml can't be edited without losing its content. If the request has
+// If-Match, values are updated only if it matches the entity tag of current values.
 func UpdateValues(ctx context.Context) (values []byte, err error) {
 	err = managerHelper(ctx, func(space storage.Space, chart storage.Chart, version storage.Version) error {
 		values, err = getValues(ctx)
 		if err != nil {
 			return err
 		}
-		yamlValues, err := yaml.JSONToYAML(values)
-		if err != nil {
+		if isYAMLRequest(ctx) {
+			if _, err = yaml.YAMLToJSON(values); err != nil {
+				return errors.ErrorParamTypeError.Format("values", "yaml", "unknown")
+			}
+		} else if !json.Valid(values) {
 			return errors.ErrorParamTypeError.Format("values", "json", "unknown")
 		}
 		data, err := version.GetContent(ctx)
@@ -180,36 +307,153 @@ func UpdateValues(ctx context.Context) (values []byte, err error) {
 			return errors.ErrorInternalTypeError.Format(
 				fmt.Sprintf("%s/%s", chart.Name(), version.Number()), "chart", "unknown")
 		}
-		origin.Values.Raw = string(yamlValues)
+		current, err := coalescedValues(origin)
+		if err != nil {
+			return err
+		}
+		resource := fmt.Sprintf("values of %s/%s/%s", space.Name(), chart.Name(), version.Number())
+		if err = checkPreconditions(ctx, resource, dataETag(current), dataETag(rawValues(origin))); err != nil {
+			return err
+		}
+		if isYAMLRequest(ctx) {
+			setRawValues(origin, values)
+		} else if err = updateValues(origin, values); err != nil {
+			return err
+		}
+		yamlValues := rawValues(origin)
+		// the package must not be changed by others before storing
+		options, err := getPutOptions(ctx, storage.SourceUpload)
+		if err != nil {
+			return err
+		}
+		options.Digest = storage.Digest(data)
 		data, err = orchestration.Archive(origin)
 		if err != nil {
 			return err
 		}
-		err = version.PutContent(ctx, data)
+		err = version.PutContent(ctx, data, options)
+		if err != nil {
+			return err
+		}
+		if acceptsYAML(ctx) {
+			values = yamlValues
+			if err = addResponseHeader(ctx, "Content-Type", definition.MIMEYAML); err != nil {
+				return err
+			}
+			return addResponseHeader(ctx, "ETag", dataETag(values))
+		}
+		current, err = version.Values(ctx)
 		if err != nil {
 			return err
 		}
-		return err
+		return addResponseHeader(ctx, "ETag", dataETag(current))
 	})
 	return
 }
 
-// getLatestMetadata gets latest metadata in a chart
-func getLatestMetadata(ctx context.Context, spaceName, chartName string) (metadata *storage.Metadata, err error) {
-	chart, err := common.GetChart(ctx, spaceName, chartName)
-	if err != nil {
-		return nil, err
-	}
-	versionNumbers, err := chart.List(ctx)
-	if err != nil {
-		return nil, err
-	}
-	if len(versionNumbers) <= 0 {
-		return nil, errors.ErrorContentNotFound.Format("metadata")
-	}
-	version, err := chart.Version(ctx, versionNumbers[len(versionNumbers)-1])
-	if err != nil {
-		return nil, err
-	}
-	return version.Metadata(ctx)
+// PatchMetadata applies a JSON Merge Patch or a JSON Patch to metadata. The patch is applied
+// under the lock of the version. If the request has If-Match, the patch is applied only if
+// it matches the entity tag of current metadata.
+func PatchMetadata(ctx context.Context) (metadata *storage.Metadata, err error) {
+	err = managerHelper(ctx, func(space storage.Space, chart storage.Chart, version storage.Version) error {
+		contentType, p, err := getPatch(ctx)
+		if err != nil {
+			return err
+		}
+		options, err := getPutOptions(ctx, storage.SourceUpload)
+		if err != nil {
+			return err
+		}
+		conditional, err := hasPreconditions(ctx)
+		if err != nil {
+			return err
+		}
+		if conditional {
+			current, err := version.Metadata(ctx)
+			if err != nil {
+				return err
+			}
+			etag, err := metadataETag(current)
+			if err != nil {
+				return err
+			}
+			resource := fmt.Sprintf("metadata of %s/%s/%s", space.Name(), chart.Name(), version.Number())
+			if err = checkPreconditions(ctx, resource, etag, yamlETag(etag)); err != nil {
+				return err
+			}
+			// the package must not be changed by others before patching
+			if current.Record != nil {
+				options.Digest = current.Record.Digest
+			}
+		}
+		err = version.UpdateContent(ctx, func(data []byte) ([]byte, error) {
+			origin, err := chartutil.LoadArchive(bytes.NewReader(data))
+			if err != nil {
+				return nil, errors.ErrorInternalTypeError.Format(
+					fmt.Sprintf("%s/%s", chart.Name(), version.Number()), "chart", "unknown")
+			}
+			if err = patchMetadata(origin, contentType, p); err != nil {
+				return nil, err
+			}
+			return orchestration.Archive(origin)
+		}, options)
+		if err != nil {
+			return err
+		}
+		metadata, err = version.Metadata(ctx)
+		if err != nil {
+			return err
+		}
+		etag, err := metadataETag(metadata)
+		if err != nil {
+			return err
+		}
+		return addResponseHeader(ctx, "ETag", negotiatedETag(ctx, etag))
+	})
+	return
+}
+
+// PatchValues applies a JSON Merge Patch or a JSON Patch to values. The patch is applied
+// under the lock of the version. If the request has If-Match, the patch is applied only if
+// it matches the entity tag of current values.
+func PatchValues(ctx context.Context) (values []byte, err error) {
+	err = managerHelper(ctx, func(space storage.Space, chart storage.Chart, version storage.Version) error {
+		contentType, p, err := getPatch(ctx)
+		if err != nil {
+			return err
+		}
+		options, err := getPutOptions(ctx, storage.SourceUpload)
+		if err != nil {
+			return err
+		}
+		resource := fmt.Sprintf("values of %s/%s/%s", space.Name(), chart.Name(), version.Number())
+		err = version.UpdateContent(ctx, func(data []byte) ([]byte, error) {
+			origin, err := chartutil.LoadArchive(bytes.NewReader(data))
+			if err != nil {
+				return nil, errors.ErrorInternalTypeError.Format(
+					fmt.Sprintf("%s/%s", chart.Name(), version.Number()), "chart", "unknown")
+			}
+			// values of the locked package are exactly the current values
+			current, err := coalescedValues(origin)
+			if err != nil {
+				return nil, err
+			}
+			if err = checkPreconditions(ctx, resource, dataETag(current), dataETag(rawValues(origin))); err != nil {
+				return nil, err
+			}
+			if err = patchValues(origin, contentType, p); err != nil {
+				return nil, err
+			}
+			return orchestration.Archive(origin)
+		}, options)
+		if err != nil {
+			return err
+		}
+		values, err = version.Values(ctx)
+		if err != nil {
+			return err
+		}
+		return addResponseHeader(ctx, "ETag", dataETag(values))
+	})
+	return
 }
@@ -0,0 +1,226 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package handlers
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/caicloud/helm-registry/pkg/api/definition"
+	"github.com/caicloud/helm-registry/pkg/errors"
+	"github.com/caicloud/helm-registry/pkg/storage"
+)
+
+// continueParameter is the query parameter of continuation token
+const continueParameter = "continue"
+
+// keyComparer compares two sort keys of items
+type keyComparer func(a, b string) int
+
+// listOrder describes the order of items in a list. Items are sorted by their keys, and
+// keys of an item must identify the item in the list.
+type listOrder struct {
+	// sort is the sort parameter which produces the order
+	sort string
+	// desc means items are in descending order of keys
+	desc bool
+	// comparers compare keys one by one
+	comparers []keyComparer
+	// keys returns sort keys of the i-th item
+	keys func(i int) []string
+}
+
+// compare compares two lists of keys by the order
+func (o *listOrder) compare(a, b []string) int {
+	for i, compare := range o.comparers {
+		if result := compare(a[i], b[i]); result != 0 {
+			if o.desc {
+				return -result
+			}
+			return result
+		}
+	}
+	return 0
+}
+
+// listCursor is the position of the last item of a page. It's encoded to a continuation
+// token. Items after the position are still found correctly even if the item was deleted.
+type listCursor struct {
+	Sort string   `json:"sort,omitempty"`
+	Keys []string `json:"keys"`
+}
+
+// encodeCursor encodes a cursor to a continuation token
+func encodeCursor(cursor *listCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor decodes a continuation token to a cursor
+func decodeCursor(token string) (*listCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+	cursor := &listCursor{}
+	if err = json.Unmarshal(data, cursor); err != nil {
+		return nil, err
+	}
+	return cursor, nil
+}
+
+// pageItems selects a page of items by paging info. items must be a slice which is sorted
+// by order. If the request has a continuation token, the page starts after the position of
+// the token and start is ignored. If there are more items after the page, a continuation
+// token is set to the request. It returns original length of items and selected items.
+func pageItems(ctx context.Context, items interface{}, order *listOrder) (int, interface{}, error) {
+	start, limit, err := getPaging(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	request, err := getRequestFromContext(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	value := reflect.ValueOf(items)
+	total := value.Len()
+	if token := request.QueryParameter(continueParameter); len(token) > 0 {
+		cursor, err := decodeCursor(token)
+		if err != nil || cursor.Sort != order.sort || len(cursor.Keys) != len(order.comparers) {
+			return 0, nil, errors.ErrorInvalidParam.Format(continueParameter, "malformed token or mismatched sort order")
+		}
+		start = sort.Search(total, func(i int) bool {
+			return order.compare(order.keys(i), cursor.Keys) > 0
+		})
+	}
+	start, end := standardizeRange(total, start, limit)
+	if start < end && end < total {
+		request.SetAttribute(definition.AttributeContinue, encodeCursor(&listCursor{
+			Sort: order.sort,
+			Keys: order.keys(end - 1),
+		}))
+	}
+	return total, value.Slice(start, end).Interface(), nil
+}
+
+// nameOrder creates an order of items sorted by names
+func nameOrder(name func(i int) string) *listOrder {
+	return &listOrder{
+		comparers: []keyComparer{strings.Compare},
+		keys: func(i int) []string {
+			return []string{name(i)}
+		},
+	}
+}
+
+// indexOrder creates an order of items sorted by their indexes. It's only available for
+// lists which only append items.
+func indexOrder() *listOrder {
+	return &listOrder{
+		comparers: []keyComparer{compareIntegers},
+		keys: func(i int) []string {
+			return []string{strconv.Itoa(i)}
+		},
+	}
+}
+
+// metadataOrder creates an order of metadata which is sorted by the sort parameter
+func metadataOrder(ctx context.Context, metadata []*storage.Metadata) (*listOrder, error) {
+	key, desc, err := getSortParameter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	order := &listOrder{sort: sortValue(key, desc), desc: desc}
+	switch key {
+	case sortByVersion:
+		order.comparers = []keyComparer{compareVersionNumbers, strings.Compare}
+		order.keys = func(i int) []string {
+			return []string{metadata[i].Version, metadata[i].Name}
+		}
+	case sortByCreated, sortByUpdated:
+		order.comparers = []keyComparer{compareTimes, strings.Compare, compareVersionNumbers}
+		order.keys = func(i int) []string {
+			return []string{formatTime(recordTime(metadata[i].Record, key)), metadata[i].Name, metadata[i].Version}
+		}
+	default:
+		order.comparers = []keyComparer{strings.Compare, compareVersionNumbers}
+		order.keys = func(i int) []string {
+			return []string{metadata[i].Name, metadata[i].Version}
+		}
+	}
+	return order, nil
+}
+
+// versionOrder creates an order of versions in a chart which is sorted by the sort parameter.
+// version returns the version number of the i-th item and record returns its record.
+func versionOrder(ctx context.Context, version func(i int) string, record func(i int) *storage.Record) (*listOrder, error) {
+	key, desc, err := getSortParameter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	order := &listOrder{sort: sortValue(key, desc), desc: desc}
+	switch key {
+	case sortByCreated, sortByUpdated:
+		order.comparers = []keyComparer{compareTimes, compareVersionNumbers}
+		order.keys = func(i int) []string {
+			return []string{formatTime(recordTime(record(i), key)), version(i)}
+		}
+	default:
+		order.comparers = []keyComparer{compareVersionNumbers}
+		order.keys = func(i int) []string {
+			return []string{version(i)}
+		}
+	}
+	return order, nil
+}
+
+// sortValue returns the value of sort parameter
+func sortValue(key string, desc bool) string {
+	if desc {
+		return "-" + key
+	}
+	return key
+}
+
+// formatTime formats a time to a sort key
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// compareTimes compares two times which are formatted by formatTime
+func compareTimes(a, b string) int {
+	ta, errA := time.Parse(time.RFC3339Nano, a)
+	tb, errB := time.Parse(time.RFC3339Nano, b)
+	switch {
+	case errA != nil || errB != nil:
+		return strings.Compare(a, b)
+	case ta.Before(tb):
+		return -1
+	case ta.After(tb):
+		return 1
+	}
+	return 0
+}
+
+// compareIntegers compares two integers
+func compareIntegers(a, b string) int {
+	ia, errA := strconv.Atoi(a)
+	ib, errB := strconv.Atoi(b)
+	switch {
+	case errA != nil || errB != nil:
+		return strings.Compare(a, b)
+	case ia < ib:
+		return -1
+	case ia > ib:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package handlers
+
+import (
+	"encoding/base64"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/caicloud/helm-registry/pkg/api/definition"
+	"github.com/emicklei/go-restful"
+)
+
+// continueToken returns the continuation token of request
+func continueToken(request *restful.Request) string {
+	token, _ := request.Attribute(definition.AttributeContinue).(string)
+	return token
+}
+
+// page selects a page of names by query. It returns the page and its continuation token.
+func page(t *testing.T, names []string, desc bool, query string) ([]string, string) {
+	ctx, request := newRequestContext(query)
+	order := nameOrder(func(i int) string { return names[i] })
+	if desc {
+		order.sort = sortValue(sortByName, true)
+		order.desc = true
+	}
+	total, items, err := pageItems(ctx, names, order)
+	if err != nil {
+		t.Fatalf("%s: unexpected error: %v", query, err)
+	}
+	if total != len(names) {
+		t.Errorf("%s: expected total %d, got %d", query, len(names), total)
+	}
+	return items.([]string), continueToken(request)
+}
+
+func TestPageItems(t *testing.T) {
+	names := []string{"a", "b", "c", "d", "e"}
+	cases := []struct {
+		query    string
+		items    []string
+		hasToken bool
+	}{
+		{"", names, false},
+		{"limit=2", []string{"a", "b"}, true},
+		{"start=3&limit=2", []string{"d", "e"}, false},
+		{"start=4&limit=2", []string{"e"}, false},
+		{"start=5", []string{}, false},
+		{"start=-1", []string{}, false},
+		{"limit=0", []string{}, false},
+	}
+	for _, c := range cases {
+		items, token := page(t, names, false, c.query)
+		if !reflect.DeepEqual(items, c.items) {
+			t.Errorf("%q: expected %v, got %v", c.query, c.items, items)
+		}
+		if (len(token) > 0) != c.hasToken {
+			t.Errorf("%q: unexpected continuation token %q", c.query, token)
+		}
+	}
+}
+
+func TestPageItemsContinue(t *testing.T) {
+	names := []string{"a", "b", "c", "d", "e"}
+	items, token := page(t, names, false, "limit=2")
+	pages := [][]string{items}
+	for len(token) > 0 && len(pages) <= len(names) {
+		// start is ignored if there is a token
+		items, token = page(t, names, false, "limit=2&start=1&continue="+token)
+		pages = append(pages, items)
+	}
+	expected := [][]string{{"a", "b"}, {"c", "d"}, {"e"}}
+	if !reflect.DeepEqual(pages, expected) {
+		t.Fatalf("expected %v, got %v", expected, pages)
+	}
+
+	// the next page starts after the position of the token even if items are deleted
+	_, token = page(t, names, false, "limit=2")
+	items, _ = page(t, []string{"a", "d", "e"}, false, "limit=2&continue="+token)
+	if !reflect.DeepEqual(items, []string{"d", "e"}) {
+		t.Fatalf("expected items after b, got %v", items)
+	}
+
+	// descending order
+	desc := []string{"e", "d", "c", "b", "a"}
+	items, token = page(t, desc, true, "limit=3")
+	if !reflect.DeepEqual(items, []string{"e", "d", "c"}) {
+		t.Fatalf("unexpected first page %v", items)
+	}
+	items, token = page(t, desc, true, "limit=3&continue="+token)
+	if !reflect.DeepEqual(items, []string{"b", "a"}) || len(token) > 0 {
+		t.Fatalf("unexpected last page %v with token %q", items, token)
+	}
+}
+
+func TestPageItemsErrors(t *testing.T) {
+	names := []string{"a", "b", "c"}
+	token := func(data string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(data))
+	}
+	cases := []struct {
+		name, query, message string
+	}{
+		{"invalid start", "start=x", "start"},
+		{"invalid limit", "limit=x", "limit"},
+		{"invalid base64", "continue=%21%21", continueParameter},
+		{"padded base64", "continue=" + base64.URLEncoding.EncodeToString([]byte(`{"keys":["a"]}`)), continueParameter},
+		{"invalid json", "continue=" + token(`{"keys":`), continueParameter},
+		{"null", "continue=" + token(`null`), continueParameter},
+		{"invalid keys", "continue=" + token(`{"keys":"a"}`), continueParameter},
+		{"missing keys", "continue=" + token(`{}`), continueParameter},
+		{"too many keys", "continue=" + token(`{"keys":["a","b"]}`), continueParameter},
+		{"mismatched sort", "continue=" + token(`{"sort":"-name","keys":["a"]}`), continueParameter},
+	}
+	for _, c := range cases {
+		ctx, _ := newRequestContext(c.query)
+		_, _, err := pageItems(ctx, names, nameOrder(func(i int) string { return names[i] }))
+		if err == nil {
+			t.Errorf("%s: expected an error", c.name)
+			continue
+		}
+		if !strings.Contains(err.Error(), c.message) {
+			t.Errorf("%s: expected an error with %q, got %q", c.name, c.message, err.Error())
+		}
+	}
+}
+
+func TestCompareKeys(t *testing.T) {
+	cases := []struct {
+		name    string
+		compare keyComparer
+		a, b    string
+		result  int
+	}{
+		{"integers", compareIntegers, "9", "10", -1},
+		{"equal integers", compareIntegers, "10", "10", 0},
+		{"invalid integers", compareIntegers, "b", "a", 1},
+		{"times", compareTimes, "2017-01-02T00:00:00Z", "2017-01-01T23:00:00-02:00", -1},
+		{"equal times", compareTimes, "2017-01-02T00:00:00Z", "2017-01-02T08:00:00+08:00", 0},
+		{"invalid times", compareTimes, "x", "2017-01-02T00:00:00Z", 1},
+		{"versions", compareVersionNumbers, "1.10.0", "1.9.0", 1},
+		{"invalid versions", compareVersionNumbers, "x", "0.0.1", -1},
+	}
+	for _, c := range cases {
+		if result := c.compare(c.a, c.b); result != c.result {
+			t.Errorf("%s: expected %d, got %d", c.name, c.result, result)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/caicloud/helm-registry/pkg/errors"
+	"github.com/caicloud/helm-registry/pkg/patch"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+// applyPatch applies a patch to a JSON document. A malformed patch is a bad request, and
+// a patch which can't be applied to the document is an unprocessable entity.
+func applyPatch(contentType string, doc []byte, p []byte) ([]byte, error) {
+	result, err := patch.Apply(contentType, doc, p)
+	if err != nil {
+		if e, ok := err.(*patch.Error); ok && e.Index < 0 {
+			return nil, errors.ErrorInvalidParam.Format("patch", err)
+		}
+		return nil, errors.ErrorUnprocessableEntity.Format("patch", err)
+	}
+	return result, nil
+}
+
+// patchMetadata applies a patch to metadata of a chart. Name and version of the chart
+// can't be changed.
+func patchMetadata(origin *chart.Chart, contentType string, p []byte) error {
+	doc, err := json.Marshal(origin.Metadata)
+	if err != nil {
+		return errors.ErrorInternalUnknown.Format(err)
+	}
+	doc, err = applyPatch(contentType, doc, p)
+	if err != nil {
+		return err
+	}
+	metadata := &chart.Metadata{}
+	decoder := json.NewDecoder(bytes.NewReader(doc))
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(metadata); err != nil {
+		return errors.ErrorUnprocessableEntity.Format("patch", err)
+	}
+	if metadata.Name != origin.Metadata.Name {
+		return errors.ErrorParamValueError.Format("name", origin.Metadata.Name, metadata.Name)
+	}
+	if metadata.Version != origin.Metadata.Version {
+		return errors.ErrorParamValueError.Format("version", origin.Metadata.Version, metadata.Version)
+	}
+	origin.Metadata = metadata
+	return nil
+}
+
+// patchValues applies a patch to values of a chart. The patch is applied to coalesced
+// values, and only keys changed by the patch are edited in values.yaml.
+func patchValues(origin *chart.Chart, contentType string, p []byte) error {
+	doc, err := coalescedValues(origin)
+	if err != nil {
+		return err
+	}
+	doc, err = applyPatch(contentType, doc, p)
+	if err != nil {
+		return err
+	}
+	return updateValues(origin, doc)
+}
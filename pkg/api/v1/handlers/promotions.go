@@ -0,0 +1,198 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/caicloud/helm-registry/pkg/api/models"
+	"github.com/caicloud/helm-registry/pkg/common"
+	"github.com/caicloud/helm-registry/pkg/errors"
+	"github.com/caicloud/helm-registry/pkg/lint"
+	"github.com/caicloud/helm-registry/pkg/log"
+	"github.com/caicloud/helm-registry/pkg/storage"
+)
+
+// PromoteVersion copies a version of chart to the target space
+func PromoteVersion(ctx context.Context) (link *models.ChartLink, err error) {
+	err = managerHelper(ctx, func(space storage.Space, chart storage.Chart, version storage.Version) error {
+		target, history, err := getPromotionParameters(ctx, space)
+		if err != nil {
+			return err
+		}
+		links, err := promote(ctx, space, chart, []string{version.Number()}, target, history)
+		if err != nil {
+			return err
+		}
+		link = links[0]
+		return nil
+	})
+	return
+}
+
+// PromoteChart copies specified versions of chart to the target space. If no version is
+// specified, all versions which are not yanked are copied.
+func PromoteChart(ctx context.Context) ([]*models.ChartLink, error) {
+	spaceName, chartName, err := getSpaceAndChartName(ctx)
+	if err != nil {
+		return nil, err
+	}
+	space, chart, err := common.GetSpaceAndChart(ctx, spaceName, chartName)
+	if err != nil {
+		return nil, err
+	}
+	target, history, err := getPromotionParameters(ctx, space)
+	if err != nil {
+		return nil, err
+	}
+	var versions []string
+	value, err := getQueryParameter(ctx, "versions")
+	if err == nil {
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); len(v) > 0 {
+				versions = append(versions, v)
+			}
+		}
+	} else {
+		versions, err = unyankedVersions(ctx, chart)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(versions) <= 0 {
+		return nil, errors.ErrorContentNotFound.Format(fmt.Sprintf("versions of %s/%s", space.Name(), chart.Name()))
+	}
+	return promote(ctx, space, chart, versions, target, history)
+}
+
+// unyankedVersions returns numbers of all versions of chart which are not yanked
+func unyankedVersions(ctx context.Context, chart storage.Chart) ([]string, error) {
+	numbers, err := chart.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]string, 0, len(numbers))
+	for _, number := range numbers {
+		version, err := chart.Version(ctx, number)
+		if err != nil {
+			return nil, err
+		}
+		state, err := version.State(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !state.Yanked() {
+			versions = append(versions, number)
+		}
+	}
+	return versions, nil
+}
+
+// getPromotionParameters gets the target space and whether to record history
+func getPromotionParameters(ctx context.Context, space storage.Space) (storage.Space, bool, error) {
+	targetName, err := getQueryParameter(ctx, "target")
+	if err != nil {
+		return nil, false, err
+	}
+	if targetName == space.Name() {
+		return nil, false, errors.ErrorInvalidParam.Format("target", "can't promote charts to the same space")
+	}
+	target, err := common.GetSpace(ctx, targetName)
+	if err != nil {
+		return nil, false, err
+	}
+	if !target.Exists(ctx) {
+		return nil, false, errors.ErrorContentNotFound.Format(targetName)
+	}
+	history, err := getBoolQueryParameter(ctx, "history")
+	if err != nil {
+		return nil, false, err
+	}
+	return target, history, nil
+}
+
+// promote copies versions of chart to the target space. All versions must not exist in the
+// target space, and their packages are linted by the lint mode of the target space. If history
+// is true, every promotion is recorded in the history of target chart.
+func promote(ctx context.Context, space storage.Space, chart storage.Chart, versions []string,
+	target storage.Space, history bool) ([]*models.ChartLink, error) {
+	targetChart, err := target.Chart(ctx, chart.Name())
+	if err != nil {
+		return nil, err
+	}
+	sources := make([]storage.Version, 0, len(versions))
+	targets := make([]storage.Version, 0, len(versions))
+	packages := make([][]byte, 0, len(versions))
+	findings := make([][]lint.Finding, 0, len(versions))
+	for _, number := range versions {
+		source, err := chart.Version(ctx, number)
+		if err != nil {
+			return nil, err
+		}
+		if err = source.Validate(ctx); err != nil {
+			return nil, err
+		}
+		dest, err := targetChart.Version(ctx, source.Number())
+		if err != nil {
+			return nil, err
+		}
+		resource := fmt.Sprintf("%s/%s/%s", target.Name(), targetChart.Name(), dest.Number())
+		if dest.Exists(ctx) {
+			return nil, errors.ErrorResourceExist.Format(resource)
+		}
+		// the package is copied without any modification, so the digest is preserved
+		// and metadata and values are restored from the same package.
+		data, err := source.GetContent(ctx)
+		if err != nil {
+			return nil, err
+		}
+		found, err := lintPackage(ctx, target, resource, data)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, source)
+		targets = append(targets, dest)
+		packages = append(packages, data)
+		findings = append(findings, found)
+	}
+	prefix, err := getAPIPrefix(ctx)
+	if err != nil {
+		return nil, err
+	}
+	options, err := getPutOptions(ctx, storage.SourcePromotion)
+	if err != nil {
+		return nil, err
+	}
+	// a version which is created after the check above must not be replaced
+	options.Create = true
+	links := make([]*models.ChartLink, 0, len(sources))
+	for i, dest := range targets {
+		if err := dest.PutContent(ctx, packages[i], options); err != nil {
+			// nothing is promoted if any version fails
+			for _, promoted := range targets[:i] {
+				if err := targetChart.Delete(ctx, promoted.Number(), true); err != nil {
+					log.Error(err)
+				}
+			}
+			return nil, err
+		}
+		link := models.NewChartLink(target.Name(), targetChart.Name(), dest.Number(),
+			fmt.Sprintf("%s/spaces/%s/charts/%s/versions/%s", prefix, target.Name(), targetChart.Name(), dest.Number()))
+		link.Lint = findings[i]
+		links = append(links, link)
+	}
+	if history {
+		for i, source := range sources {
+			event := storage.NewEvent(storage.EventTypePromotion, targets[i].Number())
+			event.Source = fmt.Sprintf("%s/%s/%s", space.Name(), chart.Name(), source.Number())
+			if err = targetChart.Record(ctx, event); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return links, nil
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package handlers
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/blang/semver"
+	"github.com/caicloud/helm-registry/pkg/errors"
+	"github.com/caicloud/helm-registry/pkg/storage"
+)
+
+// uploaderHeader is the header which specifies the uploader of a version
+const uploaderHeader = "X-Registry-Uploader"
+
+const (
+	// sortByName sorts by chart name. Versions of a chart are kept in the order of version numbers
+	sortByName = "name"
+	// sortByVersion sorts by version number. Charts with the same version number are kept in
+	// the order of chart names
+	sortByVersion = "version"
+	// sortByCreated sorts by created time of versions
+	sortByCreated = "created"
+	// sortByUpdated sorts by updated time of versions
+	sortByUpdated = "updated"
+)
+
+// GetVersionRecord gets the record of specified version
+func GetVersionRecord(ctx context.Context) (record *storage.Record, err error) {
+	err = managerHelper(ctx, func(space storage.Space, chart storage.Chart, version storage.Version) error {
+		record, err = version.Record(ctx)
+		return err
+	})
+	return
+}
+
+// getPutOptions gets options for storing a version. The registry doesn't authenticate users,
+// so the uploader is the value of uploader header. It's supplied by the client and untrusted,
+// and credentials of basic auth are never taken as an identity because they're not verified.
+func getPutOptions(ctx context.Context, source storage.Source) (*storage.PutOptions, error) {
+	request, err := getRequestFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &storage.PutOptions{Uploader: request.HeaderParameter(uploaderHeader), Source: source}, nil
+}
+
+// getSortParameter gets the sort field and whether the order is descending. The value
+// of sort parameter is a field with an optional prefix '-' for descending order. If
+// the parameter does not exist, the field is empty and items are in the default order
+// (by chart name and version number).
+func getSortParameter(ctx context.Context) (string, bool, error) {
+	const field = "sort"
+	value, err := getOptionalQueryParameter(ctx, field)
+	if err != nil || len(value) <= 0 {
+		return "", false, err
+	}
+	desc := strings.HasPrefix(value, "-")
+	key := strings.TrimPrefix(value, "-")
+	switch key {
+	case sortByName, sortByVersion, sortByCreated, sortByUpdated:
+		return key, desc, nil
+	}
+	return "", false, errors.ErrorParamValueError.Format(field, "name, version, created or updated", value)
+}
+
+// recordTime returns the time of record by the sort field
+func recordTime(record *storage.Record, key string) time.Time {
+	if record == nil {
+		return time.Time{}
+	}
+	if key == sortByUpdated {
+		return record.Updated
+	}
+	return record.Created
+}
+
+// sortRecords sorts items by the sort parameter. items must be a slice and record returns
+// the record of the i-th item. The default order of items is kept when sorting by name
+// or version.
+func sortRecords(ctx context.Context, items interface{}, record func(i int) *storage.Record) error {
+	key, desc, err := getSortParameter(ctx)
+	if err != nil {
+		return err
+	}
+	if key == sortByCreated || key == sortByUpdated {
+		sort.SliceStable(items, func(i, j int) bool {
+			return recordTime(record(i), key).Before(recordTime(record(j), key))
+		})
+	}
+	if desc {
+		swap := reflect.Swapper(items)
+		for i, j := 0, reflect.ValueOf(items).Len()-1; i < j; i, j = i+1, j-1 {
+			swap(i, j)
+		}
+	}
+	return nil
+}
+
+// sortMetadata sorts metadata by the sort parameter. Metadata may come from different charts,
+// so it's sorted by names and version numbers before sorting by records.
+func sortMetadata(ctx context.Context, metadata []*storage.Metadata) error {
+	key, _, err := getSortParameter(ctx)
+	if err != nil {
+		return err
+	}
+	switch key {
+	case sortByName:
+		sort.SliceStable(metadata, func(i, j int) bool {
+			return metadata[i].Name < metadata[j].Name
+		})
+	case sortByVersion:
+		sort.SliceStable(metadata, func(i, j int) bool {
+			return compareVersionNumbers(metadata[i].Version, metadata[j].Version) < 0
+		})
+	}
+	return sortRecords(ctx, metadata, func(i int) *storage.Record {
+		return metadata[i].Record
+	})
+}
+
+// sortVersionSummaries sorts summaries of versions by the sort parameter
+func sortVersionSummaries(ctx context.Context, summaries []*storage.VersionSummary) error {
+	return sortRecords(ctx, summaries, func(i int) *storage.Record {
+		return summaries[i].Record
+	})
+}
+
+// sortVersions sorts version numbers of chart by the sort parameter. It returns records
+// of versions if they are sorted by records.
+func sortVersions(ctx context.Context, chart storage.Chart, numbers []string) (map[string]*storage.Record, error) {
+	key, _, err := getSortParameter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	records := make(map[string]*storage.Record, len(numbers))
+	if key == sortByCreated || key == sortByUpdated {
+		for _, number := range numbers {
+			version, err := chart.Version(ctx, number)
+			if err != nil {
+				return nil, err
+			}
+			records[number], err = version.Record(ctx)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return records, sortRecords(ctx, numbers, func(i int) *storage.Record {
+		return records[numbers[i]]
+	})
+}
+
+// compareVersionNumbers compares two version numbers. Invalid version numbers are compared
+// as strings and are less than valid ones.
+func compareVersionNumbers(a, b string) int {
+	va, errA := semver.Parse(a)
+	vb, errB := semver.Parse(b)
+	switch {
+	case errA == nil && errB == nil:
+		return va.Compare(vb)
+	case errA == nil:
+		return 1
+	case errB == nil:
+		return -1
+	}
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/caicloud/helm-registry/pkg/api/definition"
+	"github.com/caicloud/helm-registry/pkg/errors"
+	"github.com/caicloud/helm-registry/pkg/render"
+	"github.com/caicloud/helm-registry/pkg/semverutil"
+	"github.com/caicloud/helm-registry/pkg/storage"
+	"k8s.io/helm/pkg/chartutil"
+)
+
+const (
+	// renderFormatFiles responds with manifests per template
+	renderFormatFiles = "files"
+	// renderFormatYAML responds with a multi-document yaml
+	renderFormatYAML = "yaml"
+)
+
+// RenderVersion renders templates of specified version, including templates of subcharts.
+// It responds with manifests per template by default, or with a multi-document yaml if
+// format is yaml. Errors of templates are unprocessable.
+func RenderVersion(ctx context.Context) (result interface{}, err error) {
+	format, err := getOptionalQueryParameter(ctx, "format")
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case "":
+		format = renderFormatFiles
+	case renderFormatFiles, renderFormatYAML:
+	default:
+		return nil, errors.ErrorParamValueError.Format("format", renderFormatFiles+" or "+renderFormatYAML, format)
+	}
+	config, err := getRenderConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(config.KubeVersion) > 0 {
+		if _, err = semverutil.ParseVersion(config.KubeVersion); err != nil {
+			return nil, errors.ErrorParamValueError.Format("kubeVersion", "a semantic version", config.KubeVersion)
+		}
+	}
+	err = managerHelper(ctx, func(space storage.Space, chart storage.Chart, version storage.Version) error {
+		data, err := version.GetContent(ctx)
+		if err != nil {
+			return err
+		}
+		resource := fmt.Sprintf("%s/%s/%s", space.Name(), chart.Name(), version.Number())
+		origin, err := chartutil.LoadArchive(bytes.NewReader(data))
+		if err != nil {
+			return errors.ErrorInternalTypeError.Format(resource, "chart", "unknown")
+		}
+		rendered, err := render.Render(ctx, origin, &render.Options{
+			Name:        config.Name,
+			Namespace:   config.Namespace,
+			Values:      config.Values,
+			KubeVersion: config.KubeVersion,
+			APIVersions: config.APIVersions,
+			IsUpgrade:   config.IsUpgrade,
+		})
+		if err != nil {
+			return errors.ErrorUnprocessableEntity.Format("render config of "+resource, err)
+		}
+		if format == renderFormatFiles {
+			result = rendered
+			return nil
+		}
+		result = rendered.YAML()
+		return addResponseHeader(ctx, "Content-Type", definition.MIMEYAML+"; charset=utf-8")
+	})
+	return
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/caicloud/helm-registry/pkg/api/v1/types"
+	"github.com/caicloud/helm-registry/pkg/diff"
+	"github.com/caicloud/helm-registry/pkg/errors"
+	"github.com/caicloud/helm-registry/pkg/storage"
+	"k8s.io/helm/pkg/chartutil"
+)
+
+// ListRevisions lists revisions of a version in ascending order
+func ListRevisions(ctx context.Context) (int, interface{}, error) {
+	var revisions []*storage.Revision
+	err := managerHelper(ctx, func(space storage.Space, chart storage.Chart, version storage.Version) error {
+		var err error
+		revisions, err = version.Revisions(ctx)
+		return err
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	order := &listOrder{
+		comparers: []keyComparer{compareIntegers},
+		keys: func(i int) []string {
+			return []string{strconv.FormatInt(revisions[i].Revision, 10)}
+		},
+	}
+	return pageItems(ctx, revisions, order)
+}
+
+// DownloadRevision downloads the chart package of a revision. The entity tag of response
+// is the digest of the chart package.
+func DownloadRevision(ctx context.Context) (data []byte, err error) {
+	err = managerHelper(ctx, func(space storage.Space, chart storage.Chart, version storage.Version) error {
+		revision, err := getRevision(ctx, "revision")
+		if err != nil {
+			return err
+		}
+		data, err = version.RevisionContent(ctx, revision)
+		if err != nil {
+			return err
+		}
+		return checkConditions(ctx, fmt.Sprintf("revision %d of %s/%s/%s", revision, space.Name(), chart.Name(), version.Number()),
+			dataETag(data), time.Time{})
+	})
+	return
+}
+
+// DiffRevisions compares a revision with another revision. The revision to compare with is
+// specified by query parameter to, and it's the current revision by default.
+func DiffRevisions(ctx context.Context) (result *types.ChartDiff, err error) {
+	err = managerHelper(ctx, func(space storage.Space, chart storage.Chart, version storage.Version) error {
+		from, err := getRevision(ctx, "revision")
+		if err != nil {
+			return err
+		}
+		fromData, err := version.RevisionContent(ctx, from)
+		if err != nil {
+			return err
+		}
+		var toData []byte
+		to, err := getOptionalQueryParameter(ctx, "to")
+		if err != nil {
+			return err
+		}
+		if len(to) > 0 {
+			revision, err := strconv.ParseInt(to, 10, 64)
+			if err != nil {
+				return errors.ErrorParamTypeError.Format("to", "integer", to)
+			}
+			toData, err = version.RevisionContent(ctx, revision)
+		} else {
+			toData, err = version.GetContent(ctx)
+		}
+		if err != nil {
+			return err
+		}
+		result, err = diffPackages(fromData, toData)
+		return err
+	})
+	return
+}
+
+// RollbackRevision rolls a version back to a revision. The chart package of the revision
+// is stored as a new revision. If the request has If-Match, the version is rolled back
+// only if it matches the digest of current chart package.
+func RollbackRevision(ctx context.Context) (current *storage.Revision, err error) {
+	err = managerHelper(ctx, func(space storage.Space, chart storage.Chart, version storage.Version) error {
+		revision, err := getRevision(ctx, "revision")
+		if err != nil {
+			return err
+		}
+		data, err := version.RevisionContent(ctx, revision)
+		if err != nil {
+			return err
+		}
+		options, err := getPutOptions(ctx, storage.SourceUpload)
+		if err != nil {
+			return err
+		}
+		conditional, err := hasPreconditions(ctx)
+		if err != nil {
+			return err
+		}
+		if conditional {
+			record, err := version.Record(ctx)
+			if err != nil {
+				return err
+			}
+			err = checkPreconditions(ctx, fmt.Sprintf("%s/%s/%s", space.Name(), chart.Name(), version.Number()),
+				newETag(record.Digest))
+			if err != nil {
+				return err
+			}
+			options.Digest = record.Digest
+		}
+		err = version.UpdateContent(ctx, func([]byte) ([]byte, error) {
+			return data, nil
+		}, options)
+		if err != nil {
+			return err
+		}
+		event := storage.NewEvent(storage.EventTypeRollback, version.Number())
+		event.Revision = revision
+		if err = chart.Record(ctx, event); err != nil {
+			return err
+		}
+		revisions, err := version.Revisions(ctx)
+		if err != nil {
+			return err
+		}
+		current = revisions[len(revisions)-1]
+		return addResponseHeader(ctx, "ETag", newETag(current.Digest))
+	})
+	return
+}
+
+// getRevision gets a revision number from path parameters
+func getRevision(ctx context.Context, name string) (int64, error) {
+	value, err := getPathParameter(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+	revision, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, errors.ErrorParamTypeError.Format(name, "integer", value)
+	}
+	return revision, nil
+}
+
+// diffPackages compares two chart packages
+func diffPackages(from, to []byte) (*types.ChartDiff, error) {
+	fromChart, err := chartutil.LoadArchive(bytes.NewReader(from))
+	if err != nil {
+		return nil, errors.ErrorInternalTypeError.Format("package", "chart", "unknown")
+	}
+	toChart, err := chartutil.LoadArchive(bytes.NewReader(to))
+	if err != nil {
+		return nil, errors.ErrorInternalTypeError.Format("package", "chart", "unknown")
+	}
+	result, err := diff.Charts(fromChart, toChart)
+	if err != nil {
+		return nil, errors.ErrorInternalUnknown.Format(err)
+	}
+	return result, nil
+}
@@ -10,22 +10,88 @@ import (
 
 	"github.com/caicloud/helm-registry/pkg/api/models"
 	"github.com/caicloud/helm-registry/pkg/common"
+	"github.com/caicloud/helm-registry/pkg/errors"
+	"github.com/caicloud/helm-registry/pkg/storage"
 )
 
-// ListSpaces lists spaces
-func ListSpaces(ctx context.Context) (int, []string, error) {
+// ListSpaces lists spaces. Spaces can be filtered by labels and visibility.
+// If detail is true, returns summaries of spaces instead of names.
+func ListSpaces(ctx context.Context) (int, interface{}, error) {
+	detail, err := isDetailRequest(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	selector, err := getLabelSelector(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	visibility, err := getQueryParameter(ctx, "visibility")
+	if err != nil && !errors.ErrorParamNotFound.Equal(err) {
+		return 0, nil, err
+	}
+	names, err := common.MustGetSpaceManager().List(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	if !detail && len(selector) <= 0 && len(visibility) <= 0 {
+		return listStrings(ctx, func() ([]string, error) {
+			return names, nil
+		})
+	}
+	result := make([]string, 0, len(names))
+	summaries := make([]*storage.SpaceSummary, 0, len(names))
+	for _, name := range names {
+		space, err := common.GetSpace(ctx, name)
+		if err != nil {
+			return 0, nil, err
+		}
+		var summary *storage.SpaceSummary
+		var properties *storage.SpaceProperties
+		if detail {
+			summary, err = space.Summary(ctx)
+			if err == nil {
+				properties = &summary.SpaceProperties
+			}
+		} else {
+			properties, err = space.Properties(ctx)
+		}
+		if err != nil {
+			return 0, nil, err
+		}
+		if len(visibility) > 0 && string(properties.Visibility) != visibility {
+			continue
+		}
+		if !properties.Match(selector) {
+			continue
+		}
+		result = append(result, name)
+		summaries = append(summaries, summary)
+	}
+	if detail {
+		return pageItems(ctx, summaries, nameOrder(func(i int) string {
+			return summaries[i].Name
+		}))
+	}
 	return listStrings(ctx, func() ([]string, error) {
-		return common.MustGetSpaceManager().List(ctx)
+		return result, nil
 	})
 }
 
-// CreateSpace creates a specified space
+// CreateSpace creates a specified space. Properties of the space can be specified
+// by a json body
 func CreateSpace(ctx context.Context) (*models.Link, error) {
 	name, err := getSpaceName(ctx)
 	if err != nil {
 		return nil, err
 	}
-	_, err = common.MustGetSpaceManager().Create(ctx, name)
+	var properties *storage.SpaceProperties
+	if isJSONRequest(ctx) {
+		properties, err = getSpaceProperties(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	_, err = common.MustGetSpaceManager().Create(ctx, name, properties)
 	if err != nil {
 		return nil, err
 	}
@@ -36,11 +102,80 @@ func CreateSpace(ctx context.Context) (*models.Link, error) {
 	return models.NewLink(name, path.Join(link, name)), nil
 }
 
-// DeleteSpace deletes a specified space
+// GetSpace gets a specified space and its properties
+func GetSpace(ctx context.Context) (*models.Space, error) {
+	name, err := getSpaceName(ctx)
+	if err != nil {
+		return nil, err
+	}
+	space, err := common.GetSpace(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	properties, err := space.Properties(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewSpace(space.Name(), properties), nil
+}
+
+// UpdateSpace replaces properties of a specified space
+func UpdateSpace(ctx context.Context) (*models.Space, error) {
+	name, err := getSpaceName(ctx)
+	if err != nil {
+		return nil, err
+	}
+	properties, err := getSpaceProperties(ctx)
+	if err != nil {
+		return nil, err
+	}
+	space, err := common.GetSpace(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if err = space.SetProperties(ctx, properties); err != nil {
+		return nil, err
+	}
+	return models.NewSpace(space.Name(), properties), nil
+}
+
+// DeleteSpace deletes a specified space. If any version in the space is referenced by
+// orchestrated versions or requirements in other spaces, it's deleted only if force is true.
 func DeleteSpace(ctx context.Context) error {
 	name, err := getSpaceName(ctx)
 	if err != nil {
 		return err
 	}
-	return common.MustGetSpaceManager().Delete(ctx, name)
+	force, err := getBoolQueryParameter(ctx, "force")
+	if err != nil {
+		return err
+	}
+	return common.MustGetSpaceManager().Delete(ctx, name, force)
+}
+
+// RenameSpace renames a specified space. If any version in the space is referenced by
+// orchestrated versions in other spaces, it's renamed only if force is true. If any version
+// is referenced by requirements, it's never renamed.
+func RenameSpace(ctx context.Context) (*models.Link, error) {
+	name, err := getSpaceName(ctx)
+	if err != nil {
+		return nil, err
+	}
+	newName, err := getQueryParameter(ctx, "name")
+	if err != nil {
+		return nil, err
+	}
+	force, err := getBoolQueryParameter(ctx, "force")
+	if err != nil {
+		return nil, err
+	}
+	space, err := common.MustGetSpaceManager().Rename(ctx, name, newName, force)
+	if err != nil {
+		return nil, err
+	}
+	prefix, err := getAPIPrefix(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewLink(space.Name(), path.Join(prefix, "spaces", space.Name())), nil
 }
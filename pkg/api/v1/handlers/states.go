@@ -0,0 +1,140 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/caicloud/helm-registry/pkg/common"
+	"github.com/caicloud/helm-registry/pkg/errors"
+	"github.com/caicloud/helm-registry/pkg/storage"
+)
+
+// GetVersionState gets the state of specified version
+func GetVersionState(ctx context.Context) (state *storage.VersionState, err error) {
+	err = managerHelper(ctx, func(space storage.Space, chart storage.Chart, version storage.Version) error {
+		state, err = version.State(ctx)
+		return err
+	})
+	return
+}
+
+// SetVersionState sets the state of specified version
+func SetVersionState(ctx context.Context) (state *storage.VersionState, err error) {
+	err = managerHelper(ctx, func(space storage.Space, chart storage.Chart, version storage.Version) error {
+		value, err := getQueryParameter(ctx, "state")
+		if err != nil {
+			return err
+		}
+		reason, err := getQueryParameter(ctx, "reason")
+		if err != nil && !errors.ErrorParamNotFound.Equal(err) {
+			return err
+		}
+		state = storage.NewVersionState(storage.State(value), reason)
+		return version.SetState(ctx, state)
+	})
+	return
+}
+
+// includeYanked returns whether yanked versions should be included in listings
+func includeYanked(ctx context.Context) (bool, error) {
+	return getBoolQueryParameter(ctx, "yanked")
+}
+
+// filterYankedMetadata removes metadata of yanked versions
+func filterYankedMetadata(metadata []*storage.Metadata) []*storage.Metadata {
+	result := make([]*storage.Metadata, 0, len(metadata))
+	for _, md := range metadata {
+		if !md.State.Yanked() {
+			result = append(result, md)
+		}
+	}
+	return result
+}
+
+// filterYankedSummaries removes summaries of yanked versions
+func filterYankedSummaries(summaries []*storage.VersionSummary) []*storage.VersionSummary {
+	result := make([]*storage.VersionSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		if !summary.State.Yanked() {
+			result = append(result, summary)
+		}
+	}
+	return result
+}
+
+// filterYankedVersions removes yanked versions from version numbers
+func filterYankedVersions(ctx context.Context, chart storage.Chart, numbers []string) ([]string, error) {
+	result := make([]string, 0, len(numbers))
+	for _, number := range numbers {
+		version, err := chart.Version(ctx, number)
+		if err != nil {
+			return nil, err
+		}
+		state, err := version.State(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !state.Yanked() {
+			result = append(result, number)
+		}
+	}
+	return result, nil
+}
+
+// warnDeprecatedState adds a warning header to response if the state is deprecated
+func warnDeprecatedState(ctx context.Context, space storage.Space, chart storage.Chart,
+	version storage.Version, state *storage.VersionState) error {
+	if !state.Deprecated() {
+		return nil
+	}
+	message := fmt.Sprintf("%s/%s/%s is deprecated", space.Name(), chart.Name(), version.Number())
+	if len(state.Reason) > 0 {
+		message += ": " + state.Reason
+	}
+	return addResponseHeader(ctx, "Warning", "299 - "+strconv.Quote(message))
+}
+
+// warnDeprecatedVersion adds a warning header to response if the version is deprecated
+func warnDeprecatedVersion(ctx context.Context, space storage.Space, chart storage.Chart, version storage.Version) error {
+	state, err := version.State(ctx)
+	if err != nil {
+		return err
+	}
+	return warnDeprecatedState(ctx, space, chart, version, state)
+}
+
+// getLatestMetadata gets metadata of the latest version which is not yanked in a chart.
+// If filter is not nil, versions which don't match the filter are skipped too. If there
+// is no such version, returns nil.
+func getLatestMetadata(ctx context.Context, spaceName, chartName string, filter *metadataFilter) (*storage.Metadata, error) {
+	chart, err := common.GetChart(ctx, spaceName, chartName)
+	if err != nil {
+		return nil, err
+	}
+	versionNumbers, err := chart.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(versionNumbers) <= 0 {
+		return nil, errors.ErrorContentNotFound.Format("metadata")
+	}
+	for i := len(versionNumbers) - 1; i >= 0; i-- {
+		version, err := chart.Version(ctx, versionNumbers[i])
+		if err != nil {
+			return nil, err
+		}
+		metadata, err := version.Metadata(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !metadata.State.Yanked() && (filter == nil || filter.Match(metadata)) {
+			return metadata, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/caicloud/helm-registry/pkg/common"
+	"github.com/caicloud/helm-registry/pkg/errors"
+	"github.com/caicloud/helm-registry/pkg/storage"
+)
+
+// getTagName gets tag name
+func getTagName(ctx context.Context) (string, error) {
+	const field = "tag"
+	return getPathParameter(ctx, field)
+}
+
+// getExistingChart gets a chart which must exist
+func getExistingChart(ctx context.Context) (storage.Chart, error) {
+	spaceName, chartName, err := getSpaceAndChartName(ctx)
+	if err != nil {
+		return nil, err
+	}
+	chart, err := common.GetChart(ctx, spaceName, chartName)
+	if err != nil {
+		return nil, err
+	}
+	if !chart.Exists(ctx) {
+		return nil, errors.ErrorContentNotFound.Format(fmt.Sprintf("%s/%s", spaceName, chartName))
+	}
+	return chart, nil
+}
+
+// ListTags lists tags of specified chart
+func ListTags(ctx context.Context) (int, interface{}, error) {
+	chart, err := getExistingChart(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	tags, err := chart.Tags(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	return pageItems(ctx, tags, nameOrder(func(i int) string {
+		return tags[i].Name
+	}))
+}
+
+// GetTag gets a specified tag
+func GetTag(ctx context.Context) (*storage.Tag, error) {
+	name, err := getTagName(ctx)
+	if err != nil {
+		return nil, err
+	}
+	chart, err := getExistingChart(ctx)
+	if err != nil {
+		return nil, err
+	}
+	version, err := chart.Version(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return &storage.Tag{Name: name, Version: version.Number()}, nil
+}
+
+// SetTag points a tag to the specified version. The version can be another tag
+func SetTag(ctx context.Context) (*storage.Tag, error) {
+	name, err := getTagName(ctx)
+	if err != nil {
+		return nil, err
+	}
+	number, err := getQueryParameter(ctx, "version")
+	if err != nil {
+		return nil, err
+	}
+	chart, err := getExistingChart(ctx)
+	if err != nil {
+		return nil, err
+	}
+	version, err := chart.Version(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	if err = chart.Tag(ctx, name, version.Number()); err != nil {
+		return nil, err
+	}
+	return &storage.Tag{Name: name, Version: version.Number()}, nil
+}
+
+// DeleteTag deletes a specified tag
+func DeleteTag(ctx context.Context) error {
+	name, err := getTagName(ctx)
+	if err != nil {
+		return err
+	}
+	chart, err := getExistingChart(ctx)
+	if err != nil {
+		return err
+	}
+	return chart.Untag(ctx, name)
+}
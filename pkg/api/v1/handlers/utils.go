@@ -8,14 +8,18 @@ import (
 	"context"
 	"encoding/json"
 	"io/ioutil"
+	"mime"
 	"strconv"
+	"strings"
 
 	"github.com/caicloud/helm-registry/pkg/api/definition"
 	"github.com/caicloud/helm-registry/pkg/api/v1/types"
 	"github.com/caicloud/helm-registry/pkg/common"
 	"github.com/caicloud/helm-registry/pkg/errors"
+	"github.com/caicloud/helm-registry/pkg/patch"
 	"github.com/caicloud/helm-registry/pkg/storage"
 	"github.com/emicklei/go-restful"
+	"github.com/ghodss/yaml"
 )
 
 // getRequestFromContext get request from context
@@ -27,6 +31,25 @@ func getRequestFromContext(ctx context.Context) (*restful.Request, error) {
 	return nil, errors.ErrorUnknownNotFoundError.Format(definition.KeyRequest)
 }
 
+// getResponseFromContext get response from context
+func getResponseFromContext(ctx context.Context) (*restful.Response, error) {
+	value := ctx.Value(definition.KeyResponse)
+	if v, ok := value.(*restful.Response); ok {
+		return v, nil
+	}
+	return nil, errors.ErrorUnknownNotFoundError.Format(definition.KeyResponse)
+}
+
+// addResponseHeader adds a header to response
+func addResponseHeader(ctx context.Context, name, value string) error {
+	response, err := getResponseFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	response.AddHeader(name, value)
+	return nil
+}
+
 // getPathParameter gets value from request.PathParameter
 func getPathParameter(ctx context.Context, name string) (string, error) {
 	request, err := getRequestFromContext(ctx)
@@ -66,6 +89,23 @@ func getQueryParameter(ctx context.Context, name string) (string, error) {
 	return value, nil
 }
 
+// getBoolQueryParameter gets a boolean value from request.QueryParameter.
+// It returns false if the parameter does not exist
+func getBoolQueryParameter(ctx context.Context, name string) (bool, error) {
+	value, err := getQueryParameter(ctx, name)
+	if err != nil {
+		if errors.ErrorParamNotFound.Equal(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	result, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errors.ErrorParamTypeError.Format(name, "boolean", value)
+	}
+	return result, nil
+}
+
 // getSpaceName gets space name
 func getSpaceName(ctx context.Context) (string, error) {
 	const field = "space"
@@ -123,6 +163,18 @@ func getRequestPath(ctx context.Context) (string, error) {
 	return request.Request.URL.Path, nil
 }
 
+// getAPIPrefix returns the prefix of request path before spaces. e.g. /api/v1
+func getAPIPrefix(ctx context.Context) (string, error) {
+	path, err := getRequestPath(ctx)
+	if err != nil {
+		return "", err
+	}
+	if index := strings.Index(path, "/spaces"); index >= 0 {
+		path = path[:index]
+	}
+	return path, nil
+}
+
 // getPaging gets paging info from context and return start and limit
 func getPaging(ctx context.Context) (int, int, error) {
 	request, err := getRequestFromContext(ctx)
@@ -151,20 +203,25 @@ func getPaging(ctx context.Context) (int, int, error) {
 	return s, l, nil
 }
 
-// listStrings is a helper to get an array of strings from f(). Then select a specified range
+// listStrings is a helper to get a sorted array of names from f(). Then select a specified range
 // of the array by paging info. It returns original array length and selected array.
 func listStrings(ctx context.Context, f func() ([]string, error)) (int, []string, error) {
-	start, limit, err := getPaging(ctx)
+	strings, err := f()
 	if err != nil {
 		return 0, nil, err
 	}
-	strings, err := f()
+	total, items, err := pageItems(ctx, strings, nameOrder(func(i int) string {
+		return strings[i]
+	}))
 	if err != nil {
 		return 0, nil, err
 	}
-	total := len(strings)
-	start, end := standardizeRange(total, start, limit)
-	return total, strings[start:end], nil
+	return total, items.([]string), nil
+}
+
+// isDetailRequest returns whether the request asks for detailed objects instead of names
+func isDetailRequest(ctx context.Context) (bool, error) {
+	return getBoolQueryParameter(ctx, "detail")
 }
 
 // standardizeRange makes start and limit conform to [0:total]
@@ -193,6 +250,121 @@ func readDataFromBody(ctx context.Context) ([]byte, error) {
 	return data, nil
 }
 
+// isJSONRequest returns whether the body of request is json
+func isJSONRequest(ctx context.Context) bool {
+	request, err := getRequestFromContext(ctx)
+	if err != nil {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(request.HeaderParameter("Content-Type"))
+	return err == nil && mediaType == restful.MIME_JSON
+}
+
+// isYAMLRequest returns whether the body of request is yaml
+func isYAMLRequest(ctx context.Context) bool {
+	request, err := getRequestFromContext(ctx)
+	if err != nil {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(request.HeaderParameter("Content-Type"))
+	return err == nil && mediaType == definition.MIMEYAML
+}
+
+// acceptsYAML returns whether the request prefers a yaml response to a json response.
+// Media types in Accept are checked in order as go-restful does, and quality values
+// are ignored.
+func acceptsYAML(ctx context.Context) bool {
+	request, err := getRequestFromContext(ctx)
+	if err != nil {
+		return false
+	}
+	for _, accept := range strings.Split(request.HeaderParameter("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil {
+			continue
+		}
+		switch mediaType {
+		case definition.MIMEYAML:
+			return true
+		case restful.MIME_JSON, "*/*":
+			return false
+		}
+	}
+	return false
+}
+
+// acceptsHTML returns whether the request prefers a html response to a raw text response.
+// Media types in Accept are checked in order, and quality values are ignored.
+func acceptsHTML(ctx context.Context) bool {
+	request, err := getRequestFromContext(ctx)
+	if err != nil {
+		return false
+	}
+	for _, accept := range strings.Split(request.HeaderParameter("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil {
+			continue
+		}
+		switch mediaType {
+		case definition.MIMEHTML:
+			return true
+		case definition.MIMEMarkdown, definition.MIMEText, definition.MIMEAny:
+			return false
+		}
+	}
+	return false
+}
+
+// getLabelSelector gets labels from query parameter label
+func getLabelSelector(ctx context.Context) (map[string]string, error) {
+	return getSelector(ctx, "label")
+}
+
+// getSelector gets a selector from query parameter field. The format of the parameter is
+// key1=value1,key2. A key without value matches any value.
+func getSelector(ctx context.Context, field string) (map[string]string, error) {
+	value, err := getQueryParameter(ctx, field)
+	if err != nil {
+		if errors.ErrorParamNotFound.Equal(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	selector := make(map[string]string)
+	for _, label := range strings.Split(value, ",") {
+		parts := strings.SplitN(label, "=", 2)
+		key := strings.TrimSpace(parts[0])
+		if len(key) <= 0 {
+			return nil, errors.ErrorInvalidParam.Format(field, value)
+		}
+		selector[key] = ""
+		if len(parts) > 1 {
+			selector[key] = strings.TrimSpace(parts[1])
+		}
+	}
+	return selector, nil
+}
+
+// getSpaceProperties gets properties of space
+func getSpaceProperties(ctx context.Context) (*storage.SpaceProperties, error) {
+	data, err := readDataFromBody(ctx)
+	if err != nil {
+		return nil, err
+	}
+	properties := storage.NewSpaceProperties()
+	if len(data) <= 0 {
+		return properties, nil
+	}
+	err = json.Unmarshal(data, properties)
+	if err != nil {
+		return nil, errors.ErrorParamTypeError.Format("body", "space properties", "unknown")
+	}
+	if err = properties.Validate(); err != nil {
+		return nil, err
+	}
+	return properties, nil
+}
+
 // getChartConfig gets a config
 func getChartConfig(ctx context.Context) (*types.OrchestrationConfig, error) {
 	data, err := readDataFromBody(ctx)
@@ -215,12 +387,60 @@ func getChartConfig(ctx context.Context) (*types.OrchestrationConfig, error) {
 	return config, err
 }
 
+// getRebuildConfig gets a rebuild config
+func getRebuildConfig(ctx context.Context) (*types.RebuildConfig, error) {
+	data, err := readDataFromBody(ctx)
+	if err != nil {
+		return nil, err
+	}
+	config := &types.RebuildConfig{}
+	if err = json.Unmarshal(data, config); err != nil {
+		return nil, errors.ErrorParamTypeError.Format("config", "rebuild config", "unknown")
+	}
+	if err = config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// getRenderConfig gets a render config. An empty body means the default config.
+func getRenderConfig(ctx context.Context) (*types.RenderConfig, error) {
+	data, err := readDataFromBody(ctx)
+	if err != nil {
+		return nil, err
+	}
+	config := &types.RenderConfig{}
+	if len(strings.TrimSpace(string(data))) <= 0 {
+		return config, nil
+	}
+	if isYAMLRequest(ctx) {
+		data, err = yaml.YAMLToJSON(data)
+		if err != nil {
+			return nil, errors.ErrorParamTypeError.Format("body", "yaml", "unknown")
+		}
+	}
+	err = json.Unmarshal(data, config)
+	if err != nil {
+		return nil, errors.ErrorParamTypeError.Format("body", "render config", "unknown")
+	}
+	if err = config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
 // getMetadata gets metadata
 func getMetadata(ctx context.Context) (*storage.Metadata, error) {
 	data, err := readDataFromBody(ctx)
 	if err != nil {
 		return nil, err
 	}
+	if isYAMLRequest(ctx) {
+		data, err = yaml.YAMLToJSON(data)
+		if err != nil {
+			return nil, errors.ErrorParamTypeError.Format("body", "yaml", "unknown")
+		}
+	}
 	metadata := &storage.Metadata{}
 	err = json.Unmarshal(data, metadata)
 	if err != nil {
@@ -234,6 +454,23 @@ func getValues(ctx context.Context) ([]byte, error) {
 	return readDataFromBody(ctx)
 }
 
+// getPatch gets a patch and its content type. The content type must be a supported patch type.
+func getPatch(ctx context.Context) (string, []byte, error) {
+	request, err := getRequestFromContext(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	contentType := request.HeaderParameter("Content-Type")
+	if !patch.Supported(contentType) {
+		return "", nil, errors.ErrorUnsupportedMediaType.Format(contentType, patch.MergePatchType+" or "+patch.JSONPatchType)
+	}
+	data, err := readDataFromBody(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	return contentType, data, nil
+}
+
 // managerCallback is used for passing space, chart and version
 type managerCallback func(space storage.Space, chart storage.Chart, version storage.Version) error
 
@@ -0,0 +1,83 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/caicloud/helm-registry/pkg/errors"
+	"github.com/caicloud/helm-registry/pkg/patch"
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+// coalescedValues returns values of a chart in the same form as the values of a version
+func coalescedValues(origin *chart.Chart) ([]byte, error) {
+	values, err := chartutil.CoalesceValues(origin, origin.Values)
+	if err != nil {
+		return nil, errors.ErrorInternalUnknown.Format(err)
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return nil, errors.ErrorInternalUnknown.Format(err)
+	}
+	return data, nil
+}
+
+// rawValues returns the original values.yaml of a chart. Comments and the order of keys
+// are kept.
+func rawValues(origin *chart.Chart) []byte {
+	if origin.Values == nil {
+		return []byte{}
+	}
+	return []byte(origin.Values.Raw)
+}
+
+// setRawValues replaces values.yaml of a chart
+func setRawValues(origin *chart.Chart, values []byte) {
+	if origin.Values == nil {
+		origin.Values = &chart.Config{}
+	}
+	origin.Values.Raw = string(values)
+}
+
+// updateValues changes values.yaml of a chart so that coalesced values of the chart are values.
+// Only changed keys are edited in values.yaml, so comments and defaults of subcharts are kept.
+// Values which can't be reached without losing content of values.yaml are unprocessable.
+func updateValues(origin *chart.Chart, values []byte) error {
+	expected := map[string]interface{}{}
+	if err := json.Unmarshal(values, &expected); err != nil || expected == nil {
+		return errors.ErrorUnprocessableEntity.Format("values", "values must be an object")
+	}
+	current, err := coalescedValues(origin)
+	if err != nil {
+		return err
+	}
+	changes, err := patch.CreateMergePatch(current, values)
+	if err != nil {
+		return errors.ErrorUnprocessableEntity.Format("values", err)
+	}
+	raw, err := patch.MergeYAML(rawValues(origin), changes)
+	if err != nil {
+		return errors.ErrorUnprocessableEntity.Format("values",
+			fmt.Errorf("values.yaml can't be changed without losing its content: %v", err))
+	}
+	setRawValues(origin, raw)
+	// values of subcharts are coalesced again, and their defaults can't be removed
+	current, err = coalescedValues(origin)
+	if err != nil {
+		return err
+	}
+	result := map[string]interface{}{}
+	if err = json.Unmarshal(current, &result); err != nil {
+		return errors.ErrorInternalUnknown.Format(err)
+	}
+	if !reflect.DeepEqual(result, expected) {
+		return errors.ErrorUnprocessableEntity.Format("values", "defaults of subcharts can't be removed or changed in type")
+	}
+	return nil
+}
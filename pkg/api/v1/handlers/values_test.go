@@ -0,0 +1,65 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+// newValuesChart creates a chart with values.yaml and a subchart with defaults
+func newValuesChart(values string) *chart.Chart {
+	return &chart.Chart{
+		Metadata: &chart.Metadata{Name: "web", Version: "1.0.0"},
+		Values:   &chart.Config{Raw: values},
+		Dependencies: []*chart.Chart{{
+			Metadata: &chart.Metadata{Name: "db", Version: "1.0.0"},
+			Values:   &chart.Config{Raw: "port: 3306\n"},
+		}},
+	}
+}
+
+func TestUpdateValues(t *testing.T) {
+	const values = "# replicas of web\nreplicas: 1 # count\nimage: web\n"
+	cases := []struct {
+		name, values, raw string
+	}{
+		{"no changes", `{"replicas":1,"image":"web","db":{"global":{},"port":3306}}`, values},
+		{"changed keys", `{"replicas":2,"image":"web","db":{"global":{},"port":3306}}`,
+			"# replicas of web\nreplicas: 2 # count\nimage: web\n"},
+		{"removed keys", `{"replicas":1,"db":{"global":{},"port":3306}}`, "# replicas of web\nreplicas: 1 # count\n"},
+		{"values of subcharts", `{"replicas":1,"image":"web","db":{"global":{},"port":3307}}`, values + "db:\n  port: 3307\n"},
+	}
+	for _, c := range cases {
+		origin := newValuesChart(values)
+		if err := updateValues(origin, []byte(c.values)); err != nil {
+			t.Errorf("%s: unexpected error: %v", c.name, err)
+			continue
+		}
+		if raw := string(rawValues(origin)); raw != c.raw {
+			t.Errorf("%s: expected\n%s\ngot\n%s", c.name, c.raw, raw)
+		}
+	}
+
+	errors := []struct {
+		name, raw, values, message string
+	}{
+		{"not an object", values, `[1]`, "values must be an object"},
+		{"defaults of subcharts", values, `{"replicas":1,"image":"web"}`, "defaults of subcharts"},
+		{"commented values", "ports:\n# http\n- 80\n", `{"db":{"global":{},"port":3306},"ports":[443]}`, "losing its content"},
+	}
+	for _, c := range errors {
+		err := updateValues(newValuesChart(c.raw), []byte(c.values))
+		if err == nil {
+			t.Errorf("%s: expected an error", c.name)
+			continue
+		}
+		if !strings.Contains(err.Error(), c.message) {
+			t.Errorf("%s: expected an error with %q, got %q", c.name, c.message, err.Error())
+		}
+	}
+}
@@ -18,8 +18,9 @@ import (
 	"k8s.io/helm/pkg/proto/hapi/chart"
 )
 
-// ListVersions lists versions in specified chart
-func ListVersions(ctx context.Context) (int, []string, error) {
+// ListVersions lists versions in specified chart. If detail is true, returns summaries
+// of versions instead of version numbers.
+func ListVersions(ctx context.Context) (int, interface{}, error) {
 	spaceName, chartName, err := getSpaceAndChartName(ctx)
 	if err != nil {
 		return 0, nil, err
@@ -28,21 +29,91 @@ func ListVersions(ctx context.Context) (int, []string, error) {
 	if err != nil {
 		return 0, nil, err
 	}
-	return listStrings(ctx, func() ([]string, error) {
-		return chart.List(ctx)
+	yanked, err := includeYanked(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	detail, err := isDetailRequest(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	if detail {
+		summaries, err := chart.VersionSummaries(ctx)
+		if err != nil {
+			return 0, nil, err
+		}
+		if !yanked {
+			summaries = filterYankedSummaries(summaries)
+		}
+		if err = sortVersionSummaries(ctx, summaries); err != nil {
+			return 0, nil, err
+		}
+		order, err := versionOrder(ctx, func(i int) string {
+			return summaries[i].Version
+		}, func(i int) *storage.Record {
+			return summaries[i].Record
+		})
+		if err != nil {
+			return 0, nil, err
+		}
+		return pageItems(ctx, summaries, order)
+	}
+	versions, err := chart.List(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	if !yanked {
+		versions, err = filterYankedVersions(ctx, chart, versions)
+		if err != nil {
+			return 0, nil, err
+		}
+	}
+	records, err := sortVersions(ctx, chart, versions)
+	if err != nil {
+		return 0, nil, err
+	}
+	order, err := versionOrder(ctx, func(i int) string {
+		return versions[i]
+	}, func(i int) *storage.Record {
+		return records[versions[i]]
 	})
+	if err != nil {
+		return 0, nil, err
+	}
+	return pageItems(ctx, versions, order)
 }
 
-// DownloadVersion handles a request for getting a version of chart
+// DownloadVersion handles a request for getting a version of chart. The entity tag of
+// response is the digest of the chart package.
 func DownloadVersion(ctx context.Context) (data []byte, err error) {
 	err = managerHelper(ctx, func(space storage.Space, chart storage.Chart, version storage.Version) error {
+		if err := warnDeprecatedVersion(ctx, space, chart, version); err != nil {
+			return err
+		}
+		record, err := version.Record(ctx)
+		if err != nil {
+			return err
+		}
+		if record != nil {
+			modified, err := versionModified(ctx, version, record.Updated)
+			if err != nil {
+				return err
+			}
+			err = checkConditions(ctx, fmt.Sprintf("%s/%s/%s", space.Name(), chart.Name(), version.Number()),
+				newETag(record.Digest), modified)
+			if err != nil {
+				return err
+			}
+		}
 		data, err = version.GetContent(ctx)
 		return err
 	})
 	return
 }
 
-// UpdateVersion handles a request for updating a version of chart. Resource must exist
+// UpdateVersion handles a request for updating a version of chart. Resource must exist.
+// If the request has If-Match, the version is updated only if it matches the digest of
+// current chart package.
 func UpdateVersion(ctx context.Context) (*models.ChartLink, error) {
 	return putVersion(ctx, func(space storage.Space, chart storage.Chart, version storage.Version) error {
 		if !version.Exists(ctx) {
@@ -75,25 +146,61 @@ func putVersion(ctx context.Context, canSave managerCallback) (link *models.Char
 		if err = canSave(space, chart, version); err != nil {
 			return err
 		}
-		err = version.PutContent(ctx, data)
+		findings, err := lintPackage(ctx, space, fmt.Sprintf("%s/%s/%s", space.Name(), chart.Name(), version.Number()), data)
+		if err != nil {
+			return err
+		}
+		options, err := getPutOptions(ctx, storage.SourceUpload)
 		if err != nil {
 			return err
 		}
+		conditional, err := hasPreconditions(ctx)
+		if err != nil {
+			return err
+		}
+		if conditional {
+			etag := ""
+			if version.Exists(ctx) {
+				record, err := version.Record(ctx)
+				if err != nil {
+					return err
+				}
+				etag = newETag(record.Digest)
+				options.Digest = record.Digest
+			}
+			err = checkPreconditions(ctx, fmt.Sprintf("%s/%s/%s", space.Name(), chart.Name(), version.Number()), etag)
+			if err != nil {
+				return err
+			}
+		}
+		err = version.PutContent(ctx, data, options)
+		if err != nil {
+			return err
+		}
+		if err = addResponseHeader(ctx, "ETag", newETag(storage.Digest(data))); err != nil {
+			return err
+		}
 		// construct a chart self-link
 		path, err := getRequestPath(ctx)
 		if err != nil {
 			return err
 		}
 		link = models.NewChartLink(space.Name(), chart.Name(), version.Number(), path)
+		link.Lint = findings
 		return nil
 	})
 	return
 }
 
-// DeleteVersion deletes specified version
+// DeleteVersion deletes specified version. If the version is referenced by orchestrated
+// versions or requirements, it's deleted only if force is true.
 func DeleteVersion(ctx context.Context) error {
 	return managerHelper(ctx, func(space storage.Space, chart storage.Chart, version storage.Version) error {
-		return chart.Delete(ctx, version.Number())
+		force, err := getBoolQueryParameter(ctx, "force")
+		if err != nil {
+			return err
+		}
+		return chart.Delete(ctx, version.Number(), force)
 	})
 }
 
@@ -12,12 +12,13 @@ import (
 
 // InstallRouters installs api WebService
 func InstallRouters(containers *restful.Container) *restful.WebService {
+	restful.RegisterEntityAccessor(definition.MIMEYAML, definition.NewEntityAccessorYAML(definition.MIMEYAML))
 	service := (&restful.WebService{}).
 		ApiVersion("v1").
 		Path("/api/v1").
 		Doc("v1 API").
-		Consumes("*/*", "application/x-www-form-urlencoded", "multipart/form-data", restful.MIME_JSON, restful.MIME_XML).
-		Produces(restful.MIME_JSON, restful.MIME_XML)
+		Consumes("*/*", "application/x-www-form-urlencoded", "multipart/form-data", restful.MIME_JSON, restful.MIME_XML, definition.MIMEYAML).
+		Produces(restful.MIME_JSON, restful.MIME_XML, definition.MIMEYAML)
 	service = definition.GenerateRoutes(service, descriptor.Descriptors)
 	containers.Add(service)
 	return service
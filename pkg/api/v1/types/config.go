@@ -56,3 +56,29 @@ func (ac *OrchestrationConfig) Validate() error {
 	}
 	return nil
 }
+
+// RebuildConfig describes how to rebuild an orchestrated version into a new version
+type RebuildConfig struct {
+	// Version is the number of the new version
+	Version string `json:"version"`
+	// Description is the description of the new version. The original one is kept if it's empty
+	Description string `json:"description,omitempty"`
+	// Upgrades are versions of independent packages by paths of charts in the orchestration
+	// config, e.g. chartB or chartB.chartD. The path of root chart is empty.
+	Upgrades map[string]string `json:"upgrades,omitempty"`
+	// Latest upgrades all independent packages which are not in Upgrades to their latest versions
+	Latest bool `json:"latest,omitempty"`
+}
+
+// Validate validates whether the config is valid
+func (rc *RebuildConfig) Validate() error {
+	if len(rc.Version) <= 0 {
+		return errors.ErrorParamNotFound.Format("version")
+	}
+	for path, version := range rc.Upgrades {
+		if len(version) <= 0 {
+			return errors.ErrorParamNotFound.Format(fmt.Sprintf("version of upgrades[%q]", path))
+		}
+	}
+	return nil
+}
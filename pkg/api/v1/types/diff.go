@@ -0,0 +1,31 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package types
+
+// ValueChange is a change of a key. Path is the dotted path of the key, e.g. image.tag
+type ValueChange struct {
+	Path string      `json:"path"`
+	Type string      `json:"type"`
+	From interface{} `json:"from,omitempty"`
+	To   interface{} `json:"to,omitempty"`
+}
+
+// FileChange is a change of a file in chart. Diff is a unified diff of a text file
+type FileChange struct {
+	Path   string `json:"path"`
+	Type   string `json:"type"`
+	Binary bool   `json:"binary,omitempty"`
+	Diff   string `json:"diff,omitempty"`
+}
+
+// ChartDiff is the difference between two charts
+type ChartDiff struct {
+	// Metadata is the changes of Chart.yaml
+	Metadata []ValueChange `json:"metadata"`
+	// Values is the changes of coalesced values
+	Values []ValueChange `json:"values"`
+	// Files is the changes of templates, subcharts and other files
+	Files []FileChange `json:"files"`
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/caicloud/helm-registry/pkg/errors"
+)
+
+// maxReleaseNameLength is the max length of release names which tiller accepts
+const maxReleaseNameLength = 53
+
+// RenderConfig describes a config to render a version
+type RenderConfig struct {
+	// Name is the release name. It's RELEASE-NAME if empty
+	Name string `json:"name"`
+	// Namespace is the namespace of release. It's default if empty
+	Namespace string `json:"namespace"`
+	// Values override the values of chart
+	Values map[string]interface{} `json:"values"`
+	// KubeVersion is the target Kubernetes version, e.g. v1.7.0
+	KubeVersion string `json:"kubeVersion"`
+	// APIVersions are api versions supported by the target Kubernetes, e.g. apps/v1beta1.
+	// v1 is always supported
+	APIVersions []string `json:"apiVersions"`
+	// IsUpgrade renders the version as an upgrade instead of an installation
+	IsUpgrade bool `json:"isUpgrade"`
+}
+
+// Validate validates whether the config is valid
+func (rc *RenderConfig) Validate() error {
+	if len(rc.Name) > maxReleaseNameLength {
+		return errors.ErrorParamValueError.Format("name", "no more than 53 characters", rc.Name)
+	}
+	for _, apiVersion := range rc.APIVersions {
+		if len(apiVersion) <= 0 {
+			return errors.ErrorParamValueError.Format("apiVersions", "non-empty strings", "an empty string")
+		}
+	}
+	return nil
+}
+
+// Manifest is a rendered template
+type Manifest struct {
+	// Path is the path of template, e.g. mychart/templates/svc.yaml
+	Path string `json:"path"`
+	// Content is the rendered content
+	Content string `json:"content"`
+}
+
+// RenderResult is the result of rendering
+type RenderResult struct {
+	// Manifests are non-empty manifests sorted by paths
+	Manifests []Manifest `json:"manifests"`
+	// Notes are rendered NOTES.txt of the top chart
+	Notes string `json:"notes,omitempty"`
+}
+
+// YAML joins manifests into a multi-document yaml. Each document starts with a comment
+// of its source template.
+func (r *RenderResult) YAML() []byte {
+	buf := bytes.NewBuffer(nil)
+	for _, manifest := range r.Manifests {
+		fmt.Fprintf(buf, "---\n# Source: %s\n%s\n", manifest.Path, strings.TrimSpace(manifest.Content))
+	}
+	return buf.Bytes()
+}
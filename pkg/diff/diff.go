@@ -0,0 +1,210 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+// Package diff compares charts. Metadata and values are compared by keys, and other files
+// are compared by unified text diffs.
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/caicloud/helm-registry/pkg/api/v1/types"
+	"github.com/ghodss/yaml"
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+// Change types
+const (
+	// Added means a key or file only exists in the new chart
+	Added = "added"
+	// Removed means a key or file only exists in the old chart
+	Removed = "removed"
+	// Changed means a key or file exists in both charts with different content
+	Changed = "changed"
+)
+
+// Charts compares two charts
+func Charts(from, to *chart.Chart) (*types.ChartDiff, error) {
+	result := &types.ChartDiff{}
+	fromMetadata, err := toMap(from.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	toMetadata, err := toMap(to.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	result.Metadata = Values(fromMetadata, toMetadata)
+	fromValues, err := coalescedValues(from)
+	if err != nil {
+		return nil, err
+	}
+	toValues, err := coalescedValues(to)
+	if err != nil {
+		return nil, err
+	}
+	result.Values = Values(fromValues, toValues)
+	fromFiles, err := files(from, "")
+	if err != nil {
+		return nil, err
+	}
+	toFiles, err := files(to, "")
+	if err != nil {
+		return nil, err
+	}
+	result.Files = Files(fromFiles, toFiles)
+	return result, nil
+}
+
+// Values compares two maps by keys. Nested maps are compared recursively, and other values
+// are compared as a whole. Changes are sorted by paths.
+func Values(from, to map[string]interface{}) []types.ValueChange {
+	changes := []types.ValueChange{}
+	compareValues("", from, to, &changes)
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
+// compareValues compares two maps and appends changes
+func compareValues(prefix string, from, to map[string]interface{}, changes *[]types.ValueChange) {
+	for key, a := range from {
+		path := joinPath(prefix, key)
+		b, ok := to[key]
+		if !ok {
+			*changes = append(*changes, types.ValueChange{Path: path, Type: Removed, From: a})
+			continue
+		}
+		mapA, okA := a.(map[string]interface{})
+		mapB, okB := b.(map[string]interface{})
+		if okA && okB {
+			compareValues(path, mapA, mapB, changes)
+			continue
+		}
+		if !reflect.DeepEqual(a, b) {
+			*changes = append(*changes, types.ValueChange{Path: path, Type: Changed, From: a, To: b})
+		}
+	}
+	for key, b := range to {
+		if _, ok := from[key]; !ok {
+			*changes = append(*changes, types.ValueChange{Path: joinPath(prefix, key), Type: Added, To: b})
+		}
+	}
+}
+
+// joinPath joins a key to a dotted path
+func joinPath(prefix, key string) string {
+	if len(prefix) <= 0 {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// Files compares two sets of files which are indexed by paths. Changes are sorted by paths.
+func Files(from, to map[string][]byte) []types.FileChange {
+	changes := []types.FileChange{}
+	for path, a := range from {
+		b, ok := to[path]
+		switch {
+		case !ok:
+			changes = append(changes, fileChange(path, Removed, a, nil))
+		case string(a) != string(b):
+			changes = append(changes, fileChange(path, Changed, a, b))
+		}
+	}
+	for path, b := range to {
+		if _, ok := from[path]; !ok {
+			changes = append(changes, fileChange(path, Added, nil, b))
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
+// fileChange creates a change of file
+func fileChange(path string, changeType string, from, to []byte) types.FileChange {
+	change := types.FileChange{Path: path, Type: changeType}
+	if isBinary(from) || isBinary(to) {
+		change.Binary = true
+		return change
+	}
+	fromName, toName := "a/"+path, "b/"+path
+	if from == nil {
+		fromName = "/dev/null"
+	}
+	if to == nil {
+		toName = "/dev/null"
+	}
+	change.Diff = Unified(fromName, toName, string(from), string(to), DefaultContext)
+	return change
+}
+
+// isBinary checks whether data is not a text
+func isBinary(data []byte) bool {
+	return !utf8.Valid(data) || strings.IndexByte(string(data), 0) >= 0
+}
+
+// files collects files of a chart except its Chart.yaml and values.yaml. Files of subcharts
+// are prefixed with charts/<name>/ and include their Chart.yaml and values.yaml.
+func files(c *chart.Chart, prefix string) (map[string][]byte, error) {
+	result := make(map[string][]byte)
+	if len(prefix) > 0 {
+		data, err := yaml.Marshal(c.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		result[prefix+chartutil.ChartfileName] = data
+		if c.Values != nil {
+			result[prefix+chartutil.ValuesfileName] = []byte(c.Values.Raw)
+		}
+	}
+	for _, template := range c.Templates {
+		result[prefix+template.Name] = template.Data
+	}
+	for _, file := range c.Files {
+		result[prefix+file.TypeUrl] = file.Value
+	}
+	for _, dependency := range c.Dependencies {
+		name := dependency.Metadata.GetName()
+		subfiles, err := files(dependency, fmt.Sprintf("%scharts/%s/", prefix, name))
+		if err != nil {
+			return nil, err
+		}
+		for path, data := range subfiles {
+			result[path] = data
+		}
+	}
+	return result, nil
+}
+
+// coalescedValues returns coalesced values of a chart as plain maps
+func coalescedValues(c *chart.Chart) (map[string]interface{}, error) {
+	values, err := chartutil.CoalesceValues(c, c.Values)
+	if err != nil {
+		return nil, err
+	}
+	return toMap(values)
+}
+
+// toMap converts an object to a map by its json tags
+func toMap(obj interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]interface{})
+	if err = json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package diff
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/caicloud/helm-registry/pkg/api/v1/types"
+	"github.com/golang/protobuf/ptypes/any"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+func TestValues(t *testing.T) {
+	cases := []struct {
+		name     string
+		from, to map[string]interface{}
+		changes  []types.ValueChange
+	}{
+		{"equal", map[string]interface{}{"a": 1}, map[string]interface{}{"a": 1}, []types.ValueChange{}},
+		{"empty", nil, nil, []types.ValueChange{}},
+		{"added", map[string]interface{}{}, map[string]interface{}{"a": 1},
+			[]types.ValueChange{{Path: "a", Type: Added, To: 1}}},
+		{"removed", map[string]interface{}{"a": 1}, nil,
+			[]types.ValueChange{{Path: "a", Type: Removed, From: 1}}},
+		{"changed", map[string]interface{}{"a": 1}, map[string]interface{}{"a": "1"},
+			[]types.ValueChange{{Path: "a", Type: Changed, From: 1, To: "1"}}},
+		{"nested",
+			map[string]interface{}{"image": map[string]interface{}{"tag": "1.0", "pull": "Always"}},
+			map[string]interface{}{"image": map[string]interface{}{"tag": "1.1", "name": "web"}},
+			[]types.ValueChange{
+				{Path: "image.name", Type: Added, To: "web"},
+				{Path: "image.pull", Type: Removed, From: "Always"},
+				{Path: "image.tag", Type: Changed, From: "1.0", To: "1.1"},
+			}},
+		{"map replaced by a scalar",
+			map[string]interface{}{"a": map[string]interface{}{"b": 1}},
+			map[string]interface{}{"a": 2},
+			[]types.ValueChange{{Path: "a", Type: Changed, From: map[string]interface{}{"b": 1}, To: 2}}},
+		{"lists are compared as a whole",
+			map[string]interface{}{"a": []interface{}{1, 2}},
+			map[string]interface{}{"a": []interface{}{1, 3}},
+			[]types.ValueChange{{Path: "a", Type: Changed, From: []interface{}{1, 2}, To: []interface{}{1, 3}}}},
+		{"sorted by paths",
+			map[string]interface{}{"c": 1, "a": 1},
+			map[string]interface{}{"b": 1},
+			[]types.ValueChange{
+				{Path: "a", Type: Removed, From: 1},
+				{Path: "b", Type: Added, To: 1},
+				{Path: "c", Type: Removed, From: 1},
+			}},
+	}
+	for _, c := range cases {
+		changes := Values(c.from, c.to)
+		if !reflect.DeepEqual(changes, c.changes) {
+			t.Errorf("%s: expected %+v, got %+v", c.name, c.changes, changes)
+		}
+	}
+}
+
+func TestUnified(t *testing.T) {
+	cases := []struct {
+		name, from, to string
+		context        int
+		diff           string
+	}{
+		{"equal", "a\nb\n", "a\nb\n", 3, ""},
+		{"add to empty", "", "a\nb\n", 3, "--- a\n+++ b\n@@ -0,0 +1,2 @@\n+a\n+b\n"},
+		{"remove all", "a\nb\n", "", 3, "--- a\n+++ b\n@@ -1,2 +0,0 @@\n-a\n-b\n"},
+		{"change", "a\n", "b\n", 3, "--- a\n+++ b\n@@ -1,1 +1,1 @@\n-a\n+b\n"},
+		{"append", "1\n2\n3\n", "1\n2\n3\n4\n", 3, "--- a\n+++ b\n@@ -1,3 +1,4 @@\n 1\n 2\n 3\n+4\n"},
+		{"context", "1\n2\n3\n4\n5\n6\n7\n", "1\n2\n3\nX\n5\n6\n7\n", 1,
+			"--- a\n+++ b\n@@ -3,3 +3,3 @@\n 3\n-4\n+X\n 5\n"},
+		{"no context", "1\n2\n3\n", "1\nX\n3\n", 0, "--- a\n+++ b\n@@ -2,1 +2,1 @@\n-2\n+X\n"},
+		// hunks are merged if there are at most twice the context lines between changes
+		{"merged hunks", "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n", "1\nX\n3\n4\n5\n6\n7\n8\nY\n10\n", 3,
+			"--- a\n+++ b\n@@ -1,10 +1,10 @@\n 1\n-2\n+X\n 3\n 4\n 5\n 6\n 7\n 8\n-9\n+Y\n 10\n"},
+		{"separate hunks", "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n", "1\nX\n3\n4\n5\n6\n7\n8\n9\nY\n11\n12\n", 3,
+			"--- a\n+++ b\n@@ -1,5 +1,5 @@\n 1\n-2\n+X\n 3\n 4\n 5\n@@ -7,6 +7,6 @@\n 7\n 8\n 9\n-10\n+Y\n 11\n 12\n"},
+		{"missing newline", "a\nb", "a\nb\n", 3,
+			"--- a\n+++ b\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n"},
+		{"both missing newlines", "a\nb", "a\nc", 3,
+			"--- a\n+++ b\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+c\n\\ No newline at end of file\n"},
+	}
+	for _, c := range cases {
+		diff := Unified("a", "b", c.from, c.to, c.context)
+		if diff != c.diff {
+			t.Errorf("%s: expected\n%s\ngot\n%s", c.name, c.diff, diff)
+		}
+	}
+}
+
+func TestFiles(t *testing.T) {
+	from := map[string][]byte{
+		"templates/a.yaml": []byte("a: 1\n"),
+		"templates/b.yaml": []byte("b: 1\n"),
+		"icon.png":         {0x89, 'P', 'N', 'G', 0},
+		"same.txt":         []byte("same\n"),
+	}
+	to := map[string][]byte{
+		"templates/a.yaml": []byte("a: 2\n"),
+		"templates/c.yaml": []byte("c: 1\n"),
+		"icon.png":         {0x89, 'P', 'N', 'G', 1},
+		"same.txt":         []byte("same\n"),
+	}
+	expected := []types.FileChange{
+		{Path: "icon.png", Type: Changed, Binary: true},
+		{Path: "templates/a.yaml", Type: Changed,
+			Diff: "--- a/templates/a.yaml\n+++ b/templates/a.yaml\n@@ -1,1 +1,1 @@\n-a: 1\n+a: 2\n"},
+		{Path: "templates/b.yaml", Type: Removed,
+			Diff: "--- a/templates/b.yaml\n+++ /dev/null\n@@ -1,1 +0,0 @@\n-b: 1\n"},
+		{Path: "templates/c.yaml", Type: Added,
+			Diff: "--- /dev/null\n+++ b/templates/c.yaml\n@@ -0,0 +1,1 @@\n+c: 1\n"},
+	}
+	changes := Files(from, to)
+	if !reflect.DeepEqual(changes, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, changes)
+	}
+}
+
+func TestIsBinary(t *testing.T) {
+	cases := []struct {
+		data   []byte
+		binary bool
+	}{
+		{nil, false},
+		{[]byte("text\n"), false},
+		{[]byte("中文\n"), false},
+		{[]byte{'a', 0, 'b'}, true},
+		{[]byte{0xff, 0xfe}, true},
+	}
+	for _, c := range cases {
+		if isBinary(c.data) != c.binary {
+			t.Errorf("%q: expected binary to be %v", c.data, c.binary)
+		}
+	}
+}
+
+// newChart creates a chart for tests
+func newChart(version, values string, templates map[string]string, dependencies ...*chart.Chart) *chart.Chart {
+	c := &chart.Chart{
+		Metadata:     &chart.Metadata{Name: "web", Version: version},
+		Values:       &chart.Config{Raw: values},
+		Dependencies: dependencies,
+	}
+	for name, data := range templates {
+		c.Templates = append(c.Templates, &chart.Template{Name: name, Data: []byte(data)})
+	}
+	return c
+}
+
+func TestCharts(t *testing.T) {
+	from := newChart("1.0.0", "replicas: 1\nimage:\n  tag: \"1.0\"\n",
+		map[string]string{"templates/deployment.yaml": "kind: Deployment\n"},
+		&chart.Chart{
+			Metadata: &chart.Metadata{Name: "db", Version: "0.1.0"},
+			Values:   &chart.Config{Raw: "port: 3306\n"},
+		})
+	to := newChart("1.1.0", "replicas: 2\nimage:\n  tag: \"1.0\"\n",
+		map[string]string{"templates/deployment.yaml": "kind: Deployment\n", "templates/service.yaml": "kind: Service\n"},
+		&chart.Chart{
+			Metadata: &chart.Metadata{Name: "db", Version: "0.1.0"},
+			Values:   &chart.Config{Raw: "port: 3307\n"},
+		})
+	to.Files = []*any.Any{{TypeUrl: "README.md", Value: []byte("# web\n")}}
+	result, err := Charts(from, to)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	metadata := []types.ValueChange{{Path: "version", Type: Changed, From: "1.0.0", To: "1.1.0"}}
+	if !reflect.DeepEqual(result.Metadata, metadata) {
+		t.Errorf("expected metadata changes %+v, got %+v", metadata, result.Metadata)
+	}
+	// values are coalesced with values of subcharts, and numbers are decoded from json
+	values := []types.ValueChange{
+		{Path: "db.port", Type: Changed, From: float64(3306), To: float64(3307)},
+		{Path: "replicas", Type: Changed, From: float64(1), To: float64(2)},
+	}
+	if !reflect.DeepEqual(result.Values, values) {
+		t.Errorf("expected value changes %+v, got %+v", values, result.Values)
+	}
+	paths := []string{}
+	for _, change := range result.Files {
+		paths = append(paths, change.Type+" "+change.Path)
+	}
+	expected := []string{
+		"added README.md",
+		"changed charts/db/values.yaml",
+		"added templates/service.yaml",
+	}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("expected file changes %q, got %q", expected, paths)
+	}
+}
+
+func TestChartsInvalidValues(t *testing.T) {
+	from := newChart("1.0.0", "a: 1\n", nil)
+	to := newChart("1.0.0", "a: [\n", nil)
+	if _, err := Charts(from, to); err == nil {
+		t.Fatal("expected an error for invalid values")
+	}
+}
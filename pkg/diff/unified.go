@@ -0,0 +1,176 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package diff
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// DefaultContext is the default number of context lines in a unified diff
+const DefaultContext = 3
+
+// edit is an operation of an edit script
+type edit struct {
+	// kind is one of ' ', '-' and '+'
+	kind byte
+	// text is the line
+	text string
+	// a and b are indexes of the line in the old and new text
+	a, b int
+}
+
+// noNewline marks a line at the end of text without a line break
+const noNewline = "\n\\ No newline at end of file"
+
+// splitLines splits text to lines without line breaks. If text doesn't end with a line
+// break, its last line is marked by noNewline so that it differs from the same line with
+// a line break.
+func splitLines(text string) []string {
+	if len(text) <= 0 {
+		return []string{}
+	}
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	if !strings.HasSuffix(text, "\n") {
+		lines[len(lines)-1] += noNewline
+	}
+	return lines
+}
+
+// editScript computes the shortest edit script from a to b by the Myers algorithm
+func editScript(a, b []string) []edit {
+	n, m := len(a), len(b)
+	max := n + m
+	offset := max + 1
+	v := make([]int, 2*max+3)
+	trace := [][]int{}
+	finished := false
+	for d := 0; d <= max && !finished; d++ {
+		trace = append(trace, append([]int(nil), v...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				finished = true
+				break
+			}
+		}
+	}
+	// backtrack from the end
+	edits := []edit{}
+	x, y := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		v := trace[d]
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			edits = append(edits, edit{' ', a[x], x, y})
+		}
+		if x == prevX {
+			y--
+			edits = append(edits, edit{'+', b[y], x, y})
+		} else {
+			x--
+			edits = append(edits, edit{'-', a[x], x, y})
+		}
+	}
+	for x > 0 && y > 0 {
+		x--
+		y--
+		edits = append(edits, edit{' ', a[x], x, y})
+	}
+	// reverse
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits
+}
+
+// Unified returns a unified diff of two texts with context lines. It returns an empty
+// string if the texts are equal.
+func Unified(fromName, toName string, from, to string, context int) string {
+	if from == to {
+		return ""
+	}
+	edits := editScript(splitLines(from), splitLines(to))
+	buf := bytes.NewBuffer(nil)
+	fmt.Fprintf(buf, "--- %s\n+++ %s\n", fromName, toName)
+	for start := 0; start < len(edits); {
+		// find the next change
+		for start < len(edits) && edits[start].kind == ' ' {
+			start++
+		}
+		if start >= len(edits) {
+			break
+		}
+		// extend the hunk until the gap between changes is larger than twice the context
+		end := start
+		for i := start; i < len(edits); i++ {
+			if edits[i].kind == ' ' {
+				if i-end > 2*context {
+					break
+				}
+				continue
+			}
+			end = i
+		}
+		first, last := start-context, end+context
+		if first < 0 {
+			first = 0
+		}
+		if last >= len(edits) {
+			last = len(edits) - 1
+		}
+		writeHunk(buf, edits[first:last+1])
+		start = last + 1
+	}
+	return buf.String()
+}
+
+// writeHunk writes a hunk of edits
+func writeHunk(buf *bytes.Buffer, edits []edit) {
+	aCount, bCount := 0, 0
+	for _, e := range edits {
+		if e.kind != '+' {
+			aCount++
+		}
+		if e.kind != '-' {
+			bCount++
+		}
+	}
+	aStart, bStart := edits[0].a, edits[0].b
+	if aCount > 0 {
+		aStart++
+	}
+	if bCount > 0 {
+		bStart++
+	}
+	fmt.Fprintf(buf, "@@ -%d,%d +%d,%d @@\n", aStart, aCount, bStart, bCount)
+	for _, e := range edits {
+		buf.WriteByte(e.kind)
+		buf.WriteString(e.text)
+		buf.WriteByte('\n')
+	}
+}
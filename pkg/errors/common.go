@@ -35,6 +35,16 @@ var (
 	ErrorResourceExist = NewFormatError(http.StatusConflict, ReasonInternal, "resource conflict because %s exist")
 	// ErrorLocking defines locking error
 	ErrorLocking = NewFormatError(http.StatusLocked, ReasonLocking, "%s is locked and can't be handled: %v")
+	// ErrorNotModified defines that a resource is not modified since the client got it
+	ErrorNotModified = NewFormatError(http.StatusNotModified, ReasonRequest, "%s is not modified")
+	// ErrorPreconditionFailed defines that a precondition of request is not satisfied
+	ErrorPreconditionFailed = NewFormatError(http.StatusPreconditionFailed, ReasonRequest, "precondition of %s failed: %s")
+	// ErrorUnsupportedMediaType defines that the content type of request is not supported
+	ErrorUnsupportedMediaType = NewFormatError(http.StatusUnsupportedMediaType, ReasonRequest, "content type %s is not supported, expected %s")
+	// ErrorUnprocessableEntity defines that a well-formed request can't be applied
+	ErrorUnprocessableEntity = NewFormatError(http.StatusUnprocessableEntity, ReasonRequest, "%s can't be applied: %v")
+	// ErrorReferenced defines that a resource can't be deleted or moved because others reference it
+	ErrorReferenced = NewFormatError(http.StatusConflict, ReasonRequest, "%s is referenced by %s")
 	// ErrorInvalidStatus defines invalid status error
 	ErrorInvalidStatus = NewFormatError(http.StatusConflict, ReasonInternal, "%s status is invalid: %v")
 
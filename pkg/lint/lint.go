@@ -0,0 +1,269 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+// Package lint checks whether charts are well-formed. Findings have severities, and only
+// error findings mean that a chart is broken.
+package lint
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/caicloud/helm-registry/pkg/render"
+	"github.com/caicloud/helm-registry/pkg/storage"
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+// Severity is the severity of a finding
+type Severity string
+
+const (
+	// SeverityError means the chart is broken
+	SeverityError Severity = "error"
+	// SeverityWarning means the chart may not work as expected
+	SeverityWarning Severity = "warning"
+	// SeverityInfo is a recommendation
+	SeverityInfo Severity = "info"
+)
+
+const (
+	// chartfileName is the name of Chart.yaml
+	chartfileName = "Chart.yaml"
+	// valuesName is the name of values.yaml
+	valuesName = "values.yaml"
+	// templatesName is the name of templates directory
+	templatesName = "templates"
+)
+
+// emailPattern matches email addresses of maintainers
+var emailPattern = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+
+// Finding is a problem found in a chart
+type Finding struct {
+	// Severity is the severity of finding
+	Severity Severity `json:"severity"`
+	// Path is the file which has the problem, e.g. Chart.yaml
+	Path string `json:"path"`
+	// Message describes the problem
+	Message string `json:"message"`
+}
+
+// String returns a human readable finding
+func (f Finding) String() string {
+	return fmt.Sprintf("[%s] %s: %s", strings.ToUpper(string(f.Severity)), f.Path, f.Message)
+}
+
+// Report is the result of linting a chart
+type Report struct {
+	// Findings are sorted by severities and paths
+	Findings []Finding `json:"findings"`
+}
+
+// add adds a finding to report
+func (r *Report) add(severity Severity, path string, format string, args ...interface{}) {
+	r.Findings = append(r.Findings, Finding{severity, path, fmt.Sprintf(format, args...)})
+}
+
+// Errors returns error findings
+func (r *Report) Errors() []Finding {
+	errs := []Finding{}
+	for _, finding := range r.Findings {
+		if finding.Severity == SeverityError {
+			errs = append(errs, finding)
+		}
+	}
+	return errs
+}
+
+// severityOrder is the order of severities in reports
+var severityOrder = map[Severity]int{
+	SeverityError:   0,
+	SeverityWarning: 1,
+	SeverityInfo:    2,
+}
+
+// sort sorts findings by severities and paths
+func (r *Report) sort() {
+	sort.SliceStable(r.Findings, func(i, j int) bool {
+		a, b := r.Findings[i], r.Findings[j]
+		if a.Severity != b.Severity {
+			return severityOrder[a.Severity] < severityOrder[b.Severity]
+		}
+		return a.Path < b.Path
+	})
+}
+
+// Archive lints a chart package. It returns an error if the package can't be loaded.
+func Archive(data []byte) (*Report, error) {
+	c, err := chartutil.LoadArchive(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	report := &Report{Findings: []Finding{}}
+	lintDirectory(report, data, c.Metadata.GetName())
+	lintChart(report, c)
+	report.sort()
+	return report, nil
+}
+
+// lintChart lints Chart.yaml, values.yaml and templates of a chart
+func lintChart(report *Report, c *chart.Chart) {
+	lintMetadata(report, c)
+	lintValues(report, c)
+	lintSchema(report, c)
+	lintTemplates(report, c)
+}
+
+// lintDirectory checks whether the package has only one top directory with the same name
+// as the chart
+func lintDirectory(report *Report, data []byte, name string) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return
+	}
+	defer reader.Close()
+	directories := map[string]bool{}
+	tr := tar.NewReader(reader)
+	for {
+		header, err := tr.Next()
+		if err != nil {
+			if err != io.EOF {
+				report.add(SeverityError, "", "can't read the chart package: %v", err)
+			}
+			break
+		}
+		if header.Typeflag == tar.TypeXGlobalHeader {
+			continue
+		}
+		// helm strips the first element of paths, which may be "." for packages without
+		// a top directory
+		directory := strings.SplitN(header.Name, "/", 2)[0]
+		if len(directory) > 0 {
+			directories[directory] = true
+		}
+	}
+	if len(directories) > 1 {
+		report.add(SeverityError, "", "chart package should have only one top directory, but got %d", len(directories))
+		return
+	}
+	for directory := range directories {
+		if directory != "." && directory != name {
+			report.add(SeverityError, chartfileName, "directory name (%s) and chart name (%s) must match", directory, name)
+		}
+	}
+}
+
+// lintMetadata checks required fields of Chart.yaml and recommends optional fields
+func lintMetadata(report *Report, c *chart.Chart) {
+	metadata := c.Metadata
+	if metadata == nil {
+		report.add(SeverityError, chartfileName, "%s is required", chartfileName)
+		return
+	}
+	switch {
+	case len(metadata.Name) <= 0:
+		report.add(SeverityError, chartfileName, "name is required")
+	case strings.ContainsAny(metadata.Name, "/\\ "):
+		report.add(SeverityError, chartfileName, "name %q can't contain slashes or spaces", metadata.Name)
+	}
+	if len(metadata.Version) <= 0 {
+		report.add(SeverityError, chartfileName, "version is required")
+	} else if v, err := semver.Parse(metadata.Version); err != nil {
+		report.add(SeverityError, chartfileName, "version %q is not a valid SemVer: %v", metadata.Version, err)
+	} else if v.Equals(semver.Version{}) {
+		report.add(SeverityError, chartfileName, "version 0.0.0 is less than or equal to 0")
+	}
+	if len(metadata.Description) <= 0 {
+		report.add(SeverityInfo, chartfileName, "description is recommended")
+	}
+	icon := metadata.GetIcon()
+	if len(icon) <= 0 {
+		report.add(SeverityInfo, chartfileName, "icon is recommended")
+	} else if iconPath := storage.IconPath(icon); len(iconPath) > 0 && !hasFile(c, iconPath) {
+		report.add(SeverityWarning, chartfileName, "icon %s is not in the chart package", iconPath)
+	} else if len(iconPath) <= 0 && !isURL(icon) {
+		report.add(SeverityWarning, chartfileName, "icon %q is not a valid url", icon)
+	}
+	if len(metadata.Maintainers) <= 0 {
+		report.add(SeverityInfo, chartfileName, "maintainers are recommended")
+	}
+	for i, maintainer := range metadata.Maintainers {
+		if len(maintainer.Name) <= 0 {
+			report.add(SeverityError, chartfileName, "name of maintainers[%d] is required", i)
+		}
+		if len(maintainer.Email) > 0 && !emailPattern.MatchString(maintainer.Email) {
+			report.add(SeverityError, chartfileName, "email of maintainers[%d] is invalid: %s", i, maintainer.Email)
+		}
+	}
+	if len(metadata.Home) > 0 && !isURL(metadata.Home) {
+		report.add(SeverityWarning, chartfileName, "home %q is not a valid url", metadata.Home)
+	}
+}
+
+// lintValues checks whether values.yaml can be parsed
+func lintValues(report *Report, c *chart.Chart) {
+	if c.Values == nil || len(strings.TrimSpace(c.Values.Raw)) <= 0 {
+		report.add(SeverityInfo, valuesName, "%s doesn't exist or is empty", valuesName)
+		return
+	}
+	if _, err := chartutil.ReadValues([]byte(c.Values.Raw)); err != nil {
+		report.add(SeverityError, valuesName, "can't parse %s: %v", valuesName, err)
+	}
+}
+
+// lintSchema checks whether values.schema.json of chart and its subcharts are valid and
+// default values satisfy them
+func lintSchema(report *Report, c *chart.Chart) {
+	values, err := storage.CoalesceValues(c)
+	if err != nil {
+		return
+	}
+	document, err := storage.ExtractSchema(c, values)
+	if err != nil {
+		report.add(SeverityError, storage.SchemaName, "%v", err)
+		return
+	}
+	if document == nil {
+		return
+	}
+	if err = storage.ValidateValues(document, values); err != nil {
+		report.add(SeverityError, valuesName, "values don't satisfy the schema: %v", err)
+	}
+}
+
+// lintTemplates checks whether templates can be parsed
+func lintTemplates(report *Report, c *chart.Chart) {
+	if len(c.Templates) <= 0 {
+		report.add(SeverityInfo, templatesName, "chart has no templates")
+		return
+	}
+	for name, err := range render.Parse(c) {
+		report.add(SeverityError, name, "can't parse template: %v", err)
+	}
+}
+
+// hasFile checks whether a chart has a file
+func hasFile(c *chart.Chart, name string) bool {
+	for _, file := range c.Files {
+		if file.TypeUrl == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isURL checks whether a string is an absolute url
+func isURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && len(u.Scheme) > 0 && len(u.Host) > 0
+}
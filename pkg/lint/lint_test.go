@@ -0,0 +1,224 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package lint
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// goodChartfile is a Chart.yaml without findings
+const goodChartfile = `name: web
+version: 1.0.0
+description: a web server
+icon: https://example.com/icon.png
+home: https://example.com
+maintainers:
+- name: admin
+  email: admin@example.com
+`
+
+// archive creates a chart package of files which are indexed by paths
+func archive(t *testing.T, files map[string]string) []byte {
+	buf := bytes.NewBuffer(nil)
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		header := &tar.Header{Name: path, Mode: 0644, Size: int64(len(files[path])), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(files[path])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// goodChart returns files of a chart without findings. Files can be replaced by changes,
+// and empty changes remove files.
+func goodChart(changes map[string]string) map[string]string {
+	files := map[string]string{
+		"web/Chart.yaml":                goodChartfile,
+		"web/values.yaml":               "replicas: 1\n",
+		"web/templates/deployment.yaml": "replicas: {{ .Values.replicas }}\n",
+	}
+	for path, content := range changes {
+		if len(content) <= 0 {
+			delete(files, path)
+			continue
+		}
+		files[path] = content
+	}
+	return files
+}
+
+// finding is an expected finding. Message is a part of the message of finding
+type finding struct {
+	severity Severity
+	path     string
+	message  string
+}
+
+func TestArchive(t *testing.T) {
+	cases := []struct {
+		name     string
+		files    map[string]string
+		findings []finding
+	}{
+		{"good chart", goodChart(nil), nil},
+		{"minimal chart", map[string]string{"web/Chart.yaml": "name: web\nversion: 1.0.0\n"}, []finding{
+			{SeverityInfo, "Chart.yaml", "description is recommended"},
+			{SeverityInfo, "Chart.yaml", "icon is recommended"},
+			{SeverityInfo, "Chart.yaml", "maintainers are recommended"},
+			{SeverityInfo, "templates", "chart has no templates"},
+			{SeverityInfo, "values.yaml", "doesn't exist or is empty"},
+		}},
+		{"missing version", goodChart(map[string]string{
+			"web/Chart.yaml": strings.Replace(goodChartfile, "version: 1.0.0\n", "", 1),
+		}), []finding{{SeverityError, "Chart.yaml", "version is required"}}},
+		{"invalid name", goodChart(map[string]string{
+			"web/Chart.yaml": strings.Replace(goodChartfile, "name: web", "name: my web", 1),
+		}), []finding{
+			{SeverityError, "Chart.yaml", "directory name (web) and chart name (my web) must match"},
+			{SeverityError, "Chart.yaml", "can't contain slashes or spaces"},
+		}},
+		{"invalid version", goodChart(map[string]string{
+			"web/Chart.yaml": strings.Replace(goodChartfile, "1.0.0", `"1.0"`, 1),
+		}), []finding{{SeverityError, "Chart.yaml", `version "1.0" is not a valid SemVer`}}},
+		{"zero version", goodChart(map[string]string{
+			"web/Chart.yaml": strings.Replace(goodChartfile, "1.0.0", "0.0.0", 1),
+		}), []finding{{SeverityError, "Chart.yaml", "less than or equal to 0"}}},
+		{"directory mismatch", map[string]string{
+			"app/Chart.yaml":                goodChartfile,
+			"app/values.yaml":               "replicas: 1\n",
+			"app/templates/deployment.yaml": "kind: Deployment\n",
+		}, []finding{{SeverityError, "Chart.yaml", "directory name (app) and chart name (web) must match"}}},
+		{"current directory", map[string]string{
+			"./Chart.yaml":                goodChartfile,
+			"./values.yaml":               "replicas: 1\n",
+			"./templates/deployment.yaml": "replicas: {{ .Values.replicas }}\n",
+		}, nil},
+		{"current directory and others", map[string]string{
+			"./Chart.yaml":    goodChartfile,
+			"web/values.yaml": "replicas: 1\n",
+		}, []finding{
+			{SeverityError, "", "only one top directory, but got 2"},
+			{SeverityInfo, "templates", "chart has no templates"},
+		}},
+		{"multiple directories", goodChart(map[string]string{"other/README.md": "# other\n"}),
+			[]finding{{SeverityError, "", "only one top directory, but got 2"}}},
+		{"invalid maintainers", goodChart(map[string]string{
+			"web/Chart.yaml": strings.Replace(goodChartfile, "- name: admin\n  email: admin@example.com\n", "- email: admin\n", 1),
+		}), []finding{
+			{SeverityError, "Chart.yaml", "name of maintainers[0] is required"},
+			{SeverityError, "Chart.yaml", "email of maintainers[0] is invalid: admin"},
+		}},
+		{"invalid urls", goodChart(map[string]string{
+			"web/Chart.yaml": strings.Replace(strings.Replace(goodChartfile,
+				"https://example.com/icon.png", "icon.png", 1), "home: https://example.com", "home: example.com", 1),
+		}), []finding{
+			{SeverityWarning, "Chart.yaml", "icon icon.png is not in the chart package"},
+			{SeverityWarning, "Chart.yaml", `home "example.com" is not a valid url`},
+		}},
+		{"packaged icon", goodChart(map[string]string{
+			"web/Chart.yaml": strings.Replace(goodChartfile, "https://example.com/icon.png", "file://icon.png", 1),
+			"web/icon.png":   "png",
+		}), nil},
+		{"invalid values", goodChart(map[string]string{"web/values.yaml": "a: [\n"}),
+			[]finding{{SeverityError, "values.yaml", "can't parse values.yaml"}}},
+		{"invalid templates", goodChart(map[string]string{
+			"web/templates/broken.yaml":  "{{ .Values.a | unknownFunc }}",
+			"web/templates/_helpers.tpl": "{{ define \"x\" }}",
+		}), []finding{
+			{SeverityError, "templates/_helpers.tpl", "can't parse template"},
+			{SeverityError, "templates/broken.yaml", "can't parse template"},
+		}},
+		{"valid schema", goodChart(map[string]string{
+			"web/values.schema.json": `{"type":"object","properties":{"replicas":{"type":"integer","minimum":1}}}`,
+		}), nil},
+		{"values don't satisfy schema", goodChart(map[string]string{
+			"web/values.schema.json": `{"type":"object","properties":{"replicas":{"type":"integer","minimum":2}}}`,
+		}), []finding{{SeverityError, "values.yaml", "values don't satisfy the schema"}}},
+		{"invalid schema", goodChart(map[string]string{"web/values.schema.json": `{"type":`}),
+			[]finding{{SeverityError, "values.schema.json", "values.schema.json of web is invalid"}}},
+	}
+	for _, c := range cases {
+		report, err := Archive(archive(t, c.files))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", c.name, err)
+			continue
+		}
+		if len(report.Findings) != len(c.findings) {
+			t.Errorf("%s: expected %d findings, got %v", c.name, len(c.findings), report.Findings)
+			continue
+		}
+		for i, f := range report.Findings {
+			expected := c.findings[i]
+			if f.Severity != expected.severity || f.Path != expected.path || !strings.Contains(f.Message, expected.message) {
+				t.Errorf("%s: expected finding %v, got %v", c.name, expected, f)
+			}
+		}
+	}
+}
+
+func TestArchiveErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		data []byte
+	}{
+		{"not gzip", []byte("chart")},
+		{"no Chart.yaml", archive(t, map[string]string{"web/values.yaml": "a: 1\n"})},
+		{"invalid Chart.yaml", archive(t, map[string]string{"web/Chart.yaml": "name: [\n"})},
+		{"missing name", archive(t, goodChart(map[string]string{
+			"web/Chart.yaml": strings.Replace(goodChartfile, "name: web\n", "", 1),
+		}))},
+	}
+	for _, c := range cases {
+		if _, err := Archive(c.data); err == nil {
+			t.Errorf("%s: expected an error", c.name)
+		}
+	}
+}
+
+func TestReport(t *testing.T) {
+	report := &Report{}
+	report.add(SeverityInfo, "b", "info")
+	report.add(SeverityError, "b", "error b")
+	report.add(SeverityWarning, "a", "warning")
+	report.add(SeverityError, "a", "error %s", "a")
+	report.sort()
+	expected := []Finding{
+		{SeverityError, "a", "error a"},
+		{SeverityError, "b", "error b"},
+		{SeverityWarning, "a", "warning"},
+		{SeverityInfo, "b", "info"},
+	}
+	if !reflect.DeepEqual(report.Findings, expected) {
+		t.Fatalf("expected %v, got %v", expected, report.Findings)
+	}
+	if errs := report.Errors(); !reflect.DeepEqual(errs, expected[:2]) {
+		t.Fatalf("expected errors %v, got %v", expected[:2], errs)
+	}
+	if s := expected[2].String(); s != "[WARNING] a: warning" {
+		t.Fatalf("unexpected string %q", s)
+	}
+}
@@ -160,8 +160,8 @@ func (rl *ResourceLock) Get(res ...string) Locker {
 		if !ok {
 			lock = NewHierarchicalLock(rl.CreateLock())
 			children[r] = lock
-			children = lock.Children
 		}
+		children = lock.Children
 		result.locks[i] = lock.Lock
 	}
 	log.Debugf("get locks %s", result.Name())
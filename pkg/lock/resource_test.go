@@ -114,3 +114,35 @@ func TestLockConflict(t *testing.T) {
 		t.Fatal("lock invalid")
 	}
 }
+
+func TestNestedLocks(t *testing.T) {
+	// the first Get creates the hierarchy, and later ones must walk the same hierarchy
+	first := locker.Get("nested", "child")
+	second := locker.Get("nested", "child")
+	other := locker.Get("child")
+	if !first.Lock(TimeoutImmediate) {
+		t.Fatal("can't lock")
+	}
+	if second.RLock(TimeoutImmediate) {
+		second.RUnlock()
+		t.Fatal("locks of the same resource don't conflict")
+	}
+	first.Unlock()
+	if !other.Lock(TimeoutImmediate) {
+		t.Fatal("can't lock")
+	}
+	if !second.Lock(TimeoutImmediate) {
+		t.Fatal("a nested resource conflicts with a top resource of the same name")
+	}
+	second.Unlock()
+	other.Unlock()
+	parent := locker.Get("nested")
+	if !parent.Lock(TimeoutImmediate) {
+		t.Fatal("can't lock")
+	}
+	if second.RLock(TimeoutImmediate) {
+		second.RUnlock()
+		t.Fatal("a nested resource is not locked by its parent")
+	}
+	parent.Unlock()
+}
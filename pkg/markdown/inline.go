@@ -0,0 +1,359 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// punctuations are characters which can be escaped by backslashes
+const punctuations = "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+
+var (
+	autolinkPattern = regexp.MustCompile(`^<([a-zA-Z][a-zA-Z0-9+.-]{1,31}:[^<>\s]*)>`)
+	emailPattern    = regexp.MustCompile(`^<([a-zA-Z0-9.!#$%&'*+/=?^_{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?)*)>`)
+)
+
+// renderInline renders inline elements of text
+func renderInline(text string) string {
+	buf := bytes.NewBuffer(nil)
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch c {
+		case '\\':
+			if i+1 < len(text) && strings.IndexByte(punctuations, text[i+1]) >= 0 {
+				buf.WriteString(html.EscapeString(text[i+1 : i+2]))
+				i += 2
+				continue
+			}
+			if i+1 < len(text) && text[i+1] == '\n' {
+				buf.WriteString("<br />\n")
+				i += 2
+				continue
+			}
+		case '\n':
+			if bytes.HasSuffix(buf.Bytes(), []byte("  ")) {
+				buf.Truncate(len(bytes.TrimRight(buf.Bytes(), " ")))
+				buf.WriteString("<br />\n")
+			} else {
+				buf.WriteString("\n")
+			}
+			i++
+			continue
+		case '`':
+			if n := renderCodeSpan(buf, text[i:]); n > 0 {
+				i += n
+				continue
+			}
+			// keep the whole backtick string literal
+			n := len(text[i:]) - len(strings.TrimLeft(text[i:], "`"))
+			buf.WriteString(text[i : i+n])
+			i += n
+			continue
+		case '!':
+			if i+1 < len(text) && text[i+1] == '[' {
+				if n := renderLink(buf, text[i+1:], true); n > 0 {
+					i += n + 1
+					continue
+				}
+			}
+		case '[':
+			if n := renderLink(buf, text[i:], false); n > 0 {
+				i += n
+				continue
+			}
+		case '<':
+			if n := renderAutolink(buf, text[i:]); n > 0 {
+				i += n
+				continue
+			}
+		case '*', '_', '~':
+			if n := renderEmphasis(buf, text, i); n > 0 {
+				i += n
+				continue
+			}
+			// keep the whole delimiter run literal
+			n := len(text[i:]) - len(strings.TrimLeft(text[i:], text[i:i+1]))
+			buf.WriteString(text[i : i+n])
+			i += n
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(text[i:])
+		buf.WriteString(html.EscapeString(text[i : i+size]))
+		i += size
+	}
+	return buf.String()
+}
+
+// renderCodeSpan renders a code span at the beginning of text. It returns the length of
+// the code span, or 0 if there is no code span.
+func renderCodeSpan(buf *bytes.Buffer, text string) int {
+	n := len(text) - len(strings.TrimLeft(text, "`"))
+	fence := text[:n]
+	for start := n; start < len(text); {
+		index := strings.Index(text[start:], fence)
+		if index < 0 {
+			return 0
+		}
+		end := start + index
+		// the closing backtick string must have the same length
+		if end+n < len(text) && text[end+n] == '`' {
+			start = end + n + len(text[end+n:]) - len(strings.TrimLeft(text[end+n:], "`"))
+			continue
+		}
+		code := strings.Replace(text[n:end], "\n", " ", -1)
+		if len(strings.TrimSpace(code)) > 0 && strings.HasPrefix(code, " ") && strings.HasSuffix(code, " ") {
+			code = code[1 : len(code)-1]
+		}
+		fmt.Fprintf(buf, "<code>%s</code>", html.EscapeString(code))
+		return end + n
+	}
+	return 0
+}
+
+// renderLink renders a link or an image at the beginning of text, which starts with '['.
+// It returns the length of the link, or 0 if there is no link.
+func renderLink(buf *bytes.Buffer, text string, image bool) int {
+	// find the end of link text. Brackets can be nested
+	depth, end := 0, -1
+	for i := 0; i < len(text) && end < 0; i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '`':
+			n := len(text[i:]) - len(strings.TrimLeft(text[i:], "`"))
+			if index := strings.Index(text[i+n:], text[i:i+n]); index >= 0 {
+				i += n + index + n - 1
+			}
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				end = i
+			}
+		}
+	}
+	if end < 0 || end+1 >= len(text) || text[end+1] != '(' {
+		return 0
+	}
+	// find the end of destination and title. Parentheses can be nested
+	depth, close := 0, -1
+	for i := end + 1; i < len(text) && close < 0; i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				close = i
+			}
+		}
+	}
+	if close < 0 {
+		return 0
+	}
+	destination, title, ok := parseDestination(text[end+2 : close])
+	if !ok {
+		return 0
+	}
+	label := text[1:end]
+	href, safe := safeURL(destination)
+	switch {
+	case image && safe:
+		fmt.Fprintf(buf, `<img src="%s" alt="%s"`, html.EscapeString(href), html.EscapeString(plainText(label)))
+		if len(title) > 0 {
+			fmt.Fprintf(buf, ` title="%s"`, html.EscapeString(title))
+		}
+		buf.WriteString(" />")
+	case image:
+		buf.WriteString(html.EscapeString(plainText(label)))
+	case safe:
+		fmt.Fprintf(buf, `<a href="%s"`, html.EscapeString(href))
+		if len(title) > 0 {
+			fmt.Fprintf(buf, ` title="%s"`, html.EscapeString(title))
+		}
+		fmt.Fprintf(buf, ">%s</a>", renderInline(label))
+	default:
+		buf.WriteString(renderInline(label))
+	}
+	return close + 1
+}
+
+// parseDestination parses the destination and optional title of a link
+func parseDestination(text string) (string, string, bool) {
+	text = strings.TrimSpace(text)
+	destination := text
+	title := ""
+	if strings.HasPrefix(text, "<") {
+		end := strings.IndexByte(text, '>')
+		if end < 0 {
+			return "", "", false
+		}
+		destination, text = text[1:end], strings.TrimSpace(text[end+1:])
+	} else if index := strings.IndexAny(text, " \n"); index >= 0 {
+		destination, text = text[:index], strings.TrimSpace(text[index:])
+	} else {
+		text = ""
+	}
+	if len(text) > 0 {
+		if len(text) < 2 {
+			return "", "", false
+		}
+		open, close := text[0], text[len(text)-1]
+		if !(open == '"' && close == '"') && !(open == '\'' && close == '\'') && !(open == '(' && close == ')') {
+			return "", "", false
+		}
+		title = unescapeText(text[1 : len(text)-1])
+	}
+	return unescapeText(destination), title, true
+}
+
+// renderAutolink renders an autolink at the beginning of text, which starts with '<'.
+// It returns the length of the autolink, or 0 if there is no autolink.
+func renderAutolink(buf *bytes.Buffer, text string) int {
+	if match := autolinkPattern.FindStringSubmatch(text); match != nil {
+		if href, ok := safeURL(match[1]); ok {
+			fmt.Fprintf(buf, `<a href="%s">%s</a>`, html.EscapeString(href), html.EscapeString(match[1]))
+		} else {
+			buf.WriteString(html.EscapeString(match[1]))
+		}
+		return len(match[0])
+	}
+	if match := emailPattern.FindStringSubmatch(text); match != nil {
+		fmt.Fprintf(buf, `<a href="mailto:%s">%s</a>`, html.EscapeString(match[1]), html.EscapeString(match[1]))
+		return len(match[0])
+	}
+	return 0
+}
+
+// emphasisTags are tags of delimiter runs
+var emphasisTags = map[string]string{
+	"*":  "em",
+	"_":  "em",
+	"**": "strong",
+	"__": "strong",
+	"~~": "del",
+}
+
+// renderEmphasis renders an emphasis which starts at text[i]. It returns the length of the
+// emphasis, or 0 if there is no emphasis.
+func renderEmphasis(buf *bytes.Buffer, text string, i int) int {
+	c := text[i : i+1]
+	run := len(text[i:]) - len(strings.TrimLeft(text[i:], c))
+	if run > 2 {
+		run = 2
+	}
+	delimiter := strings.Repeat(c, run)
+	tag, ok := emphasisTags[delimiter]
+	if !ok {
+		return 0
+	}
+	start := i + run
+	// the opening delimiter must be followed by a non-space character, and '_' can't be
+	// used inside words
+	if start >= len(text) || isSpace(text[start:]) {
+		return 0
+	}
+	if c == "_" && i > 0 && isWordEnd(text[:i]) {
+		return 0
+	}
+	for end := start + 1; end+run <= len(text); end++ {
+		switch text[end] {
+		case '\\':
+			end++
+			continue
+		case '`':
+			// code spans take precedence over emphasis
+			n := len(text[end:]) - len(strings.TrimLeft(text[end:], "`"))
+			if index := strings.Index(text[end+n:], text[end:end+n]); index >= 0 {
+				end += n + index + n - 1
+			}
+			continue
+		}
+		if text[end:end+run] != delimiter || isSpaceBefore(text[:end]) {
+			continue
+		}
+		after := end + run
+		// skip delimiters which are part of a longer run, e.g. the first '*' of '**'
+		if after < len(text) && text[after:after+1] == c {
+			if run == 1 {
+				end += len(text[end:]) - len(strings.TrimLeft(text[end:], c)) - 1
+			}
+			continue
+		}
+		if c == "_" && after < len(text) && isWordStart(text[after:]) {
+			continue
+		}
+		fmt.Fprintf(buf, "<%s>%s</%s>", tag, renderInline(text[start:end]), tag)
+		return after - i
+	}
+	return 0
+}
+
+// isSpace checks whether text starts with a white space
+func isSpace(text string) bool {
+	r, _ := utf8.DecodeRuneInString(text)
+	return unicode.IsSpace(r)
+}
+
+// isSpaceBefore checks whether text ends with a white space
+func isSpaceBefore(text string) bool {
+	r, _ := utf8.DecodeLastRuneInString(text)
+	return unicode.IsSpace(r)
+}
+
+// isWordStart checks whether text starts with a letter or a digit
+func isWordStart(text string) bool {
+	r, _ := utf8.DecodeRuneInString(text)
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// isWordEnd checks whether text ends with a letter or a digit
+func isWordEnd(text string) bool {
+	r, _ := utf8.DecodeLastRuneInString(text)
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// unescapeText removes backslashes before punctuations
+func unescapeText(text string) string {
+	buf := bytes.NewBuffer(nil)
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\\' && i+1 < len(text) && strings.IndexByte(punctuations, text[i+1]) >= 0 {
+			i++
+		}
+		buf.WriteByte(text[i])
+	}
+	return buf.String()
+}
+
+// plainText returns text of inline elements without markups. It's used by alternative text
+// of images.
+func plainText(text string) string {
+	return strings.NewReplacer("*", "", "_", "", "`", "", "[", "", "]", "").Replace(unescapeText(text))
+}
+
+// safeURL checks whether a url can be used in links. Relative urls and urls with http,
+// https and mailto schemes are safe.
+func safeURL(rawURL string) (string, bool) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "", "http", "https", "mailto":
+		return u.String(), true
+	}
+	return "", false
+}
@@ -0,0 +1,471 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+// Package markdown renders a common subset of Markdown to HTML. It supports headings,
+// paragraphs, block quotes, lists, code blocks, tables, rules, emphasis, code spans, links
+// and images. Raw HTML is escaped and links with unsafe schemes are dropped, so the result
+// can be embedded in pages directly.
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ToHTML renders markdown to HTML
+func ToHTML(source []byte) []byte {
+	text := strings.Replace(string(source), "\r\n", "\n", -1)
+	text = strings.Replace(text, "\r", "\n", -1)
+	// the line break at the end doesn't start a line
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = expandTabs(line)
+	}
+	buf := bytes.NewBuffer(nil)
+	renderBlocks(buf, lines, false)
+	return buf.Bytes()
+}
+
+// expandTabs replaces tabs in the indent of a line with spaces. A tab stop is 4 spaces.
+func expandTabs(line string) string {
+	width := 0
+	for i, c := range line {
+		switch c {
+		case ' ':
+			width++
+		case '\t':
+			width += 4 - width%4
+		default:
+			return strings.Repeat(" ", width) + line[i:]
+		}
+	}
+	return strings.Repeat(" ", width)
+}
+
+// indentOf returns the number of leading spaces of a line
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// isBlank checks whether a line contains only spaces
+func isBlank(line string) bool {
+	return len(strings.TrimSpace(line)) <= 0
+}
+
+// unindent removes at most n leading spaces from a line
+func unindent(line string, n int) string {
+	if indent := indentOf(line); indent < n {
+		n = indent
+	}
+	return line[n:]
+}
+
+var (
+	headingPattern   = regexp.MustCompile(`^(#{1,6})(?:[ ]+(.*?))?(?:[ ]+#+)?[ ]*$`)
+	rulePattern      = regexp.MustCompile(`^(?:(?:\*[ ]*){3,}|(?:-[ ]*){3,}|(?:_[ ]*){3,})$`)
+	fencePattern     = regexp.MustCompile("^(`{3,}|~{3,})[ ]*([^`]*)$")
+	bulletPattern    = regexp.MustCompile(`^([-*+])(?:[ ]+|$)`)
+	orderedPattern   = regexp.MustCompile(`^([0-9]{1,9})([.)])(?:[ ]+|$)`)
+	delimiterPattern = regexp.MustCompile(`^\|?[ ]*:?-+:?[ ]*(?:\|[ ]*:?-+:?[ ]*)*\|?$`)
+)
+
+// renderBlocks renders lines as blocks. Paragraphs are not wrapped by <p> if tight is true,
+// which is used by items of tight lists.
+func renderBlocks(buf *bytes.Buffer, lines []string, tight bool) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		if isBlank(line) {
+			i++
+			continue
+		}
+		if indentOf(line) >= 4 {
+			i = renderIndentedCode(buf, lines, i)
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		if fencePattern.MatchString(trimmed) {
+			i = renderFencedCode(buf, lines, i)
+			continue
+		}
+		if match := headingPattern.FindStringSubmatch(trimmed); match != nil {
+			writeHeading(buf, len(match[1]), match[2])
+			i++
+			continue
+		}
+		if rulePattern.MatchString(trimmed) {
+			buf.WriteString("<hr />\n")
+			i++
+			continue
+		}
+		if strings.HasPrefix(strings.TrimLeft(line, " "), ">") {
+			i = renderBlockquote(buf, lines, i)
+			continue
+		}
+		if _, ok := listMarker(line); ok {
+			i = renderList(buf, lines, i)
+			continue
+		}
+		if isTable(lines, i) {
+			i = renderTable(buf, lines, i)
+			continue
+		}
+		i = renderParagraph(buf, lines, i, tight)
+	}
+}
+
+// startsBlock checks whether a line interrupts a paragraph
+func startsBlock(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	if indentOf(line) >= 4 {
+		return false
+	}
+	if fencePattern.MatchString(trimmed) || headingPattern.MatchString(trimmed) ||
+		rulePattern.MatchString(trimmed) || strings.HasPrefix(strings.TrimLeft(line, " "), ">") {
+		return true
+	}
+	marker, ok := listMarker(line)
+	// an empty item or an ordered list which doesn't start with 1 can't interrupt a paragraph
+	return ok && !isBlank(marker.content(line)) && (!marker.ordered || marker.start == 1)
+}
+
+// writeHeading writes a heading
+func writeHeading(buf *bytes.Buffer, level int, text string) {
+	fmt.Fprintf(buf, "<h%d>%s</h%d>\n", level, renderInline(strings.TrimSpace(text)), level)
+}
+
+// renderIndentedCode renders an indented code block from lines[i] and returns the index
+// of the next line
+func renderIndentedCode(buf *bytes.Buffer, lines []string, i int) int {
+	code := []string{}
+	end := i
+	for ; i < len(lines) && (isBlank(lines[i]) || indentOf(lines[i]) >= 4); i++ {
+		code = append(code, unindent(lines[i], 4))
+		if !isBlank(lines[i]) {
+			end = i
+		}
+	}
+	code = code[:len(code)-(i-1-end)]
+	writeCode(buf, "", code)
+	return end + 1
+}
+
+// renderFencedCode renders a fenced code block from lines[i] and returns the index of the
+// next line. An unclosed block ends at the end of lines.
+func renderFencedCode(buf *bytes.Buffer, lines []string, i int) int {
+	indent := indentOf(lines[i])
+	match := fencePattern.FindStringSubmatch(strings.TrimSpace(lines[i]))
+	fence, info := match[1], strings.TrimSpace(match[2])
+	language := ""
+	if fields := strings.Fields(info); len(fields) > 0 {
+		language = fields[0]
+	}
+	code := []string{}
+	for i++; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if indentOf(lines[i]) < 4 && strings.HasPrefix(trimmed, fence) &&
+			len(strings.Trim(trimmed, fence[:1])) <= 0 {
+			i++
+			break
+		}
+		code = append(code, unindent(lines[i], indent))
+	}
+	writeCode(buf, language, code)
+	return i
+}
+
+// writeCode writes a code block
+func writeCode(buf *bytes.Buffer, language string, code []string) {
+	buf.WriteString("<pre><code")
+	if len(language) > 0 {
+		fmt.Fprintf(buf, ` class="language-%s"`, html.EscapeString(unescapeText(language)))
+	}
+	buf.WriteString(">")
+	for _, line := range code {
+		buf.WriteString(html.EscapeString(line))
+		buf.WriteString("\n")
+	}
+	buf.WriteString("</code></pre>\n")
+}
+
+// renderBlockquote renders a block quote from lines[i] and returns the index of the next
+// line. Lines without '>' are lazy continuations until a blank line.
+func renderBlockquote(buf *bytes.Buffer, lines []string, i int) int {
+	inner := []string{}
+	for ; i < len(lines) && !isBlank(lines[i]); i++ {
+		trimmed := strings.TrimLeft(lines[i], " ")
+		if !strings.HasPrefix(trimmed, ">") || indentOf(lines[i]) >= 4 {
+			if startsBlock(lines[i]) {
+				break
+			}
+			inner = append(inner, lines[i])
+			continue
+		}
+		trimmed = trimmed[1:]
+		if strings.HasPrefix(trimmed, " ") {
+			trimmed = trimmed[1:]
+		}
+		inner = append(inner, expandTabs(trimmed))
+	}
+	buf.WriteString("<blockquote>\n")
+	renderBlocks(buf, inner, false)
+	buf.WriteString("</blockquote>\n")
+	return i
+}
+
+// marker is the marker of a list item
+type marker struct {
+	// ordered means the item is in an ordered list
+	ordered bool
+	// start is the number of an ordered item
+	start int
+	// delimiter is the bullet of an unordered item or the delimiter of an ordered item
+	delimiter byte
+	// offset is the position where content of the item starts
+	offset int
+}
+
+// content returns the content of an item in the first line of the item
+func (m *marker) content(line string) string {
+	if m.offset >= len(line) {
+		return ""
+	}
+	return line[m.offset:]
+}
+
+// listMarker parses the marker of a list item
+func listMarker(line string) (*marker, bool) {
+	indent := indentOf(line)
+	if indent >= 4 {
+		return nil, false
+	}
+	rest := line[indent:]
+	result := &marker{}
+	var width int
+	if match := bulletPattern.FindStringSubmatch(rest); match != nil {
+		result.delimiter = match[1][0]
+		width = len(match[0])
+	} else if match := orderedPattern.FindStringSubmatch(rest); match != nil {
+		result.ordered = true
+		result.start, _ = strconv.Atoi(match[1])
+		result.delimiter = match[2][0]
+		width = len(match[0])
+	} else {
+		return nil, false
+	}
+	// content which is indented too much is an indented code block in the item
+	markerWidth := len(strings.TrimRight(rest[:width], " "))
+	if spaces := width - markerWidth; spaces > 4 || width >= len(rest) {
+		width = markerWidth + 1
+	}
+	result.offset = indent + width
+	return result, true
+}
+
+// sameList checks whether two markers are in a same list
+func sameList(a, b *marker) bool {
+	return a.ordered == b.ordered && a.delimiter == b.delimiter
+}
+
+// renderList renders a list from lines[i] and returns the index of the next line
+func renderList(buf *bytes.Buffer, lines []string, i int) int {
+	first, _ := listMarker(lines[i])
+	items := [][]string{}
+	loose := false
+	for i < len(lines) {
+		current, ok := listMarker(lines[i])
+		if !ok || !sameList(first, current) {
+			break
+		}
+		item := []string{expandTabs(current.content(lines[i]))}
+		blank := false
+		for i++; i < len(lines); i++ {
+			line := lines[i]
+			if isBlank(line) {
+				blank = true
+				item = append(item, "")
+				continue
+			}
+			if indentOf(line) >= current.offset {
+				if blank && len(item) > 0 {
+					loose = loose || hasContent(item)
+				}
+				blank = false
+				item = append(item, line[current.offset:])
+				continue
+			}
+			if blank {
+				break
+			}
+			// lazy continuation of a paragraph
+			if _, ok := listMarker(line); ok || startsBlock(line) {
+				break
+			}
+			item = append(item, strings.TrimLeft(line, " "))
+		}
+		items = append(items, trimBlankLines(item))
+		if blank && i < len(lines) {
+			next, ok := listMarker(lines[i])
+			if !ok || !sameList(first, next) {
+				break
+			}
+			loose = true
+		}
+	}
+	tag := "ul"
+	if first.ordered {
+		tag = "ol"
+	}
+	if first.ordered && first.start != 1 {
+		fmt.Fprintf(buf, "<ol start=\"%d\">\n", first.start)
+	} else {
+		fmt.Fprintf(buf, "<%s>\n", tag)
+	}
+	for _, item := range items {
+		inner := bytes.NewBuffer(nil)
+		renderBlocks(inner, item, !loose)
+		buf.WriteString("<li>")
+		buf.WriteString(strings.TrimSuffix(inner.String(), "\n"))
+		buf.WriteString("</li>\n")
+	}
+	fmt.Fprintf(buf, "</%s>\n", tag)
+	return i
+}
+
+// hasContent checks whether lines contain a non-blank line
+func hasContent(lines []string) bool {
+	for _, line := range lines {
+		if !isBlank(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// trimBlankLines removes trailing blank lines
+func trimBlankLines(lines []string) []string {
+	for len(lines) > 0 && isBlank(lines[len(lines)-1]) {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// isTable checks whether lines[i] is the header row of a table
+func isTable(lines []string, i int) bool {
+	if i+1 >= len(lines) || !strings.Contains(lines[i], "|") {
+		return false
+	}
+	delimiter := strings.TrimSpace(lines[i+1])
+	if !delimiterPattern.MatchString(delimiter) || !strings.ContainsAny(delimiter, "|:") {
+		return false
+	}
+	return len(splitRow(lines[i])) == len(splitRow(lines[i+1]))
+}
+
+// splitRow splits a row of table to cells. Escaped pipes are kept in cells.
+func splitRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+	cells := []string{}
+	start := 0
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '|':
+			cells = append(cells, strings.TrimSpace(line[start:i]))
+			start = i + 1
+		}
+	}
+	return append(cells, strings.TrimSpace(line[start:]))
+}
+
+// renderTable renders a table from lines[i] and returns the index of the next line
+func renderTable(buf *bytes.Buffer, lines []string, i int) int {
+	header := splitRow(lines[i])
+	aligns := []string{}
+	for _, cell := range splitRow(lines[i+1]) {
+		left, right := strings.HasPrefix(cell, ":"), strings.HasSuffix(cell, ":")
+		switch {
+		case left && right:
+			aligns = append(aligns, "center")
+		case left:
+			aligns = append(aligns, "left")
+		case right:
+			aligns = append(aligns, "right")
+		default:
+			aligns = append(aligns, "")
+		}
+	}
+	buf.WriteString("<table>\n<thead>\n")
+	writeRow(buf, "th", header, aligns)
+	buf.WriteString("</thead>\n")
+	i += 2
+	if i < len(lines) && !isBlank(lines[i]) && strings.Contains(lines[i], "|") {
+		buf.WriteString("<tbody>\n")
+		for ; i < len(lines) && !isBlank(lines[i]) && strings.Contains(lines[i], "|"); i++ {
+			writeRow(buf, "td", splitRow(lines[i]), aligns)
+		}
+		buf.WriteString("</tbody>\n")
+	}
+	buf.WriteString("</table>\n")
+	return i
+}
+
+// writeRow writes a row of table. Missing cells are empty and extra cells are ignored.
+func writeRow(buf *bytes.Buffer, tag string, cells []string, aligns []string) {
+	buf.WriteString("<tr>\n")
+	for i, align := range aligns {
+		cell := ""
+		if i < len(cells) {
+			cell = strings.Replace(cells[i], `\|`, "|", -1)
+		}
+		if len(align) > 0 {
+			fmt.Fprintf(buf, "<%s style=\"text-align: %s\">%s</%s>\n", tag, align, renderInline(cell), tag)
+		} else {
+			fmt.Fprintf(buf, "<%s>%s</%s>\n", tag, renderInline(cell), tag)
+		}
+	}
+	buf.WriteString("</tr>\n")
+}
+
+// renderParagraph renders a paragraph from lines[i] and returns the index of the next line.
+// A paragraph followed by a line of '=' or '-' is a heading.
+func renderParagraph(buf *bytes.Buffer, lines []string, i int, tight bool) int {
+	paragraph := []string{strings.TrimLeft(lines[i], " ")}
+	for i++; i < len(lines); i++ {
+		line := lines[i]
+		if isBlank(line) {
+			break
+		}
+		trimmed := strings.TrimSpace(line)
+		if indentOf(line) < 4 && len(strings.Trim(trimmed, "=")) <= 0 {
+			writeHeading(buf, 1, strings.Join(paragraph, "\n"))
+			return i + 1
+		}
+		if indentOf(line) < 4 && len(strings.Trim(trimmed, "-")) <= 0 {
+			writeHeading(buf, 2, strings.Join(paragraph, "\n"))
+			return i + 1
+		}
+		if startsBlock(line) || isTable(lines, i) {
+			break
+		}
+		paragraph = append(paragraph, strings.TrimLeft(line, " "))
+	}
+	// keep trailing spaces of inner lines for hard line breaks
+	text := renderInline(strings.TrimRight(strings.Join(paragraph, "\n"), " "))
+	if tight {
+		buf.WriteString(text)
+		buf.WriteString("\n")
+	} else {
+		fmt.Fprintf(buf, "<p>%s</p>\n", text)
+	}
+	return i
+}
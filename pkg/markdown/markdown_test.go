@@ -0,0 +1,147 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package markdown
+
+import (
+	"testing"
+)
+
+func TestBlocks(t *testing.T) {
+	cases := []struct {
+		name, source, html string
+	}{
+		{"empty", "", ""},
+		{"paragraphs", "a\nb\n\nc\n", "<p>a\nb</p>\n<p>c</p>\n"},
+		{"crlf", "a\r\nb\r\n\r\nc", "<p>a\nb</p>\n<p>c</p>\n"},
+		{"atx headings", "# a\n###### b ##\n####### c\n#d",
+			"<h1>a</h1>\n<h6>b</h6>\n<p>####### c\n#d</p>\n"},
+		{"empty heading", "#\n", "<h1></h1>\n"},
+		{"setext headings", "a\n===\nb\n---\n", "<h1>a</h1>\n<h2>b</h2>\n"},
+		{"rules", "***\n- - -\n___\n", "<hr />\n<hr />\n<hr />\n"},
+		{"indented code", "    a <b>\n\n    c\n\n\nd", "<pre><code>a &lt;b&gt;\n\nc\n</code></pre>\n<p>d</p>\n"},
+		{"tabs in indent", "\tcode\n", "<pre><code>code\n</code></pre>\n"},
+		{"fenced code", "```yaml\nkey: <v>\n```\n", "<pre><code class=\"language-yaml\">key: &lt;v&gt;\n</code></pre>\n"},
+		{"tilde fence", "~~~\n```\n~~~\n", "<pre><code>```\n</code></pre>\n"},
+		{"unclosed fence", "```\na\n", "<pre><code>a\n</code></pre>\n"},
+		{"unclosed fence with a blank line", "```\na\n\n", "<pre><code>a\n\n</code></pre>\n"},
+		{"indented fence", "  ```\n  a\n   b\n  ```\n", "<pre><code>a\n b\n</code></pre>\n"},
+		{"escaped language", "```\"><script>\nx\n```\n",
+			"<pre><code class=\"language-&#34;&gt;&lt;script&gt;\">x\n</code></pre>\n"},
+		{"block quote", "> a\n> b\nc\n\nd", "<blockquote>\n<p>a\nb\nc</p>\n</blockquote>\n<p>d</p>\n"},
+		{"nested block quote", "> > a\n", "<blockquote>\n<blockquote>\n<p>a</p>\n</blockquote>\n</blockquote>\n"},
+		{"block quote ends at a heading", "> a\n# b\n", "<blockquote>\n<p>a</p>\n</blockquote>\n<h1>b</h1>\n"},
+		{"tight list", "- a\n- b\n", "<ul>\n<li>a</li>\n<li>b</li>\n</ul>\n"},
+		{"loose list", "- a\n\n- b\n", "<ul>\n<li><p>a</p></li>\n<li><p>b</p></li>\n</ul>\n"},
+		{"ordered list", "3. a\n4. b\n", "<ol start=\"3\">\n<li>a</li>\n<li>b</li>\n</ol>\n"},
+		{"different bullets", "- a\n* b\n", "<ul>\n<li>a</li>\n</ul>\n<ul>\n<li>b</li>\n</ul>\n"},
+		{"nested list", "- a\n  - b\n- c\n", "<ul>\n<li>a\n<ul>\n<li>b</li>\n</ul></li>\n<li>c</li>\n</ul>\n"},
+		{"list item with code", "1. a\n\n       code\n",
+			"<ol>\n<li><p>a</p>\n<pre><code>code\n</code></pre></li>\n</ol>\n"},
+		{"lazy continuation", "- a\nb\n", "<ul>\n<li>a\nb</li>\n</ul>\n"},
+		{"list interrupts a paragraph", "a\n- b\n", "<p>a</p>\n<ul>\n<li>b</li>\n</ul>\n"},
+		{"numbers don't interrupt a paragraph", "a\n2. b\n", "<p>a\n2. b</p>\n"},
+		{"table",
+			"| a | b | c |\n|:--|:-:|--:|\n| 1 | `\\|` | \\| |\n| 2 |\n\nd",
+			"<table>\n<thead>\n<tr>\n<th style=\"text-align: left\">a</th>\n<th style=\"text-align: center\">b</th>\n" +
+				"<th style=\"text-align: right\">c</th>\n</tr>\n</thead>\n<tbody>\n" +
+				"<tr>\n<td style=\"text-align: left\">1</td>\n<td style=\"text-align: center\"><code>|</code></td>\n" +
+				"<td style=\"text-align: right\">|</td>\n</tr>\n" +
+				"<tr>\n<td style=\"text-align: left\">2</td>\n<td style=\"text-align: center\"></td>\n" +
+				"<td style=\"text-align: right\"></td>\n</tr>\n</tbody>\n</table>\n<p>d</p>\n"},
+		{"table without body", "a | b\n--|--\n", "<table>\n<thead>\n<tr>\n<th>a</th>\n<th>b</th>\n</tr>\n</thead>\n</table>\n"},
+		// pipes in code spans split cells unless they're escaped
+		{"pipes in code spans", "a | b\n--|--\n`x|y` | z\n",
+			"<table>\n<thead>\n<tr>\n<th>a</th>\n<th>b</th>\n</tr>\n</thead>\n<tbody>\n" +
+				"<tr>\n<td>`x</td>\n<td>y`</td>\n</tr>\n</tbody>\n</table>\n"},
+		{"mismatched delimiter row", "| a | b |\n| --- |\n", "<p>| a | b |\n| --- |</p>\n"},
+		{"raw html", "<div onclick=\"x()\">\n\n<script>alert(1)</script>",
+			"<p>&lt;div onclick=&#34;x()&#34;&gt;</p>\n<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>\n"},
+		{"hard breaks", "a  \nb\\\nc\n", "<p>a<br />\nb<br />\nc</p>\n"},
+		{"hard break after an inner line", "a\nb  \nc\n", "<p>a\nb<br />\nc</p>\n"},
+		{"trailing spaces", "a  \n", "<p>a</p>\n"},
+	}
+	for _, c := range cases {
+		html := string(ToHTML([]byte(c.source)))
+		if html != c.html {
+			t.Errorf("%s: expected\n%q\ngot\n%q", c.name, c.html, html)
+		}
+	}
+}
+
+func TestInline(t *testing.T) {
+	cases := []struct {
+		name, source, html string
+	}{
+		{"escapes", `\*a\* \\ \a & "q"`, `*a* \ \a &amp; &#34;q&#34;`},
+		{"emphasis", "*a* _b_ **c** __d__ ~~e~~", "<em>a</em> <em>b</em> <strong>c</strong> <strong>d</strong> <del>e</del>"},
+		{"nested emphasis", "**a *b* c**", "<strong>a <em>b</em> c</strong>"},
+		{"spaces around delimiters", "a * b * c", "a * b * c"},
+		{"underscores in words", "snake_case_name", "snake_case_name"},
+		{"unclosed emphasis", "*a", "*a"},
+		{"code spans", "`a<b>` `` a`b `` ` c `", "<code>a&lt;b&gt;</code> <code>a`b</code> <code>c</code>"},
+		{"code span precedence", "*a `*` b*", "<em>a <code>*</code> b</em>"},
+		{"unclosed code span", "``a`", "``a`"},
+		{"links", `[a *b*](http://x.io/?q=1&r=2 "t")`, `<a href="http://x.io/?q=1&amp;r=2" title="t">a <em>b</em></a>`},
+		{"relative links", "[a](docs/README.md)", `<a href="docs/README.md">a</a>`},
+		{"nested brackets", "[a [b]](c)", `<a href="c">a [b]</a>`},
+		{"angle destination", "[a](<b c>)", `<a href="b%20c">a</a>`},
+		{"parentheses in destination", "[a](b(c))", `<a href="b(c)">a</a>`},
+		{"invalid title", "[a](b c)", "[a](b c)"},
+		{"images", `![a *b*](img.png 'c')`, `<img src="img.png" alt="a b" title="c" />`},
+		{"autolinks", "<https://x.io> <a@b.io>", `<a href="https://x.io">https://x.io</a> <a href="mailto:a@b.io">a@b.io</a>`},
+		{"not a link", "[a] (b)", "[a] (b)"},
+		{"quotes in urls", `[a](http://x.io/"onclick=")`, `<a href="http://x.io/%22onclick=%22">a</a>`},
+	}
+	for _, c := range cases {
+		html := renderInline(c.source)
+		if html != c.html {
+			t.Errorf("%s: expected\n%q\ngot\n%q", c.name, c.html, html)
+		}
+	}
+}
+
+func TestUnsafeURLs(t *testing.T) {
+	cases := []struct {
+		source, html string
+	}{
+		{"[a](javascript:alert(1))", "a"},
+		{"[a](JaVaScRiPt:alert(1))", "a"},
+		{"[a]( javascript:alert(1))", "a"},
+		{"[a](<java\tscript:alert(1)>)", "a"},
+		{"[a](vbscript:x)", "a"},
+		{"[a](data:text/html;base64,PHNjcmlwdD4=)", "a"},
+		{"![a](javascript:alert(1))", "a"},
+		{"<javascript:alert(1)>", "javascript:alert(1)"},
+		{"[*a*](file:///etc/passwd)", "<em>a</em>"},
+	}
+	for _, c := range cases {
+		html := renderInline(c.source)
+		if html != c.html {
+			t.Errorf("%q: expected %q, got %q", c.source, c.html, html)
+		}
+	}
+}
+
+func TestSafeURL(t *testing.T) {
+	cases := []struct {
+		url  string
+		safe bool
+	}{
+		{"", true},
+		{"#anchor", true},
+		{"/path", true},
+		{"http://x.io", true},
+		{"HTTPS://x.io", true},
+		{"mailto:a@b.io", true},
+		{"javascript:x", false},
+		{"ftp://x.io", false},
+		{"%zz", false},
+	}
+	for _, c := range cases {
+		if _, safe := safeURL(c.url); safe != c.safe {
+			t.Errorf("%q: expected safe to be %v", c.url, c.safe)
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sort"
 
 	"github.com/caicloud/helm-registry/pkg/common"
 	"github.com/caicloud/helm-registry/pkg/errors"
@@ -20,6 +21,8 @@ import (
 const (
 	// packageKey is the key of package in configs
 	packageKey = "package"
+	// globalKey is the key of global values, so it can't be the name of a chart
+	globalKey = "global"
 )
 
 // convertInterface converts interface{} to map[string]interface{}
@@ -35,7 +38,7 @@ func convertInterface(name string, inf interface{}) (map[string]interface{}, err
 	return data, nil
 }
 
-// Create creates a new chart from configs.
+// Create creates a new chart from configs and returns its dependency tree.
 // An example:
 // {
 //     "package": {                     // It's the fixed description of current chart
@@ -71,8 +74,52 @@ func convertInterface(name string, inf interface{}) (map[string]interface{}, err
 //         }
 //     }
 // }
-func Create(configs map[string]interface{}) (*chart.Chart, error) {
-	return create(nil, configs)
+func Create(configs map[string]interface{}) (*chart.Chart, *Node, error) {
+	r := &resolver{}
+	c, node := r.create(nil, "", configs)
+	if len(r.problems) > 0 {
+		return nil, nil, r.problems[0].err
+	}
+	return c, node, nil
+}
+
+// Resolve resolves charts in configs as Create does, but it doesn't stop at the first
+// problem. The chart is nil if there are problems. The root node has no name.
+func Resolve(configs map[string]interface{}) (*chart.Chart, *Node, []Problem) {
+	r := &resolver{}
+	c, node := r.create(nil, "", configs)
+	if len(r.problems) > 0 {
+		return nil, node, r.problems
+	}
+	return c, node, nil
+}
+
+// References returns independent packages in the dependency tree of an orchestrated chart.
+// Parents come before their subcharts.
+func References(node *Node) []storage.Reference {
+	references := []storage.Reference{}
+	collectReferences(node, "", &references)
+	return references
+}
+
+// collectReferences collects references of node at path and its dependencies
+func collectReferences(node *Node, path string, references *[]storage.Reference) {
+	if node.Package != nil && node.Package.Independent && len(node.Version) > 0 {
+		*references = append(*references, storage.Reference{
+			Path:    path,
+			Space:   node.Package.Space,
+			Chart:   node.Package.Chart,
+			Version: node.Version,
+			Digest:  node.Digest,
+		})
+	}
+	for _, child := range node.Dependencies {
+		childPath := child.Name
+		if len(path) > 0 {
+			childPath = path + "." + child.Name
+		}
+		collectReferences(child, childPath, references)
+	}
 }
 
 // ClearValues removes all values in a chart
@@ -83,8 +130,45 @@ func ClearValues(chrt *chart.Chart) {
 	}
 }
 
-// create creates a new chart from configs.
-func create(parent *chart.Chart, configs map[string]interface{}) (*chart.Chart, error) {
+// Node is a chart in the dependency tree of an orchestrated chart
+type Node struct {
+	// Name is the name of chart in the orchestrated chart
+	Name string `json:"name,omitempty"`
+	// Package is the source of chart
+	Package *Package `json:"package,omitempty"`
+	// Digest is the digest of the source package. It's empty if the chart is not independent
+	Digest string `json:"digest,omitempty"`
+	// Version is the version number of the source package which a tag in the package is
+	// resolved to. It's empty if the chart is not independent
+	Version string `json:"version,omitempty"`
+	// Dependencies are subcharts sorted by names
+	Dependencies []*Node `json:"dependencies,omitempty"`
+}
+
+// Problem is a problem which prevents a chart in configs from being resolved
+type Problem struct {
+	// Path is the path of chart in configs, e.g. chartB.chartD. It's empty for the root chart
+	Path string `json:"path"`
+	// Message describes the problem
+	Message string `json:"message"`
+	// err is the original error
+	err error
+}
+
+// resolver resolves charts in configs and collects problems
+type resolver struct {
+	problems []Problem
+}
+
+// report records a problem of the chart at path
+func (r *resolver) report(path string, err error) {
+	r.problems = append(r.problems, Problem{Path: path, Message: err.Error(), err: err})
+}
+
+// create creates a chart at path from configs. The chart is nil if it can't be resolved,
+// but its subcharts are still resolved to find their problems.
+func (r *resolver) create(parent *chart.Chart, path string, configs map[string]interface{}) (*chart.Chart, *Node) {
+	node := &Node{}
 	// packageConfig is the config of current package
 	var packageConfig *Package
 
@@ -94,85 +178,113 @@ func create(parent *chart.Chart, configs map[string]interface{}) (*chart.Chart,
 	for key, value := range configs {
 		data, err := convertInterface(key, value)
 		if err != nil {
-			return nil, err
+			r.report(path, err)
+			continue
 		}
 		if key == packageKey {
 			// catch package
 			packageConfig, err = NewPackage(data)
 			if err != nil {
-				return nil, err
+				r.report(path, err)
 			}
 		} else {
 			// filter invalid chart name
 			if !common.MustGetSpaceManager().Validate(context.Background(),
 				storage.ValidationTypeChartName, key) {
-				return nil, errors.ErrorInvalidParam.Format("chart name", key)
+				r.report(path, errors.ErrorInvalidParam.Format("chart name", key))
+				continue
+			}
+			if key == globalKey {
+				r.report(path, errors.ErrorInvalidParam.Format("chart name", "it conflicts with global values"))
+				continue
 			}
 			deps[key] = data
 		}
 	}
-	currentChart, err := getChartByPackage(parent, packageConfig)
-	if err != nil {
-		return nil, err
+	var currentChart *chart.Chart
+	if packageConfig == nil {
+		if _, ok := configs[packageKey]; !ok {
+			r.report(path, errors.ErrorParamNotFound.Format(packageKey))
+		}
+	} else {
+		node.Package = packageConfig
+		var data []byte
+		var err error
+		currentChart, data, err = getChartByPackage(parent, packageConfig)
+		if err != nil {
+			r.report(path, err)
+		} else if data != nil {
+			node.Digest = storage.Digest(data)
+			node.Version = currentChart.Metadata.GetVersion()
+		}
 	}
 	// generate charts recursively
-	if len(deps) > 0 {
-		children := make([]*chart.Chart, 0, len(deps))
-		for name, cfg := range deps {
-			child, err := create(currentChart, cfg)
-			if err != nil {
-				return nil, err
-			}
+	names := make([]string, 0, len(deps))
+	for name := range deps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	children := make([]*chart.Chart, 0, len(deps))
+	for _, name := range names {
+		childPath := name
+		if len(path) > 0 {
+			childPath = path + "." + name
+		}
+		child, childNode := r.create(currentChart, childPath, deps[name])
+		childNode.Name = name
+		node.Dependencies = append(node.Dependencies, childNode)
+		if child != nil {
 			child.Metadata.Name = name
 			children = append(children, child)
 		}
+	}
+	if currentChart != nil {
 		currentChart.Dependencies = children
-	} else {
-		currentChart.Dependencies = []*chart.Chart{}
 	}
-	return currentChart, nil
+	return currentChart, node
 }
 
-// getChartByPackage returns a chart via package configs
-func getChartByPackage(parent *chart.Chart, pkg *Package) (*chart.Chart, error) {
+// getChartByPackage returns a chart via package configs. Data of the package is returned
+// only if the chart is independent.
+func getChartByPackage(parent *chart.Chart, pkg *Package) (*chart.Chart, []byte, error) {
 	chartName := fmt.Sprintf("%s/%s", pkg.Chart, pkg.Version)
 	if pkg.Independent {
 		return getChart(pkg.Space, pkg.Chart, pkg.Version)
 	}
 	if parent == nil {
-		return nil, errors.ErrorInvalidParam.Format("parent chart", chartName)
+		return nil, nil, errors.ErrorInvalidParam.Format("parent chart", chartName)
 	}
 	for _, dep := range parent.GetDependencies() {
 		if dep.GetMetadata().Name == pkg.Chart {
-			return dep, nil
+			return dep, nil, nil
 		}
 	}
-	return nil, errors.ErrorContentNotFound.Format(fmt.Sprintf("%s in %s/%s", chartName, parent.Metadata.Name, parent.Metadata.Version))
+	return nil, nil, errors.ErrorContentNotFound.Format(fmt.Sprintf("%s in %s/%s", chartName, parent.Metadata.Name, parent.Metadata.Version))
 }
 
-// getChart gets a chart
-func getChart(spaceName, chartName, versionNumber string) (*chart.Chart, error) {
+// getChart gets a chart and its package
+func getChart(spaceName, chartName, versionNumber string) (*chart.Chart, []byte, error) {
 	ctx := context.Background()
 	space, err := common.MustGetSpaceManager().Space(ctx, spaceName)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	chart, err := space.Chart(ctx, chartName)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	version, err := chart.Version(ctx, versionNumber)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	data, err := version.GetContent(ctx)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	c, err := chartutil.LoadArchive(bytes.NewReader(data))
 	if err != nil {
-		return nil, errors.ErrorInternalTypeError.Format(
+		return nil, nil, errors.ErrorInternalTypeError.Format(
 			fmt.Sprintf("%s/%s", chartName, versionNumber), "chart", "unknown")
 	}
-	return c, nil
+	return c, data, nil
 }
@@ -11,14 +11,14 @@ type Package struct {
 	// Independent identifies whether the chart is an independent and complete chart package.
 	// If the field is false, It means that the package is contained by its parent
 	// package. On other word, the package is not an independent package.
-	Independent bool
+	Independent bool `json:"independent"`
 	// Space is the name of space where the package is stored. If Independent is false, the
 	// space is same as its parent's space
-	Space string
+	Space string `json:"space"`
 	// Chart is the original name of the chart
-	Chart string
+	Chart string `json:"chart"`
 	// Version is the version number of the chart.
-	Version string
+	Version string `json:"version"`
 }
 
 // NewPackage creates a package from config
@@ -0,0 +1,274 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+// Package patch applies JSON Merge Patch (RFC 7386) and JSON Patch (RFC 6902) to
+// JSON documents. JSON Merge Patch can be applied to YAML documents too, keeping
+// their comments.
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+)
+
+const (
+	// MergePatchType is the media type of JSON Merge Patch
+	MergePatchType = "application/merge-patch+json"
+	// JSONPatchType is the media type of JSON Patch
+	JSONPatchType = "application/json-patch+json"
+)
+
+// Error describes an error of a patch operation
+type Error struct {
+	// Index is the index of the operation in a JSON Patch. It's -1 for a JSON Merge Patch
+	Index int
+	// Op is the operation
+	Op string
+	// Path is the path of the operation
+	Path string
+	// Err is the reason
+	Err error
+}
+
+// Error returns a message which points to the failing operation
+func (e *Error) Error() string {
+	if e.Index < 0 {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("operation %d (%s %s): %v", e.Index, e.Op, e.Path, e.Err)
+}
+
+// Supported checks whether the content type is a supported patch type
+func Supported(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == MergePatchType || mediaType == JSONPatchType)
+}
+
+// Apply applies a patch of the content type to a JSON document
+func Apply(contentType string, doc []byte, patch []byte) ([]byte, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, err
+	}
+	switch mediaType {
+	case MergePatchType:
+		return MergePatch(doc, patch)
+	case JSONPatchType:
+		return JSONPatch(doc, patch)
+	}
+	return nil, fmt.Errorf("unsupported patch type %s", mediaType)
+}
+
+// MergePatch applies a JSON Merge Patch to a JSON document
+func MergePatch(doc []byte, patch []byte) ([]byte, error) {
+	target, err := decode(doc)
+	if err != nil {
+		return nil, err
+	}
+	p, err := decode(patch)
+	if err != nil {
+		return nil, &Error{Index: -1, Err: fmt.Errorf("invalid merge patch: %v", err)}
+	}
+	return json.Marshal(mergePatch(target, p))
+}
+
+// mergePatch merges patch to target as RFC 7386 describes
+func mergePatch(target, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]interface{})
+	if !ok {
+		t = make(map[string]interface{})
+	}
+	for key, value := range p {
+		if value == nil {
+			delete(t, key)
+		} else {
+			t[key] = mergePatch(t[key], value)
+		}
+	}
+	return t
+}
+
+// Operation is an operation of JSON Patch. Value is empty if it's missing, and it's null
+// if the value is null.
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  *string         `json:"path"`
+	From  *string         `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// JSONPatch applies a JSON Patch to a JSON document. Operations are applied in order,
+// and the document is not changed if any of them fails.
+func JSONPatch(doc []byte, patch []byte) ([]byte, error) {
+	target, err := decode(doc)
+	if err != nil {
+		return nil, err
+	}
+	operations := []Operation{}
+	if err = json.Unmarshal(patch, &operations); err != nil {
+		return nil, &Error{Index: -1, Err: fmt.Errorf("invalid json patch: %v", err)}
+	}
+	for i, operation := range operations {
+		target, err = apply(target, operation)
+		if err != nil {
+			path := ""
+			if operation.Path != nil {
+				path = *operation.Path
+			}
+			return nil, &Error{Index: i, Op: operation.Op, Path: path, Err: err}
+		}
+	}
+	return json.Marshal(target)
+}
+
+// apply applies an operation to doc and returns the new doc
+func apply(doc interface{}, operation Operation) (interface{}, error) {
+	if operation.Path == nil {
+		return nil, fmt.Errorf("missing path")
+	}
+	path, err := parsePointer(*operation.Path)
+	if err != nil {
+		return nil, err
+	}
+	value := func() (interface{}, error) {
+		if len(operation.Value) <= 0 {
+			return nil, fmt.Errorf("missing value")
+		}
+		return decode(operation.Value)
+	}
+	from := func() ([]string, error) {
+		if operation.From == nil {
+			return nil, fmt.Errorf("missing from")
+		}
+		return parsePointer(*operation.From)
+	}
+	switch operation.Op {
+	case "add":
+		v, err := value()
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, path, v)
+	case "remove":
+		return remove(doc, path)
+	case "replace":
+		v, err := value()
+		if err != nil {
+			return nil, err
+		}
+		if len(path) <= 0 {
+			return v, nil
+		}
+		if doc, err = remove(doc, path); err != nil {
+			return nil, err
+		}
+		return add(doc, path, v)
+	case "move":
+		f, err := from()
+		if err != nil {
+			return nil, err
+		}
+		if isPrefix(f, path) && len(f) < len(path) {
+			return nil, fmt.Errorf("can't move a value into its child")
+		}
+		v, err := get(doc, f)
+		if err != nil {
+			return nil, err
+		}
+		if doc, err = remove(doc, f); err != nil {
+			return nil, err
+		}
+		return add(doc, path, v)
+	case "copy":
+		f, err := from()
+		if err != nil {
+			return nil, err
+		}
+		v, err := get(doc, f)
+		if err != nil {
+			return nil, err
+		}
+		// copy the value because containers are shared
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		if v, err = decode(data); err != nil {
+			return nil, err
+		}
+		return add(doc, path, v)
+	case "test":
+		expected, err := value()
+		if err != nil {
+			return nil, err
+		}
+		actual, err := get(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !equal(actual, expected) {
+			return nil, fmt.Errorf("value mismatch")
+		}
+		return doc, nil
+	}
+	return nil, fmt.Errorf("unknown operation %q", operation.Op)
+}
+
+// decode decodes JSON data and keeps numbers as json.Number
+func decode(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var result interface{}
+	if err := decoder.Decode(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// equal checks whether two decoded JSON values are equal
+func equal(a, b interface{}) bool {
+	switch x := a.(type) {
+	case map[string]interface{}:
+		y, ok := b.(map[string]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for key, value := range x {
+			other, ok := y[key]
+			if !ok || !equal(value, other) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		y, ok := b.([]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !equal(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		y, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		fx, errX := x.Float64()
+		fy, errY := y.Float64()
+		if errX != nil || errY != nil {
+			return x == y
+		}
+		return fx == fy
+	}
+	return a == b
+}
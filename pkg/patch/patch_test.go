@@ -0,0 +1,270 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package patch
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// jsonEqual checks whether two JSON documents are equal
+func jsonEqual(t *testing.T, a, b string) bool {
+	var x, y interface{}
+	if err := json.Unmarshal([]byte(a), &x); err != nil {
+		t.Fatalf("invalid json %s: %v", a, err)
+	}
+	if err := json.Unmarshal([]byte(b), &y); err != nil {
+		t.Fatalf("invalid json %s: %v", b, err)
+	}
+	return reflect.DeepEqual(x, y)
+}
+
+func TestMergePatch(t *testing.T) {
+	// cases from the appendix of RFC 7386
+	cases := []struct {
+		doc, patch, result string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+		// large numbers are kept
+		{`{"a":12345678901234567890}`, `{"b":1}`, `{"a":12345678901234567890,"b":1}`},
+	}
+	for _, c := range cases {
+		result, err := MergePatch([]byte(c.doc), []byte(c.patch))
+		if err != nil {
+			t.Errorf("merge %s into %s: unexpected error: %v", c.patch, c.doc, err)
+			continue
+		}
+		if !jsonEqual(t, string(result), c.result) {
+			t.Errorf("merge %s into %s: expected %s, got %s", c.patch, c.doc, c.result, result)
+		}
+	}
+}
+
+func TestMergePatchErrors(t *testing.T) {
+	cases := []struct {
+		doc, patch string
+		patchError bool
+	}{
+		{`{"a":`, `{}`, false},
+		{`{}`, `{"a":`, true},
+		{`{}`, ``, true},
+	}
+	for _, c := range cases {
+		_, err := MergePatch([]byte(c.doc), []byte(c.patch))
+		if err == nil {
+			t.Errorf("merge %q into %q: expected an error", c.patch, c.doc)
+			continue
+		}
+		if _, ok := err.(*Error); ok != c.patchError {
+			t.Errorf("merge %q into %q: unexpected error type %T", c.patch, c.doc, err)
+		}
+	}
+}
+
+func TestJSONPatch(t *testing.T) {
+	// most cases are from the appendix of RFC 6902
+	cases := []struct {
+		name, doc, patch, result string
+	}{
+		{"add an object member", `{"foo":"bar"}`,
+			`[{"op":"add","path":"/baz","value":"qux"}]`, `{"baz":"qux","foo":"bar"}`},
+		{"add an array element", `{"foo":["bar","baz"]}`,
+			`[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`},
+		{"append an array element", `{"foo":["bar"]}`,
+			`[{"op":"add","path":"/foo/-","value":"qux"}]`, `{"foo":["bar","qux"]}`},
+		{"add a nested member", `{"foo":"bar"}`,
+			`[{"op":"add","path":"/child","value":{"grandchild":{}}}]`, `{"foo":"bar","child":{"grandchild":{}}}`},
+		{"replace the document", `{"foo":"bar"}`,
+			`[{"op":"add","path":"","value":[1]}]`, `[1]`},
+		{"remove an object member", `{"baz":"qux","foo":"bar"}`,
+			`[{"op":"remove","path":"/baz"}]`, `{"foo":"bar"}`},
+		{"remove an array element", `{"foo":["bar","qux","baz"]}`,
+			`[{"op":"remove","path":"/foo/1"}]`, `{"foo":["bar","baz"]}`},
+		{"replace a value", `{"baz":"qux","foo":"bar"}`,
+			`[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo","foo":"bar"}`},
+		{"replace the whole document", `{"foo":"bar"}`,
+			`[{"op":"replace","path":"","value":{"a":1}}]`, `{"a":1}`},
+		{"move a value", `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+			`[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			`{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+		{"move an array element", `{"foo":["all","grass","cows","eat"]}`,
+			`[{"op":"move","from":"/foo/1","path":"/foo/3"}]`, `{"foo":["all","cows","eat","grass"]}`},
+		{"copy a value", `{"foo":{"a":[1]}}`,
+			`[{"op":"copy","from":"/foo","path":"/bar"},{"op":"add","path":"/bar/a/-","value":2}]`,
+			`{"foo":{"a":[1]},"bar":{"a":[1,2]}}`},
+		{"test a value", `{"baz":"qux","foo":["a",2,"c"]}`,
+			`[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2}]`,
+			`{"baz":"qux","foo":["a",2,"c"]}`},
+		{"test numbers by value", `{"a":1}`,
+			`[{"op":"test","path":"/a","value":1.0}]`, `{"a":1}`},
+		{"escape pointers", `{"a/b":1,"m~n":2}`,
+			`[{"op":"replace","path":"/a~1b","value":3},{"op":"remove","path":"/m~0n"}]`, `{"a/b":3}`},
+		{"add a null value", `{"foo":"bar"}`,
+			`[{"op":"add","path":"/baz","value":null}]`, `{"foo":"bar","baz":null}`},
+		{"test a null value", `{"a":null}`,
+			`[{"op":"test","path":"/a","value":null}]`, `{"a":null}`},
+		{"empty patch", `{"foo":"bar"}`, `[]`, `{"foo":"bar"}`},
+	}
+	for _, c := range cases {
+		result, err := JSONPatch([]byte(c.doc), []byte(c.patch))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", c.name, err)
+			continue
+		}
+		if !jsonEqual(t, string(result), c.result) {
+			t.Errorf("%s: expected %s, got %s", c.name, c.result, result)
+		}
+	}
+}
+
+func TestJSONPatchErrors(t *testing.T) {
+	cases := []struct {
+		name, doc, patch string
+		// index is the index of the failing operation. It's -1 if the patch is malformed
+		index int
+		// message is a part of the error message
+		message string
+	}{
+		{"malformed patch", `{}`, `{"op":"add"}`, -1, "invalid json patch"},
+		{"missing path", `{}`, `[{"op":"add","value":1}]`, 0, "missing path"},
+		{"missing value", `{}`, `[{"op":"add","path":"/a"}]`, 0, "missing value"},
+		{"missing from", `{"a":1}`, `[{"op":"move","path":"/b"}]`, 0, "missing from"},
+		{"unknown operation", `{}`, `[{"op":"merge","path":"/a","value":1}]`, 0, "unknown operation"},
+		{"invalid pointer", `{}`, `[{"op":"add","path":"a","value":1}]`, 0, "must start with /"},
+		{"missing parent", `{}`, `[{"op":"add","path":"/a/b","value":1}]`, 0, `key "a" not found`},
+		{"remove a missing member", `{"a":1}`, `[{"op":"remove","path":"/b"}]`, 0, `key "b" not found`},
+		{"remove the document", `{"a":1}`, `[{"op":"remove","path":""}]`, 0, "whole document"},
+		{"index out of range", `{"a":[1]}`, `[{"op":"add","path":"/a/2","value":1}]`, 0, "out of range"},
+		{"remove past the end", `{"a":[1]}`, `[{"op":"remove","path":"/a/-"}]`, 0, "invalid array index"},
+		{"leading zero index", `{"a":[1,2]}`, `[{"op":"remove","path":"/a/01"}]`, 0, "invalid array index"},
+		{"negative index", `{"a":[1,2]}`, `[{"op":"remove","path":"/a/-1"}]`, 0, "invalid array index"},
+		{"scalar parent", `{"a":1}`, `[{"op":"add","path":"/a/b","value":1}]`, 0, "scalar value"},
+		{"move into a child", `{"a":{"b":1}}`, `[{"op":"move","from":"/a","path":"/a/c"}]`, 0, "into its child"},
+		{"replace a missing member", `{}`, `[{"op":"replace","path":"/a","value":1}]`, 0, `key "a" not found`},
+		{"failing test", `{"a":"b"}`, `[{"op":"add","path":"/c","value":1},{"op":"test","path":"/a","value":"c"}]`, 1, "value mismatch"},
+		{"test types", `{"a":1}`, `[{"op":"test","path":"/a","value":"1"}]`, 0, "value mismatch"},
+	}
+	for _, c := range cases {
+		_, err := JSONPatch([]byte(c.doc), []byte(c.patch))
+		if err == nil {
+			t.Errorf("%s: expected an error", c.name)
+			continue
+		}
+		patchError, ok := err.(*Error)
+		if !ok {
+			t.Errorf("%s: unexpected error type %T", c.name, err)
+			continue
+		}
+		if patchError.Index != c.index {
+			t.Errorf("%s: expected index %d, got %d", c.name, c.index, patchError.Index)
+		}
+		if !strings.Contains(err.Error(), c.message) {
+			t.Errorf("%s: expected an error with %q, got %q", c.name, c.message, err.Error())
+		}
+	}
+}
+
+func TestJSONPatchDocumentError(t *testing.T) {
+	_, err := JSONPatch([]byte(`{"a":`), []byte(`[]`))
+	if err == nil {
+		t.Fatal("expected an error for a malformed document")
+	}
+	if _, ok := err.(*Error); ok {
+		t.Fatalf("a malformed document should not be reported as a patch error: %v", err)
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	_, err := JSONPatch([]byte(`{}`), []byte(`[{"op":"remove","path":"/a"}]`))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	expected := `operation 0 (remove /a): key "a" not found`
+	if err.Error() != expected {
+		t.Fatalf("expected %q, got %q", expected, err.Error())
+	}
+}
+
+func TestApply(t *testing.T) {
+	cases := []struct {
+		contentType, patch, result string
+		supported                  bool
+	}{
+		{MergePatchType, `{"a":null,"b":2}`, `{"b":2}`, true},
+		{MergePatchType + "; charset=utf-8", `{"b":2}`, `{"a":1,"b":2}`, true},
+		{JSONPatchType, `[{"op":"add","path":"/b","value":2}]`, `{"a":1,"b":2}`, true},
+		{"application/json", `{"b":2}`, "", false},
+		{"application/strategic-merge-patch+json", `{"b":2}`, "", false},
+		{"", `{"b":2}`, "", false},
+		{"invalid/type; =", `{"b":2}`, "", false},
+	}
+	for _, c := range cases {
+		if Supported(c.contentType) != c.supported {
+			t.Errorf("%q: expected supported to be %v", c.contentType, c.supported)
+		}
+		result, err := Apply(c.contentType, []byte(`{"a":1}`), []byte(c.patch))
+		if !c.supported {
+			if err == nil {
+				t.Errorf("%q: expected an error", c.contentType)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", c.contentType, err)
+			continue
+		}
+		if !jsonEqual(t, string(result), c.result) {
+			t.Errorf("%q: expected %s, got %s", c.contentType, c.result, result)
+		}
+	}
+}
+
+func TestParsePointer(t *testing.T) {
+	cases := []struct {
+		pointer string
+		tokens  []string
+		valid   bool
+	}{
+		{"", []string{}, true},
+		{"/", []string{""}, true},
+		{"/a/b", []string{"a", "b"}, true},
+		{"/a~1b/m~0n", []string{"a/b", "m~n"}, true},
+		// ~01 is ~1 rather than /
+		{"/~01", []string{"~1"}, true},
+		{"a/b", nil, false},
+	}
+	for _, c := range cases {
+		tokens, err := parsePointer(c.pointer)
+		if !c.valid {
+			if err == nil {
+				t.Errorf("%q: expected an error", c.pointer)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", c.pointer, err)
+			continue
+		}
+		if !reflect.DeepEqual(tokens, c.tokens) {
+			t.Errorf("%q: expected %q, got %q", c.pointer, c.tokens, tokens)
+		}
+	}
+}
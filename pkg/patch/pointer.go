@@ -0,0 +1,156 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package patch
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parsePointer parses a JSON Pointer (RFC 6901) to reference tokens
+func parsePointer(pointer string) ([]string, error) {
+	if len(pointer) <= 0 {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("pointer %q must start with /", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+	}
+	return tokens, nil
+}
+
+// isPrefix checks whether prefix is a prefix of tokens
+func isPrefix(prefix, tokens []string) bool {
+	if len(prefix) > len(tokens) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != tokens[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// index parses an array index. If end is true, the index can be the length of array
+func index(token string, length int, end bool) (int, error) {
+	if end && token == "-" {
+		return length, nil
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	if i > length || (!end && i == length) {
+		return 0, fmt.Errorf("array index %d out of range", i)
+	}
+	return i, nil
+}
+
+// get gets the value referenced by tokens
+func get(doc interface{}, tokens []string) (interface{}, error) {
+	for _, token := range tokens {
+		switch node := doc.(type) {
+		case map[string]interface{}:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("key %q not found", token)
+			}
+			doc = value
+		case []interface{}:
+			i, err := index(token, len(node), false)
+			if err != nil {
+				return nil, err
+			}
+			doc = node[i]
+		default:
+			return nil, fmt.Errorf("can't reference %q in a scalar value", token)
+		}
+	}
+	return doc, nil
+}
+
+// add adds a value to the location referenced by tokens and returns the new doc
+func add(doc interface{}, tokens []string, value interface{}) (interface{}, error) {
+	if len(tokens) <= 0 {
+		return value, nil
+	}
+	token, last := tokens[0], len(tokens) == 1
+	switch node := doc.(type) {
+	case map[string]interface{}:
+		if last {
+			node[token] = value
+			return node, nil
+		}
+		child, ok := node[token]
+		if !ok {
+			return nil, fmt.Errorf("key %q not found", token)
+		}
+		child, err := add(child, tokens[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		node[token] = child
+		return node, nil
+	case []interface{}:
+		i, err := index(token, len(node), last)
+		if err != nil {
+			return nil, err
+		}
+		if last {
+			node = append(node, nil)
+			copy(node[i+1:], node[i:])
+			node[i] = value
+			return node, nil
+		}
+		if node[i], err = add(node[i], tokens[1:], value); err != nil {
+			return nil, err
+		}
+		return node, nil
+	}
+	return nil, fmt.Errorf("can't reference %q in a scalar value", token)
+}
+
+// remove removes the value referenced by tokens and returns the new doc
+func remove(doc interface{}, tokens []string) (interface{}, error) {
+	if len(tokens) <= 0 {
+		return nil, fmt.Errorf("can't remove the whole document")
+	}
+	token, last := tokens[0], len(tokens) == 1
+	switch node := doc.(type) {
+	case map[string]interface{}:
+		child, ok := node[token]
+		if !ok {
+			return nil, fmt.Errorf("key %q not found", token)
+		}
+		if last {
+			delete(node, token)
+			return node, nil
+		}
+		child, err := remove(child, tokens[1:])
+		if err != nil {
+			return nil, err
+		}
+		node[token] = child
+		return node, nil
+	case []interface{}:
+		i, err := index(token, len(node), false)
+		if err != nil {
+			return nil, err
+		}
+		if last {
+			return append(node[:i], node[i+1:]...), nil
+		}
+		if node[i], err = remove(node[i], tokens[1:]); err != nil {
+			return nil, err
+		}
+		return node, nil
+	}
+	return nil, fmt.Errorf("can't reference %q in a scalar value", token)
+}
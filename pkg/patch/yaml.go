@@ -0,0 +1,472 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package patch
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// CreateMergePatch creates a JSON Merge Patch which changes the original JSON document into
+// the modified one. Merge patches can't set null, so it fails if the modified document has
+// null which is not in the original document.
+func CreateMergePatch(original, modified []byte) ([]byte, error) {
+	o, err := decode(original)
+	if err != nil {
+		return nil, err
+	}
+	m, err := decode(modified)
+	if err != nil {
+		return nil, err
+	}
+	p, err := createMergePatch(o, m, "")
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(p)
+}
+
+// createMergePatch creates a merge patch from original to modified. path is the json
+// pointer of modified.
+func createMergePatch(original, modified interface{}, path string) (interface{}, error) {
+	o, ok := original.(map[string]interface{})
+	m, isObject := modified.(map[string]interface{})
+	if !ok || !isObject {
+		if hasNull(modified) {
+			return nil, fmt.Errorf("null in %q can't be set by a merge patch", path)
+		}
+		return modified, nil
+	}
+	p := make(map[string]interface{})
+	for key, value := range m {
+		if origin, ok := o[key]; ok && equal(origin, value) {
+			continue
+		}
+		child := strings.Replace(strings.Replace(key, "~", "~0", -1), "/", "~1", -1)
+		v, err := createMergePatch(o[key], value, path+"/"+child)
+		if err != nil {
+			return nil, err
+		}
+		p[key] = v
+	}
+	for key := range o {
+		if _, ok := m[key]; !ok {
+			p[key] = nil
+		}
+	}
+	return p, nil
+}
+
+// hasNull checks whether a decoded JSON value is or contains null
+func hasNull(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		for _, item := range v {
+			if hasNull(item) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if hasNull(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// MergeYAML applies a JSON Merge Patch to a YAML document whose root is a block mapping.
+// Only lines of changed keys are edited, so comments, the order of keys and styles of
+// other values are kept. Changed values are written in the block style. It fails if the
+// document can't be edited without losing content, e.g. a replaced value has comments.
+func MergeYAML(doc []byte, patch []byte) ([]byte, error) {
+	p, err := decode(patch)
+	if err != nil {
+		return nil, &Error{Index: -1, Err: fmt.Errorf("invalid merge patch: %v", err)}
+	}
+	changes, ok := p.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("merge patch of yaml should be an object")
+	}
+	target, err := decodeYAML(doc)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) <= 0 {
+		return doc, nil
+	}
+	// target is changed by merging, so the expected document is merged from a copy
+	expected, err := decodeYAML(doc)
+	if err != nil {
+		return nil, err
+	}
+	expected = mergePatch(expected, p).(map[string]interface{})
+	text := strings.TrimSuffix(string(doc), "\n")
+	lines := []string{}
+	if len(text) > 0 {
+		lines = strings.Split(text, "\n")
+	}
+	start, err := documentStart(lines)
+	if err != nil {
+		return nil, err
+	}
+	edited, err := mergeMapping(lines[start:], target, changes)
+	if err != nil {
+		return nil, err
+	}
+	result := []byte(strings.Join(append(lines[:start:start], edited...), "\n") + "\n")
+	// the edited document must be exactly the patched document
+	actual, err := decodeYAML(result)
+	if err != nil || !equal(actual, expected) {
+		return nil, fmt.Errorf("the document can't be edited without changing other values")
+	}
+	return result, nil
+}
+
+// decodeYAML decodes a YAML document whose root is a mapping. Numbers are decoded as
+// json.Number. An empty document is an empty mapping.
+func decodeYAML(doc []byte) (map[string]interface{}, error) {
+	data, err := yaml.YAMLToJSON(doc)
+	if err != nil {
+		return nil, err
+	}
+	value, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return map[string]interface{}{}, nil
+	}
+	result, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("root of the document should be a mapping")
+	}
+	return result, nil
+}
+
+// documentStart returns the index of the first line of the document after an optional
+// directives end marker. Streams of documents are not supported.
+func documentStart(lines []string) (int, error) {
+	start := 0
+	for i, line := range lines {
+		if !isDocumentMarker(line) {
+			continue
+		}
+		if i > 0 || !strings.HasPrefix(line, "---") {
+			return 0, fmt.Errorf("multiple documents are not supported")
+		}
+		start = 1
+	}
+	return start, nil
+}
+
+// isDocumentMarker checks whether a line is --- or ...
+func isDocumentMarker(line string) bool {
+	for _, marker := range []string{"---", "..."} {
+		if line == marker || strings.HasPrefix(line, marker+" ") || strings.HasPrefix(line, marker+"\t") {
+			return true
+		}
+	}
+	return false
+}
+
+// entry is an entry of a block mapping
+type entry struct {
+	key string
+	// line is the index of the line of key. Comments directly above the line start at
+	// first.
+	line, first int
+	// end is the index after the last line of the value
+	end int
+	// colon is the index of the colon after key in the line
+	colon int
+}
+
+// mergeMapping applies a merge patch to lines of a block mapping. target is the current
+// value of the mapping. It returns the edited lines.
+func mergeMapping(lines []string, target map[string]interface{}, changes map[string]interface{}) ([]string, error) {
+	entries, err := parseEntries(lines)
+	if err != nil {
+		return nil, err
+	}
+	indent := 0
+	if len(entries) > 0 {
+		indent = lineIndent(lines[entries[0].line])
+	}
+	keys := make([]string, 0, len(changes))
+	for key := range changes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// replacements are edited lines of entries by indexes of entries
+	replacements := make(map[int][]string)
+	additions := []string{}
+	for _, key := range keys {
+		change := changes[key]
+		index := -1
+		for i, e := range entries {
+			if e.key == key {
+				index = i
+			}
+		}
+		if index < 0 {
+			if change == nil {
+				continue
+			}
+			added, err := renderEntry(strings.Repeat(" ", indent)+renderKey(key)+":", "", indent, mergePatch(nil, change))
+			if err != nil {
+				return nil, err
+			}
+			additions = append(additions, added...)
+			continue
+		}
+		e := entries[index]
+		if change == nil {
+			replacements[index] = []string{}
+			continue
+		}
+		value, comment := splitComment(lines[e.line][e.colon+1:])
+		childChanges, ok := change.(map[string]interface{})
+		childTarget, isObject := target[key].(map[string]interface{})
+		if ok && isObject && len(strings.TrimSpace(value)) <= 0 && e.end > e.line+1 {
+			// merge changes into the nested block mapping
+			child, err := mergeMapping(lines[e.line+1:e.end], childTarget, childChanges)
+			if err != nil {
+				return nil, err
+			}
+			replacements[index] = append(append([]string{}, lines[e.first:e.line+1]...), child...)
+			continue
+		}
+		for _, line := range lines[e.line+1 : e.end] {
+			if isComment(line) {
+				return nil, fmt.Errorf("comments in the value of %q would be lost", key)
+			}
+		}
+		replaced, err := renderEntry(lines[e.line][:e.colon+1], comment, indent, mergePatch(target[key], change))
+		if err != nil {
+			return nil, err
+		}
+		replacements[index] = append(append([]string{}, lines[e.first:e.line]...), replaced...)
+	}
+
+	result := make([]string, 0, len(lines)+len(additions))
+	next := 0
+	for i, e := range entries {
+		result = append(result, lines[next:e.first]...)
+		if replaced, ok := replacements[i]; ok {
+			result = append(result, replaced...)
+		} else {
+			result = append(result, lines[e.first:e.end]...)
+		}
+		next = e.end
+	}
+	if len(entries) <= 0 {
+		// comments of an empty mapping are kept above added keys
+		result, next = append(result, lines...), len(lines)
+	}
+	result = append(result, additions...)
+	return append(result, lines[next:]...), nil
+}
+
+// parseEntries parses entries of a block mapping. Lines which are more indented than keys
+// belong to the value of the previous key, as well as sequences at the indent of keys.
+func parseEntries(lines []string) ([]entry, error) {
+	entries := []entry{}
+	indent := -1
+	for _, line := range lines {
+		if !isBlank(line) && !isComment(line) {
+			indent = lineIndent(line)
+			break
+		}
+	}
+	first := -1
+	for i, line := range lines {
+		if isBlank(line) || isComment(line) {
+			if isComment(line) && first < 0 && lineIndent(line) == indent {
+				first = i
+			}
+			if isBlank(line) {
+				first = -1
+			}
+			continue
+		}
+		current := lineIndent(line)
+		content := line[current:]
+		if current > indent || (current == indent && len(entries) > 0 && isSequenceItem(content)) {
+			if len(entries) <= 0 {
+				return nil, fmt.Errorf("line %q is not in a mapping", line)
+			}
+			entries[len(entries)-1].end = i + 1
+			first = -1
+			continue
+		}
+		if current < indent {
+			return nil, fmt.Errorf("line %q is less indented than its mapping", line)
+		}
+		key, colon, err := parseKey(content)
+		if err != nil {
+			return nil, err
+		}
+		if first < 0 {
+			first = i
+		}
+		entries = append(entries, entry{key: key, line: i, first: first, end: i + 1, colon: current + colon})
+		first = -1
+	}
+	return entries, nil
+}
+
+// parseKey parses a key at the start of content. It returns the key and the index of
+// the colon after the key.
+func parseKey(content string) (string, int, error) {
+	i := 0
+	switch {
+	case strings.HasPrefix(content, `"`):
+		for i = 1; i < len(content) && content[i] != '"'; i++ {
+			if content[i] == '\\' {
+				i++
+			}
+		}
+		if i >= len(content) {
+			return "", 0, fmt.Errorf("unterminated key in %q", content)
+		}
+		i++
+	case strings.HasPrefix(content, "'"):
+		for i = 1; i < len(content); i++ {
+			if content[i] == '\'' {
+				if i+1 < len(content) && content[i+1] == '\'' {
+					i++
+					continue
+				}
+				break
+			}
+		}
+		if i >= len(content) {
+			return "", 0, fmt.Errorf("unterminated key in %q", content)
+		}
+		i++
+	case strings.ContainsAny(content[:1], "-?:,[]{}#&*!|>%@`"):
+		return "", 0, fmt.Errorf("unsupported line %q in a mapping", content)
+	default:
+		for i = 0; i < len(content); i++ {
+			if content[i] == ':' && (i+1 == len(content) || content[i+1] == ' ' || content[i+1] == '\t') {
+				break
+			}
+			if content[i] == '#' && i > 0 && (content[i-1] == ' ' || content[i-1] == '\t') {
+				return "", 0, fmt.Errorf("no key in %q", content)
+			}
+		}
+	}
+	rest := strings.TrimLeft(content[i:], " \t")
+	if !strings.HasPrefix(rest, ":") {
+		return "", 0, fmt.Errorf("no key in %q", content)
+	}
+	colon := len(content) - len(rest)
+	// keys are decoded as yaml decodes them, and they are strings in json, e.g. 1 is "1"
+	data, err := yaml.YAMLToJSON([]byte(content[:colon] + ": 0"))
+	if err != nil {
+		return "", 0, fmt.Errorf("unsupported key in %q", content)
+	}
+	decoded := map[string]interface{}{}
+	if err = json.Unmarshal(data, &decoded); err != nil || len(decoded) != 1 {
+		return "", 0, fmt.Errorf("unsupported key in %q", content)
+	}
+	for key := range decoded {
+		return key, colon, nil
+	}
+	return "", 0, nil
+}
+
+// splitComment splits the rest of a line into a value and a comment which starts with #
+func splitComment(rest string) (string, string) {
+	var quote byte
+	for i := 0; i < len(rest); i++ {
+		c := rest[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && strings.TrimSpace(rest[:i]) == "":
+			quote = c
+		case c == '#' && (i == 0 || rest[i-1] == ' ' || rest[i-1] == '\t'):
+			return rest[:i], rest[i:]
+		}
+	}
+	return rest, ""
+}
+
+// renderEntry renders an entry of a block mapping. prefix is the indented key with the
+// colon, and comment is the comment after the colon.
+func renderEntry(prefix, comment string, indent int, value interface{}) ([]string, error) {
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(comment) > 0 {
+		comment = " " + comment
+	}
+	nested := false
+	switch v := value.(type) {
+	case map[string]interface{}:
+		nested = len(v) > 0
+	case []interface{}:
+		nested = len(v) > 0
+	}
+	if nested {
+		result := []string{prefix + comment}
+		for _, line := range lines {
+			result = append(result, strings.Repeat(" ", indent+2)+line)
+		}
+		return result, nil
+	}
+	// block scalars of multiline strings are indented by yaml
+	result := []string{prefix + " " + lines[0] + comment}
+	for _, line := range lines[1:] {
+		result = append(result, strings.Repeat(" ", indent)+line)
+	}
+	return result, nil
+}
+
+// renderKey renders a key which is quoted if it's necessary
+func renderKey(key string) string {
+	data, err := yaml.Marshal(key)
+	if err != nil {
+		return strconv.Quote(key)
+	}
+	return strings.TrimSuffix(string(data), "\n")
+}
+
+// lineIndent returns the number of leading spaces of a line
+func lineIndent(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// isBlank checks whether a line has only spaces
+func isBlank(line string) bool {
+	return len(strings.TrimSpace(line)) <= 0
+}
+
+// isComment checks whether a line is a comment
+func isComment(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), "#")
+}
+
+// isSequenceItem checks whether content is an item of a block sequence
+func isSequenceItem(content string) bool {
+	return content == "-" || strings.HasPrefix(content, "- ")
+}
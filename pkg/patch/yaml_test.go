@@ -0,0 +1,117 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package patch
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCreateMergePatch(t *testing.T) {
+	cases := []struct {
+		original, modified, patch string
+	}{
+		{`{"a":1}`, `{"a":1}`, `{}`},
+		{`{"a":1,"b":{"c":1,"d":2}}`, `{"a":1.0,"b":{"c":2},"e":[1]}`, `{"b":{"c":2,"d":null},"e":[1]}`},
+		{`{"a":{"b":1}}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`{"a":null}`, `{"a":null}`, `{}`},
+		{`{"a":1}`, `[1]`, `[1]`},
+	}
+	for _, c := range cases {
+		p, err := CreateMergePatch([]byte(c.original), []byte(c.modified))
+		if err != nil {
+			t.Errorf("%s %s: unexpected error: %v", c.original, c.modified, err)
+			continue
+		}
+		if !jsonEqual(t, string(p), c.patch) {
+			t.Errorf("%s %s: expected %s, got %s", c.original, c.modified, c.patch, p)
+		}
+		// the patch changes the original document into the modified document
+		result, err := MergePatch([]byte(c.original), p)
+		if err != nil || !jsonEqual(t, string(result), c.modified) {
+			t.Errorf("%s %s: patch %s results in %s, %v", c.original, c.modified, p, result, err)
+		}
+	}
+	for _, modified := range []string{`{"a":null}`, `{"a":{"b":[null]}}`} {
+		if _, err := CreateMergePatch([]byte(`{"a":1}`), []byte(modified)); err == nil {
+			t.Errorf("%s: expected an error", modified)
+		}
+	}
+}
+
+func TestMergeYAML(t *testing.T) {
+	const values = `# values of web
+
+# replicas of web
+replicas: 1 # count
+image:
+  # tag of image
+  tag: "1.0"
+  pullPolicy: Always
+
+# ports of service
+ports:
+- 80
+- 443
+`
+	cases := []struct {
+		name, doc, patch, result string
+	}{
+		{"no changes", values, `{}`, values},
+		{"scalars", values, `{"replicas":3,"image":{"tag":"2.0"}}`,
+			strings.Replace(strings.Replace(values, "replicas: 1 #", "replicas: 3 #", 1), `tag: "1.0"`, `tag: "2.0"`, 1)},
+		{"additions", values, `{"image":{"registry":"docker.io"},"service":{"type":"ClusterIP","port":80}}`,
+			strings.Replace(values, "  pullPolicy: Always\n", "  pullPolicy: Always\n  registry: docker.io\n", 1) +
+				"service:\n  port: 80\n  type: ClusterIP\n"},
+		{"removals", values, `{"image":{"pullPolicy":null},"ports":null}`,
+			"# values of web\n\n# replicas of web\nreplicas: 1 # count\nimage:\n  # tag of image\n  tag: \"1.0\"\n\n"},
+		{"removals of commented keys", values, `{"replicas":null}`,
+			strings.Replace(values, "# replicas of web\nreplicas: 1 # count\n", "", 1)},
+		{"sequences", values, `{"ports":[8080]}`, strings.Replace(values, "- 80\n- 443\n", "  - 8080\n", 1)},
+		{"objects replace scalars", "a: 1 # a\nb: 2\n", `{"a":{"x":[]}}`, "a: # a\n  x: []\nb: 2\n"},
+		{"multiline strings", "a: 1\nb: 2\n", `{"a":"x\ny"}`, "a: |-\n  x\n  y\nb: 2\n"},
+		{"indented mappings", "  a: 1\n  b:\n      c: 1\n", `{"b":{"d":true}}`, "  a: 1\n  b:\n      c: 1\n      d: true\n"},
+		{"quoted keys", "\"a.b\": 1\n'c''d': 2\n", `{"a.b":2,"c'd":3,"e f":4}`, "\"a.b\": 2\n'c''d': 3\ne f: 4\n"},
+		{"keys of other types", "1: a\ntrue: b\n", `{"1":"c","true":"d"}`, "1: c\ntrue: d\n"},
+		{"document marker", "---\na: 1\n", `{"a":2}`, "---\na: 2\n"},
+		{"empty document", "", `{"a":{"b":"1"}}`, "a:\n  b: \"1\"\n"},
+		{"comments only", "# nothing\n", `{"a":1}`, "# nothing\na: 1\n"},
+	}
+	for _, c := range cases {
+		result, err := MergeYAML([]byte(c.doc), []byte(c.patch))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", c.name, err)
+			continue
+		}
+		if string(result) != c.result {
+			t.Errorf("%s: expected\n%s\ngot\n%s", c.name, c.result, result)
+		}
+	}
+}
+
+func TestMergeYAMLErrors(t *testing.T) {
+	cases := []struct {
+		name, doc, patch, message string
+	}{
+		{"invalid patch", "a: 1\n", `{`, "invalid merge patch"},
+		{"patch of root", "a: 1\n", `[1]`, "should be an object"},
+		{"comments in values", "a:\n# http\n- 80\n", `{"a":[1]}`, "comments in the value"},
+		{"comments in replaced mappings", "a:\n  # b\n  b: 1\n", `{"a":1}`, "comments in the value"},
+		{"documents", "a: 1\n---\nb: 1\n", `{"a":2}`, "multiple documents"},
+		{"root sequence", "- a\n", `{"a":2}`, "should be a mapping"},
+		{"flow mapping", "{a: 1}\n", `{"a":2}`, "unsupported line"},
+		{"aliases", "base: &b\n  x: 1\nother: *b\n", `{"base":{"x":2}}`, "without changing other values"},
+	}
+	for _, c := range cases {
+		_, err := MergeYAML([]byte(c.doc), []byte(c.patch))
+		if err == nil {
+			t.Errorf("%s: expected an error", c.name)
+			continue
+		}
+		if !strings.Contains(err.Error(), c.message) {
+			t.Errorf("%s: expected an error with %q, got %q", c.name, c.message, err.Error())
+		}
+	}
+}
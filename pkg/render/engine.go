@@ -0,0 +1,284 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package render
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+// renderable is a template with its values
+type renderable struct {
+	// tpl is the content of template
+	tpl string
+	// vals are the values which the template is rendered with
+	vals chartutil.Values
+	// basePath is the templates directory of the chart which the template belongs to
+	basePath string
+}
+
+// maxIncludeDepth is the max depth of nested include and tpl calls. Deeper calls are
+// considered infinite recursions, which would overflow the stack.
+const maxIncludeDepth = 1000
+
+// maxOutputSize is the max size in bytes of output which an engine writes. Output of
+// included templates is counted every time it's written.
+const maxOutputSize = 16 << 20
+
+// engine renders templates in the same way as tiller. It's not safe for concurrent use.
+type engine struct {
+	// ctx limits the time of rendering. Templates fail in the next function call or write
+	// after ctx is done.
+	ctx   context.Context
+	funcs template.FuncMap
+	// depth is the depth of nested include and tpl calls
+	depth int
+	// written is the size of output which is written by all templates
+	written int
+}
+
+// check checks whether n more bytes can be written, and whether the time is up
+func (e *engine) check(n int) error {
+	if err := e.ctx.Err(); err != nil {
+		return fmt.Errorf("rendering is stopped: %v", err)
+	}
+	if n > maxOutputSize-e.written {
+		return fmt.Errorf("output is larger than %d bytes", maxOutputSize)
+	}
+	e.written += n
+	return nil
+}
+
+// limitedWriter is a writer of templates. It fails if the engine can't write more.
+type limitedWriter struct {
+	e   *engine
+	buf *bytes.Buffer
+}
+
+// Write writes p to the buffer
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if err := w.e.check(len(p)); err != nil {
+		return 0, err
+	}
+	return w.buf.Write(p)
+}
+
+// guard wraps a template function so that it fails after ctx of the engine is done.
+// text/template converts the panic into an error of the function.
+func (e *engine) guard(f interface{}) interface{} {
+	v := reflect.ValueOf(f)
+	return reflect.MakeFunc(v.Type(), func(args []reflect.Value) []reflect.Value {
+		if err := e.ctx.Err(); err != nil {
+			panic(fmt.Errorf("rendering is stopped: %v", err))
+		}
+		if v.Type().IsVariadic() {
+			return v.CallSlice(args)
+		}
+		return v.Call(args)
+	}).Interface()
+}
+
+// enter enters a nested include or tpl call. If succeed, it returns a function to leave it.
+func (e *engine) enter(function, name string) (func(), error) {
+	if e.depth >= maxIncludeDepth {
+		return nil, fmt.Errorf("%s %q: exceeded max depth %d of nested templates", function, name, maxIncludeDepth)
+	}
+	e.depth++
+	return func() { e.depth-- }, nil
+}
+
+// newEngine creates an engine which stops rendering after ctx is done
+func newEngine(ctx context.Context) *engine {
+	e := &engine{ctx: ctx}
+	funcs := funcMap()
+	e.funcs = make(template.FuncMap, len(funcs))
+	for name, f := range funcs {
+		e.funcs[name] = e.guard(f)
+	}
+	return e
+}
+
+// render renders all templates of a chart and its subcharts. Keys of the result are
+// namespaced template names, e.g. mychart/templates/svc.yaml and
+// mychart/charts/sub/templates/svc.yaml. Partials are not in the result.
+func (e *engine) render(c *chart.Chart, vals chartutil.Values) (map[string]string, error) {
+	tpls := make(map[string]renderable)
+	collectTemplates(c, tpls, vals, true, "")
+	return e.renderWithReferences(tpls, tpls)
+}
+
+// alterFuncMap adds functions which need the template set, e.g. include and tpl
+func (e *engine) alterFuncMap(t *template.Template, references map[string]renderable) template.FuncMap {
+	funcs := make(template.FuncMap, len(e.funcs)+3)
+	for name, f := range e.funcs {
+		funcs[name] = f
+	}
+	funcs["include"] = func(name string, data interface{}) (string, error) {
+		leave, err := e.enter("include", name)
+		if err != nil {
+			return "", err
+		}
+		defer leave()
+		buf := bytes.NewBuffer(nil)
+		if err := t.ExecuteTemplate(&limitedWriter{e, buf}, name, data); err != nil {
+			return "", err
+		}
+		return buf.String(), nil
+	}
+	funcs["required"] = func(message string, value interface{}) (interface{}, error) {
+		if value == nil {
+			return value, errors.New(message)
+		}
+		if s, ok := value.(string); ok && len(s) <= 0 {
+			return value, errors.New(message)
+		}
+		return value, nil
+	}
+	funcs["tpl"] = func(tpl string, vals chartutil.Values) (string, error) {
+		name, err := vals.PathValue("Template.Name")
+		if err != nil {
+			return "", fmt.Errorf("can't get template name in tpl: %v", err)
+		}
+		basePath, err := vals.PathValue("Template.BasePath")
+		if err != nil {
+			return "", fmt.Errorf("can't get template base path in tpl: %v", err)
+		}
+		templateName := fmt.Sprint(name)
+		leave, err := e.enter("tpl", templateName)
+		if err != nil {
+			return "", err
+		}
+		defer leave()
+		result, err := e.renderWithReferences(map[string]renderable{
+			templateName: {tpl: tpl, vals: vals, basePath: fmt.Sprint(basePath)},
+		}, references)
+		if err != nil {
+			return "", fmt.Errorf("error during tpl function execution for %q: %v", tpl, err)
+		}
+		return result[templateName], nil
+	}
+	return funcs
+}
+
+// renderWithReferences renders tpls. Templates in references can be included by tpls but
+// they are not rendered.
+func (e *engine) renderWithReferences(tpls, references map[string]renderable) (rendered map[string]string, err error) {
+	// a template may panic on unexpected values, e.g. index out of range
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("rendering template failed: %v", r)
+		}
+	}()
+	t := template.New("gotpl").Option("missingkey=zero")
+	funcs := e.alterFuncMap(t, references)
+	// templates in parent charts are parsed after templates in subcharts, so that parent charts
+	// can override definitions in subcharts
+	names := sortTemplates(tpls)
+	for _, name := range names {
+		if _, err := t.New(name).Funcs(funcs).Parse(tpls[name].tpl); err != nil {
+			return nil, fmt.Errorf("parse error in %q: %v", name, err)
+		}
+	}
+	for name, r := range references {
+		if t.Lookup(name) == nil {
+			if _, err := t.New(name).Funcs(funcs).Parse(r.tpl); err != nil {
+				return nil, fmt.Errorf("parse error in %q: %v", name, err)
+			}
+		}
+	}
+	rendered = make(map[string]string, len(names))
+	buf := bytes.NewBuffer(nil)
+	for _, name := range names {
+		if isPartial(name) {
+			continue
+		}
+		vals := tpls[name].vals
+		vals["Template"] = map[string]interface{}{"Name": name, "BasePath": tpls[name].basePath}
+		if err := t.ExecuteTemplate(&limitedWriter{e, buf}, name, vals); err != nil {
+			return nil, fmt.Errorf("render error in %q: %v", name, err)
+		}
+		// text/template prints <no value> for missing keys even if missingkey is zero
+		rendered[name] = strings.Replace(buf.String(), "<no value>", "", -1)
+		buf.Reset()
+	}
+	return rendered, nil
+}
+
+// collectTemplates collects templates of a chart and its subcharts. Values of a subchart
+// are scoped to the key with the same name as the subchart in its parent values.
+func collectTemplates(c *chart.Chart, tpls map[string]renderable, parentVals chartutil.Values, top bool, parentID string) {
+	vals := parentVals
+	if !top {
+		vals = chartutil.Values{
+			"Values":       chartutil.Values{},
+			"Release":      parentVals["Release"],
+			"Chart":        c.Metadata,
+			"Files":        chartutil.NewFiles(c.Files),
+			"Capabilities": parentVals["Capabilities"],
+		}
+		if values, err := parentVals.Table("Values." + c.Metadata.GetName()); err == nil {
+			vals["Values"] = values
+		}
+	}
+	id := c.Metadata.GetName()
+	if len(parentID) > 0 {
+		id = path.Join(parentID, "charts", id)
+	}
+	for _, dependency := range c.Dependencies {
+		collectTemplates(dependency, tpls, vals, false, id)
+	}
+	for _, t := range c.Templates {
+		tpls[path.Join(id, t.Name)] = renderable{
+			tpl:      string(t.Data),
+			vals:     vals,
+			basePath: path.Join(id, "templates"),
+		}
+	}
+}
+
+// sortTemplates sorts names of templates. Deeper templates are in front of others.
+func sortTemplates(tpls map[string]renderable) []string {
+	names := make([]string, 0, len(tpls))
+	for name := range tpls {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		di, dj := strings.Count(names[i], "/"), strings.Count(names[j], "/")
+		if di == dj {
+			return names[i] > names[j]
+		}
+		return di > dj
+	})
+	return names
+}
+
+// isPartial checks whether a template is a partial. Partials only contain definitions
+// which are included by other templates.
+func isPartial(name string) bool {
+	return strings.HasPrefix(path.Base(name), "_")
+}
+
+// Parse parses templates of a chart without rendering them. It returns errors by names of
+// templates, e.g. templates/svc.yaml. Templates of subcharts are not parsed.
+func Parse(c *chart.Chart) map[string]error {
+	funcs := funcMap()
+	errs := make(map[string]error)
+	for _, tpl := range c.Templates {
+		if _, err := template.New(tpl.Name).Funcs(funcs).Parse(string(tpl.Data)); err != nil {
+			errs[tpl.Name] = err
+		}
+	}
+	return errs
+}
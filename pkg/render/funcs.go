@@ -0,0 +1,1018 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package render
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/adler32"
+	"math"
+	"math/big"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/caicloud/helm-registry/pkg/semverutil"
+	"k8s.io/helm/pkg/chartutil"
+)
+
+// maxRepeatSize is the max size in bytes of a string created by repeat, indent and
+// random functions
+const maxRepeatSize = 1 << 20
+
+// maxListLength is the max length of a list created by until and untilStep
+const maxListLength = 1 << 16
+
+// funcMap returns functions which are available in templates. They are compatible with the
+// functions of sprig which are commonly used by charts, and the functions added by helm.
+// Functions which read environments are not available.
+func funcMap() template.FuncMap {
+	return template.FuncMap{
+		// helm
+		"toToml":   chartutil.ToToml,
+		"toYaml":   chartutil.ToYaml,
+		"fromYaml": chartutil.FromYaml,
+		"toJson":   chartutil.ToJson,
+		"fromJson": chartutil.FromJson,
+		// placeholders which are replaced when rendering
+		"include":  func(string, interface{}) string { return "not implemented" },
+		"required": func(string, interface{}) interface{} { return "not implemented" },
+		"tpl":      func(string, interface{}) interface{} { return "not implemented" },
+
+		// strings
+		"upper":      strings.ToUpper,
+		"lower":      strings.ToLower,
+		"title":      strings.Title,
+		"untitle":    untitle,
+		"trim":       strings.TrimSpace,
+		"trimAll":    func(cutset, s string) string { return strings.Trim(s, cutset) },
+		"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+		"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+		"contains":   func(substr, s string) bool { return strings.Contains(s, substr) },
+		"hasPrefix":  func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+		"hasSuffix":  func(suffix, s string) bool { return strings.HasSuffix(s, suffix) },
+		"replace":    func(old, new, s string) string { return strings.Replace(s, old, new, -1) },
+		"repeat":     repeat,
+		"substr":     substr,
+		"trunc":      trunc,
+		"abbrev":     abbrev,
+		"nospace":    func(s string) string { return strings.Map(dropSpace, s) },
+		"initials":   initials,
+		"quote":      quote,
+		"squote":     squote,
+		"cat":        cat,
+		"indent":     indent,
+		"nindent":    nindent,
+		"plural":     plural,
+		"snakecase":  snakecase,
+		"camelcase":  camelcase,
+		"kebabcase":  func(s string) string { return strings.Replace(snakecase(s), "_", "-", -1) },
+		"split":      split,
+		"splitList":  func(sep, s string) []string { return strings.Split(s, sep) },
+		"join":       join,
+		"sortAlpha":  sortAlpha,
+		"toString":   strval,
+		"toStrings":  strslice,
+		"randAlphaNum": func(n int) (string, error) {
+			return randString(n, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789")
+		},
+		"randAlpha": func(n int) (string, error) {
+			return randString(n, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
+		},
+		"randNumeric": func(n int) (string, error) { return randString(n, "0123456789") },
+		"randAscii": func(n int) (string, error) {
+			return randString(n, "!\"#$%&'()*+,-./0123456789:;<=>?@ABCDEFGHIJKLMNOPQRSTUVWXYZ[\\]^_`abcdefghijklmnopqrstuvwxyz{|}~")
+		},
+		"uuidv4": uuidv4,
+
+		// regular expressions
+		"regexMatch":             func(regex, s string) (bool, error) { return regexp.MatchString(regex, s) },
+		"regexFind":              regexFind,
+		"regexFindAll":           regexFindAll,
+		"regexReplaceAll":        regexReplaceAll,
+		"regexReplaceAllLiteral": regexReplaceAllLiteral,
+		"regexSplit":             regexSplit,
+
+		// encodings
+		"b64enc":        func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) },
+		"b64dec":        b64dec,
+		"b32enc":        func(s string) string { return base32.StdEncoding.EncodeToString([]byte(s)) },
+		"b32dec":        b32dec,
+		"sha1sum":       func(s string) string { sum := sha1.Sum([]byte(s)); return hex.EncodeToString(sum[:]) },
+		"sha256sum":     func(s string) string { sum := sha256.Sum256([]byte(s)); return hex.EncodeToString(sum[:]) },
+		"adler32sum":    func(s string) string { return fmt.Sprint(adler32.Checksum([]byte(s))) },
+		"toPrettyJson":  toPrettyJSON,
+		"toRawJson":     toRawJSON,
+		"base":          path.Base,
+		"dir":           path.Dir,
+		"ext":           path.Ext,
+		"clean":         path.Clean,
+		"isAbs":         path.IsAbs,
+		"semver":        semverutil.ParseVersion,
+		"semverCompare": semverCompare,
+
+		// defaults and flow control
+		"default":  defaultValue,
+		"empty":    empty,
+		"coalesce": coalesce,
+		"ternary":  ternary,
+		"fail":     func(message string) (string, error) { return "", errors.New(message) },
+
+		// types
+		"typeOf":     func(v interface{}) string { return fmt.Sprintf("%T", v) },
+		"typeIs":     func(target string, v interface{}) bool { return target == fmt.Sprintf("%T", v) },
+		"typeIsLike": typeIsLike,
+		"kindOf":     kindOf,
+		"kindIs":     func(target string, v interface{}) bool { return target == kindOf(v) },
+		"deepEqual":  reflect.DeepEqual,
+
+		// numbers
+		"int":       func(v interface{}) int { return int(toInt64(v)) },
+		"int64":     toInt64,
+		"float64":   toFloat64,
+		"atoi":      func(s string) int { n, _ := strconv.Atoi(s); return n },
+		"add":       add,
+		"add1":      func(v interface{}) int64 { return toInt64(v) + 1 },
+		"sub":       func(a, b interface{}) int64 { return toInt64(a) - toInt64(b) },
+		"mul":       mul,
+		"div":       div,
+		"mod":       mod,
+		"max":       maxInt64,
+		"min":       minInt64,
+		"floor":     func(v interface{}) float64 { return math.Floor(toFloat64(v)) },
+		"ceil":      func(v interface{}) float64 { return math.Ceil(toFloat64(v)) },
+		"round":     round,
+		"until":     func(count int) ([]int, error) { return untilStep(0, count, 1) },
+		"untilStep": untilStep,
+
+		// lists
+		"list":    list,
+		"tuple":   list,
+		"first":   first,
+		"last":    last,
+		"rest":    rest,
+		"initial": initial,
+		"append":  push,
+		"push":    push,
+		"prepend": prepend,
+		"reverse": reverse,
+		"uniq":    uniq,
+		"without": without,
+		"has":     has,
+		"compact": compact,
+
+		// dictionaries
+		"dict":   dict,
+		"get":    func(d map[string]interface{}, key string) interface{} { return d[key] },
+		"set":    func(d map[string]interface{}, key string, v interface{}) map[string]interface{} { d[key] = v; return d },
+		"unset":  func(d map[string]interface{}, key string) map[string]interface{} { delete(d, key); return d },
+		"hasKey": func(d map[string]interface{}, key string) bool { _, ok := d[key]; return ok },
+		"pluck":  pluck,
+		"keys":   keys,
+		"values": values,
+		"pick":   pick,
+		"omit":   omit,
+		"merge": func(dst map[string]interface{}, srcs ...map[string]interface{}) map[string]interface{} {
+			return merge(false, dst, srcs)
+		},
+		"mergeOverwrite": func(dst map[string]interface{}, srcs ...map[string]interface{}) map[string]interface{} {
+			return merge(true, dst, srcs)
+		},
+		"deepCopy": deepCopy,
+
+		// dates
+		"now":        time.Now,
+		"date":       func(layout string, date interface{}) string { return dateInZone(layout, date, "Local") },
+		"dateInZone": dateInZone,
+		"htmlDate":   func(date interface{}) string { return dateInZone("2006-01-02", date, "Local") },
+		"ago":        ago,
+		"toDate":     toDate,
+		"unixEpoch":  func(date time.Time) string { return strconv.FormatInt(date.Unix(), 10) },
+		"dateModify": dateModify,
+	}
+}
+
+// strval converts a value to a string
+func strval(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// strslice converts a list to a slice of strings
+func strslice(v interface{}) []string {
+	switch v := v.(type) {
+	case []string:
+		return v
+	case nil:
+		return []string{}
+	}
+	items := toList(v)
+	if items == nil {
+		return []string{strval(v)}
+	}
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if item != nil {
+			result = append(result, strval(item))
+		}
+	}
+	return result
+}
+
+func untitle(s string) string {
+	words := strings.Fields(s)
+	for i, word := range words {
+		r, size := utf8.DecodeRuneInString(word)
+		words[i] = string(unicode.ToLower(r)) + word[size:]
+	}
+	return strings.Join(words, " ")
+}
+
+func substr(start, end int, s string) string {
+	if start < 0 {
+		start = 0
+	}
+	if end < 0 || end > len(s) {
+		end = len(s)
+	}
+	if start > end {
+		return ""
+	}
+	return s[start:end]
+}
+
+func trunc(c int, s string) string {
+	if c < 0 && len(s)+c > 0 {
+		return s[len(s)+c:]
+	}
+	if c >= 0 && len(s) > c {
+		return s[:c]
+	}
+	return s
+}
+
+func abbrev(width int, s string) string {
+	if width < 4 || len(s) <= width {
+		return s
+	}
+	return s[:width-3] + "..."
+}
+
+func dropSpace(r rune) rune {
+	if unicode.IsSpace(r) {
+		return -1
+	}
+	return r
+}
+
+func initials(s string) string {
+	buf := make([]rune, 0)
+	for _, word := range strings.Fields(s) {
+		buf = append(buf, []rune(word)[0])
+	}
+	return string(buf)
+}
+
+func quote(values ...interface{}) string {
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != nil {
+			result = append(result, strconv.Quote(strval(v)))
+		}
+	}
+	return strings.Join(result, " ")
+}
+
+func squote(values ...interface{}) string {
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != nil {
+			result = append(result, "'"+strval(v)+"'")
+		}
+	}
+	return strings.Join(result, " ")
+}
+
+func cat(values ...interface{}) string {
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != nil {
+			result = append(result, strval(v))
+		}
+	}
+	return strings.Join(result, " ")
+}
+
+// indent indents every line of s. Spaces added to s can't be more than maxRepeatSize
+func indent(spaces int, s string) (string, error) {
+	if spaces < 0 {
+		return "", fmt.Errorf("indent: negative spaces %d", spaces)
+	}
+	if lines := strings.Count(s, "\n") + 1; spaces > maxRepeatSize/lines {
+		return "", fmt.Errorf("indent: more than %d spaces are added", maxRepeatSize)
+	}
+	pad := strings.Repeat(" ", spaces)
+	return pad + strings.Replace(s, "\n", "\n"+pad, -1), nil
+}
+
+func nindent(spaces int, s string) (string, error) {
+	result, err := indent(spaces, s)
+	if err != nil {
+		return "", err
+	}
+	return "\n" + result, nil
+}
+
+func plural(one, many string, count int) string {
+	if count == 1 {
+		return one
+	}
+	return many
+}
+
+func snakecase(s string) string {
+	runes := []rune(s)
+	result := make([]rune, 0, len(runes))
+	for i, r := range runes {
+		switch {
+		case unicode.IsUpper(r):
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) &&
+				result[len(result)-1] != '_' {
+				result = append(result, '_')
+			}
+			result = append(result, unicode.ToLower(r))
+		case r == '-' || unicode.IsSpace(r):
+			result = append(result, '_')
+		default:
+			result = append(result, r)
+		}
+	}
+	return string(result)
+}
+
+func camelcase(s string) string {
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return r == '_' || r == '-' || unicode.IsSpace(r)
+	})
+	for i, word := range words {
+		words[i] = strings.Title(word)
+	}
+	return strings.Join(words, "")
+}
+
+func split(sep, s string) map[string]string {
+	result := make(map[string]string)
+	for i, part := range strings.Split(s, sep) {
+		result["_"+strconv.Itoa(i)] = part
+	}
+	return result
+}
+
+func join(sep string, v interface{}) string {
+	return strings.Join(strslice(v), sep)
+}
+
+func sortAlpha(v interface{}) []string {
+	result := append([]string{}, strslice(v)...)
+	sort.Strings(result)
+	return result
+}
+
+// randString creates a random string of letters. It can't be longer than maxRepeatSize
+func randString(n int, letters string) (string, error) {
+	if n < 0 || n > maxRepeatSize {
+		return "", fmt.Errorf("random string length %d is out of range [0, %d]", n, maxRepeatSize)
+	}
+	result := make([]byte, n)
+	max := big.NewInt(int64(len(letters)))
+	for i := range result {
+		index, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		result[i] = letters[index.Int64()]
+	}
+	return string(result), nil
+}
+
+func uuidv4() (string, error) {
+	u := make([]byte, 16)
+	if _, err := rand.Read(u); err != nil {
+		return "", err
+	}
+	u[6] = (u[6] & 0x0f) | 0x40
+	u[8] = (u[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:]), nil
+}
+
+func regexFind(regex, s string) (string, error) {
+	r, err := regexp.Compile(regex)
+	if err != nil {
+		return "", err
+	}
+	return r.FindString(s), nil
+}
+
+func regexFindAll(regex, s string, n int) ([]string, error) {
+	r, err := regexp.Compile(regex)
+	if err != nil {
+		return nil, err
+	}
+	return r.FindAllString(s, n), nil
+}
+
+func regexReplaceAll(regex, s, replacement string) (string, error) {
+	r, err := regexp.Compile(regex)
+	if err != nil {
+		return "", err
+	}
+	return r.ReplaceAllString(s, replacement), nil
+}
+
+func regexReplaceAllLiteral(regex, s, replacement string) (string, error) {
+	r, err := regexp.Compile(regex)
+	if err != nil {
+		return "", err
+	}
+	return r.ReplaceAllLiteralString(s, replacement), nil
+}
+
+func regexSplit(regex, s string, n int) ([]string, error) {
+	r, err := regexp.Compile(regex)
+	if err != nil {
+		return nil, err
+	}
+	return r.Split(s, n), nil
+}
+
+func b64dec(s string) string {
+	data, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func b32dec(s string) string {
+	data, err := base32.StdEncoding.DecodeString(s)
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func toPrettyJSON(v interface{}) string {
+	data, _ := json.MarshalIndent(v, "", "  ")
+	return string(data)
+}
+
+func toRawJSON(v interface{}) string {
+	buf := &strings.Builder{}
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// empty checks whether a value is nil or zero
+func empty(v interface{}) bool {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return true
+	}
+	switch rv.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return rv.Len() == 0
+	case reflect.Bool:
+		return !rv.Bool()
+	case reflect.Complex64, reflect.Complex128:
+		return rv.Complex() == 0
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return rv.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return rv.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return rv.IsNil()
+	case reflect.Struct:
+		return reflect.DeepEqual(v, reflect.Zero(rv.Type()).Interface())
+	}
+	return false
+}
+
+// defaultValue returns the given value if it's not empty, or returns the default value
+func defaultValue(d interface{}, given ...interface{}) interface{} {
+	if len(given) <= 0 || empty(given[0]) {
+		return d
+	}
+	return given[0]
+}
+
+func coalesce(values ...interface{}) interface{} {
+	for _, v := range values {
+		if !empty(v) {
+			return v
+		}
+	}
+	return nil
+}
+
+func ternary(whenTrue, whenFalse interface{}, condition bool) interface{} {
+	if condition {
+		return whenTrue
+	}
+	return whenFalse
+}
+
+func typeIsLike(target string, v interface{}) bool {
+	t := fmt.Sprintf("%T", v)
+	return target == t || "*"+target == t
+}
+
+func kindOf(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return "invalid"
+	}
+	return rv.Kind().String()
+}
+
+// toInt64 converts a number or a numeric string to int64. It returns 0 if the value is
+// not a number.
+func toInt64(v interface{}) int64 {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return int64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return int64(rv.Float())
+	case reflect.Bool:
+		if rv.Bool() {
+			return 1
+		}
+	case reflect.String:
+		s := strings.TrimSpace(rv.String())
+		if n, err := strconv.ParseInt(s, 0, 64); err == nil {
+			return n
+		}
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return int64(f)
+		}
+	}
+	return 0
+}
+
+// toFloat64 converts a number or a numeric string to float64. It returns 0 if the value is
+// not a number.
+func toFloat64(v interface{}) float64 {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	case reflect.String:
+		f, _ := strconv.ParseFloat(strings.TrimSpace(rv.String()), 64)
+		return f
+	}
+	return float64(toInt64(v))
+}
+
+func add(values ...interface{}) int64 {
+	var result int64
+	for _, v := range values {
+		result += toInt64(v)
+	}
+	return result
+}
+
+func mul(a interface{}, values ...interface{}) int64 {
+	result := toInt64(a)
+	for _, v := range values {
+		result *= toInt64(v)
+	}
+	return result
+}
+
+func div(a, b interface{}) (int64, error) {
+	if toInt64(b) == 0 {
+		return 0, errors.New("integer divide by zero")
+	}
+	return toInt64(a) / toInt64(b), nil
+}
+
+func mod(a, b interface{}) (int64, error) {
+	if toInt64(b) == 0 {
+		return 0, errors.New("integer divide by zero")
+	}
+	return toInt64(a) % toInt64(b), nil
+}
+
+func maxInt64(a interface{}, values ...interface{}) int64 {
+	result := toInt64(a)
+	for _, v := range values {
+		if n := toInt64(v); n > result {
+			result = n
+		}
+	}
+	return result
+}
+
+func minInt64(a interface{}, values ...interface{}) int64 {
+	result := toInt64(a)
+	for _, v := range values {
+		if n := toInt64(v); n < result {
+			result = n
+		}
+	}
+	return result
+}
+
+func round(v interface{}, precision int, roundOn ...float64) float64 {
+	on := 0.5
+	if len(roundOn) > 0 {
+		on = roundOn[0]
+	}
+	pow := math.Pow(10, float64(precision))
+	digit := pow * toFloat64(v)
+	_, frac := math.Modf(digit)
+	if frac >= on {
+		return math.Ceil(digit) / pow
+	}
+	return math.Floor(digit) / pow
+}
+
+// repeat repeats s count times. The result can't be larger than maxRepeatSize
+func repeat(count int, s string) (string, error) {
+	if count < 0 {
+		return "", fmt.Errorf("repeat: negative count %d", count)
+	}
+	if len(s) > 0 && count > maxRepeatSize/len(s) {
+		return "", fmt.Errorf("repeat: result is larger than %d bytes", maxRepeatSize)
+	}
+	return strings.Repeat(s, count), nil
+}
+
+// untilStep creates a list of integers from start to stop by step. The list can't be longer
+// than maxListLength
+func untilStep(start, stop, step int) ([]int, error) {
+	result := []int{}
+	if step == 0 || (step > 0 && start >= stop) || (step < 0 && start <= stop) {
+		return result, nil
+	}
+	for i := start; (step > 0 && i < stop) || (step < 0 && i > stop); i += step {
+		if len(result) >= maxListLength {
+			return nil, fmt.Errorf("until: list is longer than %d", maxListLength)
+		}
+		result = append(result, i)
+	}
+	return result, nil
+}
+
+// toList converts a slice or an array to []interface{}. It returns nil if the value is
+// not a list.
+func toList(v interface{}) []interface{} {
+	if items, ok := v.([]interface{}); ok {
+		return items
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil
+	}
+	result := make([]interface{}, rv.Len())
+	for i := range result {
+		result[i] = rv.Index(i).Interface()
+	}
+	return result
+}
+
+// mustList converts a value to a list or returns an error
+func mustList(v interface{}) ([]interface{}, error) {
+	if v == nil {
+		return []interface{}{}, nil
+	}
+	items := toList(v)
+	if items == nil {
+		return nil, fmt.Errorf("cannot use %T as a list", v)
+	}
+	return items, nil
+}
+
+func list(values ...interface{}) []interface{} {
+	return values
+}
+
+func first(v interface{}) (interface{}, error) {
+	items, err := mustList(v)
+	if err != nil || len(items) <= 0 {
+		return nil, err
+	}
+	return items[0], nil
+}
+
+func last(v interface{}) (interface{}, error) {
+	items, err := mustList(v)
+	if err != nil || len(items) <= 0 {
+		return nil, err
+	}
+	return items[len(items)-1], nil
+}
+
+func rest(v interface{}) ([]interface{}, error) {
+	items, err := mustList(v)
+	if err != nil || len(items) <= 0 {
+		return items, err
+	}
+	return items[1:], nil
+}
+
+func initial(v interface{}) ([]interface{}, error) {
+	items, err := mustList(v)
+	if err != nil || len(items) <= 0 {
+		return items, err
+	}
+	return items[:len(items)-1], nil
+}
+
+func push(v interface{}, item interface{}) ([]interface{}, error) {
+	items, err := mustList(v)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]interface{}{}, items...), item), nil
+}
+
+func prepend(v interface{}, item interface{}) ([]interface{}, error) {
+	items, err := mustList(v)
+	if err != nil {
+		return nil, err
+	}
+	return append([]interface{}{item}, items...), nil
+}
+
+func reverse(v interface{}) ([]interface{}, error) {
+	items, err := mustList(v)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]interface{}, len(items))
+	for i, item := range items {
+		result[len(items)-1-i] = item
+	}
+	return result, nil
+}
+
+func uniq(v interface{}) ([]interface{}, error) {
+	items, err := mustList(v)
+	if err != nil {
+		return nil, err
+	}
+	result := []interface{}{}
+	for _, item := range items {
+		if !inList(result, item) {
+			result = append(result, item)
+		}
+	}
+	return result, nil
+}
+
+func without(v interface{}, omitted ...interface{}) ([]interface{}, error) {
+	items, err := mustList(v)
+	if err != nil {
+		return nil, err
+	}
+	result := []interface{}{}
+	for _, item := range items {
+		if !inList(omitted, item) {
+			result = append(result, item)
+		}
+	}
+	return result, nil
+}
+
+func has(needle interface{}, haystack interface{}) (bool, error) {
+	items, err := mustList(haystack)
+	if err != nil {
+		return false, err
+	}
+	return inList(items, needle), nil
+}
+
+func compact(v interface{}) ([]interface{}, error) {
+	items, err := mustList(v)
+	if err != nil {
+		return nil, err
+	}
+	result := []interface{}{}
+	for _, item := range items {
+		if !empty(item) {
+			result = append(result, item)
+		}
+	}
+	return result, nil
+}
+
+func inList(items []interface{}, needle interface{}) bool {
+	for _, item := range items {
+		if reflect.DeepEqual(item, needle) {
+			return true
+		}
+	}
+	return false
+}
+
+func dict(values ...interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(values)/2)
+	for i := 0; i < len(values); i += 2 {
+		key := strval(values[i])
+		if i+1 >= len(values) {
+			result[key] = ""
+			break
+		}
+		result[key] = values[i+1]
+	}
+	return result
+}
+
+func pluck(key string, dicts ...map[string]interface{}) []interface{} {
+	result := []interface{}{}
+	for _, d := range dicts {
+		if v, ok := d[key]; ok {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func keys(dicts ...map[string]interface{}) []string {
+	result := []string{}
+	for _, d := range dicts {
+		for key := range d {
+			result = append(result, key)
+		}
+	}
+	return result
+}
+
+func values(d map[string]interface{}) []interface{} {
+	result := make([]interface{}, 0, len(d))
+	for _, v := range d {
+		result = append(result, v)
+	}
+	return result
+}
+
+func pick(d map[string]interface{}, names ...string) map[string]interface{} {
+	result := make(map[string]interface{})
+	for _, name := range names {
+		if v, ok := d[name]; ok {
+			result[name] = v
+		}
+	}
+	return result
+}
+
+func omit(d map[string]interface{}, names ...string) map[string]interface{} {
+	omitted := make(map[string]bool, len(names))
+	for _, name := range names {
+		omitted[name] = true
+	}
+	result := make(map[string]interface{})
+	for key, v := range d {
+		if !omitted[key] {
+			result[key] = v
+		}
+	}
+	return result
+}
+
+// merge merges sources into dst recursively. Values in dst are kept unless overwrite is true.
+func merge(overwrite bool, dst map[string]interface{}, srcs []map[string]interface{}) map[string]interface{} {
+	for _, src := range srcs {
+		for key, v := range src {
+			origin, ok := dst[key]
+			if !ok {
+				dst[key] = v
+				continue
+			}
+			originTable, ok1 := asTable(origin)
+			table, ok2 := asTable(v)
+			switch {
+			case ok1 && ok2:
+				dst[key] = merge(overwrite, originTable, []map[string]interface{}{table})
+			case overwrite || empty(origin):
+				dst[key] = v
+			}
+		}
+	}
+	return dst
+}
+
+// asTable converts a value to a table if it's a map
+func asTable(v interface{}) (map[string]interface{}, bool) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return v, true
+	case chartutil.Values:
+		return v, true
+	}
+	return nil, false
+}
+
+func deepCopy(v interface{}) interface{} {
+	if table, ok := asTable(v); ok {
+		result := make(map[string]interface{}, len(table))
+		for key, value := range table {
+			result[key] = deepCopy(value)
+		}
+		return result
+	}
+	if items, ok := v.([]interface{}); ok {
+		result := make([]interface{}, len(items))
+		for i, item := range items {
+			result[i] = deepCopy(item)
+		}
+		return result
+	}
+	return v
+}
+
+// toTime converts a value to time. Numbers are unix seconds.
+func toTime(date interface{}) time.Time {
+	switch date := date.(type) {
+	case time.Time:
+		return date
+	case *time.Time:
+		return *date
+	case nil:
+		return time.Now()
+	}
+	return time.Unix(toInt64(date), 0)
+}
+
+func dateInZone(layout string, date interface{}, zone string) string {
+	location, err := time.LoadLocation(zone)
+	if err != nil {
+		location = time.UTC
+	}
+	return toTime(date).In(location).Format(layout)
+}
+
+func ago(date interface{}) string {
+	return time.Since(toTime(date)).Round(time.Second).String()
+}
+
+func toDate(layout, s string) time.Time {
+	t, _ := time.ParseInLocation(layout, s, time.Local)
+	return t
+}
+
+func dateModify(duration string, date time.Time) time.Time {
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		return date
+	}
+	return date.Add(d)
+}
+
+// semverCompare checks whether a version satisfies constraints
+func semverCompare(constraints, version string) (bool, error) {
+	cs, err := semverutil.ParseConstraints(constraints)
+	if err != nil {
+		return false, err
+	}
+	v, err := semverutil.ParseVersion(version)
+	if err != nil {
+		return false, err
+	}
+	return cs.Check(*v), nil
+}
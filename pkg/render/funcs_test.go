@@ -0,0 +1,243 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package render
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/helm/pkg/chartutil"
+)
+
+// execute renders a template with values by the engine
+func execute(tpl string, values map[string]interface{}) (string, error) {
+	tpls := map[string]renderable{
+		"test/templates/test.yaml": {
+			tpl:      tpl,
+			vals:     chartutil.Values{"Values": values},
+			basePath: "test/templates",
+		},
+	}
+	rendered, err := newEngine(context.Background()).renderWithReferences(tpls, tpls)
+	if err != nil {
+		return "", err
+	}
+	return rendered["test/templates/test.yaml"], nil
+}
+
+func TestFuncs(t *testing.T) {
+	values := map[string]interface{}{
+		"name":   "web",
+		"empty":  "",
+		"count":  3,
+		"float":  2.5,
+		"list":   []interface{}{"b", "a", "b", nil},
+		"nested": map[string]interface{}{"key": "value"},
+	}
+	cases := []struct {
+		tpl, result string
+	}{
+		// strings
+		{`{{ upper .Values.name }} {{ title "a b" }} {{ untitle "Ab Über" }}`, "WEB A B ab über"},
+		{`{{ trim " a " }}|{{ trimAll "-" "--a--" }}|{{ trimPrefix "a" "ab" }}|{{ trimSuffix "b" "ab" }}`, "a|a|b|a"},
+		{`{{ contains "e" .Values.name }} {{ hasPrefix "w" .Values.name }} {{ hasSuffix "x" .Values.name }}`, "true true false"},
+		{`{{ replace "b" "c" "abab" }} {{ repeat 3 "ab" }} {{ repeat 0 "ab" }}`, "acac ababab "},
+		{`{{ substr 1 3 "abcd" }}|{{ substr 3 1 "abcd" }}|{{ substr 1 -1 "abcd" }}`, "bc||bcd"},
+		{`{{ trunc 2 "abc" }}|{{ trunc -2 "abc" }}|{{ trunc 5 "abc" }}|{{ trunc -5 "abc" }}`, "ab|bc|abc|abc"},
+		{`{{ abbrev 5 "hello world" }}|{{ abbrev 3 "hello" }}`, "he...|hello"},
+		{`{{ nospace " a b\tc " }} {{ initials "Helm Registry" }}`, "abc HR"},
+		{`{{ quote "a" 1 .Values.missing }} {{ squote "a" }} {{ cat "a" 1 .Values.missing "b" }}`, `"a" "1" 'a' a 1 b`},
+		{`{{ indent 2 "a\nb" }}|{{ nindent 1 "a" }}`, "  a\n  b|\n a"},
+		{`{{ plural "one" "many" 1 }} {{ plural "one" "many" 2 }}`, "one many"},
+		{`{{ snakecase "HTTPServerName" }} {{ camelcase "http_server" }} {{ kebabcase "fooBar" }}`, "http_server_name HttpServer foo-bar"},
+		{`{{ $s := split "," "a,b" }}{{ $s._0 }}{{ $s._1 }} {{ splitList "," "a,b" }}`, "ab [a b]"},
+		{`{{ join "," .Values.list }} {{ sortAlpha .Values.list }} {{ join "-" "a" }}`, "b,a,b [a b b] a"},
+		{`{{ toString 1 }} {{ toStrings .Values.list }}`, "1 [b a b]"},
+		{`{{ len (randAlphaNum 8) }} {{ len (randNumeric 0) }} {{ len uuidv4 }}`, "8 0 36"},
+		// regular expressions
+		{`{{ regexMatch "^w" .Values.name }} {{ regexFind "[a-z]b" "xaby" }} {{ regexFindAll "a" "aaa" 2 }}`, "true ab [a a]"},
+		{`{{ regexReplaceAll "a(x*)b" "-ab-axxb-" "${1}W" }} {{ regexReplaceAllLiteral "a" "aa" "$1" }}`, "-W-xxW- $1$1"},
+		{`{{ regexSplit "," "a,b,c" -1 }}`, "[a b c]"},
+		// encodings
+		{`{{ b64enc "helm" }} {{ b64dec "aGVsbQ==" }} {{ b32enc "helm" }} {{ b32dec "NBSWY3I=" }}`, "aGVsbQ== helm NBSWY3I= helm"},
+		{`{{ sha256sum "" }}`, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+		{`{{ toJson .Values.nested }} {{ toRawJson "<a>" }} {{ toPrettyJson .Values.nested }}`, "{\"key\":\"value\"} \"<a>\" {\n  \"key\": \"value\"\n}"},
+		{`{{ base "a/b.yaml" }} {{ dir "a/b.yaml" }} {{ ext "a/b.yaml" }} {{ clean "a/../b" }} {{ isAbs "/a" }}`, "b.yaml a .yaml b true"},
+		{`{{ (semver "v1.7").Minor }} {{ semverCompare "~1.7.0" "v1.7.3" }}`, "7 true"},
+		// defaults and flow control
+		{`{{ default "d" .Values.empty }} {{ default "d" .Values.name }} {{ default "d" .Values.missing }}`, "d web d"},
+		{`{{ empty .Values.empty }} {{ empty .Values.count }} {{ empty .Values.missing }} {{ empty (list) }}`, "true false true true"},
+		{`{{ coalesce .Values.missing .Values.empty "c" }} {{ ternary "y" "n" true }}`, "c y"},
+		// types
+		{`{{ typeOf .Values.count }} {{ typeIs "string" .Values.name }} {{ typeIsLike "int" .Values.count }}`, "int true true"},
+		{`{{ kindOf .Values.nested }} {{ kindIs "slice" .Values.list }} {{ kindOf .Values.missing }}`, "map true invalid"},
+		{`{{ deepEqual (list 1 2) (list 1 2) }}`, "true"},
+		// numbers
+		{`{{ int "12" }} {{ int64 .Values.float }} {{ float64 "1.5" }} {{ atoi "x" }}`, "12 2 1.5 0"},
+		{`{{ add 1 "2" 3.9 }} {{ add1 .Values.count }} {{ sub 1 2 }} {{ mul 2 3 4 }}`, "6 4 -1 24"},
+		{`{{ div 7 2 }} {{ mod 7 2 }} {{ max 1 5 3 }} {{ min 4 2 }}`, "3 1 5 2"},
+		{`{{ floor 1.5 }} {{ ceil 1.2 }} {{ round 1.25 1 }} {{ round 1.24 1 }}`, "1 2 1.3 1.2"},
+		{`{{ until 3 }} {{ untilStep 5 0 -2 }} {{ untilStep 0 3 0 }}`, "[0 1 2] [5 3 1] []"},
+		// lists
+		{`{{ first .Values.list }} {{ last (list 1 2) }} {{ rest (list 1 2) }} {{ initial (list 1 2) }}`, "b 2 [2] [1]"},
+		{`{{ first (list) }}|{{ append (list 1) 2 }} {{ prepend (list 1) 0 }} {{ reverse (list 1 2) }}`, "|[1 2] [0 1] [2 1]"},
+		{`{{ uniq .Values.list }} {{ without (list 1 2 1) 1 }} {{ has 2 (list 1 2) }} {{ compact .Values.list }}`, "[b a <nil>] [2] true [b a b]"},
+		// dictionaries
+		{`{{ $d := dict "a" 1 "b" }}{{ get $d "a" }} {{ hasKey $d "b" }} {{ get $d "b" | quote }}`, `1 true ""`},
+		{`{{ $d := dict "a" 1 }}{{ $_ := set $d "b" 2 }}{{ $_ := unset $d "a" }}{{ keys $d }}`, "[b]"},
+		{`{{ pluck "a" (dict "a" 1) (dict "b" 2) (dict "a" 3) }} {{ values (dict "a" 1) }}`, "[1 3] [1]"},
+		{`{{ pick (dict "a" 1 "b" 2) "a" }} {{ omit (dict "a" 1 "b" 2) "a" }}`, "map[a:1] map[b:2]"},
+		{`{{ merge (dict "a" 1 "b" (dict "c" 1)) (dict "a" 2 "b" (dict "d" 2)) }}`, "map[a:1 b:map[c:1 d:2]]"},
+		{`{{ mergeOverwrite (dict "a" 1 "e" "") (dict "a" 2) (dict "e" 3) }}`, "map[a:2 e:3]"},
+		{`{{ $d := dict "a" (dict "b" 1) }}{{ $c := deepCopy $d }}{{ $_ := set $c.a "b" 2 }}{{ $d.a.b }}`, "1"},
+		// dates
+		{`{{ dateInZone "2006-01-02 15:04" 0 "UTC" }} {{ unixEpoch (toDate "2006-01-02" "1970-01-02") }}`, "1970-01-01 00:00 " + unixDay()},
+		{`{{ dateInZone "15:04" (dateModify "90m" (toDate "15:04" "01:00")) "Local" }}`, "02:30"},
+	}
+	for _, c := range cases {
+		result, err := execute(c.tpl, values)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", c.tpl, err)
+			continue
+		}
+		if result != c.result {
+			t.Errorf("%s: expected %q, got %q", c.tpl, c.result, result)
+		}
+	}
+}
+
+// unixDay returns unix seconds of 1970-01-02 in the local zone
+func unixDay() string {
+	return strval(toDate("2006-01-02", "1970-01-02").Unix())
+}
+
+func TestFuncErrors(t *testing.T) {
+	cases := []struct {
+		tpl, message string
+	}{
+		{`{{ repeat -1 "a" }}`, "negative count"},
+		{`{{ repeat 1048577 "a" }}`, "larger than 1048576 bytes"},
+		{`{{ repeat 524289 "ab" }}`, "larger than 1048576 bytes"},
+		{`{{ until 65537 }}`, "longer than 65536"},
+		{`{{ untilStep 0 -65537 -1 }}`, "longer than 65536"},
+		{`{{ indent -1 "a" }}`, "negative spaces"},
+		{`{{ nindent 524289 "a\nb" }}`, "more than 1048576 spaces"},
+		{`{{ randAlphaNum -1 }}`, "out of range"},
+		{`{{ randAscii 1048577 }}`, "out of range"},
+		{`{{ div 1 0 }}`, "divide by zero"},
+		{`{{ mod 1 "0" }}`, "divide by zero"},
+		{`{{ first "abc" }}`, "cannot use string as a list"},
+		{`{{ append 1 2 }}`, "cannot use int as a list"},
+		{`{{ regexMatch "(" "a" }}`, "missing closing )"},
+		{`{{ regexReplaceAll "[" "a" "b" }}`, "missing closing ]"},
+		{`{{ fail "stop here" }}`, "stop here"},
+		{`{{ semverCompare "> 1.x.y" "1.0.0" }}`, "invalid constraint"},
+		{`{{ semver "a.b" }}`, "not a number"},
+	}
+	for _, c := range cases {
+		_, err := execute(c.tpl, nil)
+		if err == nil {
+			t.Errorf("%s: expected an error", c.tpl)
+			continue
+		}
+		if !strings.Contains(err.Error(), c.message) {
+			t.Errorf("%s: expected an error with %q, got %q", c.tpl, c.message, err.Error())
+		}
+	}
+}
+
+func TestLimits(t *testing.T) {
+	if s, err := repeat(maxRepeatSize, "a"); err != nil || len(s) != maxRepeatSize {
+		t.Errorf("repeat should create a string of the max size: %v", err)
+	}
+	if l, err := untilStep(0, maxListLength, 1); err != nil || len(l) != maxListLength {
+		t.Errorf("until should create a list of the max length: %v", err)
+	}
+	if s, err := indent(maxRepeatSize, "a"); err != nil || len(s) != maxRepeatSize+1 {
+		t.Errorf("indent should add the max number of spaces: %v", err)
+	}
+	// indenting large text is only limited by added spaces
+	text := strings.Repeat("a", maxRepeatSize*2)
+	if s, err := indent(2, text); err != nil || len(s) != len(text)+2 {
+		t.Errorf("indent should indent large text: %v", err)
+	}
+}
+
+func TestEmpty(t *testing.T) {
+	type object struct{ A int }
+	cases := []struct {
+		value interface{}
+		empty bool
+	}{
+		{nil, true},
+		{"", true},
+		{"a", false},
+		{0, true},
+		{uint(1), false},
+		{0.0, true},
+		{false, true},
+		{true, false},
+		{[]interface{}{}, true},
+		{map[string]interface{}{"a": nil}, false},
+		{(*object)(nil), true},
+		{object{}, true},
+		{object{A: 1}, false},
+		{complex(0, 0), true},
+	}
+	for _, c := range cases {
+		if empty(c.value) != c.empty {
+			t.Errorf("%#v: expected empty to be %v", c.value, c.empty)
+		}
+	}
+}
+
+func TestToInt64(t *testing.T) {
+	cases := []struct {
+		value  interface{}
+		result int64
+	}{
+		{nil, 0},
+		{int8(-3), -3},
+		{uint16(3), 3},
+		{3.9, 3},
+		{true, 1},
+		{false, 0},
+		{" 42 ", 42},
+		{"0x10", 16},
+		{"1.5", 1},
+		{"a", 0},
+		{[]int{1}, 0},
+	}
+	for _, c := range cases {
+		if result := toInt64(c.value); result != c.result {
+			t.Errorf("%#v: expected %d, got %d", c.value, c.result, result)
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	dst := map[string]interface{}{
+		"a": 1,
+		"b": chartutil.Values{"c": 1},
+		"e": nil,
+	}
+	src := map[string]interface{}{
+		"a": 2,
+		"b": map[string]interface{}{"d": 2},
+		"e": "e",
+		"f": "f",
+	}
+	expected := map[string]interface{}{
+		"a": 1,
+		"b": map[string]interface{}{"c": 1, "d": 2},
+		"e": "e",
+		"f": "f",
+	}
+	if result := merge(false, dst, []map[string]interface{}{src}); !reflect.DeepEqual(result, expected) {
+		t.Fatalf("expected %v, got %v", expected, result)
+	}
+}
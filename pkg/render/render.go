@@ -0,0 +1,144 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+// Package render renders templates of charts into Kubernetes manifests without a cluster.
+// Templates are rendered in the same way as tiller, including subcharts, requirement
+// conditions and imported values.
+package render
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/caicloud/helm-registry/pkg/api/v1/types"
+	"github.com/caicloud/helm-registry/pkg/semverutil"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	tversion "k8s.io/helm/pkg/proto/hapi/version"
+)
+
+const (
+	// DefaultReleaseName is the release name if it's not specified
+	DefaultReleaseName = "RELEASE-NAME"
+	// DefaultNamespace is the namespace if it's not specified
+	DefaultNamespace = "default"
+	// DefaultKubeVersion is the Kubernetes version if it's not specified
+	DefaultKubeVersion = "v1.7.0"
+	// TillerVersion is the tiller version in capabilities
+	TillerVersion = "v2.5.0"
+)
+
+// notesName is the name of NOTES.txt in templates
+const notesName = "templates/NOTES.txt"
+
+// Options are options of rendering
+type Options struct {
+	// Name is the release name
+	Name string
+	// Namespace is the namespace of release
+	Namespace string
+	// Values are values which override the values of chart
+	Values map[string]interface{}
+	// KubeVersion is the version of Kubernetes, e.g. v1.7.0
+	KubeVersion string
+	// APIVersions are the api versions supported by Kubernetes. v1 is always supported.
+	APIVersions []string
+	// IsUpgrade indicates that the release is upgraded instead of installed
+	IsUpgrade bool
+}
+
+// maxRenderTime is the max time of rendering all templates of a chart
+const maxRenderTime = 10 * time.Second
+
+// Render renders all templates of a chart. The chart is modified by requirements, so a
+// chart should not be rendered twice. Rendering fails if it takes longer than ctx allows
+// or maxRenderTime, or templates write more than maxOutputSize bytes.
+func Render(ctx context.Context, c *chart.Chart, options *Options) (*types.RenderResult, error) {
+	overrides, err := yaml.Marshal(options.Values)
+	if err != nil {
+		return nil, fmt.Errorf("can't encode values: %v", err)
+	}
+	config := &chart.Config{Raw: string(overrides)}
+	if len(options.Values) <= 0 {
+		config.Raw = ""
+	}
+	if err = chartutil.ProcessRequirementsEnabled(c, config); err != nil {
+		return nil, err
+	}
+	if err = chartutil.ProcessRequirementsImportValues(c); err != nil {
+		return nil, err
+	}
+	caps, err := capabilities(options)
+	if err != nil {
+		return nil, err
+	}
+	releaseOptions := chartutil.ReleaseOptions{
+		Name:      options.Name,
+		Namespace: options.Namespace,
+		Time:      &timestamp.Timestamp{Seconds: time.Now().Unix()},
+		IsInstall: !options.IsUpgrade,
+		IsUpgrade: options.IsUpgrade,
+		Revision:  1,
+	}
+	if len(releaseOptions.Name) <= 0 {
+		releaseOptions.Name = DefaultReleaseName
+	}
+	if len(releaseOptions.Namespace) <= 0 {
+		releaseOptions.Namespace = DefaultNamespace
+	}
+	vals, err := chartutil.ToRenderValuesCaps(c, config, releaseOptions, caps)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, maxRenderTime)
+	defer cancel()
+	rendered, err := newEngine(ctx).render(c, vals)
+	if err != nil {
+		return nil, err
+	}
+	result := &types.RenderResult{Manifests: []types.Manifest{}}
+	notes := path.Join(c.Metadata.GetName(), notesName)
+	for name, content := range rendered {
+		switch {
+		case name == notes:
+			result.Notes = content
+		case strings.HasSuffix(name, notesName):
+			// NOTES.txt of subcharts are ignored
+		case len(strings.TrimSpace(content)) > 0:
+			result.Manifests = append(result.Manifests, types.Manifest{Path: name, Content: content})
+		}
+	}
+	sort.Slice(result.Manifests, func(i, j int) bool {
+		return result.Manifests[i].Path < result.Manifests[j].Path
+	})
+	return result, nil
+}
+
+// capabilities creates capabilities of Kubernetes from options
+func capabilities(options *Options) (*chartutil.Capabilities, error) {
+	kubeVersion := options.KubeVersion
+	if len(kubeVersion) <= 0 {
+		kubeVersion = DefaultKubeVersion
+	}
+	v, err := semverutil.ParseVersion(kubeVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Kubernetes version %s: %v", kubeVersion, err)
+	}
+	return &chartutil.Capabilities{
+		APIVersions: chartutil.NewVersionSet(append([]string{"v1"}, options.APIVersions...)...),
+		KubeVersion: &version.Info{
+			Major:      fmt.Sprint(v.Major),
+			Minor:      fmt.Sprint(v.Minor),
+			GitVersion: "v" + v.String(),
+		},
+		TillerVersion: &tversion.Version{SemVer: TillerVersion},
+	}, nil
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package render
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/any"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+// newTestChart creates a chart with templates
+func newTestChart(name, values string, templates map[string]string, dependencies ...*chart.Chart) *chart.Chart {
+	c := &chart.Chart{
+		Metadata:     &chart.Metadata{Name: name, Version: "1.0.0"},
+		Values:       &chart.Config{Raw: values},
+		Dependencies: dependencies,
+	}
+	for name, data := range templates {
+		c.Templates = append(c.Templates, &chart.Template{Name: name, Data: []byte(data)})
+	}
+	return c
+}
+
+// manifests returns contents of manifests by paths
+func manifests(t *testing.T, c *chart.Chart, options *Options) (map[string]string, string) {
+	result, err := Render(context.Background(), c, options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	contents := make(map[string]string, len(result.Manifests))
+	for i, m := range result.Manifests {
+		if i > 0 && result.Manifests[i-1].Path >= m.Path {
+			t.Errorf("manifests are not sorted: %s, %s", result.Manifests[i-1].Path, m.Path)
+		}
+		contents[m.Path] = m.Content
+	}
+	return contents, result.Notes
+}
+
+func TestRender(t *testing.T) {
+	sub := newTestChart("db", "port: 3306\nname: db\n", map[string]string{
+		"templates/_helpers.tpl": `{{ define "name" }}sub-{{ .Chart.Name }}{{ end }}`,
+		"templates/svc.yaml":     `{{ include "name" . }}:{{ .Values.port }}:{{ .Values.global.env }}:{{ .Release.Name }}`,
+		"templates/NOTES.txt":    "sub notes",
+	})
+	sub.Files = []*any.Any{{TypeUrl: "config/db.conf", Value: []byte("max=10")}}
+	sub.Templates = append(sub.Templates, &chart.Template{Name: "templates/cm.yaml", Data: []byte(`{{ .Files.Get "config/db.conf" }}`)})
+	c := newTestChart("web", "replicas: 1\nglobal:\n  env: dev\ndb:\n  port: 3307\n", map[string]string{
+		"templates/_helpers.tpl":  `{{ define "name" }}web-{{ .Chart.Name }}{{ end }}`,
+		"templates/deploy.yaml":   `{{ include "name" . }}:{{ .Values.replicas }}:{{ .Release.Namespace }}:{{ .Values.missing }}`,
+		"templates/tpl.yaml":      `{{ tpl "{{ .Values.replicas }}-{{ .Template.Name }}" . }}`,
+		"templates/caps.yaml":     `{{ .Capabilities.KubeVersion.Minor }}:{{ .Capabilities.APIVersions.Has "apps/v1beta1" }}:{{ .Release.IsUpgrade }}`,
+		"templates/empty.yaml":    `{{ if false }}kind: Service{{ end }}`,
+		"templates/NOTES.txt":     "{{ .Release.Name }} notes",
+		"templates/required.yaml": `{{ required "name is required" .Values.db.name }}`,
+	}, sub)
+	contents, notes := manifests(t, c, &Options{
+		Name:        "r",
+		Values:      map[string]interface{}{"replicas": 3},
+		KubeVersion: "v1.8.2",
+		APIVersions: []string{"apps/v1beta1"},
+		IsUpgrade:   true,
+	})
+	expected := map[string]string{
+		// definitions in parent charts override definitions in subcharts
+		"web/templates/deploy.yaml":        "web-web:3:default:",
+		"web/templates/tpl.yaml":           "3-web/templates/tpl.yaml",
+		"web/templates/caps.yaml":          "8:true:true",
+		"web/templates/required.yaml":      "db",
+		"web/charts/db/templates/svc.yaml": "web-db:3307:dev:r",
+		"web/charts/db/templates/cm.yaml":  "max=10",
+	}
+	if len(contents) != len(expected) {
+		t.Errorf("expected %d manifests, got %v", len(expected), contents)
+	}
+	for path, content := range expected {
+		if contents[path] != content {
+			t.Errorf("%s: expected %q, got %q", path, content, contents[path])
+		}
+	}
+	if notes != "r notes" {
+		t.Errorf("expected notes of the root chart, got %q", notes)
+	}
+}
+
+func TestRenderDefaults(t *testing.T) {
+	c := newTestChart("web", "", map[string]string{
+		"templates/a.yaml": `{{ .Release.Name }}:{{ .Release.Namespace }}:{{ .Release.IsInstall }}:{{ .Capabilities.KubeVersion.GitVersion }}:{{ .Capabilities.TillerVersion.SemVer }}`,
+	})
+	contents, _ := manifests(t, c, &Options{})
+	expected := DefaultReleaseName + ":" + DefaultNamespace + ":true:" + DefaultKubeVersion + ":" + TillerVersion
+	if contents["web/templates/a.yaml"] != expected {
+		t.Fatalf("expected %q, got %q", expected, contents["web/templates/a.yaml"])
+	}
+}
+
+func TestRenderErrors(t *testing.T) {
+	cases := []struct {
+		name      string
+		templates map[string]string
+		options   *Options
+		message   string
+	}{
+		{"parse error", map[string]string{"templates/a.yaml": "{{ if }}"}, &Options{}, `parse error in "web/templates/a.yaml"`},
+		{"execution error", map[string]string{"templates/a.yaml": `{{ fail "broken" }}`}, &Options{}, "broken"},
+		{"required value", map[string]string{"templates/a.yaml": `{{ required "image is required" .Values.image }}`}, &Options{}, "image is required"},
+		{"required empty string", map[string]string{"templates/a.yaml": `{{ required "tag is required" "" }}`}, &Options{}, "tag is required"},
+		{"missing include", map[string]string{"templates/a.yaml": `{{ include "missing" . }}`}, &Options{}, `no template "missing"`},
+		{"tpl error", map[string]string{"templates/a.yaml": `{{ tpl "{{ fail \"inner\" }}" . }}`}, &Options{}, "inner"},
+		{"index out of range", map[string]string{"templates/a.yaml": `{{ index (list 1) 5 }}`}, &Options{}, "out of range"},
+		{"nil map", map[string]string{"templates/a.yaml": `{{ set .Values.missing "a" 1 }}`}, &Options{}, "a.yaml"},
+		{"recursive include", map[string]string{
+			"templates/_a.tpl": `{{ define "loop" }}{{ include "loop" . }}{{ end }}`,
+			"templates/a.yaml": `{{ include "loop" . }}`,
+		}, &Options{}, "exceeded max depth"},
+		{"recursive tpl", map[string]string{
+			"templates/a.yaml": `{{ tpl "{{ tpl .Values.loop . }}" . }}`,
+		}, &Options{Values: map[string]interface{}{"loop": "{{ tpl .Values.loop . }}"}}, "exceeded max depth"},
+		{"invalid kube version", map[string]string{}, &Options{KubeVersion: "latest"}, "invalid Kubernetes version"},
+		{"large output", map[string]string{
+			"templates/a.yaml": `{{ range until 65536 }}{{ repeat 1024 "a" }}{{ end }}`,
+		}, &Options{}, "output is larger than"},
+		{"large included output", map[string]string{
+			"templates/_a.tpl": `{{ define "a" }}{{ repeat 1024 "a" }}{{ end }}`,
+			"templates/a.yaml": `{{ range until 65536 }}{{ $a := include "a" . }}{{ end }}`,
+		}, &Options{}, "output is larger than"},
+	}
+	for _, c := range cases {
+		_, err := Render(context.Background(), newTestChart("web", "", c.templates), c.options)
+		if err == nil {
+			t.Errorf("%s: expected an error", c.name)
+			continue
+		}
+		if !strings.Contains(err.Error(), c.message) {
+			t.Errorf("%s: expected an error with %q, got %q", c.name, c.message, err.Error())
+		}
+	}
+}
+
+func TestRenderCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cases := map[string]string{
+		"function": `{{ upper "a" }}`,
+		"text":     "a",
+	}
+	for name, tpl := range cases {
+		_, err := Render(ctx, newTestChart("web", "", map[string]string{"templates/a.yaml": tpl}), &Options{})
+		if err == nil || !strings.Contains(err.Error(), "rendering is stopped") {
+			t.Errorf("%s: expected a stopped rendering, got %v", name, err)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	c := newTestChart("web", "", map[string]string{
+		"templates/ok.yaml":      `{{ .Values.a | quote }}`,
+		"templates/broken.yaml":  `{{ .Values.a | unknownFunc }}`,
+		"templates/_helpers.tpl": `{{ define "x" }}`,
+	})
+	errs := Parse(c)
+	if len(errs) != 2 || errs["templates/broken.yaml"] == nil || errs["templates/_helpers.tpl"] == nil {
+		t.Fatalf("expected errors of broken templates, got %v", errs)
+	}
+}
+
+func TestSortTemplates(t *testing.T) {
+	tpls := map[string]renderable{
+		"web/templates/a.yaml":                    {},
+		"web/templates/b.yaml":                    {},
+		"web/charts/db/templates/a.yaml":          {},
+		"web/charts/db/charts/x/templates/a.yaml": {},
+	}
+	expected := []string{
+		"web/charts/db/charts/x/templates/a.yaml",
+		"web/charts/db/templates/a.yaml",
+		"web/templates/b.yaml",
+		"web/templates/a.yaml",
+	}
+	names := sortTemplates(tpls)
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, names)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package rest
+
+import (
+	"bytes"
+	"container/list"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// DefaultCacheSize is the default max number of responses in a CacheTransport
+const DefaultCacheSize = 128
+
+// cacheEntry is a cached response
+type cacheEntry struct {
+	key    string
+	status string
+	code   int
+	header http.Header
+	body   []byte
+}
+
+// CacheTransport is a http.RoundTripper which caches responses of GET requests with
+// ETag or Last-Modified. When a request hits the cache, CacheTransport sends a conditional
+// request. If the server responds with 304, the cached response is returned.
+type CacheTransport struct {
+	// Transport is the underlying transport. http.DefaultTransport is used if it's nil
+	Transport http.RoundTripper
+	// Size is the max number of cached responses
+	Size int
+
+	lock    sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+// NewCacheTransport creates a CacheTransport with the underlying transport
+func NewCacheTransport(transport http.RoundTripper) *CacheTransport {
+	return &CacheTransport{
+		Transport: transport,
+		Size:      DefaultCacheSize,
+		entries:   make(map[string]*list.Element),
+		order:     list.New(),
+	}
+}
+
+// cacheKey returns the key of request. Responses for different users or media types are
+// cached separately.
+func cacheKey(req *http.Request) string {
+	return req.URL.String() + " " + req.Header.Get("Authorization") + " " + req.Header.Get("Accept")
+}
+
+// cacheable checks whether the request can use the cache. Requests which have their own
+// conditions are passed to the underlying transport directly.
+func cacheable(req *http.Request) bool {
+	return req.Method == http.MethodGet &&
+		len(req.Header.Get("If-None-Match")) <= 0 &&
+		len(req.Header.Get("If-Modified-Since")) <= 0 &&
+		len(req.Header.Get("Range")) <= 0
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *CacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := t.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	if !cacheable(req) {
+		return transport.RoundTrip(req)
+	}
+	key := cacheKey(req)
+	entry := t.get(key)
+	if entry != nil {
+		// send a conditional request and don't modify the original request
+		r := new(http.Request)
+		*r = *req
+		r.Header = make(http.Header, len(req.Header)+2)
+		for k, v := range req.Header {
+			r.Header[k] = v
+		}
+		if etag := entry.header.Get("ETag"); len(etag) > 0 {
+			r.Header.Set("If-None-Match", etag)
+		} else {
+			r.Header.Set("If-Modified-Since", entry.header.Get("Last-Modified"))
+		}
+		req = r
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotModified && entry != nil:
+		resp.Body.Close()
+		// headers of 304 response update cached headers
+		for k, v := range resp.Header {
+			entry.header[k] = v
+		}
+		t.put(entry)
+		return entry.response(req), nil
+	case resp.StatusCode == http.StatusOK &&
+		(len(resp.Header.Get("ETag")) > 0 || len(resp.Header.Get("Last-Modified")) > 0):
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		entry = &cacheEntry{
+			key:    key,
+			status: resp.Status,
+			code:   resp.StatusCode,
+			header: cloneHeader(resp.Header),
+			body:   body,
+		}
+		t.put(entry)
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+		return resp, nil
+	case entry != nil:
+		// the resource is changed or removed
+		t.remove(key)
+	}
+	return resp, nil
+}
+
+// response creates a response from cached entry
+func (e *cacheEntry) response(req *http.Request) *http.Response {
+	header := cloneHeader(e.header)
+	header.Set("Content-Length", strconv.Itoa(len(e.body)))
+	return &http.Response{
+		Status:        e.status,
+		StatusCode:    e.code,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       req,
+	}
+}
+
+// cloneHeader makes a copy of header
+func cloneHeader(header http.Header) http.Header {
+	result := make(http.Header, len(header))
+	for k, v := range header {
+		result[k] = append([]string(nil), v...)
+	}
+	return result
+}
+
+// get gets an entry and marks it as the most recently used entry
+func (t *CacheTransport) get(key string) *cacheEntry {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	element, ok := t.entries[key]
+	if !ok {
+		return nil
+	}
+	t.order.MoveToFront(element)
+	// returns a copy because headers may be updated by a 304 response
+	entry := *element.Value.(*cacheEntry)
+	entry.header = cloneHeader(entry.header)
+	return &entry
+}
+
+// put adds an entry and removes the least recently used entries if the cache is full
+func (t *CacheTransport) put(entry *cacheEntry) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.entries == nil {
+		t.entries = make(map[string]*list.Element)
+		t.order = list.New()
+	}
+	if element, ok := t.entries[entry.key]; ok {
+		element.Value = entry
+		t.order.MoveToFront(element)
+		return
+	}
+	t.entries[entry.key] = t.order.PushFront(entry)
+	for t.Size > 0 && t.order.Len() > t.Size {
+		oldest := t.order.Back()
+		t.order.Remove(oldest)
+		delete(t.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// remove removes an entry
+func (t *CacheTransport) remove(key string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if element, ok := t.entries[key]; ok {
+		t.order.Remove(element)
+		delete(t.entries, key)
+	}
+}
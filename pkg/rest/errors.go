@@ -22,6 +22,8 @@ var (
 	ErrorNotFound = errors.NewFormatError(http.StatusNotFound, errors.ReasonServer, "%s")
 	// ErrorConflict defines that a resource conflict
 	ErrorConflict = errors.NewFormatError(http.StatusConflict, errors.ReasonRequest, "%s")
+	// ErrorPreconditionFailed defines that a precondition such as If-Match is not satisfied
+	ErrorPreconditionFailed = errors.NewFormatError(http.StatusPreconditionFailed, errors.ReasonRequest, "%s")
 	// ErrorLocked defines that a resource is locked
 	ErrorLocked = errors.NewFormatError(http.StatusLocked, errors.ReasonLocking, "%s")
 	// ErrorServer defines server error
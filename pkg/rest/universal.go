@@ -5,13 +5,13 @@ Copyright 2017 caicloud authors. All rights reserved.
 package rest
 
 import (
-	"encoding/json"
 	"io/ioutil"
 	"net/http"
 	"strings"
 
 	"github.com/caicloud/helm-registry/pkg/errors"
 	"github.com/caicloud/helm-registry/pkg/log"
+	"github.com/ghodss/yaml"
 )
 
 // UniversalClient implements a basic registry client
@@ -58,6 +58,8 @@ func (cc *UniversalClient) Do(api API) (interface{}, error) {
 				merr = ErrorNotFound.Format("")
 			case ErrorConflict.Code:
 				merr = ErrorConflict.Format("")
+			case ErrorPreconditionFailed.Code:
+				merr = ErrorPreconditionFailed.Format("")
 			case ErrorLocked.Code:
 				merr = ErrorLocked.Format("")
 			case ErrorServer.Code:
@@ -65,7 +67,8 @@ func (cc *UniversalClient) Do(api API) (interface{}, error) {
 			default:
 				merr = &errors.Error{}
 			}
-			err = json.Unmarshal(data, merr)
+			// errors are in yaml if the request accepts yaml. yaml is a superset of json
+			err = yaml.Unmarshal(data, merr)
 			if err == nil {
 				merr.Code = resp.StatusCode
 				return nil, merr
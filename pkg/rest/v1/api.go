@@ -70,15 +70,21 @@ type baseAPI struct {
 	paths map[string]string
 	// values is the query parameters of request
 	values url.Values
+	// headers is the headers of request
+	headers http.Header
 	// files is an array to store file parameters
 	files []*file
 	// body is data of request body. If method is GET, ignore this field.
 	// If this field is not nil and method is not GET, ignore files and append values
 	// to url whatever method is.
 	body []byte
+	// contentType is the content type of body. It's ignored if body is nil.
+	contentType string
 	// result is a pointer and will be filled by json from body. If result is non-pointer,
 	// response will be []byte and ignore result. If result is nil, response do nothing.
 	result interface{}
+	// responseHeader is the header of response
+	responseHeader http.Header
 }
 
 // Method returns the http method of current api
@@ -107,6 +113,17 @@ func (ba *baseAPI) addValue(key, value string) {
 	ba.values.Add(key, value)
 }
 
+// addHeader adds header to request. Empty value is ignored
+func (ba *baseAPI) addHeader(key, value string) {
+	if len(value) <= 0 {
+		return
+	}
+	if ba.headers == nil {
+		ba.headers = http.Header{}
+	}
+	ba.headers.Add(key, value)
+}
+
 // addFile adds file to request
 func (ba *baseAPI) addFile(key, path string, data []byte) {
 	ba.files = append(ba.files, &file{
@@ -137,9 +154,10 @@ var stringType = reflect.TypeOf("")
 // A API object have some fileds, if any field meet the requirements, it can be
 // save as parameter:
 //  1. Not an anonymous field
-//  2. Have tag 'kind' and value is one of: path, query, file, body
-//  3. Have tag 'name' and field type is one of: string, int, *v1.File, []byte
-//  4. When 'kind' is path or query, field type should be string or int
+//  2. Have tag 'kind' and value is one of: path, query, header, file, body
+//  3. Have tag 'name' and field type is one of: string, int, bool, *v1.File, []byte
+//  4. When 'kind' is path, query or header, field type should be string, int or bool.
+//     Empty headers are not sent
 //  4. When 'kind' is file, field type should be *v1.File
 //  5. When 'kind' is body, field type should be string or []byte
 //  6. There is at most one body field in an API, If more than one, the last is valid
@@ -226,8 +244,11 @@ func (ba *baseAPI) generateParameters() {
 		case reflect.Int:
 			intValue := fieldValue.(int)
 			value = strconv.Itoa(intValue)
+		case reflect.Bool:
+			boolValue := fieldValue.(bool)
+			value = strconv.FormatBool(boolValue)
 		default:
-			log.Fatalf("unknown api kind type shoule be string or int, but got %s", field.Type.Kind())
+			log.Fatalf("unknown api kind type shoule be string, int or bool, but got %s", field.Type.Kind())
 		}
 		switch kind {
 		case "path":
@@ -236,6 +257,9 @@ func (ba *baseAPI) generateParameters() {
 		case "query":
 			// handle query field
 			ba.addValue(name, value)
+		case "header":
+			// handle header field
+			ba.addHeader(name, value)
 		default:
 			log.Fatalf("unknown api kind type: %s", kind)
 		}
@@ -249,7 +273,7 @@ func (ba *baseAPI) request(endpoint string) (*http.Request, error) {
 	path := URL(ba.Path()).Format(ba.paths)
 	contentType := ""
 	var body io.Reader
-	if ba.Method() == http.MethodGet || body != nil {
+	if ba.Method() == http.MethodGet || ba.body != nil {
 		// append values to url
 		if len(ba.values) > 0 {
 			path += "?" + ba.values.Encode()
@@ -258,6 +282,7 @@ func (ba *baseAPI) request(endpoint string) (*http.Request, error) {
 	if ba.body != nil {
 		// use ba.body as request body
 		// ignore ba.files
+		contentType = ba.contentType
 		body = bytes.NewBuffer(ba.body)
 	} else {
 		if len(ba.files) <= 0 {
@@ -301,12 +326,17 @@ func (ba *baseAPI) request(endpoint string) (*http.Request, error) {
 	}
 	// generate http request
 	req, err := http.NewRequest(ba.Method(), endpoint+path, body)
-	if len(contentType) > 0 {
-		req.Header.Set("Content-Type", contentType)
-	}
 	if err != nil {
 		return nil, rest.ErrorUnknownLocalError.Format(err.Error())
 	}
+	for key, values := range ba.headers {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	if len(contentType) > 0 {
+		req.Header.Set("Content-Type", contentType)
+	}
 	return req, nil
 }
 
@@ -340,8 +370,14 @@ func (ba *baseAPI) unmarshal(resp *http.Response, obj interface{}) error {
 	return nil
 }
 
+// ETag returns the entity tag of response. It's empty if the response doesn't have it
+func (ba *baseAPI) ETag() string {
+	return ba.responseHeader.Get("ETag")
+}
+
 // Response handles *http.Response and return result
 func (ba *baseAPI) Response(resp *http.Response) (interface{}, error) {
+	ba.responseHeader = resp.Header
 	if ba.result != nil {
 		value := reflect.ValueOf(ba.result)
 		if value.Kind() != reflect.Ptr {
@@ -8,6 +8,7 @@ import (
 	"net/http"
 
 	"github.com/caicloud/helm-registry/pkg/api/models"
+	"github.com/caicloud/helm-registry/pkg/api/v1/types"
 )
 
 // APIListCharts defines an api of listing charts
@@ -19,6 +20,8 @@ type APIListCharts struct {
 	Start int `kind:"query" name:"start"`
 	// Limit is the max length of list
 	Limit int `kind:"query" name:"limit"`
+	// Continue is the continuation token of the previous page
+	Continue string `kind:"query" name:"continue"`
 }
 
 // NewAPIListCharts creates an instance of APIListCharts
@@ -39,6 +42,40 @@ func (api *APIListCharts) Convert(result interface{}, err error) (*StringCollect
 	return result.(*StringCollectionResult), nil
 }
 
+// APIListChartSummaries defines an api of listing summaries of charts
+type APIListChartSummaries struct {
+	baseAPI
+	// Space is the name of space
+	Space string `kind:"path" name:"space"`
+	// Start is the start index of list
+	Start int `kind:"query" name:"start"`
+	// Limit is the max length of list
+	Limit int `kind:"query" name:"limit"`
+	// Continue is the continuation token of the previous page
+	Continue string `kind:"query" name:"continue"`
+	// Detail must be true to get summaries
+	Detail bool `kind:"query" name:"detail"`
+}
+
+// NewAPIListChartSummaries creates an instance of APIListChartSummaries
+func NewAPIListChartSummaries() *APIListChartSummaries {
+	api := &APIListChartSummaries{}
+	api.object = api
+	api.method = http.MethodGet
+	api.url = URLCharts
+	api.result = &ChartSummaryCollectionResult{}
+	api.Detail = true
+	return api
+}
+
+// Convert converts result to *ChartSummaryCollectionResult
+func (api *APIListChartSummaries) Convert(result interface{}, err error) (*ChartSummaryCollectionResult, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.(*ChartSummaryCollectionResult), nil
+}
+
 // APICreateChart defines an api of creating chart
 type APICreateChart struct {
 	baseAPI
@@ -46,6 +83,8 @@ type APICreateChart struct {
 	Space string `kind:"path" name:"space"`
 	// Config is a json string of orchestration config
 	Config string `kind:"body"`
+	// Uploader is the uploader of versions. It's ignored if empty
+	Uploader string `kind:"header" name:"X-Registry-Uploader"`
 }
 
 // APICreateChart creates an instance of APICreateChart
@@ -66,6 +105,63 @@ func (api *APICreateChart) Convert(result interface{}, err error) (*models.Chart
 	return result.(*models.ChartLink), nil
 }
 
+// APIPreviewChart defines an api of previewing a chart orchestrated by config
+type APIPreviewChart struct {
+	baseAPI
+	// Space is the name of space
+	Space string `kind:"path" name:"space"`
+	// Config is a json string of orchestration config
+	Config string `kind:"body"`
+}
+
+// NewAPIPreviewChart creates an instance of APIPreviewChart
+func NewAPIPreviewChart() *APIPreviewChart {
+	api := &APIPreviewChart{}
+	api.object = api
+	api.method = http.MethodPost
+	api.url = URLPreview
+	api.result = &models.OrchestrationPreview{}
+	return api
+}
+
+// Convert converts result to *models.OrchestrationPreview
+func (api *APIPreviewChart) Convert(result interface{}, err error) (*models.OrchestrationPreview, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.(*models.OrchestrationPreview), nil
+}
+
+// APIPreviewChartArchive defines an api of downloading a chart package orchestrated by config
+type APIPreviewChartArchive struct {
+	baseAPI
+	// Space is the name of space
+	Space string `kind:"path" name:"space"`
+	// Format is the format of response
+	Format string `kind:"query" name:"format"`
+	// Config is a json string of orchestration config
+	Config string `kind:"body"`
+}
+
+// NewAPIPreviewChartArchive creates an instance of APIPreviewChartArchive
+func NewAPIPreviewChartArchive() *APIPreviewChartArchive {
+	api := &APIPreviewChartArchive{}
+	api.object = api
+	api.method = http.MethodPost
+	api.url = URLPreview
+	api.Format = "archive"
+	api.result = []byte{}
+	return api
+}
+
+// Convert converts result to []byte
+func (api *APIPreviewChartArchive) Convert(result interface{}, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.([]byte), nil
+}
+
 // APIUploadChart defines an api of uploading version
 type APIUploadChart struct {
 	baseAPI
@@ -73,6 +169,8 @@ type APIUploadChart struct {
 	Space string `kind:"path" name:"space"`
 	// ChartFile is a chart file
 	ChartFile *File `kind:"file" name:"chartfile"`
+	// Uploader is the uploader of versions. It's ignored if empty
+	Uploader string `kind:"header" name:"X-Registry-Uploader"`
 }
 
 // NewAPIUploadChart creates an instance of APIUploadChart
@@ -101,6 +199,8 @@ type APIDeleteChart struct {
 	Space string `kind:"path" name:"space"`
 	// Chart is the name of Chart
 	Chart string `kind:"path" name:"chart"`
+	// Force decides whether to delete even if versions are referenced by orchestrated versions or requirements
+	Force bool `kind:"query" name:"force"`
 }
 
 // APICreateChart creates an instance of APICreateChart
@@ -116,3 +216,163 @@ func NewAPIDeleteChart() *APIDeleteChart {
 func (api *APIDeleteChart) Convert(result interface{}, err error) error {
 	return err
 }
+
+// APIPromoteChart defines an api of promoting chart
+type APIPromoteChart struct {
+	baseAPI
+	// Space is the name of space
+	Space string `kind:"path" name:"space"`
+	// Chart is the name of chart
+	Chart string `kind:"path" name:"chart"`
+	// Target is the name of target space
+	Target string `kind:"query" name:"target"`
+	// Versions is comma-separated version numbers. If it's empty, all versions are promoted
+	Versions string `kind:"query" name:"versions"`
+	// History indicates whether to record promotions in the history of target chart
+	History bool `kind:"query" name:"history"`
+	// Uploader is the uploader of versions. It's ignored if empty
+	Uploader string `kind:"header" name:"X-Registry-Uploader"`
+}
+
+// NewAPIPromoteChart creates an instance of APIPromoteChart
+func NewAPIPromoteChart() *APIPromoteChart {
+	api := &APIPromoteChart{}
+	api.object = api
+	api.method = http.MethodPost
+	api.url = URLChartPromotion
+	api.result = &[]*models.ChartLink{}
+	return api
+}
+
+// Convert converts result to []*models.ChartLink
+func (api *APIPromoteChart) Convert(result interface{}, err error) ([]*models.ChartLink, error) {
+	if err != nil {
+		return nil, err
+	}
+	return *result.(*[]*models.ChartLink), nil
+}
+
+// APIFetchChartHistory defines an api of fetching chart history
+type APIFetchChartHistory struct {
+	baseAPI
+	// Space is the name of space
+	Space string `kind:"path" name:"space"`
+	// Chart is the name of chart
+	Chart string `kind:"path" name:"chart"`
+	// Start is the start index of list
+	Start int `kind:"query" name:"start"`
+	// Limit is the max length of list
+	Limit int `kind:"query" name:"limit"`
+	// Continue is the continuation token of the previous page
+	Continue string `kind:"query" name:"continue"`
+}
+
+// NewAPIFetchChartHistory creates an instance of APIFetchChartHistory
+func NewAPIFetchChartHistory() *APIFetchChartHistory {
+	api := &APIFetchChartHistory{}
+	api.object = api
+	api.method = http.MethodGet
+	api.url = URLChartHistory
+	api.result = &EventCollectionResult{}
+	return api
+}
+
+// Convert converts result to *EventCollectionResult
+func (api *APIFetchChartHistory) Convert(result interface{}, err error) (*EventCollectionResult, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.(*EventCollectionResult), nil
+}
+
+// APILintChart defines an api of linting a chart file
+type APILintChart struct {
+	baseAPI
+	// Space is the name of space
+	Space string `kind:"path" name:"space"`
+	// ChartFile is a chart file
+	ChartFile *File `kind:"file" name:"chartfile"`
+}
+
+// NewAPILintChart creates an instance of APILintChart
+func NewAPILintChart() *APILintChart {
+	api := &APILintChart{}
+	api.object = api
+	api.method = http.MethodPost
+	api.url = URLLint
+	api.result = &models.LintResult{}
+	api.ChartFile = &File{}
+	return api
+}
+
+// Convert converts result to *models.LintResult
+func (api *APILintChart) Convert(result interface{}, err error) (*models.LintResult, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.(*models.LintResult), nil
+}
+
+// APIDiffVersions defines an api of comparing two versions of chart
+type APIDiffVersions struct {
+	baseAPI
+	// Space is the name of space
+	Space string `kind:"path" name:"space"`
+	// Chart is the name of chart
+	Chart string `kind:"path" name:"chart"`
+	// From is the old version number or tag
+	From string `kind:"query" name:"from"`
+	// To is the new version number or tag
+	To string `kind:"query" name:"to"`
+	// ToSpace is the space of the new version. It's the same as Space if empty
+	ToSpace string `kind:"query" name:"toSpace"`
+}
+
+// NewAPIDiffVersions creates an instance of APIDiffVersions
+func NewAPIDiffVersions() *APIDiffVersions {
+	api := &APIDiffVersions{}
+	api.object = api
+	api.method = http.MethodGet
+	api.url = URLChartDiff
+	api.result = &types.ChartDiff{}
+	return api
+}
+
+// Convert converts result to *types.ChartDiff
+func (api *APIDiffVersions) Convert(result interface{}, err error) (*types.ChartDiff, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.(*types.ChartDiff), nil
+}
+
+// APIMoveChart defines an api of moving chart
+type APIMoveChart struct {
+	baseAPI
+	// Space is the name of space
+	Space string `kind:"path" name:"space"`
+	// Chart is the name of chart
+	Chart string `kind:"path" name:"chart"`
+	// Target is the name of target space
+	Target string `kind:"query" name:"target"`
+	// Force decides whether to move even if versions are referenced by orchestrated versions
+	Force bool `kind:"query" name:"force"`
+}
+
+// NewAPIMoveChart creates an instance of APIMoveChart
+func NewAPIMoveChart() *APIMoveChart {
+	api := &APIMoveChart{}
+	api.object = api
+	api.method = http.MethodPost
+	api.url = URLChartMove
+	api.result = &models.Link{}
+	return api
+}
+
+// Convert converts result to *models.Link
+func (api *APIMoveChart) Convert(result interface{}, err error) (*models.Link, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.(*models.Link), nil
+}
@@ -7,9 +7,12 @@ package v1
 import (
 	"encoding/json"
 	"net/http"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/caicloud/helm-registry/pkg/api/models"
+	"github.com/caicloud/helm-registry/pkg/api/v1/types"
 	"github.com/caicloud/helm-registry/pkg/rest"
 	"github.com/caicloud/helm-registry/pkg/storage"
 	"k8s.io/helm/pkg/proto/hapi/chart"
@@ -20,15 +23,16 @@ type Client struct {
 	rest.Client
 }
 
-// NewClient creates a new registry client. endpoint is the address of server
+// NewClient creates a new registry client. endpoint is the address of server. Charts,
+// metadata and values are cached, and the client sends conditional requests for them.
 func NewClient(endpoint string) (*Client, error) {
-	client := &Client{rest.NewUniversalClient(strings.TrimRight(endpoint, "\\/"))}
-	return client, nil
+	return NewTransportClient(endpoint, nil)
 }
 
-// NewTransportClient creates a new registry client. endpoint is the address of server with transport
+// NewTransportClient creates a new registry client. endpoint is the address of server with transport.
+// Responses are cached on top of the transport.
 func NewTransportClient(endpoint string, transport http.RoundTripper) (*Client, error) {
-	client := &Client{rest.NewUniversalTransportClient(strings.TrimRight(endpoint, "\\/"), transport)}
+	client := &Client{rest.NewUniversalTransportClient(strings.TrimRight(endpoint, "\\/"), rest.NewCacheTransport(transport))}
 	return client, nil
 }
 
@@ -40,6 +44,14 @@ func (c *Client) ListSpaces(start, limit int) (*StringCollectionResult, error) {
 	return api.Convert(c.Do(api))
 }
 
+// ListSpaceSummaries lists summaries of spaces
+func (c *Client) ListSpaceSummaries(start, limit int) (*SpaceSummaryCollectionResult, error) {
+	api := NewAPIListSpaceSummaries()
+	api.Start = start
+	api.Limit = limit
+	return api.Convert(c.Do(api))
+}
+
 // CreateSpace creates a space by space name
 func (c *Client) CreateSpace(spaceName string) (*models.Link, error) {
 	api := NewAPICreateSpace()
@@ -47,6 +59,60 @@ func (c *Client) CreateSpace(spaceName string) (*models.Link, error) {
 	return api.Convert(c.Do(api))
 }
 
+// ListSpacesByLabels lists spaces which have all specified labels. A label with empty
+// value matches any value of the same key.
+func (c *Client) ListSpacesByLabels(labels map[string]string, start, limit int) (*StringCollectionResult, error) {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	selector := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if len(labels[key]) > 0 {
+			selector = append(selector, key+"="+labels[key])
+		} else {
+			selector = append(selector, key)
+		}
+	}
+	api := NewAPIListSpaces()
+	api.Start = start
+	api.Limit = limit
+	api.Label = strings.Join(selector, ",")
+	return api.Convert(c.Do(api))
+}
+
+// CreateSpaceWithProperties creates a space with properties
+func (c *Client) CreateSpaceWithProperties(spaceName string, properties *storage.SpaceProperties) (*models.Link, error) {
+	data, err := json.Marshal(properties)
+	if err != nil {
+		return nil, rest.ErrorUnknownLocalError.Format(err.Error())
+	}
+	api := NewAPICreateSpace()
+	api.Space = spaceName
+	api.Properties = data
+	return api.Convert(c.Do(api))
+}
+
+// FetchSpace fetches a space and its properties
+func (c *Client) FetchSpace(spaceName string) (*models.Space, error) {
+	api := NewAPIFetchSpace()
+	api.Space = spaceName
+	return api.Convert(c.Do(api))
+}
+
+// UpdateSpace replaces properties of a space
+func (c *Client) UpdateSpace(spaceName string, properties *storage.SpaceProperties) (*models.Space, error) {
+	data, err := json.Marshal(properties)
+	if err != nil {
+		return nil, rest.ErrorUnknownLocalError.Format(err.Error())
+	}
+	api := NewAPIUpdateSpace()
+	api.Space = spaceName
+	api.Properties = data
+	return api.Convert(c.Do(api))
+}
+
 // DeleteSpace deletes a space by space name
 func (c *Client) DeleteSpace(spaceName string) error {
 	api := NewAPIDeleteSpace()
@@ -54,6 +120,14 @@ func (c *Client) DeleteSpace(spaceName string) error {
 	return api.Convert(c.Do(api))
 }
 
+// RenameSpace renames a space. The space with the new name must not exist.
+func (c *Client) RenameSpace(spaceName string, newName string) (*models.Link, error) {
+	api := NewAPIRenameSpace()
+	api.Space = spaceName
+	api.Name = newName
+	return api.Convert(c.Do(api))
+}
+
 // ListCharts lists charts in the space
 func (c *Client) ListCharts(spaceName string, start, limit int) (*StringCollectionResult, error) {
 	api := NewAPIListCharts()
@@ -63,6 +137,15 @@ func (c *Client) ListCharts(spaceName string, start, limit int) (*StringCollecti
 	return api.Convert(c.Do(api))
 }
 
+// ListChartSummaries lists summaries of charts in the space
+func (c *Client) ListChartSummaries(spaceName string, start, limit int) (*ChartSummaryCollectionResult, error) {
+	api := NewAPIListChartSummaries()
+	api.Space = spaceName
+	api.Start = start
+	api.Limit = limit
+	return api.Convert(c.Do(api))
+}
+
 // CreateChart creates a chart by config. config is a json string to specify the hierarchical structure of chart.
 // Please refer to the descriptor of creating chart.
 func (c *Client) CreateChart(spaceName string, config string) (*models.ChartLink, error) {
@@ -72,6 +155,24 @@ func (c *Client) CreateChart(spaceName string, config string) (*models.ChartLink
 	return api.Convert(c.Do(api))
 }
 
+// PreviewChart orchestrates a chart by config without storing it. Problems of config are
+// reported in the preview. Please refer to the descriptor of previewing chart.
+func (c *Client) PreviewChart(spaceName string, config string) (*models.OrchestrationPreview, error) {
+	api := NewAPIPreviewChart()
+	api.Space = spaceName
+	api.Config = config
+	return api.Convert(c.Do(api))
+}
+
+// PreviewChartArchive orchestrates a chart by config and returns the chart package without
+// storing it. It produces an error if config has problems.
+func (c *Client) PreviewChartArchive(spaceName string, config string) ([]byte, error) {
+	api := NewAPIPreviewChartArchive()
+	api.Space = spaceName
+	api.Config = config
+	return api.Convert(c.Do(api))
+}
+
 // UploadChart uploads a chart file. If the chart exists, it produces an error.
 func (c *Client) UploadChart(spaceName string, data []byte) (*models.ChartLink, error) {
 	api := NewAPIUploadChart()
@@ -80,6 +181,14 @@ func (c *Client) UploadChart(spaceName string, data []byte) (*models.ChartLink,
 	return api.Convert(c.Do(api))
 }
 
+// LintChart lints a chart file by the lint mode of space without storing it
+func (c *Client) LintChart(spaceName string, data []byte) (*models.LintResult, error) {
+	api := NewAPILintChart()
+	api.Space = spaceName
+	api.ChartFile.Data = data
+	return api.Convert(c.Do(api))
+}
+
 // DeleteChart deletes a chart and its all versions
 func (c *Client) DeleteChart(spaceName string, chartName string) error {
 	api := NewAPIDeleteChart()
@@ -88,6 +197,50 @@ func (c *Client) DeleteChart(spaceName string, chartName string) error {
 	return api.Convert(c.Do(api))
 }
 
+// MoveChart moves a chart and its all versions to the target space. The chart must not exist
+// in the target space.
+func (c *Client) MoveChart(spaceName string, chartName string, target string) (*models.Link, error) {
+	api := NewAPIMoveChart()
+	api.Space = spaceName
+	api.Chart = chartName
+	api.Target = target
+	return api.Convert(c.Do(api))
+}
+
+// DiffVersions compares two versions of chart. If toSpace is empty, the new version is in the
+// same space as the old one.
+func (c *Client) DiffVersions(spaceName string, chartName string, from, toSpace, to string) (*types.ChartDiff, error) {
+	api := NewAPIDiffVersions()
+	api.Space = spaceName
+	api.Chart = chartName
+	api.From = from
+	api.To = to
+	api.ToSpace = toSpace
+	return api.Convert(c.Do(api))
+}
+
+// PromoteChart copies versions of the chart to the target space. If versions is empty, all
+// versions are promoted. If history is true, promotions are recorded in the history of target chart.
+func (c *Client) PromoteChart(spaceName string, chartName string, target string, versions []string, history bool) ([]*models.ChartLink, error) {
+	api := NewAPIPromoteChart()
+	api.Space = spaceName
+	api.Chart = chartName
+	api.Target = target
+	api.Versions = strings.Join(versions, ",")
+	api.History = history
+	return api.Convert(c.Do(api))
+}
+
+// FetchChartHistory fetches events in the history of chart
+func (c *Client) FetchChartHistory(spaceName string, chartName string, start, limit int) (*EventCollectionResult, error) {
+	api := NewAPIFetchChartHistory()
+	api.Space = spaceName
+	api.Chart = chartName
+	api.Start = start
+	api.Limit = limit
+	return api.Convert(c.Do(api))
+}
+
 // ListVersions lists versions of the chart
 func (c *Client) ListVersions(spaceName string, chartName string, start, limit int) (*StringCollectionResult, error) {
 	api := NewAPIListVersions()
@@ -98,6 +251,16 @@ func (c *Client) ListVersions(spaceName string, chartName string, start, limit i
 	return api.Convert(c.Do(api))
 }
 
+// ListVersionSummaries lists summaries of versions in the chart
+func (c *Client) ListVersionSummaries(spaceName string, chartName string, start, limit int) (*VersionSummaryCollectionResult, error) {
+	api := NewAPIListVersionSummaries()
+	api.Space = spaceName
+	api.Chart = chartName
+	api.Start = start
+	api.Limit = limit
+	return api.Convert(c.Do(api))
+}
+
 // DownloadVersion downloads a chart file
 func (c *Client) DownloadVersion(spaceName string, chartName string, versionNumber string) ([]byte, error) {
 	api := NewAPIDownloadVersion()
@@ -117,6 +280,18 @@ func (c *Client) UpdateVersion(spaceName string, chartName string, versionNumber
 	return api.Convert(c.Do(api))
 }
 
+// PromoteVersion copies a version of chart to the target space. If history is true, the
+// promotion is recorded in the history of target chart.
+func (c *Client) PromoteVersion(spaceName string, chartName string, versionNumber string, target string, history bool) (*models.ChartLink, error) {
+	api := NewAPIPromoteVersion()
+	api.Space = spaceName
+	api.Chart = chartName
+	api.Version = versionNumber
+	api.Target = target
+	api.History = history
+	return api.Convert(c.Do(api))
+}
+
 // DeleteChart deletes a version of chart
 func (c *Client) DeleteVersion(spaceName string, chartName string, versionNumber string) error {
 	api := NewAPIDeleteVersion()
@@ -126,6 +301,211 @@ func (c *Client) DeleteVersion(spaceName string, chartName string, versionNumber
 	return api.Convert(c.Do(api))
 }
 
+// FetchVersionState fetches the state of version
+func (c *Client) FetchVersionState(spaceName string, chartName string, versionNumber string) (*storage.VersionState, error) {
+	api := NewAPIFetchVersionState()
+	api.Space = spaceName
+	api.Chart = chartName
+	api.Version = versionNumber
+	return api.Convert(c.Do(api))
+}
+
+// SetVersionState sets the state of version. state should be one of active, deprecated and yanked
+func (c *Client) SetVersionState(spaceName string, chartName string, versionNumber string, state storage.State, reason string) (*storage.VersionState, error) {
+	api := NewAPISetVersionState()
+	api.Space = spaceName
+	api.Chart = chartName
+	api.Version = versionNumber
+	api.State = string(state)
+	api.Reason = reason
+	return api.Convert(c.Do(api))
+}
+
+// FetchVersionRecord fetches the record of version
+func (c *Client) FetchVersionRecord(spaceName string, chartName string, versionNumber string) (*storage.Record, error) {
+	api := NewAPIFetchVersionRecord()
+	api.Space = spaceName
+	api.Chart = chartName
+	api.Version = versionNumber
+	return api.Convert(c.Do(api))
+}
+
+// FetchOrchestrationConfig fetches the orchestration config which created version
+func (c *Client) FetchOrchestrationConfig(spaceName string, chartName string, versionNumber string) (*types.OrchestrationConfig, error) {
+	api := NewAPIFetchOrchestrationConfig()
+	api.Space = spaceName
+	api.Chart = chartName
+	api.Version = versionNumber
+	return api.Convert(c.Do(api))
+}
+
+// RebuildVersion creates a new version by the orchestration config of version. config is a
+// json string of rebuild config. Please refer to the descriptor of rebuilding version.
+func (c *Client) RebuildVersion(spaceName string, chartName string, versionNumber string, config string) (*models.ChartLink, error) {
+	api := NewAPIRebuildVersion()
+	api.Space = spaceName
+	api.Chart = chartName
+	api.Version = versionNumber
+	api.Config = config
+	return api.Convert(c.Do(api))
+}
+
+// ListDependents lists orchestrated versions which were built from the chart. If versionNumber
+// is not empty, only dependents of the version are listed.
+func (c *Client) ListDependents(spaceName string, chartName string, versionNumber string, start, limit int) (*DependentCollectionResult, error) {
+	api := NewAPIListDependents()
+	api.Space = spaceName
+	api.Chart = chartName
+	api.Version = versionNumber
+	api.Start = start
+	api.Limit = limit
+	return api.Convert(c.Do(api))
+}
+
+// ListOutdatedVersions lists orchestrated versions in the space whose packages have newer
+// versions. Only the latest orchestrated version of each chart is checked.
+func (c *Client) ListOutdatedVersions(spaceName string, start, limit int) (*OutdatedVersionCollectionResult, error) {
+	api := NewAPIListOutdatedVersions()
+	api.Space = spaceName
+	api.Start = start
+	api.Limit = limit
+	return api.Convert(c.Do(api))
+}
+
+// ListRevisions lists revisions of version in ascending order
+func (c *Client) ListRevisions(spaceName string, chartName string, versionNumber string, start, limit int) (*RevisionCollectionResult, error) {
+	api := NewAPIListRevisions()
+	api.Space = spaceName
+	api.Chart = chartName
+	api.Version = versionNumber
+	api.Start = start
+	api.Limit = limit
+	return api.Convert(c.Do(api))
+}
+
+// DownloadRevision downloads the chart package of a revision
+func (c *Client) DownloadRevision(spaceName string, chartName string, versionNumber string, revision int64) ([]byte, error) {
+	api := NewAPIDownloadRevision()
+	api.Space = spaceName
+	api.Chart = chartName
+	api.Version = versionNumber
+	api.Revision = int(revision)
+	return api.Convert(c.Do(api))
+}
+
+// DiffRevisions compares revision from with revision to. If to is less than 0, from is
+// compared with the current revision.
+func (c *Client) DiffRevisions(spaceName string, chartName string, versionNumber string, from, to int64) (*types.ChartDiff, error) {
+	api := NewAPIDiffRevisions()
+	api.Space = spaceName
+	api.Chart = chartName
+	api.Version = versionNumber
+	api.Revision = int(from)
+	if to >= 0 {
+		api.To = strconv.FormatInt(to, 10)
+	}
+	return api.Convert(c.Do(api))
+}
+
+// RollbackRevision rolls version back to a revision. It returns the new revision
+func (c *Client) RollbackRevision(spaceName string, chartName string, versionNumber string, revision int64) (*storage.Revision, error) {
+	api := NewAPIRollbackRevision()
+	api.Space = spaceName
+	api.Chart = chartName
+	api.Version = versionNumber
+	api.Revision = int(revision)
+	return api.Convert(c.Do(api))
+}
+
+// ListFiles lists files in the chart package of version
+func (c *Client) ListFiles(spaceName string, chartName string, versionNumber string, start, limit int) (*FileCollectionResult, error) {
+	api := NewAPIListFiles()
+	api.Space = spaceName
+	api.Chart = chartName
+	api.Version = versionNumber
+	api.Start = start
+	api.Limit = limit
+	return api.Convert(c.Do(api))
+}
+
+// FetchFile fetches raw content of a file in the chart package of version
+func (c *Client) FetchFile(spaceName string, chartName string, versionNumber string, path string) ([]byte, error) {
+	api := NewAPIFetchFile()
+	api.Space = spaceName
+	api.Chart = chartName
+	api.Version = versionNumber
+	api.File = path
+	return api.Convert(c.Do(api))
+}
+
+// FetchVersionReadme fetches README.md of version. If html is true, it fetches html rendered
+// from the markdown.
+func (c *Client) FetchVersionReadme(spaceName string, chartName string, versionNumber string, html bool) ([]byte, error) {
+	api := NewAPIFetchVersionReadme()
+	api.Space = spaceName
+	api.Chart = chartName
+	api.Version = versionNumber
+	api.SetHTML(html)
+	return api.Convert(c.Do(api))
+}
+
+// FetchVersionNotes fetches templates/NOTES.txt of version
+func (c *Client) FetchVersionNotes(spaceName string, chartName string, versionNumber string) ([]byte, error) {
+	api := NewAPIFetchVersionNotes()
+	api.Space = spaceName
+	api.Chart = chartName
+	api.Version = versionNumber
+	return api.Convert(c.Do(api))
+}
+
+// FetchVersionIcon fetches the icon in the chart package of version
+func (c *Client) FetchVersionIcon(spaceName string, chartName string, versionNumber string) ([]byte, error) {
+	api := NewAPIFetchVersionIcon()
+	api.Space = spaceName
+	api.Chart = chartName
+	api.Version = versionNumber
+	return api.Convert(c.Do(api))
+}
+
+// FetchVersionSchema fetches the json schema of values of version
+func (c *Client) FetchVersionSchema(spaceName string, chartName string, versionNumber string) ([]byte, error) {
+	api := NewAPIFetchVersionSchema()
+	api.Space = spaceName
+	api.Chart = chartName
+	api.Version = versionNumber
+	return api.Convert(c.Do(api))
+}
+
+// InferVersionSchema infers a json schema from values of version
+func (c *Client) InferVersionSchema(spaceName string, chartName string, versionNumber string) ([]byte, error) {
+	api := NewAPIInferVersionSchema()
+	api.Space = spaceName
+	api.Chart = chartName
+	api.Version = versionNumber
+	return api.Convert(c.Do(api))
+}
+
+// RenderVersion renders templates of version. config is a json string of render config and
+// it can be empty. Please refer to the descriptor of rendering version.
+func (c *Client) RenderVersion(spaceName string, chartName string, versionNumber string, config string) (*types.RenderResult, error) {
+	api := NewAPIRenderVersion()
+	api.Space = spaceName
+	api.Chart = chartName
+	api.Version = versionNumber
+	api.Config = config
+	return api.Convert(c.Do(api))
+}
+
+// RenderVersionYAML renders templates of version into a multi-document yaml
+func (c *Client) RenderVersionYAML(spaceName string, chartName string, versionNumber string, config string) ([]byte, error) {
+	api := NewAPIRenderVersionYAML()
+	api.Space = spaceName
+	api.Chart = chartName
+	api.Version = versionNumber
+	api.Config = config
+	return api.Convert(c.Do(api))
+}
+
 // FetchChartMetadata fetches all metadata of chart
 func (c *Client) FetchChartMetadata(spaceName string, chartName string, start, limit int) (*MetadataCollectionResult, error) {
 	api := NewAPIFetchChartMetadata()
@@ -159,6 +539,33 @@ func (c *Client) UpdateVersionMetadata(spaceName string, chartName string, versi
 	return api.Convert(c.Do(api))
 }
 
+// FetchVersionMetadataWithETag fetches metadata of version and its entity tag
+func (c *Client) FetchVersionMetadataWithETag(spaceName string, chartName string, versionNumber string) (*storage.Metadata, string, error) {
+	api := NewAPIFetchVersionMetadata()
+	api.Space = spaceName
+	api.Chart = chartName
+	api.Version = versionNumber
+	metadata, err := api.Convert(c.Do(api))
+	return metadata, api.ETag(), err
+}
+
+// UpdateVersionMetadataIfMatch updates metadata of version if etag matches the entity tag of
+// current metadata. It returns new metadata and its entity tag.
+func (c *Client) UpdateVersionMetadataIfMatch(spaceName string, chartName string, versionNumber string, metadata *chart.Metadata, etag string) (*storage.Metadata, string, error) {
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, "", rest.ErrorUnknownLocalError.Format(err.Error())
+	}
+	api := NewAPIUpdateVersionMetadata()
+	api.Space = spaceName
+	api.Chart = chartName
+	api.Version = versionNumber
+	api.Metadata = data
+	api.IfMatch = etag
+	md, err := api.Convert(c.Do(api))
+	return md, api.ETag(), err
+}
+
 // FetchVersionValues fetches values of version
 func (c *Client) FetchVersionValues(spaceName string, chartName string, versionNumber string) ([]byte, error) {
 	api := NewAPIFetchVersionValues()
@@ -177,3 +584,120 @@ func (c *Client) UpdateVersionValues(spaceName string, chartName string, version
 	api.Values = values
 	return api.Convert(c.Do(api))
 }
+
+// FetchVersionValuesWithETag fetches values of version and its entity tag
+func (c *Client) FetchVersionValuesWithETag(spaceName string, chartName string, versionNumber string) ([]byte, string, error) {
+	api := NewAPIFetchVersionValues()
+	api.Space = spaceName
+	api.Chart = chartName
+	api.Version = versionNumber
+	values, err := api.Convert(c.Do(api))
+	return values, api.ETag(), err
+}
+
+// UpdateVersionValuesIfMatch updates values of version if etag matches the entity tag of
+// current values. It returns the values and the entity tag of new values.
+func (c *Client) UpdateVersionValuesIfMatch(spaceName string, chartName string, versionNumber string, values []byte, etag string) ([]byte, string, error) {
+	api := NewAPIUpdateVersionValues()
+	api.Space = spaceName
+	api.Chart = chartName
+	api.Version = versionNumber
+	api.Values = values
+	api.IfMatch = etag
+	result, err := api.Convert(c.Do(api))
+	return result, api.ETag(), err
+}
+
+// FetchVersionValuesYAML fetches the original values.yaml of version and its entity tag
+func (c *Client) FetchVersionValuesYAML(spaceName string, chartName string, versionNumber string) ([]byte, string, error) {
+	api := NewAPIFetchVersionValuesYAML()
+	api.Space = spaceName
+	api.Chart = chartName
+	api.Version = versionNumber
+	values, err := api.Convert(c.Do(api))
+	return values, api.ETag(), err
+}
+
+// UpdateVersionValuesYAML updates values.yaml of version. Comments and the order of keys are
+// kept. If etag is not empty, values are updated only if it matches the entity tag of current
+// values. It returns the values and the entity tag of new values.
+func (c *Client) UpdateVersionValuesYAML(spaceName string, chartName string, versionNumber string, values []byte, etag string) ([]byte, string, error) {
+	api := NewAPIUpdateVersionValuesYAML()
+	api.Space = spaceName
+	api.Chart = chartName
+	api.Version = versionNumber
+	api.Values = values
+	api.IfMatch = etag
+	result, err := api.Convert(c.Do(api))
+	return result, api.ETag(), err
+}
+
+// PatchVersionMetadata applies a patch to metadata of version. patchType should be
+// patch.MergePatchType or patch.JSONPatchType. If etag is not empty, the patch is applied
+// only if it matches the entity tag of current metadata. It returns the patched metadata
+// and its entity tag.
+func (c *Client) PatchVersionMetadata(spaceName string, chartName string, versionNumber string, patchType string, patch []byte, etag string) (*storage.Metadata, string, error) {
+	api := NewAPIPatchVersionMetadata()
+	api.Space = spaceName
+	api.Chart = chartName
+	api.Version = versionNumber
+	api.SetPatchType(patchType)
+	api.Patch = patch
+	api.IfMatch = etag
+	md, err := api.Convert(c.Do(api))
+	return md, api.ETag(), err
+}
+
+// PatchVersionValues applies a patch to values of version. patchType should be
+// patch.MergePatchType or patch.JSONPatchType. If etag is not empty, the patch is applied
+// only if it matches the entity tag of current values. It returns the patched values
+// and its entity tag.
+func (c *Client) PatchVersionValues(spaceName string, chartName string, versionNumber string, patchType string, patch []byte, etag string) ([]byte, string, error) {
+	api := NewAPIPatchVersionValues()
+	api.Space = spaceName
+	api.Chart = chartName
+	api.Version = versionNumber
+	api.SetPatchType(patchType)
+	api.Patch = patch
+	api.IfMatch = etag
+	values, err := api.Convert(c.Do(api))
+	return values, api.ETag(), err
+}
+
+// ListTags lists tags of a chart
+func (c *Client) ListTags(spaceName string, chartName string, start, limit int) (*TagCollectionResult, error) {
+	api := NewAPIListTags()
+	api.Space = spaceName
+	api.Chart = chartName
+	api.Start = start
+	api.Limit = limit
+	return api.Convert(c.Do(api))
+}
+
+// FetchTag fetches a tag of a chart
+func (c *Client) FetchTag(spaceName string, chartName string, tagName string) (*storage.Tag, error) {
+	api := NewAPIFetchTag()
+	api.Space = spaceName
+	api.Chart = chartName
+	api.Tag = tagName
+	return api.Convert(c.Do(api))
+}
+
+// SetTag creates a tag or moves a tag to the version. versionNumber can also be a tag.
+func (c *Client) SetTag(spaceName string, chartName string, tagName string, versionNumber string) (*storage.Tag, error) {
+	api := NewAPISetTag()
+	api.Space = spaceName
+	api.Chart = chartName
+	api.Tag = tagName
+	api.Version = versionNumber
+	return api.Convert(c.Do(api))
+}
+
+// DeleteTag deletes a tag of a chart
+func (c *Client) DeleteTag(spaceName string, chartName string, tagName string) error {
+	api := NewAPIDeleteTag()
+	api.Space = spaceName
+	api.Chart = chartName
+	api.Tag = tagName
+	return api.Convert(c.Do(api))
+}
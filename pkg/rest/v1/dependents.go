@@ -0,0 +1,78 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package v1
+
+import "net/http"
+
+// APIListDependents defines an api of listing orchestrated versions which were built from a chart
+type APIListDependents struct {
+	baseAPI
+	// Space is the name of space
+	Space string `kind:"path" name:"space"`
+	// Chart is the name of chart
+	Chart string `kind:"path" name:"chart"`
+	// Version is the version number or tag of the chart. All versions are matched if empty
+	Version string `kind:"query" name:"version"`
+	// Start is the start index of list
+	Start int `kind:"query" name:"start"`
+	// Limit is the max length of list
+	Limit int `kind:"query" name:"limit"`
+	// Continue is the continuation token of the previous page
+	Continue string `kind:"query" name:"continue"`
+	// Yanked decides whether to include yanked dependents
+	Yanked bool `kind:"query" name:"yanked"`
+}
+
+// NewAPIListDependents creates an instance of APIListDependents
+func NewAPIListDependents() *APIListDependents {
+	api := &APIListDependents{}
+	api.object = api
+	api.method = http.MethodGet
+	api.url = URLChartDependents
+	api.result = &DependentCollectionResult{}
+	return api
+}
+
+// Convert converts result to *DependentCollectionResult
+func (api *APIListDependents) Convert(result interface{}, err error) (*DependentCollectionResult, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.(*DependentCollectionResult), nil
+}
+
+// APIListOutdatedVersions defines an api of listing orchestrated versions whose packages
+// have newer versions
+type APIListOutdatedVersions struct {
+	baseAPI
+	// Space is the name of space
+	Space string `kind:"path" name:"space"`
+	// Start is the start index of list
+	Start int `kind:"query" name:"start"`
+	// Limit is the max length of list
+	Limit int `kind:"query" name:"limit"`
+	// Continue is the continuation token of the previous page
+	Continue string `kind:"query" name:"continue"`
+	// All decides whether to check all versions instead of the latest version of each chart
+	All bool `kind:"query" name:"all"`
+}
+
+// NewAPIListOutdatedVersions creates an instance of APIListOutdatedVersions
+func NewAPIListOutdatedVersions() *APIListOutdatedVersions {
+	api := &APIListOutdatedVersions{}
+	api.object = api
+	api.method = http.MethodGet
+	api.url = URLOutdated
+	api.result = &OutdatedVersionCollectionResult{}
+	return api
+}
+
+// Convert converts result to *OutdatedVersionCollectionResult
+func (api *APIListOutdatedVersions) Convert(result interface{}, err error) (*OutdatedVersionCollectionResult, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.(*OutdatedVersionCollectionResult), nil
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package v1
+
+import "net/http"
+
+const (
+	// mimeMarkdown is the media type of markdown
+	mimeMarkdown = "text/markdown"
+	// mimeHTML is the media type of html
+	mimeHTML = "text/html"
+)
+
+// APIFetchVersionReadme defines an api of fetching README.md of version
+type APIFetchVersionReadme struct {
+	baseAPI
+	// Space is the name of space
+	Space string `kind:"path" name:"space"`
+	// Chart is the name of chart
+	Chart string `kind:"path" name:"chart"`
+	// Version is the name of version
+	Version string `kind:"path" name:"version"`
+	// Accept is the media type of response. It's markdown by default
+	Accept string `kind:"header" name:"Accept"`
+}
+
+// NewAPIFetchVersionReadme creates an instance of APIFetchVersionReadme
+func NewAPIFetchVersionReadme() *APIFetchVersionReadme {
+	api := &APIFetchVersionReadme{}
+	api.object = api
+	api.method = http.MethodGet
+	api.url = URLVersionReadme
+	api.Accept = mimeMarkdown
+	api.result = []byte{}
+	return api
+}
+
+// SetHTML requests html rendered from the markdown
+func (api *APIFetchVersionReadme) SetHTML(html bool) {
+	api.Accept = mimeMarkdown
+	if html {
+		api.Accept = mimeHTML
+	}
+}
+
+// Convert converts result to []byte
+func (api *APIFetchVersionReadme) Convert(result interface{}, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.([]byte), nil
+}
+
+// APIFetchVersionNotes defines an api of fetching templates/NOTES.txt of version
+type APIFetchVersionNotes struct {
+	baseAPI
+	// Space is the name of space
+	Space string `kind:"path" name:"space"`
+	// Chart is the name of chart
+	Chart string `kind:"path" name:"chart"`
+	// Version is the name of version
+	Version string `kind:"path" name:"version"`
+}
+
+// NewAPIFetchVersionNotes creates an instance of APIFetchVersionNotes
+func NewAPIFetchVersionNotes() *APIFetchVersionNotes {
+	api := &APIFetchVersionNotes{}
+	api.object = api
+	api.method = http.MethodGet
+	api.url = URLVersionNotes
+	api.result = []byte{}
+	return api
+}
+
+// Convert converts result to []byte
+func (api *APIFetchVersionNotes) Convert(result interface{}, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.([]byte), nil
+}
+
+// APIFetchVersionIcon defines an api of fetching the icon of version
+type APIFetchVersionIcon struct {
+	baseAPI
+	// Space is the name of space
+	Space string `kind:"path" name:"space"`
+	// Chart is the name of chart
+	Chart string `kind:"path" name:"chart"`
+	// Version is the name of version
+	Version string `kind:"path" name:"version"`
+}
+
+// NewAPIFetchVersionIcon creates an instance of APIFetchVersionIcon
+func NewAPIFetchVersionIcon() *APIFetchVersionIcon {
+	api := &APIFetchVersionIcon{}
+	api.object = api
+	api.method = http.MethodGet
+	api.url = URLVersionIcon
+	api.result = []byte{}
+	return api
+}
+
+// Convert converts result to []byte
+func (api *APIFetchVersionIcon) Convert(result interface{}, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.([]byte), nil
+}
+
+// APIFetchVersionSchema defines an api of fetching the json schema of values of version
+type APIFetchVersionSchema struct {
+	baseAPI
+	// Space is the name of space
+	Space string `kind:"path" name:"space"`
+	// Chart is the name of chart
+	Chart string `kind:"path" name:"chart"`
+	// Version is the name of version
+	Version string `kind:"path" name:"version"`
+}
+
+// NewAPIFetchVersionSchema creates an instance of APIFetchVersionSchema
+func NewAPIFetchVersionSchema() *APIFetchVersionSchema {
+	api := &APIFetchVersionSchema{}
+	api.object = api
+	api.method = http.MethodGet
+	api.url = URLVersionSchema
+	api.result = []byte{}
+	return api
+}
+
+// Convert converts result to []byte
+func (api *APIFetchVersionSchema) Convert(result interface{}, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.([]byte), nil
+}
+
+// APIInferVersionSchema defines an api of inferring a json schema from values of version
+type APIInferVersionSchema struct {
+	baseAPI
+	// Space is the name of space
+	Space string `kind:"path" name:"space"`
+	// Chart is the name of chart
+	Chart string `kind:"path" name:"chart"`
+	// Version is the name of version
+	Version string `kind:"path" name:"version"`
+}
+
+// NewAPIInferVersionSchema creates an instance of APIInferVersionSchema
+func NewAPIInferVersionSchema() *APIInferVersionSchema {
+	api := &APIInferVersionSchema{}
+	api.object = api
+	api.method = http.MethodGet
+	api.url = URLInferredSchema
+	api.result = []byte{}
+	return api
+}
+
+// Convert converts result to []byte
+func (api *APIInferVersionSchema) Convert(result interface{}, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.([]byte), nil
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package v1
+
+import "net/http"
+
+// APIListFiles defines an api of listing files in the chart package of a version
+type APIListFiles struct {
+	baseAPI
+	// Space is the name of space
+	Space string `kind:"path" name:"space"`
+	// Chart is the name of chart
+	Chart string `kind:"path" name:"chart"`
+	// Version is the name of version
+	Version string `kind:"path" name:"version"`
+	// Start is the start index of list
+	Start int `kind:"query" name:"start"`
+	// Limit is the max length of list
+	Limit int `kind:"query" name:"limit"`
+	// Continue is the continuation token of the previous page
+	Continue string `kind:"query" name:"continue"`
+}
+
+// NewAPIListFiles creates an instance of APIListFiles
+func NewAPIListFiles() *APIListFiles {
+	api := &APIListFiles{}
+	api.object = api
+	api.method = http.MethodGet
+	api.url = URLFiles
+	api.result = &FileCollectionResult{}
+	return api
+}
+
+// Convert converts result to *FileCollectionResult
+func (api *APIListFiles) Convert(result interface{}, err error) (*FileCollectionResult, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.(*FileCollectionResult), nil
+}
+
+// APIFetchFile defines an api of fetching a file in the chart package of a version
+type APIFetchFile struct {
+	baseAPI
+	// Space is the name of space
+	Space string `kind:"path" name:"space"`
+	// Chart is the name of chart
+	Chart string `kind:"path" name:"chart"`
+	// Version is the name of version
+	Version string `kind:"path" name:"version"`
+	// File is the path of file, e.g. templates/deployment.yaml
+	File string `kind:"path" name:"path"`
+}
+
+// NewAPIFetchFile creates an instance of APIFetchFile
+func NewAPIFetchFile() *APIFetchFile {
+	api := &APIFetchFile{}
+	api.object = api
+	api.method = http.MethodGet
+	api.url = URLFile
+	api.result = []byte{}
+	return api
+}
+
+// Convert converts result to []byte
+func (api *APIFetchFile) Convert(result interface{}, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.([]byte), nil
+}
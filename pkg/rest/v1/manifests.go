@@ -7,6 +7,7 @@ package v1
 import (
 	"net/http"
 
+	"github.com/caicloud/helm-registry/pkg/patch"
 	"github.com/caicloud/helm-registry/pkg/storage"
 )
 
@@ -21,6 +22,26 @@ type APIFetchChartMetadata struct {
 	Start int `kind:"query" name:"start"`
 	// Limit is the max length of list
 	Limit int `kind:"query" name:"limit"`
+	// Continue is the continuation token of the previous page
+	Continue string `kind:"query" name:"continue"`
+	// Yanked decides whether to include yanked versions
+	Yanked bool `kind:"query" name:"yanked"`
+	// Sort is the sort field: name, version, created or updated. Prefix '-' means descending order
+	Sort string `kind:"query" name:"sort"`
+	// Keyword is a case-insensitive keyword in names, descriptions or keywords
+	Keyword string `kind:"query" name:"keyword"`
+	// Maintainer is the name or email of a maintainer
+	Maintainer string `kind:"query" name:"maintainer"`
+	// AppVersion is the app version
+	AppVersion string `kind:"query" name:"appVersion"`
+	// Annotation is a selector of annotations in format key1=value1,key2
+	Annotation string `kind:"query" name:"annotation"`
+	// VersionRange is a range of version numbers, such as ">=1.0.0 <2.0.0"
+	VersionRange string `kind:"query" name:"version"`
+	// CreatedAfter is a RFC3339 time. Only versions created after the time are included
+	CreatedAfter string `kind:"query" name:"createdAfter"`
+	// CreatedBefore is a RFC3339 time. Only versions created before the time are included
+	CreatedBefore string `kind:"query" name:"createdBefore"`
 }
 
 // NewAPIFetchChartMetadata creates an instance of APIFetchChartMetadata
@@ -81,6 +102,10 @@ type APIUpdateVersionMetadata struct {
 	Version string `kind:"path" name:"version"`
 	// Metadata is the metadata of version
 	Metadata []byte `kind:"body"`
+	// Uploader is the uploader of versions. It's ignored if empty
+	Uploader string `kind:"header" name:"X-Registry-Uploader"`
+	// IfMatch is the entity tag which must match the current one. It's ignored if empty
+	IfMatch string `kind:"header" name:"If-Match"`
 }
 
 // NewAPIUpdateVersionMetadata creates an instance of APIFetchVersionMetadata
@@ -122,6 +147,41 @@ func (api *APIFetchVersionValues) Convert(result interface{}, err error) ([]byte
 	return result.([]byte), nil
 }
 
+// mimeYAML is the media type of yaml
+const mimeYAML = "application/yaml"
+
+// APIFetchVersionValuesYAML defines an api of fetching the original values.yaml of version
+type APIFetchVersionValuesYAML struct {
+	baseAPI
+	// Space is the name of space
+	Space string `kind:"path" name:"space"`
+	// Chart is the name of chart
+	Chart string `kind:"path" name:"chart"`
+	// Version is the name of version
+	Version string `kind:"path" name:"version"`
+	// Accept is the media type of response
+	Accept string `kind:"header" name:"Accept"`
+}
+
+// NewAPIFetchVersionValuesYAML creates an instance of APIFetchVersionValuesYAML
+func NewAPIFetchVersionValuesYAML() *APIFetchVersionValuesYAML {
+	api := &APIFetchVersionValuesYAML{}
+	api.object = api
+	api.method = http.MethodGet
+	api.url = URLVersionValues
+	api.Accept = mimeYAML
+	api.result = []byte{}
+	return api
+}
+
+// Convert converts result to []byte
+func (api *APIFetchVersionValuesYAML) Convert(result interface{}, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.([]byte), nil
+}
+
 // APIUpdateVersionValues defines an api for updating version values
 type APIUpdateVersionValues struct {
 	baseAPI
@@ -133,6 +193,10 @@ type APIUpdateVersionValues struct {
 	Version string `kind:"path" name:"version"`
 	// Values is the values of version
 	Values []byte `kind:"body"`
+	// Uploader is the uploader of versions. It's ignored if empty
+	Uploader string `kind:"header" name:"X-Registry-Uploader"`
+	// IfMatch is the entity tag which must match the current one. It's ignored if empty
+	IfMatch string `kind:"header" name:"If-Match"`
 }
 
 // NewAPIUpdateVersionValues creates an instance of APIUpdateVersionValues
@@ -152,3 +216,113 @@ func (api *APIUpdateVersionValues) Convert(result interface{}, err error) ([]byt
 	}
 	return result.([]byte), nil
 }
+
+// APIUpdateVersionValuesYAML defines an api for updating values.yaml of version. Comments
+// and the order of keys are kept.
+type APIUpdateVersionValuesYAML struct {
+	baseAPI
+	// Space is the name of space
+	Space string `kind:"path" name:"space"`
+	// Chart is the name of chart
+	Chart string `kind:"path" name:"chart"`
+	// Version is the name of version
+	Version string `kind:"path" name:"version"`
+	// Values is the values.yaml of version
+	Values []byte `kind:"body"`
+	// Uploader is the uploader of versions. It's ignored if empty
+	Uploader string `kind:"header" name:"X-Registry-Uploader"`
+	// IfMatch is the entity tag which must match the current one. It's ignored if empty
+	IfMatch string `kind:"header" name:"If-Match"`
+	// Accept is the media type of response
+	Accept string `kind:"header" name:"Accept"`
+}
+
+// NewAPIUpdateVersionValuesYAML creates an instance of APIUpdateVersionValuesYAML
+func NewAPIUpdateVersionValuesYAML() *APIUpdateVersionValuesYAML {
+	api := &APIUpdateVersionValuesYAML{}
+	api.object = api
+	api.method = http.MethodPut
+	api.url = URLVersionValues
+	api.contentType = mimeYAML
+	api.Accept = mimeYAML
+	api.result = []byte{}
+	return api
+}
+
+// Convert converts result to []byte
+func (api *APIUpdateVersionValuesYAML) Convert(result interface{}, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.([]byte), nil
+}
+
+// APIPatchVersionMetadata defines an api for patching version metadata
+type APIPatchVersionMetadata struct {
+	baseAPI
+	// Space is the name of space
+	Space string `kind:"path" name:"space"`
+	// Chart is the name of chart
+	Chart string `kind:"path" name:"chart"`
+	// Version is the name of version
+	Version string `kind:"path" name:"version"`
+	// Patch is a JSON Merge Patch or a JSON Patch of metadata
+	Patch []byte `kind:"body"`
+	// Uploader is the uploader of versions. It's ignored if empty
+	Uploader string `kind:"header" name:"X-Registry-Uploader"`
+	// IfMatch is the entity tag which must match the current one. It's ignored if empty
+	IfMatch string `kind:"header" name:"If-Match"`
+}
+
+// NewAPIPatchVersionMetadata creates an instance of APIPatchVersionMetadata. The default
+// patch type is patch.MergePatchType.
+func NewAPIPatchVersionMetadata() *APIPatchVersionMetadata {
+	api := &APIPatchVersionMetadata{}
+	api.object = api
+	api.method = http.MethodPatch
+	api.url = URLVersionMetadata
+	api.contentType = patch.MergePatchType
+	api.result = &storage.Metadata{}
+	return api
+}
+
+// SetPatchType sets the type of patch. It should be patch.MergePatchType or patch.JSONPatchType
+func (api *APIPatchVersionMetadata) SetPatchType(patchType string) {
+	api.contentType = patchType
+}
+
+// Convert converts result to *storage.Metadata
+func (api *APIPatchVersionMetadata) Convert(result interface{}, err error) (*storage.Metadata, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.(*storage.Metadata), nil
+}
+
+// APIPatchVersionValues defines an api for patching version values
+type APIPatchVersionValues APIPatchVersionMetadata
+
+// NewAPIPatchVersionValues creates an instance of APIPatchVersionValues. The default
+// patch type is patch.MergePatchType.
+func NewAPIPatchVersionValues() *APIPatchVersionValues {
+	api := &APIPatchVersionValues{}
+	api.object = api
+	api.method = http.MethodPatch
+	api.url = URLVersionValues
+	api.contentType = patch.MergePatchType
+	api.result = []byte{}
+	return api
+}
+
+// SetPatchType sets the type of patch. It should be patch.MergePatchType or patch.JSONPatchType
+func (api *APIPatchVersionValues) SetPatchType(patchType string) {
+	api.contentType = patchType
+}
+
+// Convert converts result to []byte
+func (api *APIPatchVersionValues) Convert(result interface{}, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.([]byte), nil
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package v1
+
+import (
+	"net/http"
+
+	"github.com/caicloud/helm-registry/pkg/api/v1/types"
+)
+
+// APIRenderVersion defines an api of rendering templates of version
+type APIRenderVersion struct {
+	baseAPI
+	// Space is the name of space
+	Space string `kind:"path" name:"space"`
+	// Chart is the name of chart
+	Chart string `kind:"path" name:"chart"`
+	// Version is the name of version
+	Version string `kind:"path" name:"version"`
+	// Config is a json string of render config
+	Config string `kind:"body"`
+}
+
+// NewAPIRenderVersion creates an instance of APIRenderVersion
+func NewAPIRenderVersion() *APIRenderVersion {
+	api := &APIRenderVersion{}
+	api.object = api
+	api.method = http.MethodPost
+	api.url = URLVersionRender
+	api.contentType = "application/json"
+	api.result = &types.RenderResult{}
+	return api
+}
+
+// Convert converts result to *types.RenderResult
+func (api *APIRenderVersion) Convert(result interface{}, err error) (*types.RenderResult, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.(*types.RenderResult), nil
+}
+
+// APIRenderVersionYAML defines an api of rendering templates of version into a
+// multi-document yaml
+type APIRenderVersionYAML struct {
+	baseAPI
+	// Space is the name of space
+	Space string `kind:"path" name:"space"`
+	// Chart is the name of chart
+	Chart string `kind:"path" name:"chart"`
+	// Version is the name of version
+	Version string `kind:"path" name:"version"`
+	// Format is the format of response
+	Format string `kind:"query" name:"format"`
+	// Config is a json string of render config
+	Config string `kind:"body"`
+}
+
+// NewAPIRenderVersionYAML creates an instance of APIRenderVersionYAML
+func NewAPIRenderVersionYAML() *APIRenderVersionYAML {
+	api := &APIRenderVersionYAML{}
+	api.object = api
+	api.method = http.MethodPost
+	api.url = URLVersionRender
+	api.contentType = "application/json"
+	api.Format = "yaml"
+	api.result = []byte{}
+	return api
+}
+
+// Convert converts result to []byte
+func (api *APIRenderVersionYAML) Convert(result interface{}, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.([]byte), nil
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package v1
+
+import (
+	"net/http"
+
+	"github.com/caicloud/helm-registry/pkg/api/v1/types"
+	"github.com/caicloud/helm-registry/pkg/storage"
+)
+
+// APIListRevisions defines an api of listing revisions of a version
+type APIListRevisions struct {
+	baseAPI
+	// Space is the name of space
+	Space string `kind:"path" name:"space"`
+	// Chart is the name of chart
+	Chart string `kind:"path" name:"chart"`
+	// Version is the name of version
+	Version string `kind:"path" name:"version"`
+	// Start is the start index of list
+	Start int `kind:"query" name:"start"`
+	// Limit is the max length of list
+	Limit int `kind:"query" name:"limit"`
+	// Continue is the continuation token of the previous page
+	Continue string `kind:"query" name:"continue"`
+}
+
+// NewAPIListRevisions creates an instance of APIListRevisions
+func NewAPIListRevisions() *APIListRevisions {
+	api := &APIListRevisions{}
+	api.object = api
+	api.method = http.MethodGet
+	api.url = URLRevisions
+	api.result = &RevisionCollectionResult{}
+	return api
+}
+
+// Convert converts result to *RevisionCollectionResult
+func (api *APIListRevisions) Convert(result interface{}, err error) (*RevisionCollectionResult, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.(*RevisionCollectionResult), nil
+}
+
+// APIDownloadRevision defines an api of downloading the chart package of a revision
+type APIDownloadRevision struct {
+	baseAPI
+	// Space is the name of space
+	Space string `kind:"path" name:"space"`
+	// Chart is the name of chart
+	Chart string `kind:"path" name:"chart"`
+	// Version is the name of version
+	Version string `kind:"path" name:"version"`
+	// Revision is the revision number
+	Revision int `kind:"path" name:"revision"`
+}
+
+// NewAPIDownloadRevision creates an instance of APIDownloadRevision
+func NewAPIDownloadRevision() *APIDownloadRevision {
+	api := &APIDownloadRevision{}
+	api.object = api
+	api.method = http.MethodGet
+	api.url = URLRevision
+	api.result = []byte{}
+	return api
+}
+
+// Convert converts result to []byte
+func (api *APIDownloadRevision) Convert(result interface{}, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.([]byte), nil
+}
+
+// APIDiffRevisions defines an api of comparing two revisions
+type APIDiffRevisions struct {
+	baseAPI
+	// Space is the name of space
+	Space string `kind:"path" name:"space"`
+	// Chart is the name of chart
+	Chart string `kind:"path" name:"chart"`
+	// Version is the name of version
+	Version string `kind:"path" name:"version"`
+	// Revision is the revision number to compare from
+	Revision int `kind:"path" name:"revision"`
+	// To is the revision number to compare with. It's the current revision if empty
+	To string `kind:"query" name:"to"`
+}
+
+// NewAPIDiffRevisions creates an instance of APIDiffRevisions
+func NewAPIDiffRevisions() *APIDiffRevisions {
+	api := &APIDiffRevisions{}
+	api.object = api
+	api.method = http.MethodGet
+	api.url = URLRevisionDiff
+	api.result = &types.ChartDiff{}
+	return api
+}
+
+// Convert converts result to *types.ChartDiff
+func (api *APIDiffRevisions) Convert(result interface{}, err error) (*types.ChartDiff, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.(*types.ChartDiff), nil
+}
+
+// APIRollbackRevision defines an api of rolling a version back to a revision
+type APIRollbackRevision struct {
+	baseAPI
+	// Space is the name of space
+	Space string `kind:"path" name:"space"`
+	// Chart is the name of chart
+	Chart string `kind:"path" name:"chart"`
+	// Version is the name of version
+	Version string `kind:"path" name:"version"`
+	// Revision is the revision number to roll back to
+	Revision int `kind:"path" name:"revision"`
+	// Uploader is the author of the new revision. It's ignored if empty
+	Uploader string `kind:"header" name:"X-Registry-Uploader"`
+	// IfMatch is the entity tag which must match the current one. It's ignored if empty
+	IfMatch string `kind:"header" name:"If-Match"`
+}
+
+// NewAPIRollbackRevision creates an instance of APIRollbackRevision
+func NewAPIRollbackRevision() *APIRollbackRevision {
+	api := &APIRollbackRevision{}
+	api.object = api
+	api.method = http.MethodPost
+	api.url = URLRevisionRollback
+	api.result = &storage.Revision{}
+	return api
+}
+
+// Convert converts result to *storage.Revision
+func (api *APIRollbackRevision) Convert(result interface{}, err error) (*storage.Revision, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.(*storage.Revision), nil
+}
@@ -17,6 +17,12 @@ type APIListSpaces struct {
 	Start int `kind:"query" name:"start"`
 	// Limit is the max length of list
 	Limit int `kind:"query" name:"limit"`
+	// Continue is the continuation token of the previous page
+	Continue string `kind:"query" name:"continue"`
+	// Label is a comma-separated list of labels which spaces must have
+	Label string `kind:"query" name:"label"`
+	// Visibility is the visibility of spaces
+	Visibility string `kind:"query" name:"visibility"`
 }
 
 // NewAPIListSpaces creates an instance of APIListSpace
@@ -37,11 +43,49 @@ func (api *APIListSpaces) Convert(result interface{}, err error) (*StringCollect
 	return result.(*StringCollectionResult), nil
 }
 
+// APIListSpaceSummaries defines an api of listing summaries of spaces
+type APIListSpaceSummaries struct {
+	baseAPI
+	// Start is the start index of list
+	Start int `kind:"query" name:"start"`
+	// Limit is the max length of list
+	Limit int `kind:"query" name:"limit"`
+	// Continue is the continuation token of the previous page
+	Continue string `kind:"query" name:"continue"`
+	// Label is a comma-separated list of labels which spaces must have
+	Label string `kind:"query" name:"label"`
+	// Visibility is the visibility of spaces
+	Visibility string `kind:"query" name:"visibility"`
+	// Detail must be true to get summaries
+	Detail bool `kind:"query" name:"detail"`
+}
+
+// NewAPIListSpaceSummaries creates an instance of APIListSpaceSummaries
+func NewAPIListSpaceSummaries() *APIListSpaceSummaries {
+	api := &APIListSpaceSummaries{}
+	api.object = api
+	api.method = http.MethodGet
+	api.url = URLSpaces
+	api.result = &SpaceSummaryCollectionResult{}
+	api.Detail = true
+	return api
+}
+
+// Convert converts result to *SpaceSummaryCollectionResult
+func (api *APIListSpaceSummaries) Convert(result interface{}, err error) (*SpaceSummaryCollectionResult, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.(*SpaceSummaryCollectionResult), nil
+}
+
 // APICreateSpace defines an api of creating space
 type APICreateSpace struct {
 	baseAPI
 	// Space is the name of space
 	Space string `kind:"query" name:"space"`
+	// Properties is the json data of space properties. It can be nil
+	Properties []byte `kind:"body"`
 }
 
 // APICreateSpace creates an instance of APICreateSpace
@@ -50,6 +94,7 @@ func NewAPICreateSpace() *APICreateSpace {
 	api.object = api
 	api.method = http.MethodPost
 	api.url = URLSpaces
+	api.contentType = "application/json"
 	api.result = &models.Link{}
 	return api
 }
@@ -62,11 +107,66 @@ func (api *APICreateSpace) Convert(result interface{}, err error) (*models.Link,
 	return result.(*models.Link), nil
 }
 
+// APIFetchSpace defines an api of fetching space
+type APIFetchSpace struct {
+	baseAPI
+	// Space is the name of space
+	Space string `kind:"path" name:"space"`
+}
+
+// NewAPIFetchSpace creates an instance of APIFetchSpace
+func NewAPIFetchSpace() *APIFetchSpace {
+	api := &APIFetchSpace{}
+	api.object = api
+	api.method = http.MethodGet
+	api.url = URLSpace
+	api.result = &models.Space{}
+	return api
+}
+
+// Convert converts result to *models.Space
+func (api *APIFetchSpace) Convert(result interface{}, err error) (*models.Space, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.(*models.Space), nil
+}
+
+// APIUpdateSpace defines an api of updating properties of space
+type APIUpdateSpace struct {
+	baseAPI
+	// Space is the name of space
+	Space string `kind:"path" name:"space"`
+	// Properties is the json data of space properties
+	Properties []byte `kind:"body"`
+}
+
+// NewAPIUpdateSpace creates an instance of APIUpdateSpace
+func NewAPIUpdateSpace() *APIUpdateSpace {
+	api := &APIUpdateSpace{}
+	api.object = api
+	api.method = http.MethodPut
+	api.url = URLSpace
+	api.contentType = "application/json"
+	api.result = &models.Space{}
+	return api
+}
+
+// Convert converts result to *models.Space
+func (api *APIUpdateSpace) Convert(result interface{}, err error) (*models.Space, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.(*models.Space), nil
+}
+
 // APIDeleteSpace defines an api of deleting space
 type APIDeleteSpace struct {
 	baseAPI
 	// Space is the name of space
 	Space string `kind:"path" name:"space"`
+	// Force decides whether to delete even if versions are referenced by orchestrated versions or requirements
+	Force bool `kind:"query" name:"force"`
 }
 
 // APICreateSpace creates an instance of APICreateSpace
@@ -82,3 +182,32 @@ func NewAPIDeleteSpace() *APIDeleteSpace {
 func (api *APIDeleteSpace) Convert(result interface{}, err error) error {
 	return err
 }
+
+// APIRenameSpace defines an api of renaming space
+type APIRenameSpace struct {
+	baseAPI
+	// Space is the name of space
+	Space string `kind:"path" name:"space"`
+	// Name is the new name of space
+	Name string `kind:"query" name:"name"`
+	// Force decides whether to rename even if versions are referenced by orchestrated versions
+	Force bool `kind:"query" name:"force"`
+}
+
+// NewAPIRenameSpace creates an instance of APIRenameSpace
+func NewAPIRenameSpace() *APIRenameSpace {
+	api := &APIRenameSpace{}
+	api.object = api
+	api.method = http.MethodPost
+	api.url = URLSpaceRename
+	api.result = &models.Link{}
+	return api
+}
+
+// Convert converts result to *models.Link
+func (api *APIRenameSpace) Convert(result interface{}, err error) (*models.Link, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.(*models.Link), nil
+}
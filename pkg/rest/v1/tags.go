@@ -0,0 +1,129 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package v1
+
+import (
+	"net/http"
+
+	"github.com/caicloud/helm-registry/pkg/storage"
+)
+
+// APIListTags defines an api of listing tags
+type APIListTags struct {
+	baseAPI
+	// Space is the name of space
+	Space string `kind:"path" name:"space"`
+	// Chart is the name of chart
+	Chart string `kind:"path" name:"chart"`
+	// Start is the start index of list
+	Start int `kind:"query" name:"start"`
+	// Limit is the max length of list
+	Limit int `kind:"query" name:"limit"`
+	// Continue is the continuation token of the previous page
+	Continue string `kind:"query" name:"continue"`
+}
+
+// NewAPIListTags creates an instance of APIListTags
+func NewAPIListTags() *APIListTags {
+	api := &APIListTags{}
+	api.object = api
+	api.method = http.MethodGet
+	api.url = URLTags
+	api.result = &TagCollectionResult{}
+	return api
+}
+
+// Convert converts result to *TagCollectionResult
+func (api *APIListTags) Convert(result interface{}, err error) (*TagCollectionResult, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.(*TagCollectionResult), nil
+}
+
+// APIFetchTag defines an api of fetching tag
+type APIFetchTag struct {
+	baseAPI
+	// Space is the name of space
+	Space string `kind:"path" name:"space"`
+	// Chart is the name of chart
+	Chart string `kind:"path" name:"chart"`
+	// Tag is the name of tag
+	Tag string `kind:"path" name:"tag"`
+}
+
+// NewAPIFetchTag creates an instance of APIFetchTag
+func NewAPIFetchTag() *APIFetchTag {
+	api := &APIFetchTag{}
+	api.object = api
+	api.method = http.MethodGet
+	api.url = URLTag
+	api.result = &storage.Tag{}
+	return api
+}
+
+// Convert converts result to *storage.Tag
+func (api *APIFetchTag) Convert(result interface{}, err error) (*storage.Tag, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.(*storage.Tag), nil
+}
+
+// APISetTag defines an api of creating or moving tag
+type APISetTag struct {
+	baseAPI
+	// Space is the name of space
+	Space string `kind:"path" name:"space"`
+	// Chart is the name of chart
+	Chart string `kind:"path" name:"chart"`
+	// Tag is the name of tag
+	Tag string `kind:"path" name:"tag"`
+	// Version is the version number or tag which the tag points to
+	Version string `kind:"query" name:"version"`
+}
+
+// NewAPISetTag creates an instance of APISetTag
+func NewAPISetTag() *APISetTag {
+	api := &APISetTag{}
+	api.object = api
+	api.method = http.MethodPut
+	api.url = URLTag
+	api.result = &storage.Tag{}
+	return api
+}
+
+// Convert converts result to *storage.Tag
+func (api *APISetTag) Convert(result interface{}, err error) (*storage.Tag, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.(*storage.Tag), nil
+}
+
+// APIDeleteTag defines an api of deleting tag
+type APIDeleteTag struct {
+	baseAPI
+	// Space is the name of space
+	Space string `kind:"path" name:"space"`
+	// Chart is the name of chart
+	Chart string `kind:"path" name:"chart"`
+	// Tag is the name of tag
+	Tag string `kind:"path" name:"tag"`
+}
+
+// NewAPIDeleteTag creates an instance of APIDeleteTag
+func NewAPIDeleteTag() *APIDeleteTag {
+	api := &APIDeleteTag{}
+	api.object = api
+	api.method = http.MethodDelete
+	api.url = URLTag
+	return api
+}
+
+// Convert converts result to nothing
+func (api *APIDeleteTag) Convert(result interface{}, err error) error {
+	return err
+}
@@ -20,3 +20,57 @@ type MetadataCollectionResult struct {
 	Metadata models.Metadata     `json:"metadata"`
 	Items    []*storage.Metadata `json:"items"`
 }
+
+// EventCollectionResult describes a collection of []*storage.Event
+type EventCollectionResult struct {
+	Metadata models.Metadata  `json:"metadata"`
+	Items    []*storage.Event `json:"items"`
+}
+
+// RevisionCollectionResult describes a collection of []*storage.Revision
+type RevisionCollectionResult struct {
+	Metadata models.Metadata     `json:"metadata"`
+	Items    []*storage.Revision `json:"items"`
+}
+
+// FileCollectionResult describes a collection of []*models.File
+type FileCollectionResult struct {
+	Metadata models.Metadata `json:"metadata"`
+	Items    []*models.File  `json:"items"`
+}
+
+// TagCollectionResult describes a collection of []*storage.Tag
+type TagCollectionResult struct {
+	Metadata models.Metadata `json:"metadata"`
+	Items    []*storage.Tag  `json:"items"`
+}
+
+// SpaceSummaryCollectionResult describes a collection of []*storage.SpaceSummary
+type SpaceSummaryCollectionResult struct {
+	Metadata models.Metadata         `json:"metadata"`
+	Items    []*storage.SpaceSummary `json:"items"`
+}
+
+// ChartSummaryCollectionResult describes a collection of []*storage.ChartSummary
+type ChartSummaryCollectionResult struct {
+	Metadata models.Metadata         `json:"metadata"`
+	Items    []*storage.ChartSummary `json:"items"`
+}
+
+// VersionSummaryCollectionResult describes a collection of []*storage.VersionSummary
+type VersionSummaryCollectionResult struct {
+	Metadata models.Metadata           `json:"metadata"`
+	Items    []*storage.VersionSummary `json:"items"`
+}
+
+// DependentCollectionResult describes a collection of []*models.Dependent
+type DependentCollectionResult struct {
+	Metadata models.Metadata     `json:"metadata"`
+	Items    []*models.Dependent `json:"items"`
+}
+
+// OutdatedVersionCollectionResult describes a collection of []*models.OutdatedVersion
+type OutdatedVersionCollectionResult struct {
+	Metadata models.Metadata           `json:"metadata"`
+	Items    []*models.OutdatedVersion `json:"items"`
+}
@@ -10,15 +10,43 @@ import "strings"
 type URL string
 
 const (
-	URLSpaces          URL = "/spaces"
-	URLSpace           URL = "/spaces/{space}"
-	URLCharts          URL = "/spaces/{space}/charts"
-	URLChart           URL = "/spaces/{space}/charts/{chart}"
-	URLChartMetadata   URL = "/spaces/{space}/charts/{chart}/metadata"
-	URLVersions        URL = "/spaces/{space}/charts/{chart}/versions"
-	URLVersion         URL = "/spaces/{space}/charts/{chart}/versions/{version}"
-	URLVersionMetadata URL = "/spaces/{space}/charts/{chart}/versions/{version}/manifests/metadata"
-	URLVersionValues   URL = "/spaces/{space}/charts/{chart}/versions/{version}/manifests/values"
+	URLSpaces           URL = "/spaces"
+	URLSpace            URL = "/spaces/{space}"
+	URLSpaceRename      URL = "/spaces/{space}/rename"
+	URLCharts           URL = "/spaces/{space}/charts"
+	URLPreview          URL = "/spaces/{space}/preview"
+	URLLint             URL = "/spaces/{space}/lint"
+	URLOutdated         URL = "/spaces/{space}/outdated"
+	URLChart            URL = "/spaces/{space}/charts/{chart}"
+	URLChartMetadata    URL = "/spaces/{space}/charts/{chart}/metadata"
+	URLChartMove        URL = "/spaces/{space}/charts/{chart}/move"
+	URLChartPromotion   URL = "/spaces/{space}/charts/{chart}/promote"
+	URLChartHistory     URL = "/spaces/{space}/charts/{chart}/history"
+	URLChartDiff        URL = "/spaces/{space}/charts/{chart}/diff"
+	URLChartDependents  URL = "/spaces/{space}/charts/{chart}/dependents"
+	URLTags             URL = "/spaces/{space}/charts/{chart}/tags"
+	URLTag              URL = "/spaces/{space}/charts/{chart}/tags/{tag}"
+	URLVersions         URL = "/spaces/{space}/charts/{chart}/versions"
+	URLVersion          URL = "/spaces/{space}/charts/{chart}/versions/{version}"
+	URLVersionMetadata  URL = "/spaces/{space}/charts/{chart}/versions/{version}/manifests/metadata"
+	URLVersionValues    URL = "/spaces/{space}/charts/{chart}/versions/{version}/manifests/values"
+	URLVersionPromotion URL = "/spaces/{space}/charts/{chart}/versions/{version}/promote"
+	URLVersionState     URL = "/spaces/{space}/charts/{chart}/versions/{version}/state"
+	URLVersionRecord    URL = "/spaces/{space}/charts/{chart}/versions/{version}/record"
+	URLRevisions        URL = "/spaces/{space}/charts/{chart}/versions/{version}/revisions"
+	URLRevision         URL = "/spaces/{space}/charts/{chart}/versions/{version}/revisions/{revision}"
+	URLRevisionDiff     URL = "/spaces/{space}/charts/{chart}/versions/{version}/revisions/{revision}/diff"
+	URLRevisionRollback URL = "/spaces/{space}/charts/{chart}/versions/{version}/revisions/{revision}/rollback"
+	URLFiles            URL = "/spaces/{space}/charts/{chart}/versions/{version}/files"
+	URLFile             URL = "/spaces/{space}/charts/{chart}/versions/{version}/files/{path}"
+	URLVersionReadme    URL = "/spaces/{space}/charts/{chart}/versions/{version}/readme"
+	URLVersionNotes     URL = "/spaces/{space}/charts/{chart}/versions/{version}/notes"
+	URLVersionIcon      URL = "/spaces/{space}/charts/{chart}/versions/{version}/icon"
+	URLVersionSchema    URL = "/spaces/{space}/charts/{chart}/versions/{version}/schema"
+	URLInferredSchema   URL = "/spaces/{space}/charts/{chart}/versions/{version}/schema/inferred"
+	URLOrchestration    URL = "/spaces/{space}/charts/{chart}/versions/{version}/orchestration"
+	URLVersionRebuild   URL = "/spaces/{space}/charts/{chart}/versions/{version}/rebuild"
+	URLVersionRender    URL = "/spaces/{space}/charts/{chart}/versions/{version}/render"
 )
 
 // Format generates url. values should contain all keys in url.
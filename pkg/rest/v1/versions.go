@@ -8,6 +8,8 @@ import (
 	"net/http"
 
 	"github.com/caicloud/helm-registry/pkg/api/models"
+	"github.com/caicloud/helm-registry/pkg/api/v1/types"
+	"github.com/caicloud/helm-registry/pkg/storage"
 )
 
 // APIListVersions defines an api of listing versions
@@ -21,6 +23,12 @@ type APIListVersions struct {
 	Start int `kind:"query" name:"start"`
 	// Limit is the max length of list
 	Limit int `kind:"query" name:"limit"`
+	// Continue is the continuation token of the previous page
+	Continue string `kind:"query" name:"continue"`
+	// Yanked decides whether to include yanked versions
+	Yanked bool `kind:"query" name:"yanked"`
+	// Sort is the sort field: version, created or updated. Prefix '-' means descending order
+	Sort string `kind:"query" name:"sort"`
 }
 
 // NewAPIListVersions creates an instance of APIListVersions
@@ -41,6 +49,46 @@ func (api *APIListVersions) Convert(result interface{}, err error) (*StringColle
 	return result.(*StringCollectionResult), nil
 }
 
+// APIListVersionSummaries defines an api of listing summaries of versions
+type APIListVersionSummaries struct {
+	baseAPI
+	// Space is the name of space
+	Space string `kind:"path" name:"space"`
+	// Chart is the name of chart
+	Chart string `kind:"path" name:"chart"`
+	// Start is the start index of list
+	Start int `kind:"query" name:"start"`
+	// Limit is the max length of list
+	Limit int `kind:"query" name:"limit"`
+	// Continue is the continuation token of the previous page
+	Continue string `kind:"query" name:"continue"`
+	// Yanked decides whether to include yanked versions
+	Yanked bool `kind:"query" name:"yanked"`
+	// Sort is the sort field: version, created or updated. Prefix '-' means descending order
+	Sort string `kind:"query" name:"sort"`
+	// Detail must be true to get summaries
+	Detail bool `kind:"query" name:"detail"`
+}
+
+// NewAPIListVersionSummaries creates an instance of APIListVersionSummaries
+func NewAPIListVersionSummaries() *APIListVersionSummaries {
+	api := &APIListVersionSummaries{}
+	api.object = api
+	api.method = http.MethodGet
+	api.url = URLVersions
+	api.result = &VersionSummaryCollectionResult{}
+	api.Detail = true
+	return api
+}
+
+// Convert converts result to *VersionSummaryCollectionResult
+func (api *APIListVersionSummaries) Convert(result interface{}, err error) (*VersionSummaryCollectionResult, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.(*VersionSummaryCollectionResult), nil
+}
+
 // APIDownloadVersion defines an api of downloading version
 type APIDownloadVersion struct {
 	baseAPI
@@ -81,6 +129,8 @@ type APIUpdateVersion struct {
 	Version string `kind:"path" name:"version"`
 	// ChartFile is a chart file
 	ChartFile *File `kind:"file" name:"chartfile"`
+	// Uploader is the uploader of versions. It's ignored if empty
+	Uploader string `kind:"header" name:"X-Registry-Uploader"`
 }
 
 // NewAPIUpdateVersion creates an instance of APIUpdateVersion
@@ -111,6 +161,8 @@ type APIDeleteVersion struct {
 	Chart string `kind:"path" name:"chart"`
 	// Version is the name of Version
 	Version string `kind:"path" name:"version"`
+	// Force decides whether to delete even if versions are referenced by orchestrated versions or requirements
+	Force bool `kind:"query" name:"force"`
 }
 
 // APICreateVersion creates an instance of APICreateVersion
@@ -126,3 +178,176 @@ func NewAPIDeleteVersion() *APIDeleteVersion {
 func (api *APIDeleteVersion) Convert(result interface{}, err error) error {
 	return err
 }
+
+// APIPromoteVersion defines an api of promoting version
+type APIPromoteVersion struct {
+	baseAPI
+	// Space is the name of space
+	Space string `kind:"path" name:"space"`
+	// Chart is the name of chart
+	Chart string `kind:"path" name:"chart"`
+	// Version is the name of Version
+	Version string `kind:"path" name:"version"`
+	// Target is the name of target space
+	Target string `kind:"query" name:"target"`
+	// History indicates whether to record the promotion in the history of target chart
+	History bool `kind:"query" name:"history"`
+	// Uploader is the uploader of versions. It's ignored if empty
+	Uploader string `kind:"header" name:"X-Registry-Uploader"`
+}
+
+// NewAPIPromoteVersion creates an instance of APIPromoteVersion
+func NewAPIPromoteVersion() *APIPromoteVersion {
+	api := &APIPromoteVersion{}
+	api.object = api
+	api.method = http.MethodPost
+	api.url = URLVersionPromotion
+	api.result = &models.ChartLink{}
+	return api
+}
+
+// Convert converts result to *models.ChartLink
+func (api *APIPromoteVersion) Convert(result interface{}, err error) (*models.ChartLink, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.(*models.ChartLink), nil
+}
+
+// APIFetchVersionState defines an api of fetching version state
+type APIFetchVersionState struct {
+	baseAPI
+	// Space is the name of space
+	Space string `kind:"path" name:"space"`
+	// Chart is the name of chart
+	Chart string `kind:"path" name:"chart"`
+	// Version is the number of version
+	Version string `kind:"path" name:"version"`
+}
+
+// NewAPIFetchVersionState creates an instance of APIFetchVersionState
+func NewAPIFetchVersionState() *APIFetchVersionState {
+	api := &APIFetchVersionState{}
+	api.object = api
+	api.method = http.MethodGet
+	api.url = URLVersionState
+	api.result = &storage.VersionState{}
+	return api
+}
+
+// Convert converts result to *storage.VersionState
+func (api *APIFetchVersionState) Convert(result interface{}, err error) (*storage.VersionState, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.(*storage.VersionState), nil
+}
+
+// APISetVersionState defines an api of setting version state
+type APISetVersionState struct {
+	baseAPI
+	// Space is the name of space
+	Space string `kind:"path" name:"space"`
+	// Chart is the name of chart
+	Chart string `kind:"path" name:"chart"`
+	// Version is the number of version
+	Version string `kind:"path" name:"version"`
+	// State is the new state of version
+	State string `kind:"query" name:"state"`
+	// Reason is the reason of the state
+	Reason string `kind:"query" name:"reason"`
+}
+
+// NewAPISetVersionState creates an instance of APISetVersionState
+func NewAPISetVersionState() *APISetVersionState {
+	api := &APISetVersionState{}
+	api.object = api
+	api.method = http.MethodPut
+	api.url = URLVersionState
+	api.result = &storage.VersionState{}
+	return api
+}
+
+// Convert converts result to *storage.VersionState
+func (api *APISetVersionState) Convert(result interface{}, err error) (*storage.VersionState, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.(*storage.VersionState), nil
+}
+
+// APIFetchVersionRecord defines an api of fetching version record
+type APIFetchVersionRecord APIFetchVersionState
+
+// NewAPIFetchVersionRecord creates an instance of APIFetchVersionRecord
+func NewAPIFetchVersionRecord() *APIFetchVersionRecord {
+	api := &APIFetchVersionRecord{}
+	api.object = api
+	api.method = http.MethodGet
+	api.url = URLVersionRecord
+	api.result = &storage.Record{}
+	return api
+}
+
+// Convert converts result to *storage.Record
+func (api *APIFetchVersionRecord) Convert(result interface{}, err error) (*storage.Record, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.(*storage.Record), nil
+}
+
+// APIFetchOrchestrationConfig defines an api of fetching the orchestration config of version
+type APIFetchOrchestrationConfig APIFetchVersionState
+
+// NewAPIFetchOrchestrationConfig creates an instance of APIFetchOrchestrationConfig
+func NewAPIFetchOrchestrationConfig() *APIFetchOrchestrationConfig {
+	api := &APIFetchOrchestrationConfig{}
+	api.object = api
+	api.method = http.MethodGet
+	api.url = URLOrchestration
+	api.result = &types.OrchestrationConfig{}
+	return api
+}
+
+// Convert converts result to *types.OrchestrationConfig
+func (api *APIFetchOrchestrationConfig) Convert(result interface{}, err error) (*types.OrchestrationConfig, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.(*types.OrchestrationConfig), nil
+}
+
+// APIRebuildVersion defines an api of rebuilding an orchestrated version
+type APIRebuildVersion struct {
+	baseAPI
+	// Space is the name of space
+	Space string `kind:"path" name:"space"`
+	// Chart is the name of chart
+	Chart string `kind:"path" name:"chart"`
+	// Version is the number of version
+	Version string `kind:"path" name:"version"`
+	// Config is a json string of rebuild config
+	Config string `kind:"body"`
+	// Uploader is the uploader of the new version. It's ignored if empty
+	Uploader string `kind:"header" name:"X-Registry-Uploader"`
+}
+
+// NewAPIRebuildVersion creates an instance of APIRebuildVersion
+func NewAPIRebuildVersion() *APIRebuildVersion {
+	api := &APIRebuildVersion{}
+	api.object = api
+	api.method = http.MethodPost
+	api.url = URLVersionRebuild
+	api.contentType = "application/json"
+	api.result = &models.ChartLink{}
+	return api
+}
+
+// Convert converts result to *models.ChartLink
+func (api *APIRebuildVersion) Convert(result interface{}, err error) (*models.ChartLink, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.(*models.ChartLink), nil
+}
@@ -0,0 +1,238 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package schema
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Draft is the json schema draft of inferred schemas
+const Draft = "http://json-schema.org/draft-07/schema#"
+
+// itemsToken is the token of array items in paths of descriptions
+const itemsToken = "[]"
+
+var (
+	// keyPattern matches a key of block mapping in yaml, e.g. `tag: "1.0" # comment`
+	keyPattern = regexp.MustCompile(`^("(?:[^"\\]|\\.)*"|'(?:[^']|'')*'|[^\s#'"{}\[\]-][^#:]*?|-[^\s#:][^#:]*?)\s*:(?:\s+(.*))?$`)
+	// blockScalarPattern matches the indicator of literal or folded scalars
+	blockScalarPattern = regexp.MustCompile(`^[|>][-+0-9]*\s*(#.*)?$`)
+)
+
+// Infer infers a json schema from values. Objects have properties, arrays have items which
+// accept all of their items, and scalars have defaults.
+// descriptions are keyed by json pointers of values, e.g. /image/tag or /ports/[]/name for
+// all items of ports.
+func Infer(values interface{}, descriptions map[string]string) map[string]interface{} {
+	s := infer(normalize(values), "", descriptions)
+	s["$schema"] = Draft
+	return s
+}
+
+// infer infers the schema of a normalized value at path
+func infer(value interface{}, path string, descriptions map[string]string) map[string]interface{} {
+	s := map[string]interface{}{}
+	if description, ok := descriptions[path]; ok {
+		s["description"] = description
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		s["type"] = "object"
+		properties := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			properties[key] = infer(item, path+"/"+escapePointer(key), descriptions)
+		}
+		s["properties"] = properties
+	case []interface{}:
+		s["type"] = "array"
+		s["default"] = v
+		if len(v) > 0 {
+			var items map[string]interface{}
+			for _, item := range v {
+				inferred := infer(item, path+"/"+itemsToken, descriptions)
+				if items == nil {
+					items = inferred
+				} else {
+					items = merge(items, inferred)
+				}
+			}
+			// defaults of items are defaults of the array
+			delete(items, "default")
+			s["items"] = items
+		}
+	case nil:
+		s["default"] = nil
+	default:
+		s["type"] = typeOf(v)
+		s["default"] = v
+	}
+	return s
+}
+
+// merge merges the inferred schema of another value into s, so that s accepts both values.
+// Integers and numbers are merged to numbers, and types are removed if values have different
+// types.
+func merge(s, other map[string]interface{}) map[string]interface{} {
+	t, _ := s["type"].(string)
+	o, _ := other["type"].(string)
+	switch {
+	case t == o:
+	case (t == "integer" && o == "number") || (t == "number" && o == "integer"):
+		s["type"] = "number"
+		return s
+	default:
+		delete(s, "type")
+		delete(s, "properties")
+		delete(s, "items")
+		return s
+	}
+	switch t {
+	case "object":
+		properties := s["properties"].(map[string]interface{})
+		for key, sub := range other["properties"].(map[string]interface{}) {
+			if existing, ok := properties[key]; ok {
+				properties[key] = merge(existing.(map[string]interface{}), sub.(map[string]interface{}))
+			} else {
+				properties[key] = sub
+			}
+		}
+	case "array":
+		// empty arrays have no items
+		items, ok := s["items"].(map[string]interface{})
+		if otherItems, found := other["items"].(map[string]interface{}); found {
+			if ok {
+				s["items"] = merge(items, otherItems)
+			} else {
+				s["items"] = otherItems
+			}
+		}
+	}
+	return s
+}
+
+// frame is a mapping key or sequence in the path of current line
+type frame struct {
+	indent int
+	token  string
+}
+
+// Descriptions extracts descriptions of values from comments in yaml. A value is described by
+// the comments right above its key with the same indent, or by the comment at the end of the
+// line. Keys of the result are json pointers which Infer accepts. Flow collections are not
+// scanned.
+func Descriptions(data []byte) map[string]string {
+	descriptions := map[string]string{}
+	stack := []frame{}
+	comments := []string{}
+	commentIndent := -1
+	scalarIndent := -1
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		trimmed := strings.TrimLeft(line, " ")
+		indent := len(line) - len(trimmed)
+		if scalarIndent >= 0 {
+			if len(trimmed) <= 0 || indent > scalarIndent {
+				continue
+			}
+			scalarIndent = -1
+		}
+		switch {
+		case len(trimmed) <= 0, trimmed == "---", trimmed == "...":
+			comments, commentIndent = nil, -1
+			if trimmed != "" {
+				stack = stack[:0]
+			}
+			continue
+		case strings.HasPrefix(trimmed, "#"):
+			if indent != commentIndent {
+				// only the nearest comments with the same indent describe the next key
+				comments, commentIndent = nil, indent
+			}
+			comments = append(comments, strings.TrimSpace(strings.TrimLeft(trimmed, "#")))
+			continue
+		}
+		// sequence items
+		for trimmed == "-" || strings.HasPrefix(trimmed, "- ") {
+			stack = popFrames(stack, indent, true)
+			stack = append(stack, frame{indent: indent, token: itemsToken})
+			rest := strings.TrimLeft(strings.TrimPrefix(trimmed, "-"), " ")
+			indent += len(trimmed) - len(rest)
+			trimmed = rest
+			comments, commentIndent = nil, -1
+		}
+		match := keyPattern.FindStringSubmatch(trimmed)
+		if match == nil {
+			comments, commentIndent = nil, -1
+			continue
+		}
+		stack = popFrames(stack, indent, false)
+		key := unquoteKey(match[1])
+		path := ""
+		for _, f := range stack {
+			path += "/" + f.token
+		}
+		path += "/" + escapePointer(key)
+		value := match[2]
+		description := ""
+		if commentIndent == indent && len(comments) > 0 {
+			description = strings.TrimSpace(strings.Join(comments, "\n"))
+		} else if inline := inlineComment(value); len(inline) > 0 {
+			description = inline
+		}
+		if len(description) > 0 {
+			descriptions[path] = description
+		}
+		comments, commentIndent = nil, -1
+		stack = append(stack, frame{indent: indent, token: escapePointer(key)})
+		if blockScalarPattern.MatchString(value) {
+			scalarIndent = indent
+		}
+	}
+	return descriptions
+}
+
+// popFrames pops frames which don't contain a line with indent. A sequence may have the
+// same indent as its key, so a key frame with the same indent contains a sequence item.
+func popFrames(stack []frame, indent int, item bool) []frame {
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		if top.indent < indent || (item && top.indent == indent && top.token != itemsToken) {
+			break
+		}
+		stack = stack[:len(stack)-1]
+	}
+	return stack
+}
+
+// unquoteKey removes quotes of a key
+func unquoteKey(key string) string {
+	switch {
+	case len(key) >= 2 && key[0] == '"':
+		return strings.Replace(key[1:len(key)-1], `\"`, `"`, -1)
+	case len(key) >= 2 && key[0] == '\'':
+		return strings.Replace(key[1:len(key)-1], "''", "'", -1)
+	}
+	return key
+}
+
+// inlineComment returns the comment at the end of a value
+func inlineComment(value string) string {
+	if strings.HasPrefix(value, "#") {
+		return strings.TrimSpace(strings.TrimLeft(value, "#"))
+	}
+	if strings.HasPrefix(value, `"`) || strings.HasPrefix(value, "'") {
+		quote := value[:1]
+		end := strings.LastIndex(value, quote)
+		if end <= 0 {
+			return ""
+		}
+		value = value[end:]
+	}
+	if index := strings.Index(value, " #"); index >= 0 {
+		return strings.TrimSpace(strings.TrimLeft(value[index+1:], "#"))
+	}
+	return ""
+}
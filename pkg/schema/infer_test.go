@@ -0,0 +1,110 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestInfer(t *testing.T) {
+	cases := []struct {
+		name, values, descriptions, schema string
+	}{
+		{"scalars", `{"a":1,"b":1.5,"c":"x","d":true,"e":null}`, `{}`,
+			`{"$schema":"` + Draft + `","type":"object","properties":{` +
+				`"a":{"type":"integer","default":1},"b":{"type":"number","default":1.5},` +
+				`"c":{"type":"string","default":"x"},"d":{"type":"boolean","default":true},"e":{"default":null}}}`},
+		{"arrays", `{"a":[],"b":[1,2.5],"c":[{"x":1},{"x":1.5,"y":"a"}],"d":[1,"a",null],"e":[[],[1]]}`, `{}`,
+			`{"$schema":"` + Draft + `","type":"object","properties":{` +
+				`"a":{"type":"array","default":[]},` +
+				`"b":{"type":"array","default":[1,2.5],"items":{"type":"number"}},` +
+				`"c":{"type":"array","default":[{"x":1},{"x":1.5,"y":"a"}],"items":{"type":"object","properties":{` +
+				`"x":{"type":"number","default":1},"y":{"type":"string","default":"a"}}}},` +
+				`"d":{"type":"array","default":[1,"a",null],"items":{}},` +
+				`"e":{"type":"array","default":[[],[1]],"items":{"type":"array","items":{"type":"integer"}}}}}`},
+		{"descriptions", `{"image":{"tag":"1.0"},"a/b":1,"ports":[{"name":"http"}]}`,
+			`{"":"root","/image":"image","/image/tag":"tag","/a~1b":"slash","/ports/[]":"port","/ports/[]/name":"name"}`,
+			`{"$schema":"` + Draft + `","description":"root","type":"object","properties":{` +
+				`"image":{"description":"image","type":"object","properties":{"tag":{"description":"tag","type":"string","default":"1.0"}}},` +
+				`"a/b":{"description":"slash","type":"integer","default":1},` +
+				`"ports":{"type":"array","default":[{"name":"http"}],"items":{"description":"port","type":"object","properties":{` +
+				`"name":{"description":"name","type":"string","default":"http"}}}}}}`},
+	}
+	for _, c := range cases {
+		var descriptions map[string]string
+		decodeInto(t, c.descriptions, &descriptions)
+		s := Infer(decode(t, c.values), descriptions)
+		if !reflect.DeepEqual(normalize(s), decode(t, c.schema)) {
+			t.Errorf("%s: expected\n%s\ngot\n%s", c.name, c.schema, encode(s))
+			continue
+		}
+		// inferred schemas are valid and accept their defaults
+		parsed, err := New(normalize(s))
+		if err != nil {
+			t.Errorf("%s: invalid schema: %v", c.name, err)
+			continue
+		}
+		if errs := parsed.Validate(decode(t, c.values)); len(errs) > 0 {
+			t.Errorf("%s: values don't satisfy the schema: %v", c.name, errs)
+		}
+	}
+}
+
+func TestInferYAML(t *testing.T) {
+	var values interface{}
+	if err := yaml.Unmarshal([]byte("replicas: 1\nlabels:\n  1: a\n"), &values); err != nil {
+		t.Fatal(err)
+	}
+	s := encode(Infer(values, nil))
+	expected := `{"$schema":"` + Draft + `","properties":{"labels":{"properties":{"1":{"default":"a","type":"string"}},"type":"object"},` +
+		`"replicas":{"default":1,"type":"integer"}},"type":"object"}`
+	if s != expected {
+		t.Fatalf("expected\n%s\ngot\n%s", expected, s)
+	}
+}
+
+func TestDescriptions(t *testing.T) {
+	cases := []struct {
+		name, yaml   string
+		descriptions map[string]string
+	}{
+		{"empty", "", map[string]string{}},
+		{"comments above keys", "# the image\n# of web\nimage:\n  # tag of image\n  tag: \"1.0\"\n  pullPolicy: Always\n",
+			map[string]string{"/image": "the image\nof web", "/image/tag": "tag of image"}},
+		{"inline comments", "a: 1 # one\nb: \"x # y\" # quoted\nc: 'it''s' # single\nd: x#y\ne: # empty\n  f: 1\n",
+			map[string]string{"/a": "one", "/b": "quoted", "/c": "single", "/e": "empty"}},
+		{"comments of other indents", "a:\n# not a description of b\n  b: 1\n  # c\n\n  c: 1\n",
+			map[string]string{}},
+		{"sequences", "ports:\n# a port\n- name: http # name\n  # port number\n  port: 80\n- - x: 1 # nested\n",
+			map[string]string{"/ports/[]/name": "name", "/ports/[]/port": "port number", "/ports/[]/[]/x": "nested"}},
+		{"indented sequences", "a:\n  - b: 1 # b\n    c: 2 # c\nd: 3 # d\n",
+			map[string]string{"/a/[]/b": "b", "/a/[]/c": "c", "/d": "d"}},
+		{"block scalars", "a: | # literal\n  # not a comment\n  b: 1\nc: >-\n  x\n\n  y\nd: 1 # d\n",
+			map[string]string{"/a": "literal", "/d": "d"}},
+		{"quoted keys", "\"a.b\": 1 # dot\n'a/b': 1 # slash\n\"c\\\"d\": 1 # quote\n",
+			map[string]string{"/a.b": "dot", "/a~1b": "slash", "/c\"d": "quote"}},
+		{"documents", "a:\n  b: 1\n---\nb: 1 # root b\n",
+			map[string]string{"/b": "root b"}},
+		{"flow collections", "a: {b: 1} # flow\nc: [1, 2]\n",
+			map[string]string{"/a": "flow"}},
+	}
+	for _, c := range cases {
+		descriptions := Descriptions([]byte(c.yaml))
+		if !reflect.DeepEqual(descriptions, c.descriptions) {
+			t.Errorf("%s: expected %v, got %v", c.name, c.descriptions, descriptions)
+		}
+	}
+}
+
+// decodeInto decodes a json document into result for tests
+func decodeInto(t *testing.T, data string, result interface{}) {
+	if err := json.Unmarshal([]byte(data), result); err != nil {
+		t.Fatalf("invalid json %s: %v", data, err)
+	}
+}
@@ -0,0 +1,225 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+// Package schema validates values by JSON Schema. Most keywords of draft-07 are supported.
+// References must be local, e.g. #/definitions/port, and unknown formats are ignored.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Error is a validation error of a value
+type Error struct {
+	// Path is the dotted path of the invalid value, e.g. image.tag or ports[0]. It's empty
+	// for the root value
+	Path string `json:"path"`
+	// Message describes why the value is invalid
+	Message string `json:"message"`
+}
+
+// String returns a human readable error
+func (e Error) String() string {
+	if len(e.Path) <= 0 {
+		return e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+// Schema is a JSON Schema document
+type Schema struct {
+	// root is the decoded document. It's a map or a bool
+	root interface{}
+}
+
+// Parse parses a JSON Schema document
+func Parse(data []byte) (*Schema, error) {
+	var root interface{}
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+	return New(root)
+}
+
+// New creates a schema from a decoded document
+func New(root interface{}) (*Schema, error) {
+	switch root.(type) {
+	case map[string]interface{}, bool:
+	default:
+		return nil, fmt.Errorf("schema should be an object or a boolean, but got %s", typeOf(root))
+	}
+	s := &Schema{root: root}
+	if err := s.check(root, ""); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Document returns the decoded document of schema
+func (s *Schema) Document() interface{} {
+	return s.root
+}
+
+// check checks whether keywords of a schema have valid types and references can be resolved
+func (s *Schema) check(node interface{}, pointer string) error {
+	schema, ok := node.(map[string]interface{})
+	if !ok {
+		if _, ok := node.(bool); !ok {
+			return fmt.Errorf("schema at %q should be an object or a boolean, but got %s", pointer, typeOf(node))
+		}
+		return nil
+	}
+	if ref, ok := schema["$ref"]; ok {
+		r, ok := ref.(string)
+		if !ok {
+			return fmt.Errorf("$ref at %q should be a string", pointer)
+		}
+		if _, err := s.resolve(r); err != nil {
+			return err
+		}
+	}
+	if pattern, ok := schema["pattern"]; ok {
+		if _, err := compilePattern(pattern); err != nil {
+			return fmt.Errorf("pattern at %q is invalid: %v", pointer, err)
+		}
+	}
+	for _, keyword := range []string{"not", "additionalProperties", "additionalItems", "contains", "propertyNames", "if", "then", "else"} {
+		if sub, ok := schema[keyword]; ok {
+			if err := s.check(sub, pointer+"/"+keyword); err != nil {
+				return err
+			}
+		}
+	}
+	for _, keyword := range []string{"allOf", "anyOf", "oneOf"} {
+		if sub, ok := schema[keyword]; ok {
+			subs, ok := sub.([]interface{})
+			if !ok {
+				return fmt.Errorf("%s at %q should be an array", keyword, pointer)
+			}
+			for i, item := range subs {
+				if err := s.check(item, fmt.Sprintf("%s/%s/%d", pointer, keyword, i)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if items, ok := schema["items"]; ok {
+		if list, ok := items.([]interface{}); ok {
+			for i, item := range list {
+				if err := s.check(item, fmt.Sprintf("%s/items/%d", pointer, i)); err != nil {
+					return err
+				}
+			}
+		} else if err := s.check(items, pointer+"/items"); err != nil {
+			return err
+		}
+	}
+	if dependencies, ok := schema["dependencies"].(map[string]interface{}); ok {
+		for key, dependency := range dependencies {
+			if _, ok := dependency.([]interface{}); ok {
+				continue
+			}
+			if err := s.check(dependency, pointer+"/dependencies/"+escapePointer(key)); err != nil {
+				return err
+			}
+		}
+	}
+	for _, keyword := range []string{"properties", "patternProperties", "definitions", "$defs"} {
+		if sub, ok := schema[keyword]; ok {
+			subs, ok := sub.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("%s at %q should be an object", keyword, pointer)
+			}
+			for key, item := range subs {
+				if keyword == "patternProperties" {
+					if _, err := compilePattern(key); err != nil {
+						return fmt.Errorf("pattern property %q at %q is invalid: %v", key, pointer, err)
+					}
+				}
+				if err := s.check(item, pointer+"/"+keyword+"/"+escapePointer(key)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// resolve resolves a local reference, e.g. #/definitions/port
+func (s *Schema) resolve(ref string) (interface{}, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("only local references are supported, but got %q", ref)
+	}
+	node := s.root
+	pointer := strings.TrimPrefix(ref, "#")
+	if len(pointer) <= 0 {
+		return node, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("reference %q is not a json pointer", ref)
+	}
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = unescapePointer(token)
+		switch n := node.(type) {
+		case map[string]interface{}:
+			next, ok := n[token]
+			if !ok {
+				return nil, fmt.Errorf("reference %q can't be resolved", ref)
+			}
+			node = next
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(n) {
+				return nil, fmt.Errorf("reference %q can't be resolved", ref)
+			}
+			node = n[i]
+		default:
+			return nil, fmt.Errorf("reference %q can't be resolved", ref)
+		}
+	}
+	return node, nil
+}
+
+// escapePointer escapes a token of json pointer
+func escapePointer(token string) string {
+	return strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1)
+}
+
+// unescapePointer unescapes a token of json pointer
+func unescapePointer(token string) string {
+	return strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+}
+
+// Embed returns a copy of document whose local references are relative to pointer. It's used
+// to embed a schema into another schema at pointer, e.g. #/properties/sub.
+func Embed(document interface{}, pointer string) interface{} {
+	switch node := document.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(node))
+		for key, value := range node {
+			if ref, ok := value.(string); ok && key == "$ref" && strings.HasPrefix(ref, "#") {
+				value = "#" + strings.TrimPrefix(pointer, "#") + strings.TrimPrefix(ref, "#")
+			} else {
+				value = Embed(value, pointer)
+			}
+			result[key] = value
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(node))
+		for i, value := range node {
+			result[i] = Embed(value, pointer)
+		}
+		return result
+	}
+	return document
+}
+
+// EscapePointer escapes a key to be a token of json pointer
+func EscapePointer(key string) string {
+	return escapePointer(key)
+}
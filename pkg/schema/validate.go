@@ -0,0 +1,492 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// formats are checkers of string formats. Unknown formats are ignored.
+var formats = map[string]func(string) bool{
+	"date-time": func(s string) bool {
+		_, err := time.Parse(time.RFC3339, s)
+		return err == nil
+	},
+	"date": func(s string) bool {
+		_, err := time.Parse("2006-01-02", s)
+		return err == nil
+	},
+	"email": func(s string) bool {
+		return emailPattern.MatchString(s)
+	},
+	"hostname": func(s string) bool {
+		return len(s) <= 253 && hostnamePattern.MatchString(s)
+	},
+	"ipv4": func(s string) bool {
+		ip := net.ParseIP(s)
+		return ip != nil && ip.To4() != nil && !strings.Contains(s, ":")
+	},
+	"ipv6": func(s string) bool {
+		ip := net.ParseIP(s)
+		return ip != nil && strings.Contains(s, ":")
+	},
+	"uri": func(s string) bool {
+		u, err := url.Parse(s)
+		return err == nil && u.IsAbs()
+	},
+	"regex": func(s string) bool {
+		_, err := regexp.Compile(s)
+		return err == nil
+	},
+}
+
+var (
+	// emailPattern matches email addresses
+	emailPattern = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+	// hostnamePattern matches host names
+	hostnamePattern = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$`)
+)
+
+// maxRefDepth is the max depth of nested references. Deeper references are considered
+// infinite recursions, e.g. {"$ref": "#"}, which would overflow the stack.
+const maxRefDepth = 1000
+
+// compilePattern compiles a regular expression in schema
+func compilePattern(pattern interface{}) (*regexp.Regexp, error) {
+	s, ok := pattern.(string)
+	if !ok {
+		return nil, fmt.Errorf("pattern should be a string, but got %s", typeOf(pattern))
+	}
+	return regexp.Compile(s)
+}
+
+// Validate validates a value. Values decoded from yaml or json are both accepted. Errors are
+// sorted by paths.
+func (s *Schema) Validate(value interface{}) []Error {
+	v := &validator{schema: s}
+	v.validate(s.root, normalize(value), "")
+	sort.SliceStable(v.errs, func(i, j int) bool {
+		return v.errs[i].Path < v.errs[j].Path
+	})
+	return v.errs
+}
+
+// validator collects errors of a validation
+type validator struct {
+	schema *Schema
+	errs   []Error
+	// depth is the depth of nested references
+	depth int
+}
+
+// addf adds an error of the value at path
+func (v *validator) addf(path string, format string, args ...interface{}) {
+	v.errs = append(v.errs, Error{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// matches checks whether a value matches a schema without collecting errors
+func (v *validator) matches(node interface{}, value interface{}, path string) bool {
+	sub := &validator{schema: v.schema, depth: v.depth}
+	sub.validate(node, value, path)
+	return len(sub.errs) <= 0
+}
+
+// validate validates a value at path by a schema node
+func (v *validator) validate(node interface{}, value interface{}, path string) {
+	schema, ok := node.(map[string]interface{})
+	if !ok {
+		if b, ok := node.(bool); ok && !b {
+			v.addf(path, "value is not allowed")
+		}
+		return
+	}
+	if ref, ok := schema["$ref"].(string); ok {
+		target, err := v.schema.resolve(ref)
+		if err != nil {
+			v.addf(path, "%v", err)
+			return
+		}
+		if v.depth >= maxRefDepth {
+			v.addf(path, "reference %q exceeded max depth %d of nested references", ref, maxRefDepth)
+			return
+		}
+		v.depth++
+		v.validate(target, value, path)
+		v.depth--
+	}
+	v.validateGeneric(schema, value, path)
+	switch value := value.(type) {
+	case float64:
+		v.validateNumber(schema, value, path)
+	case string:
+		v.validateString(schema, value, path)
+	case []interface{}:
+		v.validateArray(schema, value, path)
+	case map[string]interface{}:
+		v.validateObject(schema, value, path)
+	}
+}
+
+// validateGeneric validates keywords for all types
+func (v *validator) validateGeneric(schema map[string]interface{}, value interface{}, path string) {
+	if t, ok := schema["type"]; ok {
+		types := []string{}
+		switch t := t.(type) {
+		case string:
+			types = append(types, t)
+		case []interface{}:
+			for _, item := range t {
+				types = append(types, fmt.Sprint(item))
+			}
+		}
+		actual := typeOf(value)
+		matched := false
+		for _, expected := range types {
+			if expected == actual || (expected == "number" && actual == "integer") {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			v.addf(path, "expected %s, but got %s", strings.Join(types, " or "), actual)
+			// other keywords make no sense for a value of another type
+			return
+		}
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		matched := false
+		for _, item := range enum {
+			if equal(item, value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			v.addf(path, "value should be one of %s, but got %s", encode(enum), encode(value))
+		}
+	}
+	if c, ok := schema["const"]; ok && !equal(c, value) {
+		v.addf(path, "value should be %s, but got %s", encode(c), encode(value))
+	}
+	if all, ok := schema["allOf"].([]interface{}); ok {
+		for _, sub := range all {
+			v.validate(sub, value, path)
+		}
+	}
+	if any, ok := schema["anyOf"].([]interface{}); ok {
+		matched := false
+		for _, sub := range any {
+			if v.matches(sub, value, path) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			v.addf(path, "value doesn't match any schema of anyOf")
+		}
+	}
+	if one, ok := schema["oneOf"].([]interface{}); ok {
+		count := 0
+		for _, sub := range one {
+			if v.matches(sub, value, path) {
+				count++
+			}
+		}
+		if count != 1 {
+			v.addf(path, "value should match exactly one schema of oneOf, but matched %d", count)
+		}
+	}
+	if not, ok := schema["not"]; ok && v.matches(not, value, path) {
+		v.addf(path, "value should not match the schema of not")
+	}
+	if cond, ok := schema["if"]; ok {
+		if v.matches(cond, value, path) {
+			if then, ok := schema["then"]; ok {
+				v.validate(then, value, path)
+			}
+		} else if otherwise, ok := schema["else"]; ok {
+			v.validate(otherwise, value, path)
+		}
+	}
+}
+
+// validateNumber validates keywords for numbers
+func (v *validator) validateNumber(schema map[string]interface{}, value float64, path string) {
+	if minimum, ok := schema["minimum"].(float64); ok {
+		if exclusive, _ := schema["exclusiveMinimum"].(bool); exclusive && value <= minimum {
+			v.addf(path, "value should be greater than %v, but got %v", minimum, value)
+		} else if value < minimum {
+			v.addf(path, "value should be greater than or equal to %v, but got %v", minimum, value)
+		}
+	}
+	if minimum, ok := schema["exclusiveMinimum"].(float64); ok && value <= minimum {
+		v.addf(path, "value should be greater than %v, but got %v", minimum, value)
+	}
+	if maximum, ok := schema["maximum"].(float64); ok {
+		if exclusive, _ := schema["exclusiveMaximum"].(bool); exclusive && value >= maximum {
+			v.addf(path, "value should be less than %v, but got %v", maximum, value)
+		} else if value > maximum {
+			v.addf(path, "value should be less than or equal to %v, but got %v", maximum, value)
+		}
+	}
+	if maximum, ok := schema["exclusiveMaximum"].(float64); ok && value >= maximum {
+		v.addf(path, "value should be less than %v, but got %v", maximum, value)
+	}
+	if divisor, ok := schema["multipleOf"].(float64); ok && divisor > 0 {
+		quotient := value / divisor
+		if math.Abs(quotient-math.Round(quotient)) > 1e-9 {
+			v.addf(path, "value should be a multiple of %v, but got %v", divisor, value)
+		}
+	}
+}
+
+// validateString validates keywords for strings
+func (v *validator) validateString(schema map[string]interface{}, value string, path string) {
+	length := utf8.RuneCountInString(value)
+	if minLength, ok := schema["minLength"].(float64); ok && float64(length) < minLength {
+		v.addf(path, "length should be at least %v, but got %d", minLength, length)
+	}
+	if maxLength, ok := schema["maxLength"].(float64); ok && float64(length) > maxLength {
+		v.addf(path, "length should be at most %v, but got %d", maxLength, length)
+	}
+	if pattern, ok := schema["pattern"]; ok {
+		if re, err := compilePattern(pattern); err == nil && !re.MatchString(value) {
+			v.addf(path, "value should match %s, but got %q", re.String(), value)
+		}
+	}
+	if format, ok := schema["format"].(string); ok {
+		if check, ok := formats[format]; ok && !check(value) {
+			v.addf(path, "value should be a valid %s, but got %q", format, value)
+		}
+	}
+}
+
+// validateArray validates keywords for arrays
+func (v *validator) validateArray(schema map[string]interface{}, value []interface{}, path string) {
+	if minItems, ok := schema["minItems"].(float64); ok && float64(len(value)) < minItems {
+		v.addf(path, "array should have at least %v items, but got %d", minItems, len(value))
+	}
+	if maxItems, ok := schema["maxItems"].(float64); ok && float64(len(value)) > maxItems {
+		v.addf(path, "array should have at most %v items, but got %d", maxItems, len(value))
+	}
+	if unique, _ := schema["uniqueItems"].(bool); unique {
+	loop:
+		for i := range value {
+			for j := 0; j < i; j++ {
+				if equal(value[i], value[j]) {
+					v.addf(path, "items should be unique, but items %d and %d are equal", j, i)
+					break loop
+				}
+			}
+		}
+	}
+	switch items := schema["items"].(type) {
+	case []interface{}:
+		for i, item := range value {
+			if i < len(items) {
+				v.validate(items[i], item, indexPath(path, i))
+			} else if additional, ok := schema["additionalItems"]; ok {
+				v.validate(additional, item, indexPath(path, i))
+			}
+		}
+	case nil:
+	default:
+		for i, item := range value {
+			v.validate(items, item, indexPath(path, i))
+		}
+	}
+	if contains, ok := schema["contains"]; ok {
+		matched := false
+		for i, item := range value {
+			if v.matches(contains, item, indexPath(path, i)) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			v.addf(path, "array should contain an item which matches the schema of contains")
+		}
+	}
+}
+
+// validateObject validates keywords for objects
+func (v *validator) validateObject(schema map[string]interface{}, value map[string]interface{}, path string) {
+	if minProperties, ok := schema["minProperties"].(float64); ok && float64(len(value)) < minProperties {
+		v.addf(path, "object should have at least %v properties, but got %d", minProperties, len(value))
+	}
+	if maxProperties, ok := schema["maxProperties"].(float64); ok && float64(len(value)) > maxProperties {
+		v.addf(path, "object should have at most %v properties, but got %d", maxProperties, len(value))
+	}
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, key := range required {
+			name := fmt.Sprint(key)
+			if _, ok := value[name]; !ok {
+				v.addf(keyPath(path, name), "value is required")
+			}
+		}
+	}
+	if dependencies, ok := schema["dependencies"].(map[string]interface{}); ok {
+		for key, dependency := range dependencies {
+			if _, ok := value[key]; !ok {
+				continue
+			}
+			if names, ok := dependency.([]interface{}); ok {
+				for _, name := range names {
+					if _, ok := value[fmt.Sprint(name)]; !ok {
+						v.addf(keyPath(path, fmt.Sprint(name)), "value is required by %s", key)
+					}
+				}
+			} else {
+				v.validate(dependency, value, path)
+			}
+		}
+	}
+	properties, _ := schema["properties"].(map[string]interface{})
+	patterns, _ := schema["patternProperties"].(map[string]interface{})
+	additional, hasAdditional := schema["additionalProperties"]
+	names, hasNames := schema["propertyNames"]
+	keys := make([]string, 0, len(value))
+	for key := range value {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		item := value[key]
+		p := keyPath(path, key)
+		if hasNames && !v.matches(names, key, p) {
+			v.addf(p, "property name %q doesn't match the schema of propertyNames", key)
+		}
+		matched := false
+		if sub, ok := properties[key]; ok {
+			matched = true
+			v.validate(sub, item, p)
+		}
+		for pattern, sub := range patterns {
+			if re, err := regexp.Compile(pattern); err == nil && re.MatchString(key) {
+				matched = true
+				v.validate(sub, item, p)
+			}
+		}
+		if !matched && hasAdditional {
+			if b, ok := additional.(bool); ok && !b {
+				v.addf(p, "property is not allowed")
+			} else {
+				v.validate(additional, item, p)
+			}
+		}
+	}
+}
+
+// keyPath returns the path of a key in an object
+func keyPath(path string, key string) string {
+	if strings.ContainsAny(key, ".[]") {
+		key = fmt.Sprintf("[%q]", key)
+		return path + key
+	}
+	if len(path) <= 0 {
+		return key
+	}
+	return path + "." + key
+}
+
+// indexPath returns the path of an item in an array
+func indexPath(path string, index int) string {
+	return fmt.Sprintf("%s[%d]", path, index)
+}
+
+// normalize converts a value decoded from yaml to the same types as json, e.g. ints to
+// float64 and map[interface{}]interface{} to map[string]interface{}
+func normalize(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			result[key] = normalize(item)
+		}
+		return result
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			result[fmt.Sprint(key)] = normalize(item)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(value))
+		for i, item := range value {
+			result[i] = normalize(item)
+		}
+		return result
+	case nil, bool, string, float64:
+		return value
+	case json.Number:
+		f, _ := value.Float64()
+		return f
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint())
+	case reflect.Float32:
+		return rv.Float()
+	}
+	// other values are converted by json
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var result interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return value
+	}
+	return result
+}
+
+// typeOf returns the json type of a normalized value
+func typeOf(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if value == math.Trunc(value) && !math.IsInf(value, 0) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// equal checks whether two normalized values are equal
+func equal(a, b interface{}) bool {
+	return reflect.DeepEqual(normalize(a), normalize(b))
+}
+
+// encode encodes a value to json for messages
+func encode(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
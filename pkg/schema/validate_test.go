@@ -0,0 +1,194 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+// decode decodes a json document for tests
+func decode(t *testing.T, data string) interface{} {
+	var value interface{}
+	if err := json.Unmarshal([]byte(data), &value); err != nil {
+		t.Fatalf("invalid json %s: %v", data, err)
+	}
+	return value
+}
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		name   string
+		schema string
+		value  string
+		// errs are expected errors. Messages are parts of the messages of errors
+		errs []Error
+	}{
+		{"true schema", `true`, `{"a":1}`, nil},
+		{"false schema", `false`, `1`, []Error{{"", "value is not allowed"}}},
+		{"type", `{"type":"string"}`, `1`, []Error{{"", "expected string, but got integer"}}},
+		{"types", `{"type":["string","null"]}`, `null`, nil},
+		{"integer is a number", `{"type":"number"}`, `1`, nil},
+		{"number is not an integer", `{"type":"integer"}`, `1.5`, []Error{{"", "expected integer, but got number"}}},
+		{"integral number is an integer", `{"type":"integer"}`, `2.0`, nil},
+		{"enum", `{"enum":["a",1]}`, `"b"`, []Error{{"", `value should be one of ["a",1], but got "b"`}}},
+		{"const", `{"const":{"a":[1]}}`, `{"a":[1]}`, nil},
+		{"minimum", `{"minimum":1,"maximum":3}`, `0`, []Error{{"", "greater than or equal to 1"}}},
+		{"maximum", `{"minimum":1,"maximum":3}`, `4`, []Error{{"", "less than or equal to 3"}}},
+		{"boolean exclusive minimum", `{"minimum":1,"exclusiveMinimum":true}`, `1`, []Error{{"", "greater than 1"}}},
+		{"numeric exclusive maximum", `{"exclusiveMaximum":3}`, `3`, []Error{{"", "less than 3"}}},
+		{"multipleOf", `{"multipleOf":0.01}`, `0.07`, nil},
+		{"not multipleOf", `{"multipleOf":2}`, `3`, []Error{{"", "multiple of 2"}}},
+		{"string length", `{"minLength":2,"maxLength":3}`, `"日本語"`, nil},
+		{"short string", `{"minLength":2}`, `"a"`, []Error{{"", "at least 2"}}},
+		{"pattern", `{"pattern":"^v[0-9]+$"}`, `"1"`, []Error{{"", "should match ^v[0-9]+$"}}},
+		{"formats", `{"properties":{"a":{"format":"email"},"b":{"format":"ipv4"},"c":{"format":"hostname"},"d":{"format":"unknown"}}}`,
+			`{"a":"a@b.io","b":"::1","c":"-a","d":"x"}`, []Error{
+				{"b", "valid ipv4"},
+				{"c", "valid hostname"},
+			}},
+		{"date-time", `{"format":"date-time"}`, `"2017-01-02"`, []Error{{"", "valid date-time"}}},
+		{"array", `{"minItems":1,"uniqueItems":true,"items":{"type":"integer"}}`, `[1,"a",1]`, []Error{
+			{"", "items 0 and 2 are equal"},
+			{"[1]", "expected integer"},
+		}},
+		{"tuple", `{"items":[{"type":"string"}],"additionalItems":false}`, `["a",1]`, []Error{{"[1]", "value is not allowed"}}},
+		{"contains", `{"contains":{"const":2}}`, `[1,3]`, []Error{{"", "should contain an item"}}},
+		{"required", `{"required":["a","b.c"]}`, `{"a":null}`, []Error{{`["b.c"]`, "value is required"}}},
+		{"dependencies", `{"dependencies":{"a":["b"],"c":{"required":["d"]}}}`, `{"a":1,"c":2}`, []Error{
+			{"b", "value is required by a"},
+			{"d", "value is required"},
+		}},
+		{"properties", `{"properties":{"a":{"type":"object","properties":{"b":{"type":"string"}}}},"additionalProperties":false}`,
+			`{"a":{"b":1},"x":1}`, []Error{
+				{"a.b", "expected string"},
+				{"x", "property is not allowed"},
+			}},
+		{"pattern properties", `{"patternProperties":{"^x-":{"type":"string"}},"additionalProperties":{"type":"integer"}}`,
+			`{"x-a":"a","b":1,"c":"c"}`, []Error{{"c", "expected integer"}}},
+		{"property names", `{"propertyNames":{"maxLength":1}}`, `{"ab":1}`, []Error{{"ab", `property name "ab"`}}},
+		{"object size", `{"minProperties":2}`, `{"a":1}`, []Error{{"", "at least 2 properties"}}},
+		{"allOf", `{"allOf":[{"minimum":1},{"maximum":0}]}`, `2`, []Error{{"", "less than or equal to 0"}}},
+		{"anyOf", `{"anyOf":[{"type":"string"},{"minimum":5}]}`, `1`, []Error{{"", "any schema of anyOf"}}},
+		{"oneOf", `{"oneOf":[{"type":"integer"},{"minimum":0}]}`, `1`, []Error{{"", "matched 2"}}},
+		{"not", `{"not":{"type":"null"}}`, `null`, []Error{{"", "should not match"}}},
+		{"if then else", `{"if":{"type":"string"},"then":{"minLength":2},"else":{"minimum":2}}`, `1`,
+			[]Error{{"", "greater than or equal to 2"}}},
+		{"references", `{"definitions":{"port":{"type":"integer"}},"properties":{"ports":{"items":{"$ref":"#/definitions/port"}}}}`,
+			`{"ports":[80,"a"]}`, []Error{{"ports[1]", "expected integer"}}},
+		{"recursive references", `{"properties":{"child":{"$ref":"#"},"name":{"type":"string"}}}`,
+			`{"child":{"child":{"name":1}}}`, []Error{{"child.child.name", "expected string"}}},
+		{"infinite references", `{"definitions":{"a":{"$ref":"#/definitions/a"}},"$ref":"#/definitions/a"}`, `1`,
+			[]Error{{"", "exceeded max depth"}}},
+		{"infinite references in anyOf", `{"anyOf":[{"$ref":"#"}]}`, `1`, []Error{{"", "any schema of anyOf"}}},
+	}
+	for _, c := range cases {
+		s, err := Parse([]byte(c.schema))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", c.name, err)
+			continue
+		}
+		errs := s.Validate(decode(t, c.value))
+		if len(errs) != len(c.errs) {
+			t.Errorf("%s: expected %d errors, got %v", c.name, len(c.errs), errs)
+			continue
+		}
+		for i, e := range errs {
+			if e.Path != c.errs[i].Path || !strings.Contains(e.Message, c.errs[i].Message) {
+				t.Errorf("%s: expected error %v, got %v", c.name, c.errs[i], e)
+			}
+		}
+	}
+}
+
+func TestValidateYAML(t *testing.T) {
+	s, err := Parse([]byte(`{"properties":{"replicas":{"type":"integer","minimum":1},"labels":{"additionalProperties":{"type":"string"}}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var values interface{}
+	if err := yaml.Unmarshal([]byte("replicas: 0\nlabels:\n  1: a\n  b: 2\n"), &values); err != nil {
+		t.Fatal(err)
+	}
+	errs := s.Validate(values)
+	expected := []string{"labels.b: expected string, but got integer", "replicas: value should be greater than or equal to 1, but got 0"}
+	if len(errs) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, errs)
+	}
+	for i, e := range errs {
+		if e.String() != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], e.String())
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := []struct {
+		name, schema, message string
+	}{
+		{"invalid json", `{"type":`, "unexpected end"},
+		{"invalid root", `1`, "should be an object or a boolean"},
+		{"invalid subschema", `{"properties":{"a":1}}`, `"/properties/a"`},
+		{"invalid properties", `{"properties":[]}`, "properties"},
+		{"invalid allOf", `{"allOf":{}}`, "should be an array"},
+		{"invalid pattern", `{"items":{"pattern":"("}}`, `pattern at "/items" is invalid`},
+		{"invalid pattern property", `{"patternProperties":{"(":{}}}`, `pattern property "("`},
+		{"invalid reference type", `{"$ref":1}`, "should be a string"},
+		{"remote reference", `{"$ref":"http://x.io/schema.json"}`, "only local references"},
+		{"unresolved reference", `{"not":{"$ref":"#/definitions/a"}}`, "can't be resolved"},
+		{"invalid json pointer", `{"$ref":"#definitions"}`, "not a json pointer"},
+		{"invalid reference in dependencies", `{"dependencies":{"a":{"$ref":"#/x"}}}`, "can't be resolved"},
+	}
+	for _, c := range cases {
+		_, err := Parse([]byte(c.schema))
+		if err == nil {
+			t.Errorf("%s: expected an error", c.name)
+			continue
+		}
+		if !strings.Contains(err.Error(), c.message) {
+			t.Errorf("%s: expected an error with %q, got %q", c.name, c.message, err.Error())
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	s, err := Parse([]byte(`{"definitions":{"a/b":{"items":[{"const":1}]},"c~d":{"const":2}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		ref, result string
+	}{
+		{"#/definitions/a~1b/items/0", `{"const":1}`},
+		{"#/definitions/c~0d", `{"const":2}`},
+	}
+	for _, c := range cases {
+		node, err := s.resolve(c.ref)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", c.ref, err)
+			continue
+		}
+		if encode(node) != c.result {
+			t.Errorf("%s: expected %s, got %s", c.ref, c.result, encode(node))
+		}
+	}
+	for _, ref := range []string{"#/definitions/a~1b/items/1", "#/definitions/a~1b/items/-1", "#/definitions/c~0d/const/x"} {
+		if _, err := s.resolve(ref); err == nil {
+			t.Errorf("%s: expected an error", ref)
+		}
+	}
+}
+
+func TestEmbed(t *testing.T) {
+	document := decode(t, `{"properties":{"a":{"$ref":"#/definitions/a"},"b":{"$ref":"#"}}}`)
+	embedded := encode(Embed(document, "#/properties/sub"))
+	expected := `{"properties":{"a":{"$ref":"#/properties/sub/definitions/a"},"b":{"$ref":"#/properties/sub"}}}`
+	if embedded != expected {
+		t.Fatalf("expected %s, got %s", expected, embedded)
+	}
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+// Package semverutil parses versions and version constraints as helm does. Constraints
+// are used in templates and in filters of versions, so both match versions in the same way.
+package semverutil
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/blang/semver"
+)
+
+var (
+	// constraintPattern matches a constraint in a group, e.g. >= 1.2.x
+	constraintPattern = regexp.MustCompile(`(!=|>=|=>|<=|=<|~>|=|>|<|~|\^)?\s*(v?[0-9xX*]+(?:\.[0-9xX*]+){0,2}(?:-[0-9A-Za-z.-]+)?(?:\+[0-9A-Za-z.-]+)?)`)
+	// rangePattern matches a hyphen range, e.g. 1.2 - 1.4.5
+	rangePattern = regexp.MustCompile(`^\s*(\S+)\s+-\s+(\S+)\s*$`)
+)
+
+// ParseVersion parses a version tolerantly. A leading v and missing minor or patch
+// numbers are allowed, e.g. v1.7 is 1.7.0.
+func ParseVersion(s string) (*semver.Version, error) {
+	c, err := parseConstraintVersion(s)
+	if err != nil {
+		return nil, err
+	}
+	if c.parts < 1 {
+		return nil, fmt.Errorf("%s has no major version", s)
+	}
+	return &c.version, nil
+}
+
+// constraint is a comparison with a version which may have wildcards, e.g. ~1.2.x
+type constraint struct {
+	// op is the comparison operator. An empty op means =
+	op string
+	// version is the version with wildcards replaced by 0
+	version semver.Version
+	// parts is the number of specified parts before wildcards
+	parts int
+}
+
+// parseConstraintVersion parses the version of a constraint
+func parseConstraintVersion(s string) (*constraint, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if index := strings.IndexByte(s, '+'); index >= 0 {
+		s = s[:index]
+	}
+	c := &constraint{}
+	if index := strings.IndexByte(s, '-'); index >= 0 {
+		for _, part := range strings.Split(s[index+1:], ".") {
+			pre, err := semver.NewPRVersion(part)
+			if err != nil {
+				return nil, err
+			}
+			c.version.Pre = append(c.version.Pre, pre)
+		}
+		s = s[:index]
+	}
+	numbers := []*uint64{&c.version.Major, &c.version.Minor, &c.version.Patch}
+	for i, part := range strings.Split(s, ".") {
+		if i >= len(numbers) {
+			return nil, fmt.Errorf("%s has too many parts", s)
+		}
+		if part == "x" || part == "X" || part == "*" {
+			break
+		}
+		n, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s is not a number", part)
+		}
+		*numbers[i] = n
+		c.parts++
+	}
+	return c, nil
+}
+
+// upper returns the smallest version which doesn't match the wildcards of constraint
+func (c *constraint) upper(parts int) semver.Version {
+	v := semver.Version{Major: c.version.Major, Minor: c.version.Minor}
+	if parts <= 1 {
+		v.Major, v.Minor = v.Major+1, 0
+	} else {
+		v.Minor++
+	}
+	return v
+}
+
+// check checks whether a version satisfies the constraint
+func (c *constraint) check(v semver.Version) bool {
+	// prereleases only satisfy constraints with prereleases
+	if len(v.Pre) > 0 && len(c.version.Pre) <= 0 && c.op != "!=" {
+		return false
+	}
+	any := c.parts <= 0
+	exact := c.parts >= 3
+	switch c.op {
+	case "", "=":
+		return any || (exact && v.EQ(c.version)) || (!exact && v.GTE(c.version) && v.LT(c.upper(c.parts)))
+	case "!=":
+		return !any && ((exact && v.NE(c.version)) || (!exact && (v.LT(c.version) || v.GTE(c.upper(c.parts)))))
+	case ">":
+		return !any && ((exact && v.GT(c.version)) || (!exact && v.GTE(c.upper(c.parts))))
+	case ">=", "=>":
+		return v.GTE(c.version)
+	case "<":
+		return !any && v.LT(c.version)
+	case "<=", "=<":
+		return any || (exact && v.LTE(c.version)) || (!exact && v.LT(c.upper(c.parts)))
+	case "~", "~>":
+		parts := c.parts
+		if parts > 2 {
+			parts = 2
+		}
+		return any || (v.GTE(c.version) && v.LT(c.upper(parts)))
+	case "^":
+		return any || (v.GTE(c.version) && v.Major == c.version.Major)
+	}
+	return false
+}
+
+// Constraints are groups of constraints. A version satisfies constraints if it satisfies
+// all constraints in any group.
+type Constraints [][]*constraint
+
+// ParseConstraints parses constraints, e.g. ">= 1.2, < 2.0 || 3.x". Groups are separated
+// by ||, and constraints in a group are separated by commas or spaces. Operators are
+// =, !=, >, >=, <, <=, ~ (patch updates) and ^ (updates of the same major version).
+// Versions may have wildcards, e.g. 1.2.x, and hyphen ranges like 1.2 - 1.4.5 are
+// allowed. Prereleases only satisfy constraints with prereleases, except !=.
+func ParseConstraints(s string) (Constraints, error) {
+	groups := Constraints{}
+	for _, group := range strings.Split(s, "||") {
+		if match := rangePattern.FindStringSubmatch(group); match != nil {
+			group = fmt.Sprintf(">=%s, <=%s", match[1], match[2])
+		}
+		constraints := []*constraint{}
+		for _, match := range constraintPattern.FindAllStringSubmatch(group, -1) {
+			c, err := parseConstraintVersion(match[2])
+			if err != nil {
+				return nil, fmt.Errorf("invalid constraint %q: %v", match[0], err)
+			}
+			c.op = match[1]
+			constraints = append(constraints, c)
+		}
+		if rest := constraintPattern.ReplaceAllString(group, ""); len(strings.Trim(rest, ", ")) > 0 || len(constraints) <= 0 {
+			return nil, fmt.Errorf("invalid constraints %q", s)
+		}
+		groups = append(groups, constraints)
+	}
+	return groups, nil
+}
+
+// Check checks whether a version satisfies constraints
+func (cs Constraints) Check(v semver.Version) bool {
+	for _, group := range cs {
+		satisfied := true
+		for _, c := range group {
+			if !c.check(v) {
+				satisfied = false
+				break
+			}
+		}
+		if satisfied {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package semverutil
+
+import (
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	cases := []struct {
+		version, result string
+		valid           bool
+	}{
+		{"1.2.3", "1.2.3", true},
+		{"v1.7", "1.7.0", true},
+		{" v1 ", "1.0.0", true},
+		{"1.2.3-beta.1", "1.2.3-beta.1", true},
+		{"1.2.3+build.5", "1.2.3", true},
+		{"1.2.3-rc.1+build", "1.2.3-rc.1", true},
+		{"", "", false},
+		{"x", "", false},
+		{"1.2.3.4", "", false},
+		{"1.a", "", false},
+		{"1.2.3-", "", false},
+		{"1.2.3-01", "", false},
+		{"-1", "", false},
+	}
+	for _, c := range cases {
+		v, err := ParseVersion(c.version)
+		if !c.valid {
+			if err == nil {
+				t.Errorf("%q: expected an error, got %s", c.version, v)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", c.version, err)
+			continue
+		}
+		if v.String() != c.result {
+			t.Errorf("%q: expected %s, got %s", c.version, c.result, v)
+		}
+	}
+}
+
+// check checks whether a version satisfies constraints
+func check(constraints, version string) (bool, error) {
+	cs, err := ParseConstraints(constraints)
+	if err != nil {
+		return false, err
+	}
+	v, err := ParseVersion(version)
+	if err != nil {
+		return false, err
+	}
+	return cs.Check(*v), nil
+}
+
+func TestConstraints(t *testing.T) {
+	cases := []struct {
+		constraints, version string
+		satisfied            bool
+	}{
+		{"1.2.3", "1.2.3", true},
+		{"= 1.2.3", "1.2.4", false},
+		{"1.2", "1.2.9", true},
+		{"1.2.x", "1.3.0", false},
+		{"*", "5.0.0", true},
+		{"x", "0.0.1", true},
+		{"!= 1.2.3", "1.2.4", true},
+		{"!= 1.2.x", "1.2.4", false},
+		{"!= 1.2.x", "1.3.0", true},
+		{"> 1.2.3", "1.2.4", true},
+		{"> 1.2", "1.2.9", false},
+		{"> 1.2", "1.3.0", true},
+		{"> *", "1.0.0", false},
+		{">= 1.2.3", "1.2.3", true},
+		{"=> 1.2.3", "1.2.2", false},
+		{"< 1.2.3", "1.2.2", true},
+		{"< 1.2", "1.2.0", false},
+		{"<= 1.2.3", "1.2.3", true},
+		{"<= 1.2", "1.2.9", true},
+		{"=< 1.2", "1.3.0", false},
+		{"~1.2.3", "1.2.9", true},
+		{"~1.2.3", "1.3.0", false},
+		{"~1", "1.9.0", true},
+		{"~> 1.2", "1.2.5", true},
+		{"^1.2.3", "1.9.9", true},
+		{"^1.2.3", "2.0.0", false},
+		{"^1.2.3", "1.2.2", false},
+		{">= 1.2, < 2.0", "1.9.0", true},
+		{">= 1.2 < 2.0", "2.0.0", false},
+		{"< 1.0 || >= 3.0", "3.1.0", true},
+		{"< 1.0 || >= 3.0", "2.0.0", false},
+		{"1.2 - 1.4.5", "1.4.5", true},
+		{"1.2 - 1.4.5", "1.4.6", false},
+		{"v1.7.x", "v1.7.3", true},
+		// prereleases only satisfy constraints with prereleases
+		{">= 1.2.0", "1.3.0-beta", false},
+		{">= 1.2.0-0", "1.3.0-beta", true},
+		{"!= 1.2.0", "1.3.0-beta", true},
+		{">= 1.2.0-beta.2", "1.2.0-beta.10", true},
+		{"~1.2.0", "1.2.5-beta", false},
+		{"^1.0.0", "2.0.0-beta", false},
+		{"^1.0.0-0", "1.5.0-beta", true},
+		// ^ allows updates of the same major version even if it's 0
+		{"^0.2.3", "0.3.0", true},
+	}
+	for _, c := range cases {
+		satisfied, err := check(c.constraints, c.version)
+		if err != nil {
+			t.Errorf("%q %q: unexpected error: %v", c.constraints, c.version, err)
+			continue
+		}
+		if satisfied != c.satisfied {
+			t.Errorf("%q %q: expected %v, got %v", c.constraints, c.version, c.satisfied, satisfied)
+		}
+	}
+}
+
+func TestConstraintsErrors(t *testing.T) {
+	cases := []struct {
+		constraints, version string
+	}{
+		{"", "1.0.0"},
+		{"abc", "1.0.0"},
+		{">= 1.0 ||", "1.0.0"},
+		{">= 1.0.0.0", "1.0.0"},
+		{">= 1.0-", "1.0.0"},
+		{">= 1.0 and < 2.0", "1.0.0"},
+		{">= 1.0", "a.b"},
+		{">= 1.0", ""},
+	}
+	for _, c := range cases {
+		if _, err := check(c.constraints, c.version); err == nil {
+			t.Errorf("%q %q: expected an error", c.constraints, c.version)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package storage
+
+import (
+	"net/url"
+	"path"
+	"strings"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+// DocumentKind is the kind of a document
+type DocumentKind string
+
+const (
+	// DocumentReadme is README.md of a chart
+	DocumentReadme DocumentKind = "readme"
+	// DocumentNotes is templates/NOTES.txt of a chart
+	DocumentNotes DocumentKind = "notes"
+	// DocumentIcon is the icon file which is referenced by Chart.yaml of a chart
+	DocumentIcon DocumentKind = "icon"
+	// DocumentSchema is the json schema of values which is composed from values.schema.json
+	// of a chart and its subcharts
+	DocumentSchema DocumentKind = "schema"
+)
+
+// DocumentKinds contains all kinds of documents
+var DocumentKinds = []DocumentKind{DocumentReadme, DocumentNotes, DocumentIcon, DocumentSchema}
+
+// notesName is the name of NOTES.txt in a chart
+const notesName = "templates/NOTES.txt"
+
+// Document is a document of a version which is extracted from its chart package
+type Document struct {
+	// Kind is the kind of document
+	Kind DocumentKind `json:"kind"`
+	// Path is the path of document in the chart package
+	Path string `json:"path"`
+	// Data is the content of document
+	Data []byte `json:"-"`
+}
+
+// ExtractDocuments extracts README.md, NOTES.txt and icon from a chart. Documents which
+// don't exist in the chart are not in the result. An icon is extracted only if Chart.yaml
+// references a file in the chart, e.g. icon.png or file://icon.png.
+func ExtractDocuments(c *chart.Chart) map[DocumentKind]*Document {
+	documents := make(map[DocumentKind]*Document)
+	for _, file := range c.Files {
+		if strings.EqualFold(file.TypeUrl, "README.md") {
+			documents[DocumentReadme] = &Document{Kind: DocumentReadme, Path: file.TypeUrl, Data: file.Value}
+			break
+		}
+	}
+	for _, template := range c.Templates {
+		if template.Name == notesName {
+			documents[DocumentNotes] = &Document{Kind: DocumentNotes, Path: template.Name, Data: template.Data}
+			break
+		}
+	}
+	if icon := IconPath(c.Metadata.GetIcon()); len(icon) > 0 {
+		for _, file := range c.Files {
+			if file.TypeUrl == icon {
+				documents[DocumentIcon] = &Document{Kind: DocumentIcon, Path: file.TypeUrl, Data: file.Value}
+				break
+			}
+		}
+	}
+	return documents
+}
+
+// IconPath returns the path of icon in a chart. It returns an empty string if the icon
+// is a remote url.
+func IconPath(icon string) string {
+	if len(icon) <= 0 {
+		return ""
+	}
+	u, err := url.Parse(icon)
+	if err != nil {
+		return ""
+	}
+	switch u.Scheme {
+	case "":
+	case "file":
+		icon = strings.TrimPrefix(icon, "file://")
+	default:
+		return ""
+	}
+	icon = path.Clean("/" + icon)
+	return strings.TrimPrefix(icon, "/")
+}
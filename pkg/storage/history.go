@@ -0,0 +1,52 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package storage
+
+import "time"
+
+// EventType defines the type of an event in the history of a chart
+type EventType string
+
+const (
+	// EventTypePromotion means a version is promoted from another space
+	EventTypePromotion EventType = "Promotion"
+	// EventTypeTag means a tag is created or moved to a version
+	EventTypeTag EventType = "Tag"
+	// EventTypeUntag means a tag is removed from a version
+	EventTypeUntag EventType = "Untag"
+	// EventTypeRollback means a version is rolled back to a previous revision
+	EventTypeRollback EventType = "Rollback"
+	// EventTypeMove means the chart is moved from another space, or its space is renamed
+	EventTypeMove EventType = "Move"
+)
+
+// Event describes an operation recorded in the history of a chart
+type Event struct {
+	// Type is the type of the event
+	Type EventType `json:"type"`
+	// Version is the version number which the event is related to. It's empty if the event is
+	// related to the whole chart
+	Version string `json:"version,omitempty"`
+	// Source is the origin (space/chart/version) of the version, or the origin (space/chart)
+	// of a moved chart
+	Source string `json:"source,omitempty"`
+	// Tag is the tag name of tag events
+	Tag string `json:"tag,omitempty"`
+	// Previous is the version which the tag pointed to before the event
+	Previous string `json:"previous,omitempty"`
+	// Revision is the revision which a version is rolled back to
+	Revision int64 `json:"revision,omitempty"`
+	// Time is the time when the event happened
+	Time time.Time `json:"time"`
+}
+
+// NewEvent creates an event which happens now
+func NewEvent(eventType EventType, version string) *Event {
+	return &Event{
+		Type:    eventType,
+		Version: version,
+		Time:    time.Now().UTC(),
+	}
+}
@@ -29,15 +29,26 @@ type base interface {
 type SpaceManager interface {
 	base
 
-	// Create creates specific space
-	Create(ctx context.Context, space string) (Space, error)
+	// Create creates specific space with properties. If properties is nil, default
+	// properties are used.
+	Create(ctx context.Context, space string, properties *SpaceProperties) (Space, error)
 
-	// Delete deletes specific space.
-	Delete(ctx context.Context, space string) error
+	// Delete deletes specific space. If force is false, a space which has packages referenced
+	// by orchestrated versions or requirements in other spaces is not deleted.
+	Delete(ctx context.Context, space string, force bool) error
 
 	// List lists all space names in current space manager
 	List(ctx context.Context) ([]string, error)
 
+	// Rename renames specific space to name. The space with the new name must not exist.
+	// If force is false, a space which has packages referenced by orchestrated versions in
+	// other spaces is not renamed. References of orchestrated versions are moved with the
+	// space. A space which has packages referenced by requirements is never renamed.
+	Rename(ctx context.Context, space string, name string, force bool) (Space, error)
+
+	// Dependencies returns references of all orchestrated versions and requirements of all versions
+	Dependencies(ctx context.Context) ([]*Dependency, error)
+
 	// Space returns a Space to manage specific space
 	Space(ctx context.Context, space string) (Space, error)
 
@@ -53,18 +64,37 @@ type Space interface {
 	// Name returns name of instance
 	Name() string
 
-	// Delete deletes specific chart
-	Delete(ctx context.Context, chart string) error
+	// Delete deletes specific chart. If force is false, a chart which has packages referenced
+	// by orchestrated versions or requirements of other charts is not deleted.
+	Delete(ctx context.Context, chart string, force bool) error
 
 	// List lists all chart names in current space
 	List(ctx context.Context) ([]string, error)
 
+	// Move moves specific chart to the target space. The chart must not exist in the target space.
+	// If force is false, a chart which has packages referenced by orchestrated versions of
+	// other charts is not moved. References of orchestrated versions are moved with the chart.
+	// A chart which has packages referenced by requirements is never moved.
+	Move(ctx context.Context, chart string, target string, force bool) (Chart, error)
+
 	// Exists returns whether the space exists
 	Exists(ctx context.Context) bool
 
+	// Properties returns properties of current space
+	Properties(ctx context.Context) (*SpaceProperties, error)
+
+	// SetProperties replaces properties of current space
+	SetProperties(ctx context.Context, properties *SpaceProperties) error
+
 	// VersionMetadata returns all version metadata in current space
 	VersionMetadata(ctx context.Context) ([]*Metadata, error)
 
+	// Summary returns the summary of current space
+	Summary(ctx context.Context) (*SpaceSummary, error)
+
+	// ChartSummaries returns summaries of all charts in current space
+	ChartSummaries(ctx context.Context) ([]*ChartSummary, error)
+
 	// Chart returns a Chart for managing specific chart
 	Chart(ctx context.Context, chart string) (Chart, error)
 }
@@ -76,8 +106,9 @@ type Chart interface {
 	// Name returns name of instance
 	Name() string
 
-	// Delete deletes specific chart
-	Delete(ctx context.Context, version string) error
+	// Delete deletes specific version. If force is false, a version which is referenced by
+	// orchestrated versions or requirements is not deleted.
+	Delete(ctx context.Context, version string, force bool) error
 
 	// List lists all version numbers in current chart
 	List(ctx context.Context) ([]string, error)
@@ -88,8 +119,27 @@ type Chart interface {
 	// VersionMetadata returns all version metadata in current chart
 	VersionMetadata(ctx context.Context) ([]*Metadata, error)
 
-	// Version returns a Version for managing specific version
+	// VersionSummaries returns summaries of all versions in current chart
+	VersionSummaries(ctx context.Context) ([]*VersionSummary, error)
+
+	// Version returns a Version for managing specific version. version can be a
+	// version number or a tag of the chart.
 	Version(ctx context.Context, version string) (Version, error)
+
+	// Tags returns all tags of current chart. Tags are sorted by name
+	Tags(ctx context.Context) ([]*Tag, error)
+
+	// Tag points the tag to specific version. If the tag exists, it's moved to the version
+	Tag(ctx context.Context, tag string, version string) error
+
+	// Untag removes specific tag
+	Untag(ctx context.Context, tag string) error
+
+	// History returns all events of current chart in chronological order
+	History(ctx context.Context) ([]*Event, error)
+
+	// Record appends an event to the history of current chart
+	Record(ctx context.Context, event *Event) error
 }
 
 // Version defines methods for managing specific version of a chart
@@ -99,8 +149,13 @@ type Version interface {
 	// Number returns version number
 	Number() string
 
-	// PutContent stores chart data
-	PutContent(ctx context.Context, data []byte) error
+	// PutContent stores chart data. options can be nil. If the version exists, its record
+	// keeps the created time, uploader and source.
+	PutContent(ctx context.Context, data []byte, options *PutOptions) error
+
+	// UpdateContent reads chart data, updates it by update and stores the result atomically.
+	// The version must exist. options can be nil.
+	UpdateContent(ctx context.Context, update func(data []byte) ([]byte, error), options *PutOptions) error
 
 	// GetContent gets chart data
 	GetContent(ctx context.Context) ([]byte, error)
@@ -114,6 +169,28 @@ type Version interface {
 	// Metadata returns a Metadata of current chart
 	Metadata(ctx context.Context) (*Metadata, error)
 
+	// Record returns the record of current version
+	Record(ctx context.Context) (*Record, error)
+
+	// OrchestrationConfig returns the orchestration config which created current version
+	OrchestrationConfig(ctx context.Context) ([]byte, error)
+
+	// Revisions returns all revisions of current version in ascending order. The last one
+	// is the current revision
+	Revisions(ctx context.Context) ([]*Revision, error)
+
+	// RevisionContent gets chart data of a revision
+	RevisionContent(ctx context.Context, revision int64) ([]byte, error)
+
 	// Values gets data from values.yaml file which in current chart data
 	Values(ctx context.Context) ([]byte, error)
+
+	// Document gets a document which is extracted from the chart package of current version
+	Document(ctx context.Context, kind DocumentKind) (*Document, error)
+
+	// State returns the state of current version. A version is active by default
+	State(ctx context.Context) (*VersionState, error)
+
+	// SetState sets the state of current version
+	SetState(ctx context.Context, state *VersionState) error
 }
@@ -5,6 +5,7 @@ Copyright 2017 caicloud authors. All rights reserved.
 package storage
 
 import (
+	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/proto/hapi/chart"
 )
 
@@ -12,6 +13,12 @@ import (
 type Metadata struct {
 	chart.Metadata
 	Dependencies []*Metadata `json:"dependencies,omitempty"`
+	// State is the state of the version. It's only available for root charts
+	State *VersionState `json:"state,omitempty"`
+	// Record is the record of the version. It's only available for root charts
+	Record *Record `json:"record,omitempty"`
+	// HasReadme means the chart package has a README.md. It's only available for root charts
+	HasReadme bool `json:"hasReadme,omitempty"`
 }
 
 // CoalesceMetadata coalesces all metadata in chart
@@ -27,3 +34,9 @@ func CoalesceMetadata(chart *chart.Chart) (*Metadata, error) {
 	}
 	return metadata, nil
 }
+
+// CoalesceValues coalesces values of chart and its dependencies. The result is the values
+// which are stored with the version of chart.
+func CoalesceValues(chart *chart.Chart) (chartutil.Values, error) {
+	return chartutil.CoalesceValues(chart, chart.Values)
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package storage
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"time"
+)
+
+// Source describes how a version was stored
+type Source string
+
+const (
+	// SourceUpload means the version was uploaded
+	SourceUpload Source = "upload"
+	// SourceOrchestration means the version was created by an orchestration config
+	SourceOrchestration Source = "orchestration"
+	// SourcePromotion means the version was promoted from another space
+	SourcePromotion Source = "promotion"
+)
+
+// Record is a server-side record of a version
+type Record struct {
+	// Created is the time when the version was created
+	Created time.Time `json:"created"`
+	// Updated is the time when the version was updated last time
+	Updated time.Time `json:"updated"`
+	// Uploader is the user who created the version. It's supplied by the client and not verified
+	Uploader string `json:"uploader,omitempty"`
+	// Updater is the user who updated the version last time. It's supplied by the client and
+	// not verified
+	Updater string `json:"updater,omitempty"`
+	// Size is the size of the chart package in bytes
+	Size int64 `json:"size"`
+	// Digest is the sha256 digest of the chart package
+	Digest string `json:"digest"`
+	// Source describes how the version was created
+	Source Source `json:"source,omitempty"`
+	// Revision is increased every time the chart package is stored
+	Revision int64 `json:"revision"`
+	// References are independent packages which the version was built from. Only versions
+	// created by orchestration have references.
+	References []Reference `json:"references,omitempty"`
+	// Requirements are packages in the registry which are dependencies in requirements.yaml
+	// of the version
+	Requirements []Reference `json:"requirements,omitempty"`
+}
+
+// Reference is an independent package which an orchestrated version was built from, or a
+// package which a version requires in requirements.yaml
+type Reference struct {
+	// Path is the path of chart in the orchestration config, e.g. chartB.chartD. It's empty
+	// for the root chart
+	Path string `json:"path"`
+	// Space is the space of the package
+	Space string `json:"space"`
+	// Chart is the chart of the package
+	Chart string `json:"chart"`
+	// Version is the version number of the package. A tag in the config is resolved to the
+	// version which it referred to when the version was built
+	Version string `json:"version"`
+	// Digest is the digest of the package
+	Digest string `json:"digest,omitempty"`
+	// Requirement identifies whether the package is a dependency in requirements.yaml. Path
+	// is the name of the dependency, or its alias if it has one
+	Requirement bool `json:"requirement,omitempty"`
+}
+
+// Dependency is a reference from a version to a package which it was built from or requires
+type Dependency struct {
+	// Space is the space of the referencing version
+	Space string `json:"space"`
+	// Chart is the chart of the referencing version
+	Chart string `json:"chart"`
+	// Version is the version number of the referencing version
+	Version string `json:"version"`
+	// Reference is the referenced package
+	Reference Reference `json:"reference"`
+}
+
+// Revision is a revision of the chart package of a version. A new revision is created every
+// time the chart package is stored.
+type Revision struct {
+	// Revision is the number of the revision
+	Revision int64 `json:"revision"`
+	// Created is the time when the revision was stored
+	Created time.Time `json:"created"`
+	// Author is the user who stored the revision
+	Author string `json:"author,omitempty"`
+	// Size is the size of the chart package in bytes
+	Size int64 `json:"size"`
+	// Digest is the sha256 digest of the chart package
+	Digest string `json:"digest"`
+	// Current means the revision is the current chart package of the version
+	Current bool `json:"current,omitempty"`
+}
+
+// PutOptions describes options for storing a version
+type PutOptions struct {
+	// Uploader is the user who stores the version as the client claims
+	Uploader string
+	// Source describes how the version is stored
+	Source Source
+	// Digest is the expected digest of current chart package. If it's not empty and the
+	// version has a different package, the version is not stored.
+	Digest string
+	// Create means the version must not exist. If it exists, the version is not stored.
+	Create bool
+	// Config is the orchestration config which creates the version. If it's empty, the
+	// stored config of the version is kept.
+	Config []byte
+	// References are packages which the version is built from. They are stored with Config
+	// and kept if Config is empty.
+	References []Reference
+}
+
+// Digest returns the sha256 digest of data. e.g. sha256:e3b0c442...
+func Digest(data []byte) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(data))
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/caicloud/helm-registry/pkg/schema"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+// SchemaName is the name of the json schema of values in a chart
+const SchemaName = "values.schema.json"
+
+// configKey is the key of values of a chart created by orchestration
+const configKey = "_config"
+
+// ExtractSchema composes a json schema of coalesced values from values.schema.json of a
+// chart and its subcharts. Schemas of subcharts describe the values under their names. If
+// values are orchestrated, which means that each chart keeps its values in _config, schemas
+// describe _config instead. It returns nil if no chart has a schema.
+func ExtractSchema(c *chart.Chart, values map[string]interface{}) (*Document, error) {
+	document, found, err := composeSchema(c, values, "#")
+	if err != nil || !found {
+		return nil, err
+	}
+	if _, err = schema.New(document); err != nil {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return &Document{Kind: DocumentSchema, Path: SchemaName, Data: data}, nil
+}
+
+// ValidateValues validates coalesced values by a schema extracted by ExtractSchema. All
+// invalid values are listed in the error.
+func ValidateValues(document *Document, values map[string]interface{}) error {
+	s, err := schema.Parse(document.Data)
+	if err != nil {
+		return err
+	}
+	errs := s.Validate(values)
+	if len(errs) <= 0 {
+		return nil
+	}
+	messages := make([]string, len(errs))
+	for i, e := range errs {
+		messages[i] = e.String()
+	}
+	return fmt.Errorf("%s", strings.Join(messages, "; "))
+}
+
+// composeSchema composes the schema of a chart which is embedded at pointer. It reports
+// whether the chart or any of its subcharts has a schema.
+func composeSchema(c *chart.Chart, values map[string]interface{}, pointer string) (map[string]interface{}, bool, error) {
+	var own interface{} = map[string]interface{}{}
+	found := false
+	for _, file := range c.Files {
+		if file.TypeUrl != SchemaName {
+			continue
+		}
+		s, err := schema.Parse(file.Value)
+		if err != nil {
+			return nil, false, fmt.Errorf("%s of %s is invalid: %v", SchemaName, c.Metadata.GetName(), err)
+		}
+		own, found = s.Document(), true
+		break
+	}
+	document := map[string]interface{}{}
+	properties := map[string]interface{}{}
+	propertiesPointer := pointer + "/properties"
+	if _, orchestrated := values[configKey]; orchestrated {
+		document["type"] = "object"
+		properties[configKey] = schema.Embed(own, propertiesPointer+"/"+configKey)
+	} else if m, ok := schema.Embed(own, pointer).(map[string]interface{}); ok {
+		document = m
+		if p, ok := document["properties"].(map[string]interface{}); ok {
+			properties = p
+		}
+	} else {
+		// a boolean schema
+		document["allOf"] = []interface{}{own}
+	}
+	for _, dependency := range c.Dependencies {
+		name := dependency.Metadata.GetName()
+		subValues, _ := values[name].(map[string]interface{})
+		subPointer := propertiesPointer + "/" + schema.EscapePointer(name)
+		existing, exists := properties[name]
+		if exists {
+			// the parent schema also describes the values of subchart
+			subPointer += "/allOf/1"
+		}
+		sub, subFound, err := composeSchema(dependency, subValues, subPointer)
+		if err != nil {
+			return nil, false, err
+		}
+		if !subFound {
+			continue
+		}
+		found = true
+		if exists {
+			properties[name] = map[string]interface{}{"allOf": []interface{}{existing, sub}}
+		} else {
+			properties[name] = sub
+		}
+	}
+	if len(properties) > 0 {
+		document["properties"] = properties
+	}
+	return document, found, nil
+}
+
+// InferSchema infers a json schema from coalesced values of a chart. Descriptions are taken
+// from comments in values.yaml of the chart and its subcharts, and comments of a parent chart
+// take precedence.
+func InferSchema(c *chart.Chart, values map[string]interface{}) map[string]interface{} {
+	descriptions := map[string]string{}
+	collectDescriptions(c, values, "", descriptions)
+	return schema.Infer(values, descriptions)
+}
+
+// collectDescriptions collects descriptions of values of a chart whose values are at prefix
+func collectDescriptions(c *chart.Chart, values map[string]interface{}, prefix string, descriptions map[string]string) {
+	own := prefix
+	if _, orchestrated := values[configKey]; orchestrated {
+		own += "/" + configKey
+	}
+	if c.Values != nil {
+		for path, description := range schema.Descriptions([]byte(c.Values.Raw)) {
+			if _, ok := descriptions[own+path]; !ok {
+				descriptions[own+path] = description
+			}
+		}
+	}
+	for _, dependency := range c.Dependencies {
+		name := dependency.Metadata.GetName()
+		subValues, _ := values[name].(map[string]interface{})
+		collectDescriptions(dependency, subValues, prefix+"/"+schema.EscapePointer(name), descriptions)
+	}
+}
@@ -29,8 +29,14 @@ var (
 	ErrorLocking = errors.ErrorLocking
 	// ErrorInvalidStatus defines invalid status error
 	ErrorInvalidStatus = errors.ErrorInvalidStatus
+	// ErrorPreconditionFailed defines that a precondition is not satisfied
+	ErrorPreconditionFailed = errors.ErrorPreconditionFailed
 	// ErrorParamTypeError defines param type error
 	ErrorParamTypeError = errors.ErrorParamTypeError
+	// ErrorUnprocessableEntity defines that a well-formed request can't be applied
+	ErrorUnprocessableEntity = errors.ErrorUnprocessableEntity
 	// ErrorContentNotFound defines not found error
 	ErrorContentNotFound = errors.ErrorContentNotFound
+	// ErrorReferenced defines that a resource can't be changed because others reference it
+	ErrorReferenced = errors.ErrorReferenced
 )
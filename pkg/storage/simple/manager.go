@@ -28,6 +28,21 @@ const managerName = "simple"
 const chartPackageName = "chart.tgz"
 const metadataName = "metadata.dat"
 const valuesName = "values.dat"
+const historyName = "history.dat"
+const propertiesName = "properties.dat"
+const tagsName = "tags.dat"
+const stateName = "state.dat"
+const recordName = "record.dat"
+const orchestrationName = "orchestration.dat"
+
+// previous revisions of a version are stored in revisionsName/<revision>/
+const revisionsName = "revisions"
+const revisionName = "revision.dat"
+
+// documents extracted from the package of a version are stored in documentsName/<kind>,
+// and documentsIndexName lists the documents
+const documentsName = "documents"
+const documentsIndexName = "documents.dat"
 
 // chart status
 const statusName = ".status"
@@ -115,13 +130,19 @@ func (sm *SpaceManager) Kind() string {
 	return managerName
 }
 
-// Create creates a new Space with space name
-func (sm *SpaceManager) Create(ctx context.Context, space string) (storage.Space, error) {
-	lock := sm.Lock.Get(space)
-	if !lock.Lock(sm.LockTimeout) {
+// Create creates a new Space with space name and properties
+func (sm *SpaceManager) Create(ctx context.Context, space string, properties *storage.SpaceProperties) (storage.Space, error) {
+	if properties == nil {
+		properties = storage.NewSpaceProperties()
+	}
+	if err := properties.Validate(); err != nil {
+		return nil, err
+	}
+	unlock, ok := sm.lockInOrder([]string{allSpacesLockName}, []string{space})
+	if !ok {
 		return nil, ErrorLocking.Format("space", space)
 	}
-	defer lock.Unlock()
+	defer unlock()
 	newSpace, err := sm.Space(ctx, space)
 	if err != nil {
 		return nil, err
@@ -130,6 +151,10 @@ func (sm *SpaceManager) Create(ctx context.Context, space string) (storage.Space
 		return nil, ErrorResourceExist.Format(space)
 	}
 	// space does not exist
+	err = putJSON(ctx, sm.Backend, path.Join(sm.Prefix, space, propertiesName), properties)
+	if err != nil {
+		return nil, err
+	}
 	key := path.Join(sm.Prefix, space, statusName)
 	err = sm.Backend.PutContent(ctx, key, []byte(statusSuccess))
 	if err != nil {
@@ -138,14 +163,15 @@ func (sm *SpaceManager) Create(ctx context.Context, space string) (storage.Space
 	return sm.Space(ctx, space)
 }
 
-// Delete deletes specific space.
-func (sm *SpaceManager) Delete(ctx context.Context, space string) error {
-	lock := sm.Lock.Get(space)
-	if !lock.Lock(sm.LockTimeout) {
+// Delete deletes specific space. If force is false, a space which has packages referenced
+// by orchestrated versions or requirements in other spaces is not deleted.
+func (sm *SpaceManager) Delete(ctx context.Context, space string, force bool) error {
+	unlock, ok := sm.lockInOrder([]string{allSpacesLockName}, []string{space})
+	if !ok {
 		return ErrorLocking.Format("space", space)
 	}
-	defer lock.Unlock()
-	return deleteKeys(ctx, sm.Backend, path.Join(sm.Prefix, space), true)
+	defer unlock()
+	return sm.deleteResource(ctx, path.Join(sm.Prefix, space), []string{space}, force)
 }
 
 // List returns all space names
@@ -158,6 +184,78 @@ func (sm *SpaceManager) List(ctx context.Context) ([]string, error) {
 	return list(ctx, sm.Backend, sm.Prefix, validateName, sortNames)
 }
 
+// Rename renames specific space to name. If force is false, a space which has packages
+// referenced by orchestrated versions in other spaces is not renamed. A space which has
+// packages referenced by requirements is never renamed.
+func (sm *SpaceManager) Rename(ctx context.Context, space string, name string, force bool) (storage.Space, error) {
+	if space == name {
+		return nil, ErrorInvalidParam.Format("name", "new name should be different from the old one")
+	}
+	source, err := NewSpace(sm, space)
+	if err != nil {
+		return nil, err
+	}
+	target, err := NewSpace(sm, name)
+	if err != nil {
+		return nil, err
+	}
+	unlock, ok := sm.lockInOrder([]string{allSpacesLockName}, []string{space}, []string{name})
+	if !ok {
+		return nil, ErrorLocking.Format("space", space+", "+name)
+	}
+	defer unlock()
+	if !source.Exists(ctx) {
+		return nil, ErrorContentNotFound.Format(space)
+	}
+	if target.Exists(ctx) {
+		return nil, ErrorResourceExist.Format(name)
+	}
+	err = sm.moveResource(ctx, source.Prefix, target.Prefix, []string{space}, []string{name}, force)
+	if err != nil {
+		return nil, err
+	}
+	// every chart is moved with the space
+	charts, err := list(ctx, sm.Backend, target.Prefix, validateName, sortNames)
+	if err != nil {
+		return nil, err
+	}
+	for _, chartName := range charts {
+		chart, err := NewChart(target, chartName)
+		if err != nil {
+			return nil, err
+		}
+		event := storage.NewEvent(storage.EventTypeMove, "")
+		event.Source = space + "/" + chartName
+		if err = chart.record(ctx, event); err != nil {
+			return nil, err
+		}
+	}
+	return target, nil
+}
+
+// lockInOrder locks all resources for writing in a fixed order to avoid deadlock.
+// If succeed, it returns a function to unlock them.
+func (sm *SpaceManager) lockInOrder(resources ...[]string) (func(), bool) {
+	sort.Slice(resources, func(i, j int) bool {
+		return path.Join(resources[i]...) < path.Join(resources[j]...)
+	})
+	locks := make([]lock.Locker, 0, len(resources))
+	unlock := func() {
+		for i := len(locks) - 1; i >= 0; i-- {
+			locks[i].Unlock()
+		}
+	}
+	for _, res := range resources {
+		l := sm.Lock.Get(res...)
+		if !l.Lock(sm.LockTimeout) {
+			unlock()
+			return nil, false
+		}
+		locks = append(locks, l)
+	}
+	return unlock, true
+}
+
 // Space returns a Space that it can manage specific space
 func (sm *SpaceManager) Space(ctx context.Context, space string) (storage.Space, error) {
 	if !validateName(space) {
@@ -209,14 +307,15 @@ func (s *Space) Name() string {
 	return s.Space
 }
 
-// Delete deletes specific chart
-func (s *Space) Delete(ctx context.Context, chart string) error {
+// Delete deletes specific chart. If force is false, a chart which has packages referenced
+// by orchestrated versions or requirements of other charts is not deleted.
+func (s *Space) Delete(ctx context.Context, chart string, force bool) error {
 	lock := s.SpaceManager.Lock.Get(s.Name(), chart)
 	if !lock.Lock(s.SpaceManager.LockTimeout) {
 		return ErrorLocking.Format("chart", s.Name()+"/"+chart)
 	}
 	defer lock.Unlock()
-	return deleteKeys(ctx, s.SpaceManager.Backend, path.Join(s.Prefix, chart), true)
+	return s.SpaceManager.deleteResource(ctx, path.Join(s.Prefix, chart), []string{s.Name(), chart}, force)
 }
 
 // List returns all chart names
@@ -229,11 +328,98 @@ func (s *Space) List(ctx context.Context) ([]string, error) {
 	return list(ctx, s.SpaceManager.Backend, s.Prefix, validateName, sortNames)
 }
 
+// Move moves specific chart to the target space. If force is false, a chart which has
+// packages referenced by orchestrated versions of other charts is not moved. A chart which
+// has packages referenced by requirements is never moved.
+func (s *Space) Move(ctx context.Context, chart string, target string, force bool) (storage.Chart, error) {
+	if target == s.Name() {
+		return nil, ErrorInvalidParam.Format("target", "target space should be different from the current one")
+	}
+	source, err := NewChart(s, chart)
+	if err != nil {
+		return nil, err
+	}
+	targetSpace, err := NewSpace(s.SpaceManager, target)
+	if err != nil {
+		return nil, err
+	}
+	dest, err := NewChart(targetSpace, chart)
+	if err != nil {
+		return nil, err
+	}
+	unlock, ok := s.SpaceManager.lockInOrder([]string{s.Name(), chart}, []string{target, chart})
+	if !ok {
+		return nil, ErrorLocking.Format("chart", s.Name()+"/"+chart+", "+target+"/"+chart)
+	}
+	defer unlock()
+	if !targetSpace.Exists(ctx) {
+		return nil, ErrorContentNotFound.Format(target)
+	}
+	if !source.Exists(ctx) {
+		return nil, ErrorContentNotFound.Format(s.Name() + "/" + chart)
+	}
+	if dest.Exists(ctx) {
+		return nil, ErrorResourceExist.Format(target + "/" + chart)
+	}
+	err = s.SpaceManager.moveResource(ctx, source.Prefix, dest.Prefix,
+		[]string{s.Name(), chart}, []string{target, chart}, force)
+	if err != nil {
+		return nil, err
+	}
+	event := storage.NewEvent(storage.EventTypeMove, "")
+	event.Source = s.Name() + "/" + chart
+	if err = dest.record(ctx, event); err != nil {
+		return nil, err
+	}
+	return dest, nil
+}
+
 // Exists returns whether the space exists
 func (s *Space) Exists(ctx context.Context) bool {
 	return keyExists(ctx, s.SpaceManager.Backend, s.Prefix)
 }
 
+// Properties returns properties of current space. A space created without properties
+// has default properties.
+func (s *Space) Properties(ctx context.Context) (*storage.SpaceProperties, error) {
+	lock := s.SpaceManager.Lock.Get(s.Name())
+	if !lock.RLock(s.SpaceManager.LockTimeout) {
+		return nil, ErrorLocking.Format("space", s.Name())
+	}
+	defer lock.RUnlock()
+	if !s.Exists(ctx) {
+		return nil, ErrorContentNotFound.Format(s.Name())
+	}
+	properties := storage.NewSpaceProperties()
+	key := path.Join(s.Prefix, propertiesName)
+	if !keyExists(ctx, s.SpaceManager.Backend, key) {
+		return properties, nil
+	}
+	if err := getJSON(ctx, s.SpaceManager.Backend, key, properties); err != nil {
+		return nil, err
+	}
+	return properties, nil
+}
+
+// SetProperties replaces properties of current space
+func (s *Space) SetProperties(ctx context.Context, properties *storage.SpaceProperties) error {
+	if properties == nil {
+		return ErrorNoParameter.Format("properties")
+	}
+	if err := properties.Validate(); err != nil {
+		return err
+	}
+	lock := s.SpaceManager.Lock.Get(s.Name())
+	if !lock.Lock(s.SpaceManager.LockTimeout) {
+		return ErrorLocking.Format("space", s.Name())
+	}
+	defer lock.Unlock()
+	if !s.Exists(ctx) {
+		return ErrorContentNotFound.Format(s.Name())
+	}
+	return putJSON(ctx, s.SpaceManager.Backend, path.Join(s.Prefix, propertiesName), properties)
+}
+
 // VersionMetadata returns all metadata of charts in current Space
 func (s *Space) VersionMetadata(ctx context.Context) ([]*storage.Metadata, error) {
 	list, err := s.List(ctx)
@@ -255,6 +441,57 @@ func (s *Space) VersionMetadata(ctx context.Context) ([]*storage.Metadata, error
 	return mtAll, nil
 }
 
+// Summary returns the summary of current space
+func (s *Space) Summary(ctx context.Context) (*storage.SpaceSummary, error) {
+	properties, err := s.Properties(ctx)
+	if err != nil {
+		return nil, err
+	}
+	charts, err := s.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &storage.SpaceSummary{
+		Name:            s.Name(),
+		SpaceProperties: *properties,
+		Charts:          len(charts),
+	}, nil
+}
+
+// ChartSummaries returns summaries of all charts in current space
+func (s *Space) ChartSummaries(ctx context.Context) ([]*storage.ChartSummary, error) {
+	charts, err := s.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	summaries := make([]*storage.ChartSummary, 0, len(charts))
+	for _, name := range charts {
+		chart, err := NewChart(s, name)
+		if err != nil {
+			return nil, err
+		}
+		versions, err := chart.VersionSummaries(ctx)
+		if err != nil {
+			return nil, err
+		}
+		summary := &storage.ChartSummary{
+			Name:     name,
+			Versions: len(versions),
+		}
+		// versions are in increasing order
+		for i := len(versions) - 1; i >= 0; i-- {
+			if summary.Latest == nil && !versions[i].State.Yanked() {
+				summary.Latest = versions[i]
+			}
+			if record := versions[i].Record; record != nil && record.Updated.After(summary.Updated) {
+				summary.Updated = record.Updated
+			}
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
 // Chart returns a Chart for managing specific chart
 func (s *Space) Chart(ctx context.Context, chart string) (storage.Chart, error) {
 	if !validateName(chart) {
@@ -291,24 +528,41 @@ func (c *Chart) Name() string {
 	return c.Chart
 }
 
-// Delete deletes specific chart
-func (c *Chart) Delete(ctx context.Context, version string) error {
+// Delete deletes specific version. If force is false, a version which is referenced by
+// orchestrated versions or requirements is not deleted.
+func (c *Chart) Delete(ctx context.Context, version string, force bool) error {
 	lock := c.Space.SpaceManager.Lock.Get(c.Space.Name(), c.Name(), version)
 	if !lock.Lock(c.Space.SpaceManager.LockTimeout) {
 		return ErrorLocking.Format("version", c.Space.Name()+"/"+c.Name()+"/"+version)
 	}
-	err := deleteKeys(ctx, c.Space.SpaceManager.Backend, path.Join(c.Prefix, version), true)
+	err := c.Space.SpaceManager.deleteResource(ctx, path.Join(c.Prefix, version),
+		[]string{c.Space.Name(), c.Name(), version}, force)
 	// unlock before return
 	lock.Unlock()
 	if err != nil {
 		return err
 	}
-	versions, err := c.List(ctx)
-	if err == nil && len(versions) <= 0 {
-		// delete chart if has no version
-		return c.Space.Delete(ctx, c.Chart)
+	if err = c.removeTags(ctx, version); err != nil {
+		return err
 	}
-	return err
+	return c.deleteIfEmpty(ctx)
+}
+
+// deleteIfEmpty deletes current chart if it has no version
+func (c *Chart) deleteIfEmpty(ctx context.Context) error {
+	lock := c.Space.SpaceManager.Lock.Get(c.Space.Name(), c.Name())
+	if !lock.Lock(c.Space.SpaceManager.LockTimeout) {
+		return ErrorLocking.Format("chart", c.Space.Name()+"/"+c.Name())
+	}
+	defer lock.Unlock()
+	if !c.Exists(ctx) {
+		return nil
+	}
+	versions, err := list(ctx, c.Space.SpaceManager.Backend, c.Prefix, validateVersion, nil)
+	if err != nil || len(versions) > 0 {
+		return err
+	}
+	return deleteKeys(ctx, c.Space.SpaceManager.Backend, c.Prefix, true)
 }
 
 // List returns all version numbers
@@ -347,14 +601,227 @@ func (c *Chart) VersionMetadata(ctx context.Context) ([]*storage.Metadata, error
 	return mtList, nil
 }
 
-// Version returns a Version for managing specific version
+// VersionSummaries returns summaries of all versions in current chart
+func (c *Chart) VersionSummaries(ctx context.Context) ([]*storage.VersionSummary, error) {
+	list, err := c.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	summaries := make([]*storage.VersionSummary, 0, len(list))
+	for _, number := range list {
+		version, err := NewVersion(c, number)
+		if err != nil {
+			return nil, err
+		}
+		summary, err := version.summary(ctx)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+// Version returns a Version for managing specific version. If version is a tag,
+// returns the Version which the tag points to.
 func (c *Chart) Version(ctx context.Context, version string) (storage.Version, error) {
 	if !validateVersion(version) {
-		return nil, ErrorInvalidParam.Format("version", version)
+		if !validateName(version) {
+			return nil, ErrorInvalidParam.Format("version", version)
+		}
+		tags, err := c.Tags(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range tags {
+			if tag.Name == version {
+				return NewVersion(c, tag.Version)
+			}
+		}
+		return nil, ErrorContentNotFound.Format(fmt.Sprintf("tag %s/%s/%s", c.Space.Name(), c.Name(), version))
 	}
 	return NewVersion(c, version)
 }
 
+// Tags returns all tags of current chart
+func (c *Chart) Tags(ctx context.Context) ([]*storage.Tag, error) {
+	lock := c.Space.SpaceManager.Lock.Get(c.Space.Name(), c.Name())
+	if !lock.RLock(c.Space.SpaceManager.LockTimeout) {
+		return nil, ErrorLocking.Format("chart", c.Space.Name()+"/"+c.Name())
+	}
+	defer lock.RUnlock()
+	tags, err := c.tags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(tags))
+	for name := range tags {
+		names = append(names, name)
+	}
+	result := make([]*storage.Tag, 0, len(tags))
+	for _, name := range sortNames(names) {
+		result = append(result, &storage.Tag{Name: name, Version: tags[name]})
+	}
+	return result, nil
+}
+
+// Tag points the tag to specific version and records it in history
+func (c *Chart) Tag(ctx context.Context, tag string, version string) error {
+	if !validateName(tag) {
+		return ErrorInvalidParam.Format("tag", tag)
+	}
+	if !validateVersion(version) {
+		return ErrorInvalidParam.Format("version", version)
+	}
+	lock := c.Space.SpaceManager.Lock.Get(c.Space.Name(), c.Name())
+	if !lock.Lock(c.Space.SpaceManager.LockTimeout) {
+		return ErrorLocking.Format("chart", c.Space.Name()+"/"+c.Name())
+	}
+	defer lock.Unlock()
+	// versions can't be modified when the chart is locked, so check the status directly
+	data, err := c.Space.SpaceManager.Backend.GetContent(ctx, path.Join(c.Prefix, version, statusName))
+	if err != nil {
+		return ErrorContentNotFound.Format(path.Join(c.Space.Name(), c.Name(), version))
+	}
+	if string(data) != statusSuccess {
+		return ErrorInvalidStatus.Format("version", string(data))
+	}
+	tags, err := c.tags(ctx)
+	if err != nil {
+		return err
+	}
+	previous := tags[tag]
+	if previous == version {
+		return nil
+	}
+	tags[tag] = version
+	if err = putJSON(ctx, c.Space.SpaceManager.Backend, path.Join(c.Prefix, tagsName), tags); err != nil {
+		return err
+	}
+	event := storage.NewEvent(storage.EventTypeTag, version)
+	event.Tag = tag
+	event.Previous = previous
+	return c.record(ctx, event)
+}
+
+// Untag removes specific tag and records it in history
+func (c *Chart) Untag(ctx context.Context, tag string) error {
+	lock := c.Space.SpaceManager.Lock.Get(c.Space.Name(), c.Name())
+	if !lock.Lock(c.Space.SpaceManager.LockTimeout) {
+		return ErrorLocking.Format("chart", c.Space.Name()+"/"+c.Name())
+	}
+	defer lock.Unlock()
+	tags, err := c.tags(ctx)
+	if err != nil {
+		return err
+	}
+	version, ok := tags[tag]
+	if !ok {
+		return ErrorContentNotFound.Format(fmt.Sprintf("tag %s/%s/%s", c.Space.Name(), c.Name(), tag))
+	}
+	delete(tags, tag)
+	if err = putJSON(ctx, c.Space.SpaceManager.Backend, path.Join(c.Prefix, tagsName), tags); err != nil {
+		return err
+	}
+	event := storage.NewEvent(storage.EventTypeUntag, version)
+	event.Tag = tag
+	event.Previous = version
+	return c.record(ctx, event)
+}
+
+// removeTags removes all tags which point to the version
+func (c *Chart) removeTags(ctx context.Context, version string) error {
+	if !keyExists(ctx, c.Space.SpaceManager.Backend, path.Join(c.Prefix, tagsName)) {
+		return nil
+	}
+	lock := c.Space.SpaceManager.Lock.Get(c.Space.Name(), c.Name())
+	if !lock.Lock(c.Space.SpaceManager.LockTimeout) {
+		return ErrorLocking.Format("chart", c.Space.Name()+"/"+c.Name())
+	}
+	defer lock.Unlock()
+	tags, err := c.tags(ctx)
+	if err != nil {
+		return err
+	}
+	names := []string{}
+	for name, v := range tags {
+		if v == version {
+			names = append(names, name)
+		}
+	}
+	if len(names) <= 0 {
+		return nil
+	}
+	for _, name := range sortNames(names) {
+		delete(tags, name)
+		event := storage.NewEvent(storage.EventTypeUntag, version)
+		event.Tag = name
+		event.Previous = version
+		if err = c.record(ctx, event); err != nil {
+			return err
+		}
+	}
+	return putJSON(ctx, c.Space.SpaceManager.Backend, path.Join(c.Prefix, tagsName), tags)
+}
+
+// tags reads tags of current chart. The caller must hold the chart lock
+func (c *Chart) tags(ctx context.Context) (map[string]string, error) {
+	tags := make(map[string]string)
+	key := path.Join(c.Prefix, tagsName)
+	if !keyExists(ctx, c.Space.SpaceManager.Backend, key) {
+		return tags, nil
+	}
+	if err := getJSON(ctx, c.Space.SpaceManager.Backend, key, &tags); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// History returns all events of current chart in chronological order
+func (c *Chart) History(ctx context.Context) ([]*storage.Event, error) {
+	lock := c.Space.SpaceManager.Lock.Get(c.Space.Name(), c.Name())
+	if !lock.RLock(c.Space.SpaceManager.LockTimeout) {
+		return nil, ErrorLocking.Format("chart", c.Space.Name()+"/"+c.Name())
+	}
+	defer lock.RUnlock()
+	return c.history(ctx)
+}
+
+// Record appends an event to the history of current chart
+func (c *Chart) Record(ctx context.Context, event *storage.Event) error {
+	if event == nil {
+		return ErrorNoParameter.Format("event")
+	}
+	lock := c.Space.SpaceManager.Lock.Get(c.Space.Name(), c.Name())
+	if !lock.Lock(c.Space.SpaceManager.LockTimeout) {
+		return ErrorLocking.Format("chart", c.Space.Name()+"/"+c.Name())
+	}
+	defer lock.Unlock()
+	return c.record(ctx, event)
+}
+
+// history reads events of current chart. The caller must hold the chart lock
+func (c *Chart) history(ctx context.Context) ([]*storage.Event, error) {
+	events := []*storage.Event{}
+	key := path.Join(c.Prefix, historyName)
+	if !keyExists(ctx, c.Space.SpaceManager.Backend, key) {
+		return events, nil
+	}
+	if err := getJSON(ctx, c.Space.SpaceManager.Backend, key, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// record appends an event to history. The caller must hold the chart write lock
+func (c *Chart) record(ctx context.Context, event *storage.Event) error {
+	events, err := c.history(ctx)
+	if err != nil {
+		return err
+	}
+	return putJSON(ctx, c.Space.SpaceManager.Backend, path.Join(c.Prefix, historyName), append(events, event))
+}
+
 // Version defines methods for managing specific version of a chart
 type Version struct {
 	Chart   *Chart
@@ -384,38 +851,62 @@ func (v *Version) Number() string {
 	return v.Version
 }
 
-// PutContent stores chart data
-func (v *Version) PutContent(ctx context.Context, data []byte) error {
+// PutContent stores chart data and updates the record of current version
+func (v *Version) PutContent(ctx context.Context, data []byte, options *storage.PutOptions) error {
+	lock := v.Chart.Space.SpaceManager.Lock.Get(v.Chart.Space.Name(), v.Chart.Name(), v.Number())
+	if !lock.Lock(v.Chart.Space.SpaceManager.LockTimeout) {
+		return ErrorLocking.Format("version", v.Chart.Space.Name()+"/"+v.Chart.Name()+"/"+v.Number())
+	}
+	err := v.putContent(ctx, data, options)
+	lock.Unlock()
+	if err != nil && !v.Exists(ctx) {
+		// the failed put removed the version which it created
+		if err := v.Chart.deleteIfEmpty(ctx); err != nil {
+			log.Error(err)
+		}
+	}
+	return err
+}
+
+// UpdateContent reads chart data, updates it by update and stores the result. The version
+// is locked during the whole process.
+func (v *Version) UpdateContent(ctx context.Context, update func(data []byte) ([]byte, error), options *storage.PutOptions) error {
 	lock := v.Chart.Space.SpaceManager.Lock.Get(v.Chart.Space.Name(), v.Chart.Name(), v.Number())
 	if !lock.Lock(v.Chart.Space.SpaceManager.LockTimeout) {
 		return ErrorLocking.Format("version", v.Chart.Space.Name()+"/"+v.Chart.Name()+"/"+v.Number())
 	}
 	defer lock.Unlock()
+	if err := v.validate(ctx); err != nil {
+		return err
+	}
+	data, err := v.Backend.GetContent(ctx, path.Join(v.Prefix, chartPackageName))
+	if err != nil {
+		return ErrorContentNotFound.Format(v.Prefix)
+	}
+	data, err = update(data)
+	if err != nil {
+		return err
+	}
+	return v.putContent(ctx, data, options)
+}
+
+// putContent stores chart data. The caller must hold the lock of current version
+func (v *Version) putContent(ctx context.Context, data []byte, options *storage.PutOptions) error {
 	if len(data) <= 0 {
 		return ErrorNoParameter.Format("data")
 	}
-	// Check whether process succeed
-	var success = false
-	defer func() {
-		if !success {
-			// GC when it's failed
-			err := v.Chart.Delete(ctx, v.Version)
-			if err != nil {
-				log.Error(err)
-			}
-		}
-	}()
-	statusKey := path.Join(v.Prefix, statusName)
-	statusData, err := v.Backend.GetContent(ctx, statusKey)
-	if string(statusData) == statusLocking {
-		return ErrorLocking.Format("chart", v.Chart.Name()+"/"+v.Version)
-	}
-	// Create a `statusName` file with `statusLocking` to lock the place
-	err = v.Backend.PutContent(ctx, statusKey, []byte(statusLocking))
+	// Keep the created time, uploader and source of an existing version
+	record, err := v.record(ctx)
 	if err != nil {
-		return ErrorInternalUnknown.Format(err)
+		return err
+	}
+	if options != nil && options.Create && record != nil {
+		return ErrorResourceExist.Format(v.Chart.Space.Name() + "/" + v.Chart.Name() + "/" + v.Number())
+	}
+	if options != nil && len(options.Digest) > 0 && (record == nil || record.Digest != options.Digest) {
+		return ErrorPreconditionFailed.Format(v.Chart.Space.Name()+"/"+v.Chart.Name()+"/"+v.Number(), "digest mismatch")
 	}
-	// Validate chart
+	// Validate chart before touching the stored version
 	chart, err := chartutil.LoadArchive(bytes.NewReader(data))
 	if err != nil {
 		return ErrorParamTypeError.Format("chart", "gzip", "unknown")
@@ -426,11 +917,88 @@ func (v *Version) PutContent(ctx context.Context, data []byte) error {
 		return ErrorInvalidParam.Format("metadata", err.Error())
 	}
 	// Coalesce values
-	values, err := chartutil.CoalesceValues(chart, chart.Values)
+	values, err := storage.CoalesceValues(chart)
 	if err != nil {
 		return ErrorInvalidParam.Format("values", err.Error())
 	}
-
+	documents := storage.ExtractDocuments(chart)
+	requirements, err := v.Chart.Space.SpaceManager.requirements(ctx, chart)
+	if err != nil {
+		return err
+	}
+	// Validate values by the json schema of chart
+	schema, err := storage.ExtractSchema(chart, values)
+	if err != nil {
+		return ErrorInvalidParam.Format(storage.SchemaName, err.Error())
+	}
+	if schema != nil {
+		if err = storage.ValidateValues(schema, values); err != nil {
+			return ErrorUnprocessableEntity.Format("values", err.Error())
+		}
+		documents[storage.DocumentSchema] = schema
+	}
+	// A version which is being stored or was broken by an interrupted put can't be replaced
+	statusKey := path.Join(v.Prefix, statusName)
+	statusData, err := v.Backend.GetContent(ctx, statusKey)
+	if string(statusData) == statusLocking {
+		return ErrorLocking.Format("chart", v.Chart.Name()+"/"+v.Version)
+	}
+	created := record == nil
+	var previous versionFiles
+	var archived int64
+	if !created {
+		archived = record.Revision
+		// Keep the current package as a revision before replacing it, and keep the stored
+		// files to restore them if the put fails
+		if err = v.archiveRevision(ctx, record); err != nil {
+			return err
+		}
+		if previous, err = v.readFiles(ctx); err != nil {
+			return err
+		}
+	}
+	// Check whether process succeed
+	var success = false
+	defer func() {
+		if success {
+			return
+		}
+		var err error
+		if created {
+			// remove the version which is created by the put
+			err = deleteKeys(ctx, v.Backend, v.Prefix, true)
+		} else {
+			err = v.restoreFiles(ctx, previous, archived)
+		}
+		if err != nil {
+			log.Error(err)
+		}
+	}()
+	now := time.Now().UTC()
+	if record == nil {
+		record = &storage.Record{Created: now}
+		if options != nil {
+			record.Uploader = options.Uploader
+			record.Source = options.Source
+		}
+	}
+	record.Updated = now
+	record.Updater = ""
+	if options != nil {
+		record.Updater = options.Uploader
+	}
+	record.Revision++
+	record.Size = int64(len(data))
+	record.Digest = storage.Digest(data)
+	if options != nil && len(options.Config) > 0 {
+		record.References = options.References
+	}
+	record.Requirements = requirements
+	// Create a `statusName` file with `statusLocking` to lock the place
+	err = v.Backend.PutContent(ctx, statusKey, []byte(statusLocking))
+	if err != nil {
+		return ErrorInternalUnknown.Format(err)
+	}
 	// Store chart
 	err = v.Backend.PutContent(ctx, path.Join(v.Prefix, chartPackageName), data)
 	if err != nil {
@@ -454,16 +1022,93 @@ func (v *Version) PutContent(ctx context.Context, data []byte) error {
 	if err != nil {
 		return ErrorInternalUnknown.Format(err)
 	}
+	// Store documents
+	err = v.putDocuments(ctx, documents)
+	if err != nil {
+		return err
+	}
+	// Store record
+	err = putJSON(ctx, v.Backend, path.Join(v.Prefix, recordName), record)
+	if err != nil {
+		return err
+	}
+	// Store orchestration config
+	if options != nil && len(options.Config) > 0 {
+		err = v.Backend.PutContent(ctx, path.Join(v.Prefix, orchestrationName), options.Config)
+		if err != nil {
+			return ErrorInternalUnknown.Format(err)
+		}
+	}
 	// Write `statusSuccess` to `statusName` file
 	err = v.Backend.PutContent(ctx, statusKey, []byte(statusSuccess))
 	if err != nil {
 		return ErrorInternalUnknown.Format(err)
 	}
+	// Index references and requirements of the version
+	references := append(append([]storage.Reference{}, record.References...), record.Requirements...)
+	err = v.Chart.Space.SpaceManager.updateReferences(ctx, v.Chart.Space.Name(), v.Chart.Name(), v.Number(), references)
+	if err != nil {
+		return err
+	}
 	// Succeed in storing chart
 	success = true
 	return nil
 }
 
+// versionFiles stores files of a version which are replaced by a put. A file which doesn't
+// exist has nil data.
+type versionFiles map[string][]byte
+
+// readFiles reads files of current version which are replaced by a put. The caller must hold
+// the lock of current version
+func (v *Version) readFiles(ctx context.Context) (versionFiles, error) {
+	keys := []string{chartPackageName, metadataName, valuesName, recordName, orchestrationName, documentsIndexName}
+	for _, kind := range storage.DocumentKinds {
+		keys = append(keys, path.Join(documentsName, string(kind)))
+	}
+	files := versionFiles{}
+	for _, key := range keys {
+		key = path.Join(v.Prefix, key)
+		if !keyExists(ctx, v.Backend, key) {
+			files[key] = nil
+			continue
+		}
+		data, err := v.Backend.GetContent(ctx, key)
+		if err != nil {
+			return nil, ErrorInternalUnknown.Format(err)
+		}
+		files[key] = data
+	}
+	return files, nil
+}
+
+// restoreFiles restores files which are read by readFiles, removes the revision which was
+// archived before replacing them and marks current version as stored. The caller must hold
+// the lock of current version
+func (v *Version) restoreFiles(ctx context.Context, files versionFiles, revision int64) error {
+	for key, data := range files {
+		var err error
+		if data != nil {
+			err = v.Backend.PutContent(ctx, key, data)
+		} else if keyExists(ctx, v.Backend, key) {
+			err = v.Backend.Delete(ctx, key)
+		}
+		if err != nil {
+			return ErrorInternalUnknown.Format(err)
+		}
+	}
+	prefix := path.Join(v.Prefix, revisionsName, strconv.FormatInt(revision, 10))
+	if keyExists(ctx, v.Backend, prefix) {
+		if err := v.Backend.Delete(ctx, prefix); err != nil {
+			return ErrorInternalUnknown.Format(err)
+		}
+	}
+	if err := v.Backend.PutContent(ctx, path.Join(v.Prefix, statusName), []byte(statusSuccess)); err != nil {
+		return ErrorInternalUnknown.Format(err)
+	}
+	return nil
+}
+
 // GetContent gets chart data
 func (v *Version) GetContent(ctx context.Context) ([]byte, error) {
 	lock := v.Chart.Space.SpaceManager.Lock.Get(v.Chart.Space.Name(), v.Chart.Name(), v.Number())
@@ -482,6 +1127,250 @@ func (v *Version) GetContent(ctx context.Context) ([]byte, error) {
 	return data, nil
 }
 
+// putDocuments stores documents and removes documents which don't exist any more. The
+// caller must hold the lock of current version
+func (v *Version) putDocuments(ctx context.Context, documents map[storage.DocumentKind]*storage.Document) error {
+	index := []*storage.Document{}
+	for _, kind := range storage.DocumentKinds {
+		key := path.Join(v.Prefix, documentsName, string(kind))
+		document, ok := documents[kind]
+		if !ok {
+			if keyExists(ctx, v.Backend, key) {
+				if err := v.Backend.Delete(ctx, key); err != nil {
+					return ErrorInternalUnknown.Format(err)
+				}
+			}
+			continue
+		}
+		if err := v.Backend.PutContent(ctx, key, document.Data); err != nil {
+			return ErrorInternalUnknown.Format(err)
+		}
+		index = append(index, document)
+	}
+	return putJSON(ctx, v.Backend, path.Join(v.Prefix, documentsIndexName), index)
+}
+
+// documents reads the index of documents. Data of documents is not loaded. A version stored
+// before documents were introduced gets documents with data from its package. The caller
+// must hold the lock of current version
+func (v *Version) documents(ctx context.Context) (map[storage.DocumentKind]*storage.Document, error) {
+	key := path.Join(v.Prefix, documentsIndexName)
+	if keyExists(ctx, v.Backend, key) {
+		index := []*storage.Document{}
+		if err := getJSON(ctx, v.Backend, key, &index); err != nil {
+			return nil, err
+		}
+		documents := make(map[storage.DocumentKind]*storage.Document)
+		for _, document := range index {
+			documents[document.Kind] = document
+		}
+		return documents, nil
+	}
+	data, err := v.Backend.GetContent(ctx, path.Join(v.Prefix, chartPackageName))
+	if err != nil {
+		return nil, ErrorContentNotFound.Format(v.Prefix)
+	}
+	chart, err := chartutil.LoadArchive(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrorInternalUnknown.Format(err)
+	}
+	documents := storage.ExtractDocuments(chart)
+	values, err := storage.CoalesceValues(chart)
+	if err != nil {
+		return documents, nil
+	}
+	if schema, err := storage.ExtractSchema(chart, values); err == nil && schema != nil {
+		documents[storage.DocumentSchema] = schema
+	}
+	return documents, nil
+}
+
+// Document gets a document which is extracted from the chart package of current version
+func (v *Version) Document(ctx context.Context, kind storage.DocumentKind) (*storage.Document, error) {
+	lock := v.Chart.Space.SpaceManager.Lock.Get(v.Chart.Space.Name(), v.Chart.Name(), v.Number())
+	if !lock.RLock(v.Chart.Space.SpaceManager.LockTimeout) {
+		return nil, ErrorLocking.Format("version", v.Chart.Space.Name()+"/"+v.Chart.Name()+"/"+v.Number())
+	}
+	defer lock.RUnlock()
+	if err := v.validate(ctx); err != nil {
+		return nil, err
+	}
+	documents, err := v.documents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	document, ok := documents[kind]
+	if !ok {
+		return nil, ErrorContentNotFound.Format(fmt.Sprintf("%s of %s", kind, v.Prefix))
+	}
+	if document.Data == nil {
+		document.Data, err = v.Backend.GetContent(ctx, path.Join(v.Prefix, documentsName, string(kind)))
+		if err != nil {
+			return nil, ErrorContentNotFound.Format(fmt.Sprintf("%s of %s", kind, v.Prefix))
+		}
+	}
+	return document, nil
+}
+
+// archiveRevision copies the current package to the revisions of current version. The caller
+// must hold the lock of current version
+func (v *Version) archiveRevision(ctx context.Context, record *storage.Record) error {
+	data, err := v.Backend.GetContent(ctx, path.Join(v.Prefix, chartPackageName))
+	if err != nil {
+		return ErrorContentNotFound.Format(v.Prefix)
+	}
+	prefix := path.Join(v.Prefix, revisionsName, strconv.FormatInt(record.Revision, 10))
+	if err = v.Backend.PutContent(ctx, path.Join(prefix, chartPackageName), data); err != nil {
+		return ErrorInternalUnknown.Format(err)
+	}
+	return putJSON(ctx, v.Backend, path.Join(prefix, revisionName), revisionOf(record))
+}
+
+// revisionOf returns the revision of a record
+func revisionOf(record *storage.Record) *storage.Revision {
+	author := record.Updater
+	if len(author) <= 0 && record.Created.Equal(record.Updated) {
+		author = record.Uploader
+	}
+	return &storage.Revision{
+		Revision: record.Revision,
+		Created:  record.Updated,
+		Author:   author,
+		Size:     record.Size,
+		Digest:   record.Digest,
+	}
+}
+
+// Revisions returns all revisions of current version in ascending order
+func (v *Version) Revisions(ctx context.Context) ([]*storage.Revision, error) {
+	lock := v.Chart.Space.SpaceManager.Lock.Get(v.Chart.Space.Name(), v.Chart.Name(), v.Number())
+	if !lock.RLock(v.Chart.Space.SpaceManager.LockTimeout) {
+		return nil, ErrorLocking.Format("version", v.Chart.Space.Name()+"/"+v.Chart.Name()+"/"+v.Number())
+	}
+	defer lock.RUnlock()
+	if err := v.validate(ctx); err != nil {
+		return nil, err
+	}
+	record, err := v.record(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, ErrorContentNotFound.Format(v.Prefix)
+	}
+	revisions := []*storage.Revision{}
+	prefix := path.Join(v.Prefix, revisionsName)
+	if keyExists(ctx, v.Backend, prefix) {
+		numbers, err := list(ctx, v.Backend, prefix, validateRevision, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, number := range numbers {
+			revision := &storage.Revision{}
+			if err = getJSON(ctx, v.Backend, path.Join(prefix, number, revisionName), revision); err != nil {
+				return nil, err
+			}
+			revisions = append(revisions, revision)
+		}
+	}
+	current := revisionOf(record)
+	current.Current = true
+	revisions = append(revisions, current)
+	sort.SliceStable(revisions, func(i, j int) bool {
+		return revisions[i].Revision < revisions[j].Revision
+	})
+	return revisions, nil
+}
+
+// RevisionContent gets chart data of a revision
+func (v *Version) RevisionContent(ctx context.Context, revision int64) ([]byte, error) {
+	lock := v.Chart.Space.SpaceManager.Lock.Get(v.Chart.Space.Name(), v.Chart.Name(), v.Number())
+	if !lock.RLock(v.Chart.Space.SpaceManager.LockTimeout) {
+		return nil, ErrorLocking.Format("version", v.Chart.Space.Name()+"/"+v.Chart.Name()+"/"+v.Number())
+	}
+	defer lock.RUnlock()
+	if err := v.validate(ctx); err != nil {
+		return nil, err
+	}
+	record, err := v.record(ctx)
+	if err != nil {
+		return nil, err
+	}
+	key := path.Join(v.Prefix, revisionsName, strconv.FormatInt(revision, 10), chartPackageName)
+	if record != nil && record.Revision == revision {
+		key = path.Join(v.Prefix, chartPackageName)
+	}
+	data, err := v.Backend.GetContent(ctx, key)
+	if err != nil {
+		return nil, ErrorContentNotFound.Format(fmt.Sprintf("revision %d of %s", revision, v.Prefix))
+	}
+	return data, nil
+}
+
+// summary returns the summary of current version
+func (v *Version) summary(ctx context.Context) (*storage.VersionSummary, error) {
+	metadata, err := v.Metadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &storage.VersionSummary{
+		Version:     v.Number(),
+		AppVersion:  metadata.AppVersion,
+		Description: metadata.Description,
+		State:       metadata.State,
+		Record:      metadata.Record,
+	}, nil
+}
+
+// State returns the state of current version
+func (v *Version) State(ctx context.Context) (*storage.VersionState, error) {
+	lock := v.Chart.Space.SpaceManager.Lock.Get(v.Chart.Space.Name(), v.Chart.Name(), v.Number())
+	if !lock.RLock(v.Chart.Space.SpaceManager.LockTimeout) {
+		return nil, ErrorLocking.Format("version", v.Chart.Space.Name()+"/"+v.Chart.Name()+"/"+v.Number())
+	}
+	defer lock.RUnlock()
+	if err := v.Validate(ctx); err != nil {
+		return nil, err
+	}
+	return v.state(ctx)
+}
+
+// SetState sets the state of current version
+func (v *Version) SetState(ctx context.Context, state *storage.VersionState) error {
+	if state == nil {
+		return ErrorNoParameter.Format("state")
+	}
+	if err := state.Validate(); err != nil {
+		return err
+	}
+	lock := v.Chart.Space.SpaceManager.Lock.Get(v.Chart.Space.Name(), v.Chart.Name(), v.Number())
+	if !lock.Lock(v.Chart.Space.SpaceManager.LockTimeout) {
+		return ErrorLocking.Format("version", v.Chart.Space.Name()+"/"+v.Chart.Name()+"/"+v.Number())
+	}
+	defer lock.Unlock()
+	data, err := v.Backend.GetContent(ctx, path.Join(v.Prefix, statusName))
+	if err != nil {
+		return ErrorContentNotFound.Format(v.Prefix)
+	}
+	if string(data) != statusSuccess {
+		return ErrorInvalidStatus.Format("version", string(data))
+	}
+	return putJSON(ctx, v.Backend, path.Join(v.Prefix, stateName), state)
+}
+
+// state reads the state of current version. The caller must hold the version lock
+func (v *Version) state(ctx context.Context) (*storage.VersionState, error) {
+	state := &storage.VersionState{State: storage.StateActive}
+	key := path.Join(v.Prefix, stateName)
+	if !keyExists(ctx, v.Backend, key) {
+		return state, nil
+	}
+	if err := getJSON(ctx, v.Backend, key, state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
 // Validate validates whether the chart is valid
 func (v *Version) Validate(ctx context.Context) error {
 	lock := v.Chart.Space.SpaceManager.Lock.Get(v.Chart.Space.Name(), v.Chart.Name(), v.Number())
@@ -489,6 +1378,11 @@ func (v *Version) Validate(ctx context.Context) error {
 		return ErrorLocking.Format("version", v.Chart.Space.Name()+"/"+v.Chart.Name()+"/"+v.Number())
 	}
 	defer lock.RUnlock()
+	return v.validate(ctx)
+}
+
+// validate checks the status of current version. The caller must hold the lock of current version
+func (v *Version) validate(ctx context.Context) error {
 	data, err := v.Backend.GetContent(ctx, path.Join(v.Prefix, statusName))
 	if err != nil {
 		return ErrorContentNotFound.Format(v.Prefix)
@@ -525,9 +1419,91 @@ func (v *Version) Metadata(ctx context.Context) (*storage.Metadata, error) {
 	if err != nil {
 		return nil, ErrorInternalUnknown.Format(err)
 	}
+	meta.State, err = v.state(ctx)
+	if err != nil {
+		return nil, err
+	}
+	meta.Record, err = v.record(ctx)
+	if err != nil {
+		return nil, err
+	}
+	documents, err := v.documents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	_, meta.HasReadme = documents[storage.DocumentReadme]
 	return meta, nil
 }
 
+// Record returns the record of current version
+func (v *Version) Record(ctx context.Context) (*storage.Record, error) {
+	lock := v.Chart.Space.SpaceManager.Lock.Get(v.Chart.Space.Name(), v.Chart.Name(), v.Number())
+	if !lock.RLock(v.Chart.Space.SpaceManager.LockTimeout) {
+		return nil, ErrorLocking.Format("version", v.Chart.Space.Name()+"/"+v.Chart.Name()+"/"+v.Number())
+	}
+	defer lock.RUnlock()
+	if err := v.Validate(ctx); err != nil {
+		return nil, err
+	}
+	record, err := v.record(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, ErrorContentNotFound.Format(v.Prefix)
+	}
+	return record, nil
+}
+
+// OrchestrationConfig returns the orchestration config which created current version
+func (v *Version) OrchestrationConfig(ctx context.Context) ([]byte, error) {
+	lock := v.Chart.Space.SpaceManager.Lock.Get(v.Chart.Space.Name(), v.Chart.Name(), v.Number())
+	if !lock.RLock(v.Chart.Space.SpaceManager.LockTimeout) {
+		return nil, ErrorLocking.Format("version", v.Chart.Space.Name()+"/"+v.Chart.Name()+"/"+v.Number())
+	}
+	defer lock.RUnlock()
+	if err := v.Validate(ctx); err != nil {
+		return nil, err
+	}
+	data, err := v.Backend.GetContent(ctx, path.Join(v.Prefix, orchestrationName))
+	if err != nil {
+		return nil, ErrorContentNotFound.Format("orchestration config of " + v.Prefix)
+	}
+	return data, nil
+}
+
+// record reads the record of current version. A version stored before records were
+// introduced gets a record from its package. If the version has no package, returns nil.
+// The caller must hold the version lock
+func (v *Version) record(ctx context.Context) (*storage.Record, error) {
+	key := path.Join(v.Prefix, recordName)
+	if keyExists(ctx, v.Backend, key) {
+		record := &storage.Record{}
+		if err := getJSON(ctx, v.Backend, key, record); err != nil {
+			return nil, err
+		}
+		return record, nil
+	}
+	key = path.Join(v.Prefix, chartPackageName)
+	if !keyExists(ctx, v.Backend, key) {
+		return nil, nil
+	}
+	info, err := v.Backend.Stat(ctx, key)
+	if err != nil {
+		return nil, ErrorInternalUnknown.Format(err)
+	}
+	data, err := v.Backend.GetContent(ctx, key)
+	if err != nil {
+		return nil, ErrorInternalUnknown.Format(err)
+	}
+	return &storage.Record{
+		Created: info.ModTime().UTC(),
+		Updated: info.ModTime().UTC(),
+		Size:    info.Size(),
+		Digest:  storage.Digest(data),
+	}, nil
+}
+
 // Values gets data from values.yaml file which in current chart data
 func (v *Version) Values(ctx context.Context) ([]byte, error) {
 	lock := v.Chart.Space.SpaceManager.Lock.Get(v.Chart.Space.Name(), v.Chart.Name(), v.Number())
@@ -560,6 +1536,12 @@ func validateVersion(version string) bool {
 	return versionFilter.MatchString(version)
 }
 
+// validateRevision checks whether a key is a revision number
+func validateRevision(revision string) bool {
+	_, err := strconv.ParseInt(revision, 10, 64)
+	return err == nil
+}
+
 // lastElement returns the last element of key. Its behavior like path.Base()
 func lastElement(key string) string {
 	key = strings.TrimRight(key, "/\\")
@@ -611,6 +1593,30 @@ func deleteKeys(ctx context.Context, backend driver.StorageDriver, prefix string
 	return nil
 }
 
+// getJSON gets content of key and unmarshals it to obj
+func getJSON(ctx context.Context, backend driver.StorageDriver, key string, obj interface{}) error {
+	data, err := backend.GetContent(ctx, key)
+	if err != nil {
+		return ErrorContentNotFound.Format(key)
+	}
+	if err = json.Unmarshal(data, obj); err != nil {
+		return ErrorInternalUnknown.Format(err)
+	}
+	return nil
+}
+
+// putJSON marshals obj and stores it to key
+func putJSON(ctx context.Context, backend driver.StorageDriver, key string, obj interface{}) error {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return ErrorInternalUnknown.Format(err)
+	}
+	if err = backend.PutContent(ctx, key, data); err != nil {
+		return ErrorInternalUnknown.Format(err)
+	}
+	return nil
+}
+
 // keyExists check whether the key exists
 func keyExists(ctx context.Context, backend driver.StorageDriver, key string) bool {
 	_, err := backend.Stat(ctx, key)
@@ -0,0 +1,443 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package simple
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/caicloud/helm-registry/pkg/log"
+	"github.com/caicloud/helm-registry/pkg/semverutil"
+	"github.com/caicloud/helm-registry/pkg/storage"
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+// references of orchestrated versions and requirements of versions are indexed in
+// referencesName. The file can't be a space because its name is not a valid space name. Its
+// lock is always taken after the locks of resources.
+const referencesName = "references.dat"
+
+// Dependencies returns references of all orchestrated versions and requirements of all versions
+func (sm *SpaceManager) Dependencies(ctx context.Context) ([]*storage.Dependency, error) {
+	lock := sm.Lock.Get(referencesName)
+	if !lock.RLock(sm.LockTimeout) {
+		return nil, ErrorLocking.Format("references", referencesName)
+	}
+	key := path.Join(sm.Prefix, referencesName)
+	if keyExists(ctx, sm.Backend, key) {
+		defer lock.RUnlock()
+		dependencies := []*storage.Dependency{}
+		if err := getJSON(ctx, sm.Backend, key, &dependencies); err != nil {
+			return nil, err
+		}
+		return dependencies, nil
+	}
+	lock.RUnlock()
+	// the index is built by the first caller
+	unlock, err := sm.lockReferences()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return sm.references(ctx)
+}
+
+// lockReferences locks the index of references for writing. If succeed, it returns a
+// function to unlock it.
+func (sm *SpaceManager) lockReferences() (func(), error) {
+	lock := sm.Lock.Get(referencesName)
+	if !lock.Lock(sm.LockTimeout) {
+		return nil, ErrorLocking.Format("references", referencesName)
+	}
+	return lock.Unlock, nil
+}
+
+// references reads the index of references. If the index doesn't exist, it's built from
+// records of all versions. The caller must hold the lock of the index for writing.
+func (sm *SpaceManager) references(ctx context.Context) ([]*storage.Dependency, error) {
+	key := path.Join(sm.Prefix, referencesName)
+	dependencies := []*storage.Dependency{}
+	if keyExists(ctx, sm.Backend, key) {
+		if err := getJSON(ctx, sm.Backend, key, &dependencies); err != nil {
+			return nil, err
+		}
+		return dependencies, nil
+	}
+	if keyExists(ctx, sm.Backend, sm.Prefix) {
+		spaces, err := list(ctx, sm.Backend, sm.Prefix, validateName, sortNames)
+		if err != nil {
+			return nil, err
+		}
+		for _, space := range spaces {
+			found, err := sm.scanReferences(ctx, space)
+			if err != nil {
+				return nil, err
+			}
+			dependencies = append(dependencies, found...)
+		}
+	}
+	if err := putJSON(ctx, sm.Backend, key, dependencies); err != nil {
+		return nil, err
+	}
+	return dependencies, nil
+}
+
+// scanReferences reads references and requirements from records of versions in space.
+// Versions which are being stored or can't be read are skipped.
+func (sm *SpaceManager) scanReferences(ctx context.Context, space string) ([]*storage.Dependency, error) {
+	prefix := path.Join(sm.Prefix, space)
+	charts, err := list(ctx, sm.Backend, prefix, validateName, sortNames)
+	if err != nil {
+		return nil, err
+	}
+	dependencies := []*storage.Dependency{}
+	for _, chart := range charts {
+		versions, err := list(ctx, sm.Backend, path.Join(prefix, chart), validateVersion, sortVersions)
+		if err != nil {
+			return nil, err
+		}
+		for _, version := range versions {
+			key := path.Join(prefix, chart, version)
+			status, err := sm.Backend.GetContent(ctx, path.Join(key, statusName))
+			if err != nil || string(status) != statusSuccess {
+				log.Warnf("references of %s are skipped because its status is invalid", key)
+				continue
+			}
+			if !keyExists(ctx, sm.Backend, path.Join(key, recordName)) {
+				continue
+			}
+			record := &storage.Record{}
+			if err = getJSON(ctx, sm.Backend, path.Join(key, recordName), record); err != nil {
+				log.Warnf("references of %s are skipped: %v", key, err)
+				continue
+			}
+			dependencies = append(dependencies, newDependencies(space, chart, version, record.References)...)
+			dependencies = append(dependencies, newDependencies(space, chart, version, record.Requirements)...)
+		}
+	}
+	return dependencies, nil
+}
+
+// putReferences stores the index of references. The caller must hold the lock of the index
+// for writing.
+func (sm *SpaceManager) putReferences(ctx context.Context, dependencies []*storage.Dependency) error {
+	return putJSON(ctx, sm.Backend, path.Join(sm.Prefix, referencesName), dependencies)
+}
+
+// newDependencies creates dependencies of a version from its references
+func newDependencies(space, chart, version string, references []storage.Reference) []*storage.Dependency {
+	dependencies := make([]*storage.Dependency, 0, len(references))
+	for _, reference := range references {
+		dependencies = append(dependencies, &storage.Dependency{
+			Space:     space,
+			Chart:     chart,
+			Version:   version,
+			Reference: reference,
+		})
+	}
+	return dependencies
+}
+
+// repositorySpace returns the space which a repository in requirements.yaml refers to. A
+// repository of the registry is a space, e.g. http://registry/api/v1/spaces/library. It's
+// empty if the repository is not a space.
+func repositorySpace(repository string) string {
+	u, err := url.Parse(repository)
+	if err != nil || len(u.Host) <= 0 {
+		return ""
+	}
+	elements := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(elements) < 2 || elements[len(elements)-2] != "spaces" || !validateName(elements[len(elements)-1]) {
+		return ""
+	}
+	return elements[len(elements)-1]
+}
+
+// requirements resolves dependencies in requirements.yaml of a chart to packages in the
+// registry. A dependency references the version which is packaged in the chart, or the latest
+// version which satisfies its version range if the chart has no such subchart. Dependencies
+// which are not in spaces of the registry or have no such version are skipped.
+func (sm *SpaceManager) requirements(ctx context.Context, c *chart.Chart) ([]storage.Reference, error) {
+	requirements, err := chartutil.LoadRequirements(c)
+	if err == chartutil.ErrRequirementsNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, ErrorInvalidParam.Format("requirements.yaml", err.Error())
+	}
+	packaged := map[string]string{}
+	for _, dependency := range c.Dependencies {
+		if dependency.Metadata != nil {
+			packaged[dependency.Metadata.Name] = dependency.Metadata.Version
+		}
+	}
+	references := []storage.Reference{}
+	for _, dependency := range requirements.Dependencies {
+		space := repositorySpace(dependency.Repository)
+		if len(space) <= 0 || !validateName(dependency.Name) {
+			continue
+		}
+		version, err := sm.resolveRequirement(ctx, space, dependency.Name, dependency.Version, packaged[dependency.Name])
+		if err != nil {
+			return nil, err
+		}
+		if len(version) <= 0 {
+			continue
+		}
+		name := dependency.Name
+		if len(dependency.Alias) > 0 {
+			name = dependency.Alias
+		}
+		references = append(references, storage.Reference{
+			Path:        name,
+			Space:       space,
+			Chart:       dependency.Name,
+			Version:     version,
+			Requirement: true,
+		})
+	}
+	return references, nil
+}
+
+// resolveRequirement returns the version of a chart in space which a dependency references.
+// constraints is the version range of the dependency, and packaged is the version of the
+// subchart in the package. It's empty if the chart has no such version.
+func (sm *SpaceManager) resolveRequirement(ctx context.Context, space, chart, constraints, packaged string) (string, error) {
+	prefix := path.Join(sm.Prefix, space, chart)
+	if !keyExists(ctx, sm.Backend, prefix) {
+		return "", nil
+	}
+	versions, err := list(ctx, sm.Backend, prefix, validateVersion, sortVersions)
+	if err != nil {
+		return "", err
+	}
+	var ranges semverutil.Constraints
+	if len(strings.TrimSpace(constraints)) > 0 {
+		if ranges, err = semverutil.ParseConstraints(constraints); err != nil {
+			return "", nil
+		}
+	}
+	for i := len(versions) - 1; i >= 0; i-- {
+		if len(packaged) > 0 && versions[i] != packaged {
+			continue
+		}
+		v, err := semverutil.ParseVersion(versions[i])
+		if err != nil || (ranges != nil && !ranges.Check(*v)) {
+			continue
+		}
+		return versions[i], nil
+	}
+	return "", nil
+}
+
+// inScope reports whether the resource of elements is in scope. scope is a space, a chart
+// or a version, e.g. [space, chart].
+func inScope(scope []string, elements ...string) bool {
+	for i, element := range scope {
+		if elements[i] != element {
+			return false
+		}
+	}
+	return true
+}
+
+// describeReferences describes dependencies which reference packages in scope and match
+func describeReferences(dependencies []*storage.Dependency, scope []string, match func(d *storage.Dependency) bool) []string {
+	references := []string{}
+	for _, d := range dependencies {
+		r := d.Reference
+		if !inScope(scope, r.Space, r.Chart, r.Version) || !match(d) {
+			continue
+		}
+		verb := "uses"
+		if r.Requirement {
+			verb = "requires"
+		}
+		description := fmt.Sprintf("%s/%s/%s which %s %s/%s/%s", d.Space, d.Chart, d.Version, verb,
+			r.Space, r.Chart, r.Version)
+		if len(r.Path) > 0 {
+			description += " as " + r.Path
+		}
+		references = append(references, description)
+	}
+	return references
+}
+
+// checkReferences returns an error which lists versions outside scope which reference
+// packages in scope. subject describes scope.
+func checkReferences(dependencies []*storage.Dependency, scope []string, subject string) error {
+	references := describeReferences(dependencies, scope, func(d *storage.Dependency) bool {
+		return !inScope(scope, d.Space, d.Chart, d.Version)
+	})
+	if len(references) > 0 {
+		return ErrorReferenced.Format(subject, strings.Join(references, ", "))
+	}
+	return nil
+}
+
+// checkRequirements returns an error which lists versions which require packages in scope.
+// Requirements are in the packages of versions and can't be rewritten when scope is moved,
+// so versions in scope are listed too. subject describes scope.
+func checkRequirements(dependencies []*storage.Dependency, scope []string, subject string) error {
+	references := describeReferences(dependencies, scope, func(d *storage.Dependency) bool {
+		return d.Reference.Requirement
+	})
+	if len(references) > 0 {
+		return ErrorReferenced.Format(subject, strings.Join(references, ", ")+
+			", and requirements can't be changed by moves")
+	}
+	return nil
+}
+
+// updateReferences replaces references of the version with references and stores the index.
+// Every package which is not referenced by the version yet must exist. The caller must hold
+// the lock of the version.
+func (sm *SpaceManager) updateReferences(ctx context.Context, space, chart, version string, references []storage.Reference) error {
+	unlock, err := sm.lockReferences()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	dependencies, err := sm.references(ctx)
+	if err != nil {
+		return err
+	}
+	scope := []string{space, chart, version}
+	indexed := map[storage.Reference]bool{}
+	result := newDependencies(space, chart, version, references)
+	for _, d := range dependencies {
+		if inScope(scope, d.Space, d.Chart, d.Version) {
+			indexed[d.Reference] = true
+		} else {
+			result = append(result, d)
+		}
+	}
+	for _, r := range references {
+		if !indexed[r] && !keyExists(ctx, sm.Backend, path.Join(sm.Prefix, r.Space, r.Chart, r.Version)) {
+			return ErrorContentNotFound.Format(r.Space + "/" + r.Chart + "/" + r.Version)
+		}
+	}
+	return sm.putReferences(ctx, result)
+}
+
+// deleteResource deletes the resource of scope which is stored in prefix, and removes
+// references of versions in it. If force is false and any package in it is referenced by a
+// version outside, nothing is deleted. The caller must hold the lock of the resource.
+func (sm *SpaceManager) deleteResource(ctx context.Context, prefix string, scope []string, force bool) error {
+	unlock, err := sm.lockReferences()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	dependencies, err := sm.references(ctx)
+	if err != nil {
+		return err
+	}
+	if !force {
+		if err = checkReferences(dependencies, scope, path.Join(scope...)); err != nil {
+			return err
+		}
+	}
+	if err = deleteKeys(ctx, sm.Backend, prefix, true); err != nil {
+		return err
+	}
+	result := make([]*storage.Dependency, 0, len(dependencies))
+	for _, d := range dependencies {
+		if !inScope(scope, d.Space, d.Chart, d.Version) {
+			result = append(result, d)
+		}
+	}
+	return sm.putReferences(ctx, result)
+}
+
+// moveResource moves the resource of scope from source to target. target is the new scope
+// of the resource and has the same level. References of versions in it are relocated, and
+// references to packages in it are rewritten in records of versions too. If force is false
+// and any package in it is referenced by a version outside, nothing is moved. If any package
+// in it is required by a version, nothing is moved even if force is true. The caller must
+// hold locks of both resources.
+func (sm *SpaceManager) moveResource(ctx context.Context, source, dest string, scope, target []string, force bool) error {
+	unlock, err := sm.lockReferences()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	dependencies, err := sm.references(ctx)
+	if err != nil {
+		return err
+	}
+	subject := path.Join(scope...)
+	if err = checkRequirements(dependencies, scope, subject); err != nil {
+		return err
+	}
+	if !force {
+		if err = checkReferences(dependencies, scope, subject); err != nil {
+			return err
+		}
+	}
+	// versions outside which reference packages in the resource are locked to rewrite their records
+	outside := map[string][]string{}
+	for _, d := range dependencies {
+		r := d.Reference
+		if !inScope(scope, d.Space, d.Chart, d.Version) && inScope(scope, r.Space, r.Chart, r.Version) {
+			outside[path.Join(d.Space, d.Chart, d.Version)] = []string{d.Space, d.Chart, d.Version}
+		}
+	}
+	resources := make([][]string, 0, len(outside))
+	for _, resource := range outside {
+		resources = append(resources, resource)
+	}
+	unlockVersions, ok := sm.lockInOrder(resources...)
+	if !ok {
+		return ErrorLocking.Format("versions", "referencing "+subject)
+	}
+	defer unlockVersions()
+	if err = sm.Backend.Move(ctx, source, dest); err != nil {
+		return ErrorInternalUnknown.Format(err)
+	}
+	// records of versions which reference packages in the resource
+	records := map[string]bool{}
+	for _, d := range dependencies {
+		if inScope(scope, d.Space, d.Chart, d.Version) {
+			d.Space, d.Chart = relocate(scope, target, d.Space, d.Chart)
+		}
+		if inScope(scope, d.Reference.Space, d.Reference.Chart, d.Reference.Version) {
+			d.Reference.Space, d.Reference.Chart = relocate(scope, target, d.Reference.Space, d.Reference.Chart)
+			records[path.Join(sm.Prefix, d.Space, d.Chart, d.Version, recordName)] = true
+		}
+	}
+	if err = sm.putReferences(ctx, dependencies); err != nil {
+		return err
+	}
+	for key := range records {
+		record := &storage.Record{}
+		if err = getJSON(ctx, sm.Backend, key, record); err != nil {
+			return err
+		}
+		for i := range record.References {
+			r := &record.References[i]
+			if inScope(scope, r.Space, r.Chart, r.Version) {
+				r.Space, r.Chart = relocate(scope, target, r.Space, r.Chart)
+			}
+		}
+		if err = putJSON(ctx, sm.Backend, key, record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// relocate returns the space and chart of a resource in scope after scope is moved to target
+func relocate(scope, target []string, space, chart string) (string, string) {
+	space = target[0]
+	if len(scope) > 1 {
+		chart = target[1]
+	}
+	return space, chart
+}
@@ -0,0 +1,236 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package simple
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/caicloud/helm-registry/pkg/lock"
+	"github.com/caicloud/helm-registry/pkg/storage"
+	"github.com/docker/distribution/registry/storage/driver/filesystem"
+	"github.com/golang/protobuf/ptypes/any"
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+// newTestManager creates a space manager in a temporary directory. The returned function
+// removes the directory.
+func newTestManager(t *testing.T, spaces ...string) (*SpaceManager, func()) {
+	dir, err := ioutil.TempDir("", "simple")
+	if err != nil {
+		t.Fatal(err)
+	}
+	locker, err := lock.Create("memory", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend := filesystem.New(filesystem.DriverParameters{RootDirectory: dir, MaxThreads: 100})
+	sm := NewSpaceManager(backend, locker, lock.TimeoutImmediate)
+	for _, space := range spaces {
+		if _, err = sm.Create(context.Background(), space, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return sm, func() { os.RemoveAll(dir) }
+}
+
+// newPackage creates a chart package with files. Files are indexed by paths in the chart.
+func newPackage(t *testing.T, name, version string, files map[string]string) []byte {
+	c := &chart.Chart{
+		Metadata: &chart.Metadata{Name: name, Version: version, ApiVersion: chartutil.ApiVersionV1},
+		Values:   &chart.Config{Raw: ""},
+	}
+	for path, content := range files {
+		c.Files = append(c.Files, &any.Any{TypeUrl: path, Value: []byte(content)})
+	}
+	dir, err := ioutil.TempDir("", "chart")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path, err := chartutil.Save(c, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// putVersion stores a package as the version of a chart in space
+func putVersion(t *testing.T, sm *SpaceManager, space, name, version string, data []byte, options *storage.PutOptions) {
+	ctx := context.Background()
+	s, err := sm.Space(ctx, space)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := s.Chart(ctx, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := c.Version(ctx, version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = v.PutContent(ctx, data, options); err != nil {
+		t.Fatalf("%s/%s/%s: %v", space, name, version, err)
+	}
+}
+
+func TestRepositorySpace(t *testing.T) {
+	cases := []struct {
+		repository, space string
+	}{
+		{"http://registry/api/v1/spaces/library", "library"},
+		{"https://registry:8080/api/v1/spaces/library/", "library"},
+		{"http://registry/spaces/library/charts", ""},
+		{"http://registry/api/v1/spaces/-library", ""},
+		{"https://kubernetes-charts.storage.googleapis.com", ""},
+		{"file://../db", ""},
+		{"/spaces/library", ""},
+		{"", ""},
+	}
+	for _, c := range cases {
+		if space := repositorySpace(c.repository); space != c.space {
+			t.Errorf("%q: expected %q, got %q", c.repository, c.space, space)
+		}
+	}
+}
+
+func TestRequirements(t *testing.T) {
+	sm, cleanup := newTestManager(t, "library", "apps", "other")
+	defer cleanup()
+	ctx := context.Background()
+	db := map[string][]byte{}
+	for _, version := range []string{"1.0.0", "1.1.0", "2.0.0"} {
+		db[version] = newPackage(t, "db", version, nil)
+		putVersion(t, sm, "library", "db", version, db[version], nil)
+	}
+	const repository = "http://registry/api/v1/spaces/library"
+	cases := []struct {
+		name         string
+		files        map[string]string
+		requirements []storage.Reference
+	}{
+		{"no requirements", nil, nil},
+		{"latest version in range", map[string]string{
+			"requirements.yaml": "dependencies:\n- name: db\n  version: ^1.0.0\n  repository: " + repository + "\n",
+		}, []storage.Reference{{Path: "db", Space: "library", Chart: "db", Version: "1.1.0", Requirement: true}}},
+		{"packaged version", map[string]string{
+			"requirements.yaml":   "dependencies:\n- name: db\n  version: ^1.0.0\n  repository: " + repository + "\n  alias: mysql\n",
+			"charts/db-1.0.0.tgz": string(db["1.0.0"]),
+		}, []storage.Reference{{Path: "mysql", Space: "library", Chart: "db", Version: "1.0.0", Requirement: true}}},
+		{"other repositories", map[string]string{
+			"requirements.yaml": "dependencies:\n- name: db\n  version: 1.0.0\n  repository: https://kubernetes-charts.storage.googleapis.com\n" +
+				"- name: db\n  version: ^3.0.0\n  repository: " + repository + "\n",
+		}, nil},
+	}
+	for _, c := range cases {
+		putVersion(t, sm, "apps", "web", "1.0.0", newPackage(t, "web", "1.0.0", c.files), nil)
+		record := &storage.Record{}
+		if err := getJSON(ctx, sm.Backend, "/apps/web/1.0.0/"+recordName, record); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(record.Requirements, c.requirements) {
+			t.Errorf("%s: expected requirements %v, got %v", c.name, c.requirements, record.Requirements)
+		}
+		dependencies, err := sm.Dependencies(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(dependencies) != len(c.requirements) {
+			t.Errorf("%s: expected %d dependencies, got %d", c.name, len(c.requirements), len(dependencies))
+		}
+	}
+
+	// web requires library/db/1.1.0
+	putVersion(t, sm, "apps", "web", "1.0.0", newPackage(t, "web", "1.0.0", map[string]string{
+		"requirements.yaml": "dependencies:\n- name: db\n  version: ^1.0.0\n  repository: " + repository + "\n",
+	}), nil)
+	library, err := sm.Space(ctx, "library")
+	if err != nil {
+		t.Fatal(err)
+	}
+	chrt, err := library.Chart(ctx, "db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = chrt.Delete(ctx, "1.1.0", false); !ErrorReferenced.Equal(err) {
+		t.Errorf("expected the required version not to be deleted, got %v", err)
+	}
+	if err = chrt.Delete(ctx, "1.0.0", false); err != nil {
+		t.Errorf("unexpected error of deleting a version which is not required: %v", err)
+	}
+	if err = library.Delete(ctx, "db", false); !ErrorReferenced.Equal(err) {
+		t.Errorf("expected the required chart not to be deleted, got %v", err)
+	}
+	_, err = library.Move(ctx, "db", "other", true)
+	if !ErrorReferenced.Equal(err) || !strings.Contains(err.Error(), "apps/web/1.0.0 which requires library/db/1.1.0 as db") {
+		t.Errorf("expected the required chart not to be moved even if forced, got %v", err)
+	}
+	if _, err = sm.Rename(ctx, "library", "charts", true); !ErrorReferenced.Equal(err) {
+		t.Errorf("expected the space of the required chart not to be renamed even if forced, got %v", err)
+	}
+	if err = library.Delete(ctx, "db", true); err != nil {
+		t.Errorf("unexpected error of forced deletion: %v", err)
+	}
+}
+
+func TestMoveReferences(t *testing.T) {
+	sm, cleanup := newTestManager(t, "library", "apps", "other")
+	defer cleanup()
+	ctx := context.Background()
+	putVersion(t, sm, "library", "db", "1.0.0", newPackage(t, "db", "1.0.0", nil), nil)
+	putVersion(t, sm, "apps", "web", "1.0.0", newPackage(t, "web", "1.0.0", nil), &storage.PutOptions{
+		Source:     storage.SourceOrchestration,
+		Config:     []byte("{}"),
+		References: []storage.Reference{{Path: "db", Space: "library", Chart: "db", Version: "1.0.0"}},
+	})
+	library, err := sm.Space(ctx, "library")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = library.Move(ctx, "db", "other", false); !ErrorReferenced.Equal(err) {
+		t.Fatalf("expected the referenced chart not to be moved, got %v", err)
+	}
+	if _, err = library.Move(ctx, "db", "other", true); err != nil {
+		t.Fatalf("unexpected error of forced move: %v", err)
+	}
+	// the reference of the orchestrated version follows the moved chart
+	expected := storage.Reference{Path: "db", Space: "other", Chart: "db", Version: "1.0.0"}
+	record := &storage.Record{}
+	if err = getJSON(ctx, sm.Backend, "/apps/web/1.0.0/"+recordName, record); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(record.References, []storage.Reference{expected}) {
+		t.Errorf("expected references %v, got %v", []storage.Reference{expected}, record.References)
+	}
+	dependencies, err := sm.Dependencies(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dependencies) != 1 || dependencies[0].Reference != expected {
+		t.Errorf("expected the index to reference %v, got %v", expected, dependencies)
+	}
+	other, err := sm.Space(ctx, "other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = other.Delete(ctx, "db", false); !ErrorReferenced.Equal(err) {
+		t.Errorf("expected the moved chart to be still referenced, got %v", err)
+	}
+	// updating the orchestrated version keeps its references
+	putVersion(t, sm, "apps", "web", "1.0.0", newPackage(t, "web", "1.0.0", nil), nil)
+	if err = other.Delete(ctx, "db", false); !ErrorReferenced.Equal(err) {
+		t.Errorf("expected the chart to be referenced after the update, got %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package storage
+
+import (
+	"github.com/caicloud/helm-registry/pkg/errors"
+)
+
+// Visibility defines who can see a space
+type Visibility string
+
+const (
+	// VisibilityPublic means everyone can see the space
+	VisibilityPublic Visibility = "public"
+	// VisibilityPrivate means only owners can see the space
+	VisibilityPrivate Visibility = "private"
+)
+
+// LintMode defines how charts are linted when they are stored in a space
+type LintMode string
+
+const (
+	// LintOff means charts are not linted
+	LintOff LintMode = "off"
+	// LintWarn means findings of linting are reported but charts are always stored
+	LintWarn LintMode = "warn"
+	// LintError means charts with error findings are rejected
+	LintError LintMode = "error"
+)
+
+// SpaceProperties describes properties of a space
+type SpaceProperties struct {
+	// Description describes what the space is for
+	Description string `json:"description,omitempty"`
+	// Owners is a list of users or teams who own the space
+	Owners []string `json:"owners,omitempty"`
+	// Labels are key/value pairs for classifying spaces
+	Labels map[string]string `json:"labels,omitempty"`
+	// Visibility is the visibility of the space. Default to public
+	Visibility Visibility `json:"visibility"`
+	// Lint is the lint mode of the space. Default to warn
+	Lint LintMode `json:"lint"`
+}
+
+// NewSpaceProperties creates default properties of a space
+func NewSpaceProperties() *SpaceProperties {
+	return &SpaceProperties{Visibility: VisibilityPublic, Lint: LintWarn}
+}
+
+// Validate validates whether the properties are valid. An empty visibility is set to public,
+// and an empty lint mode is set to warn.
+func (p *SpaceProperties) Validate() error {
+	switch p.Visibility {
+	case "":
+		p.Visibility = VisibilityPublic
+	case VisibilityPublic, VisibilityPrivate:
+	default:
+		return errors.ErrorParamValueError.Format("visibility", "public or private", p.Visibility)
+	}
+	switch p.Lint {
+	case "":
+		p.Lint = LintWarn
+	case LintOff, LintWarn, LintError:
+	default:
+		return errors.ErrorParamValueError.Format("lint", "off, warn or error", p.Lint)
+	}
+	for key := range p.Labels {
+		if len(key) <= 0 {
+			return errors.ErrorInvalidParam.Format("labels", "label key can't be empty")
+		}
+	}
+	return nil
+}
+
+// Match returns whether the properties have all labels in selector. A label with empty
+// value in selector matches any value of the same key.
+func (p *SpaceProperties) Match(selector map[string]string) bool {
+	for key, value := range selector {
+		v, ok := p.Labels[key]
+		if !ok || (len(value) > 0 && v != value) {
+			return false
+		}
+	}
+	return true
+}
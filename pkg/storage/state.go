@@ -0,0 +1,63 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package storage
+
+import (
+	"time"
+
+	"github.com/caicloud/helm-registry/pkg/errors"
+)
+
+// State is the state of a version
+type State string
+
+const (
+	// StateActive means the version can be used normally
+	StateActive State = "active"
+	// StateDeprecated means the version should not be used any more
+	StateDeprecated State = "deprecated"
+	// StateYanked means the version is broken. It can only be fetched by exact version
+	// and is excluded from listings and latest versions.
+	StateYanked State = "yanked"
+)
+
+// VersionState describes the state of a version
+type VersionState struct {
+	// State is the state of the version
+	State State `json:"state"`
+	// Reason describes why the version is in the state
+	Reason string `json:"reason,omitempty"`
+	// Time is the time when the state was set. It's nil if the state was never set
+	Time *time.Time `json:"time,omitempty"`
+}
+
+// NewVersionState creates a state which is set now
+func NewVersionState(state State, reason string) *VersionState {
+	now := time.Now().UTC()
+	return &VersionState{
+		State:  state,
+		Reason: reason,
+		Time:   &now,
+	}
+}
+
+// Validate validates whether the state is valid
+func (vs *VersionState) Validate() error {
+	switch vs.State {
+	case StateActive, StateDeprecated, StateYanked:
+		return nil
+	}
+	return errors.ErrorParamValueError.Format("state", "active, deprecated or yanked", vs.State)
+}
+
+// Yanked returns whether the version is yanked
+func (vs *VersionState) Yanked() bool {
+	return vs != nil && vs.State == StateYanked
+}
+
+// Deprecated returns whether the version is deprecated
+func (vs *VersionState) Deprecated() bool {
+	return vs != nil && vs.State == StateDeprecated
+}
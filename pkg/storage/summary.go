@@ -0,0 +1,42 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package storage
+
+import "time"
+
+// VersionSummary describes a version of a chart
+type VersionSummary struct {
+	// Version is version number
+	Version string `json:"version"`
+	// AppVersion is the version of the app in the chart
+	AppVersion string `json:"appVersion,omitempty"`
+	// Description is the description of the chart
+	Description string `json:"description,omitempty"`
+	// State is the state of the version
+	State *VersionState `json:"state,omitempty"`
+	// Record is the record of the version
+	Record *Record `json:"record,omitempty"`
+}
+
+// ChartSummary describes a chart in a space
+type ChartSummary struct {
+	// Name is chart name
+	Name string `json:"name"`
+	// Versions is the number of versions in the chart
+	Versions int `json:"versions"`
+	// Updated is the last time when any version of the chart was updated
+	Updated time.Time `json:"updated"`
+	// Latest is the latest version which is not yanked. It's nil if all versions are yanked
+	Latest *VersionSummary `json:"latest,omitempty"`
+}
+
+// SpaceSummary describes a space
+type SpaceSummary struct {
+	// Name is space name
+	Name string `json:"name"`
+	SpaceProperties
+	// Charts is the number of charts in the space
+	Charts int `json:"charts"`
+}
@@ -0,0 +1,13 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package storage
+
+// Tag is a mutable name which points to a version of a chart
+type Tag struct {
+	// Name is tag name
+	Name string `json:"name"`
+	// Version is the version number which the tag points to
+	Version string `json:"version"`
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package chart_test
+
+import (
+	"os"
+
+	"github.com/caicloud/helm-registry/pkg/rest"
+	"github.com/caicloud/helm-registry/pkg/rest/v1"
+	"github.com/caicloud/helm-registry/test/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+var _ = Describe("Concurrency", func() {
+	const (
+		space   = "concurrency"
+		web     = "web"
+		version = "1.0.0"
+	)
+	var (
+		endpoint = ""
+		client   *v1.Client
+	)
+	BeforeEach(func() {
+		By("getting registry host from env")
+		endpoint = os.Getenv(EnvEndpoint)
+		Expect(endpoint).NotTo(BeEmpty())
+		cli, err := v1.NewClient(endpoint)
+		Expect(err).To(BeNil())
+		client = cli
+	})
+
+	Context("upload version", func() {
+		It("should upload version", func() {
+			data, err := utils.Package(web, version, "replicas: 1\n", nil)
+			Expect(err).To(BeNil())
+			_, err = client.UploadChart(space, data)
+			Expect(err).To(BeNil())
+		})
+	})
+
+	Context("update metadata conditionally", func() {
+		It("should update metadata which matches the entity tag", func() {
+			metadata, etag, err := client.FetchVersionMetadataWithETag(space, web, version)
+			Expect(err).To(BeNil())
+			Expect(etag).NotTo(BeEmpty())
+
+			metadata.Description = "edited by alice"
+			updated, newTag, err := client.UpdateVersionMetadataIfMatch(space, web, version, &metadata.Metadata, etag)
+			Expect(err).To(BeNil())
+			Expect(updated.Description).To(Equal("edited by alice"))
+			Expect(newTag).NotTo(BeEmpty())
+			Expect(newTag).NotTo(Equal(etag))
+
+			// another editor updates metadata with the stale entity tag
+			metadata.Description = "edited by bob"
+			_, _, err = client.UpdateVersionMetadataIfMatch(space, web, version, &metadata.Metadata, etag)
+			Expect(rest.ErrorPreconditionFailed.Equal(err)).To(BeTrue())
+
+			current, currentTag, err := client.FetchVersionMetadataWithETag(space, web, version)
+			Expect(err).To(BeNil())
+			Expect(current.Description).To(Equal("edited by alice"))
+			Expect(currentTag).To(Equal(newTag))
+		})
+		It("should update metadata unconditionally without entity tags", func() {
+			_, err := client.UpdateVersionMetadata(space, web, version, &chart.Metadata{
+				Name:        web,
+				Version:     version,
+				Description: "edited by carol",
+			})
+			Expect(err).To(BeNil())
+		})
+	})
+
+	Context("update values conditionally", func() {
+		It("should update values which match the entity tag", func() {
+			_, etag, err := client.FetchVersionValuesWithETag(space, web, version)
+			Expect(err).To(BeNil())
+			Expect(etag).NotTo(BeEmpty())
+
+			_, newTag, err := client.UpdateVersionValuesIfMatch(space, web, version, []byte(`{"replicas":2}`), etag)
+			Expect(err).To(BeNil())
+			Expect(newTag).NotTo(Equal(etag))
+
+			_, _, err = client.UpdateVersionValuesIfMatch(space, web, version, []byte(`{"replicas":3}`), etag)
+			Expect(rest.ErrorPreconditionFailed.Equal(err)).To(BeTrue())
+
+			values, err := client.FetchVersionValues(space, web, version)
+			Expect(err).To(BeNil())
+			Expect(values).To(MatchJSON(`{"replicas":2}`))
+		})
+		It("should update values after metadata is updated", func() {
+			// entity tags of values only change with values
+			_, etag, err := client.FetchVersionValuesWithETag(space, web, version)
+			Expect(err).To(BeNil())
+			_, err = client.UpdateVersionMetadata(space, web, version, &chart.Metadata{
+				Name:        web,
+				Version:     version,
+				Description: "edited by dave",
+			})
+			Expect(err).To(BeNil())
+			_, _, err = client.UpdateVersionValuesIfMatch(space, web, version, []byte(`{"replicas":4}`), etag)
+			Expect(err).To(BeNil())
+		})
+	})
+
+	Context("delete space", func() {
+		It("should delete space", func() {
+			Expect(client.DeleteChart(space, web)).To(BeNil())
+			Expect(client.DeleteSpace(space)).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,119 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package chart_test
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+
+	"github.com/caicloud/helm-registry/pkg/rest/v1"
+	"github.com/caicloud/helm-registry/test/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/helm/pkg/chartutil"
+)
+
+var _ = Describe("Conditional requests", func() {
+	const (
+		space = "conditions"
+		chart = "web"
+	)
+	var (
+		endpoint = ""
+		client   *v1.Client
+		versions = []string{"1.0.0", "1.1.0"}
+	)
+	// get gets a version with conditional headers
+	get := func(version string, headers map[string]string) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, endpoint+"/api/v1/spaces/"+space+"/charts/"+chart+"/versions/"+version, nil)
+		Expect(err).To(BeNil())
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		Expect(err).To(BeNil())
+		return resp
+	}
+	BeforeEach(func() {
+		By("getting registry host from env")
+		endpoint = os.Getenv(EnvEndpoint)
+		Expect(endpoint).NotTo(BeEmpty())
+		cli, err := v1.NewClient(endpoint)
+		Expect(err).To(BeNil())
+		client = cli
+	})
+
+	Context("upload versions", func() {
+		It("should upload versions", utils.Multicase(versions, func(version string) {
+			data, err := utils.Package(chart, version, "replicas: 1\n", nil)
+			Expect(err).To(BeNil())
+			_, err = client.UploadChart(space, data)
+			Expect(err).To(BeNil())
+		}))
+	})
+
+	Context("get versions conditionally", func() {
+		It("should get versions by entity tags", func() {
+			resp := get("1.0.0", nil)
+			resp.Body.Close()
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			etag := resp.Header.Get("ETag")
+			Expect(etag).NotTo(BeEmpty())
+			Expect(resp.Header.Get("Last-Modified")).NotTo(BeEmpty())
+
+			resp = get("1.0.0", map[string]string{"If-None-Match": etag})
+			resp.Body.Close()
+			Expect(resp.StatusCode).To(Equal(http.StatusNotModified))
+			resp = get("1.1.0", map[string]string{"If-None-Match": etag})
+			resp.Body.Close()
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		})
+		It("should get versions by last modified time", func() {
+			resp := get("1.1.0", nil)
+			resp.Body.Close()
+			modified := resp.Header.Get("Last-Modified")
+			Expect(modified).NotTo(BeEmpty())
+			resp = get("1.1.0", map[string]string{"If-Modified-Since": modified})
+			resp.Body.Close()
+			Expect(resp.StatusCode).To(Equal(http.StatusNotModified))
+		})
+		It("shouldn't get versions of moved tags by last modified time", func() {
+			_, err := client.SetTag(space, chart, "stable", "1.1.0")
+			Expect(err).To(BeNil())
+			resp := get("stable", nil)
+			resp.Body.Close()
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			Expect(resp.Header.Get("Last-Modified")).To(BeEmpty())
+			etag := resp.Header.Get("ETag")
+			resp = get("1.1.0", nil)
+			resp.Body.Close()
+			modified := resp.Header.Get("Last-Modified")
+
+			// the tag is moved to an older version
+			_, err = client.SetTag(space, chart, "stable", "1.0.0")
+			Expect(err).To(BeNil())
+			resp = get("stable", map[string]string{"If-Modified-Since": modified})
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			buf := bytes.NewBuffer(nil)
+			_, err = buf.ReadFrom(resp.Body)
+			resp.Body.Close()
+			Expect(err).To(BeNil())
+			downloaded, err := chartutil.LoadArchive(buf)
+			Expect(err).To(BeNil())
+			Expect(downloaded.Metadata.Version).To(Equal("1.0.0"))
+			resp = get("stable", map[string]string{"If-None-Match": etag})
+			resp.Body.Close()
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		})
+	})
+
+	Context("delete space", func() {
+		It("should delete space", func() {
+			Expect(client.DeleteChart(space, chart)).To(BeNil())
+			Expect(client.DeleteSpace(space)).To(BeNil())
+		})
+	})
+})
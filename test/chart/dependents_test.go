@@ -0,0 +1,160 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package chart_test
+
+import (
+	"os"
+
+	"github.com/caicloud/helm-registry/pkg/api/models"
+	"github.com/caicloud/helm-registry/pkg/rest/v1"
+	"github.com/caicloud/helm-registry/pkg/storage"
+	"github.com/caicloud/helm-registry/test/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Dependents", func() {
+	const (
+		base = "dependents-base"
+		apps = "dependents-apps"
+		web  = "web"
+		db   = "db"
+		site = "site"
+	)
+	var (
+		endpoint = ""
+		client   *v1.Client
+		packages = map[string][]byte{}
+	)
+	// outdated lists outdated versions in apps
+	outdated := func(all bool) []*models.OutdatedVersion {
+		api := v1.NewAPIListOutdatedVersions()
+		api.Space = apps
+		api.Limit = 10
+		api.All = all
+		result, err := api.Convert(client.Do(api))
+		Expect(err).To(BeNil())
+		return result.Items
+	}
+	// upload uploads a version of chart to base
+	upload := func(chart, version string) {
+		data, err := utils.Package(chart, version, "replicas: 1\n", nil)
+		Expect(err).To(BeNil())
+		_, err = client.UploadChart(base, data)
+		Expect(err).To(BeNil())
+		packages[chart+"/"+version] = data
+	}
+	BeforeEach(func() {
+		By("getting registry host from env")
+		endpoint = os.Getenv(EnvEndpoint)
+		Expect(endpoint).NotTo(BeEmpty())
+		cli, err := v1.NewClient(endpoint)
+		Expect(err).To(BeNil())
+		client = cli
+	})
+
+	Context("create charts", func() {
+		It("should create charts from packages", func() {
+			upload(web, "1.0.0")
+			upload(db, "1.0.0")
+			_, err := client.CreateSpace(apps)
+			Expect(err).To(BeNil())
+			config := `{
+    "save": {"space": "` + apps + `", "chart": "` + site + `", "version": "1.0.0"},
+    "configs": {
+        "package": {"independent": true, "space": "` + base + `", "chart": "` + web + `", "version": "1.0.0"},
+        "database": {
+            "package": {"independent": true, "space": "` + base + `", "chart": "` + db + `", "version": "1.0.0"}
+        }
+    }
+}`
+			_, err = client.CreateChart(apps, config)
+			Expect(err).To(BeNil())
+		})
+	})
+
+	Context("list dependents", func() {
+		It("should list versions built from packages", func() {
+			result, err := client.ListDependents(base, db, "", 0, 10)
+			Expect(err).To(BeNil())
+			Expect(result.Items).To(HaveLen(1))
+			dependent := result.Items[0]
+			Expect([]string{dependent.Space, dependent.Chart, dependent.Version}).To(Equal([]string{apps, site, "1.0.0"}))
+			Expect(dependent.Reference).To(Equal(storage.Reference{
+				Path:    "database",
+				Space:   base,
+				Chart:   db,
+				Version: "1.0.0",
+				Digest:  storage.Digest(packages[db+"/1.0.0"]),
+			}))
+
+			result, err = client.ListDependents(base, web, "1.0.0", 0, 10)
+			Expect(err).To(BeNil())
+			Expect(result.Items).To(HaveLen(1))
+			Expect(result.Items[0].Reference.Path).To(BeEmpty())
+		})
+		It("should list dependents of versions", func() {
+			upload(db, "1.1.0")
+			result, err := client.ListDependents(base, db, "1.1.0", 0, 10)
+			Expect(err).To(BeNil())
+			Expect(result.Items).To(BeEmpty())
+		})
+	})
+
+	Context("report outdated versions", func() {
+		It("should report versions with newer packages", func() {
+			versions := outdated(false)
+			Expect(versions).To(HaveLen(1))
+			Expect([]string{versions[0].Space, versions[0].Chart, versions[0].Version}).To(Equal([]string{apps, site, "1.0.0"}))
+			Expect(versions[0].Packages).To(HaveLen(1))
+			Expect(versions[0].Packages[0].Path).To(Equal("database"))
+			Expect(versions[0].Packages[0].Version).To(Equal("1.0.0"))
+			Expect(versions[0].Packages[0].Latest).To(Equal("1.1.0"))
+		})
+		It("should only check the latest versions by default", func() {
+			_, err := client.RebuildVersion(apps, site, "1.0.0", `{"version": "1.1.0", "latest": true}`)
+			Expect(err).To(BeNil())
+			Expect(outdated(false)).To(BeEmpty())
+			versions := outdated(true)
+			Expect(versions).To(HaveLen(1))
+			Expect(versions[0].Version).To(Equal("1.0.0"))
+		})
+		It("shouldn't take yanked packages as newer versions", func() {
+			_, err := client.SetVersionState(base, db, "1.1.0", storage.StateYanked, "broken")
+			Expect(err).To(BeNil())
+			Expect(outdated(true)).To(BeEmpty())
+		})
+	})
+
+	Context("list yanked dependents", func() {
+		It("should list yanked dependents explicitly", func() {
+			_, err := client.SetVersionState(apps, site, "1.0.0", storage.StateYanked, "outdated")
+			Expect(err).To(BeNil())
+			result, err := client.ListDependents(base, web, "", 0, 10)
+			Expect(err).To(BeNil())
+			Expect(result.Items).To(HaveLen(1))
+			Expect(result.Items[0].Version).To(Equal("1.1.0"))
+
+			api := v1.NewAPIListDependents()
+			api.Space = base
+			api.Chart = web
+			api.Limit = 10
+			api.Yanked = true
+			result, err = api.Convert(client.Do(api))
+			Expect(err).To(BeNil())
+			Expect(result.Items).To(HaveLen(2))
+		})
+	})
+
+	Context("delete spaces", func() {
+		It("should delete spaces", func() {
+			Expect(client.DeleteChart(apps, site)).To(BeNil())
+			Expect(client.DeleteChart(base, web)).To(BeNil())
+			Expect(client.DeleteChart(base, db)).To(BeNil())
+			Expect(client.DeleteSpace(apps)).To(BeNil())
+			Expect(client.DeleteSpace(base)).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,83 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package chart_test
+
+import (
+	"os"
+
+	"github.com/caicloud/helm-registry/pkg/rest"
+	"github.com/caicloud/helm-registry/pkg/rest/v1"
+	"github.com/caicloud/helm-registry/test/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Files", func() {
+	const (
+		space   = "files"
+		chart   = "web"
+		version = "1.0.0"
+		values  = "# replicas of web\nreplicas: 1\n"
+	)
+	var (
+		endpoint = ""
+		client   *v1.Client
+		db       []byte
+	)
+	BeforeEach(func() {
+		By("getting registry host from env")
+		endpoint = os.Getenv(EnvEndpoint)
+		Expect(endpoint).NotTo(BeEmpty())
+		cli, err := v1.NewClient(endpoint)
+		Expect(err).To(BeNil())
+		client = cli
+	})
+
+	Context("upload chart", func() {
+		It("should upload chart with a subchart package", func() {
+			var err error
+			db, err = utils.Package("db", "1.0.0", "port: 3306\n", nil)
+			Expect(err).To(BeNil())
+			data, err := utils.Package(chart, version, values, map[string]string{
+				"templates/svc.yaml":  "kind: Service\n",
+				"charts/db-1.0.0.tgz": string(db),
+			})
+			Expect(err).To(BeNil())
+			_, err = client.UploadChart(space, data)
+			Expect(err).To(BeNil())
+		})
+	})
+
+	Context("browse files", func() {
+		It("should list archived files", func() {
+			result, err := client.ListFiles(space, chart, version, 0, 100)
+			Expect(err).To(BeNil())
+			paths := []string{}
+			for _, file := range result.Items {
+				paths = append(paths, file.Path)
+			}
+			Expect(paths).To(Equal([]string{"Chart.yaml", "charts/db-1.0.0.tgz", "templates/svc.yaml", "values.yaml"}))
+		})
+		It("should fetch raw files", func() {
+			data, err := client.FetchFile(space, chart, version, "values.yaml")
+			Expect(err).To(BeNil())
+			Expect(string(data)).To(Equal(values))
+			data, err = client.FetchFile(space, chart, version, "charts/db-1.0.0.tgz")
+			Expect(err).To(BeNil())
+			Expect(data).To(Equal(db))
+		})
+		It("shouldn't fetch files of subcharts in packages", func() {
+			_, err := client.FetchFile(space, chart, version, "charts/db/values.yaml")
+			Expect(rest.ErrorNotFound.Equal(err)).To(BeTrue())
+		})
+	})
+
+	Context("delete space", func() {
+		It("should delete space", func() {
+			Expect(client.DeleteChart(space, chart)).To(BeNil())
+			Expect(client.DeleteSpace(space)).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,152 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package chart_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"os"
+
+	"github.com/caicloud/helm-registry/pkg/errors"
+	"github.com/caicloud/helm-registry/pkg/lint"
+	"github.com/caicloud/helm-registry/pkg/rest"
+	"github.com/caicloud/helm-registry/pkg/rest/v1"
+	"github.com/caicloud/helm-registry/pkg/storage"
+	"github.com/caicloud/helm-registry/test/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// archive creates a chart package from files. Paths of files are kept as they are.
+func archive(files [][2]string) []byte {
+	buf := bytes.NewBuffer(nil)
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+	for _, file := range files {
+		Expect(tw.WriteHeader(&tar.Header{Name: file[0], Mode: 0644, Size: int64(len(file[1]))})).To(BeNil())
+		_, err := tw.Write([]byte(file[1]))
+		Expect(err).To(BeNil())
+	}
+	Expect(tw.Close()).To(BeNil())
+	Expect(gz.Close()).To(BeNil())
+	return buf.Bytes()
+}
+
+// severities returns severities of findings
+func severities(findings []lint.Finding) []lint.Severity {
+	result := []lint.Severity{}
+	for _, finding := range findings {
+		result = append(result, finding.Severity)
+	}
+	return result
+}
+
+var _ = Describe("Lint", func() {
+	const (
+		created = "lint-created"
+		strict  = "lint-strict"
+		off     = "lint-off"
+		web     = "web"
+	)
+	var (
+		endpoint = ""
+		client   *v1.Client
+		broken   []byte
+	)
+	BeforeEach(func() {
+		By("getting registry host from env")
+		endpoint = os.Getenv(EnvEndpoint)
+		Expect(endpoint).NotTo(BeEmpty())
+		cli, err := v1.NewClient(endpoint)
+		Expect(err).To(BeNil())
+		client = cli
+	})
+
+	Context("prepare spaces", func() {
+		It("should create spaces with lint modes", func() {
+			properties := storage.NewSpaceProperties()
+			properties.Lint = storage.LintError
+			_, err := client.CreateSpaceWithProperties(strict, properties)
+			Expect(err).To(BeNil())
+			properties.Lint = storage.LintOff
+			_, err = client.CreateSpaceWithProperties(off, properties)
+			Expect(err).To(BeNil())
+
+			// the template can't be parsed
+			broken, err = utils.Package(web, "1.0.0", "replicas: 1\n", map[string]string{
+				"templates/svc.yaml": "kind: {{ .Values.kind\n",
+			})
+			Expect(err).To(BeNil())
+		})
+	})
+
+	Context("lint uploads", func() {
+		It("should warn findings of uploads to new spaces", func() {
+			link, err := client.UploadChart(created, broken)
+			Expect(err).To(BeNil())
+			Expect(severities(link.Lint)).To(ContainElement(lint.SeverityError))
+			space, err := client.FetchSpace(created)
+			Expect(err).To(BeNil())
+			Expect(space.Lint).To(Equal(storage.LintWarn))
+		})
+		It("should reject broken uploads to strict spaces", func() {
+			_, err := client.UploadChart(strict, broken)
+			e, ok := err.(*errors.Error)
+			Expect(ok).To(BeTrue())
+			Expect(e.Code).To(Equal(http.StatusUnprocessableEntity))
+			Expect(e.Message).To(ContainSubstring("lint failed"))
+			_, err = client.DownloadVersion(strict, web, "1.0.0")
+			Expect(rest.ErrorNotFound.Equal(err)).To(BeTrue())
+		})
+		It("should accept packages without top directories in strict spaces", func() {
+			data := archive([][2]string{
+				{"./Chart.yaml", "apiVersion: v1\nname: web\nversion: 1.1.0\ndescription: a chart for tests\n"},
+				{"./values.yaml", "replicas: 1\n"},
+				{"./templates/deployment.yaml", "replicas: {{ .Values.replicas }}\n"},
+			})
+			link, err := client.UploadChart(strict, data)
+			Expect(err).To(BeNil())
+			Expect(severities(link.Lint)).NotTo(ContainElement(lint.SeverityError))
+		})
+		It("shouldn't lint uploads to spaces with lint off", func() {
+			link, err := client.UploadChart(off, broken)
+			Expect(err).To(BeNil())
+			Expect(link.Lint).To(BeEmpty())
+		})
+	})
+
+	Context("lint without uploads", func() {
+		It("should lint packages by spaces", func() {
+			result, err := client.LintChart(strict, broken)
+			Expect(err).To(BeNil())
+			Expect(result.Mode).To(Equal(storage.LintError))
+			Expect(result.Accepted).To(BeFalse())
+			Expect(severities(result.Findings)).To(ContainElement(lint.SeverityError))
+
+			result, err = client.LintChart(created, broken)
+			Expect(err).To(BeNil())
+			Expect(result.Mode).To(Equal(storage.LintWarn))
+			Expect(result.Accepted).To(BeTrue())
+
+			// spaces which don't exist are linted by default properties
+			result, err = client.LintChart("lint-nothing", broken)
+			Expect(err).To(BeNil())
+			Expect(result.Mode).To(Equal(storage.LintWarn))
+			_, err = client.FetchSpace("lint-nothing")
+			Expect(rest.ErrorNotFound.Equal(err)).To(BeTrue())
+		})
+	})
+
+	Context("delete spaces", func() {
+		It("should delete spaces", func() {
+			for _, space := range []string{created, strict, off} {
+				Expect(client.DeleteChart(space, web)).To(BeNil())
+				Expect(client.DeleteSpace(space)).To(BeNil())
+			}
+		})
+	})
+})
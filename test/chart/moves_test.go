@@ -0,0 +1,117 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package chart_test
+
+import (
+	"os"
+
+	"github.com/caicloud/helm-registry/pkg/rest"
+	"github.com/caicloud/helm-registry/pkg/rest/v1"
+	"github.com/caicloud/helm-registry/pkg/storage"
+	"github.com/caicloud/helm-registry/test/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Moves", func() {
+	const (
+		source  = "moves-source"
+		target  = "moves-target"
+		renamed = "moves-renamed"
+		web     = "web"
+		db      = "db"
+	)
+	var (
+		endpoint = ""
+		client   *v1.Client
+		packages = map[string][]byte{}
+	)
+	BeforeEach(func() {
+		By("getting registry host from env")
+		endpoint = os.Getenv(EnvEndpoint)
+		Expect(endpoint).NotTo(BeEmpty())
+		cli, err := v1.NewClient(endpoint)
+		Expect(err).To(BeNil())
+		client = cli
+	})
+
+	Context("upload charts", func() {
+		It("should upload charts", utils.Multicase([]string{web, db}, func(chart string) {
+			data, err := utils.Package(chart, "1.0.0", "replicas: 1\n", nil)
+			Expect(err).To(BeNil())
+			_, err = client.UploadChart(source, data)
+			Expect(err).To(BeNil())
+			_, err = client.UploadChart(target, data)
+			Expect(err).To(BeNil())
+			packages[chart] = data
+		}))
+		It("should delete a chart from the target space", func() {
+			Expect(client.DeleteChart(target, web)).To(BeNil())
+		})
+	})
+
+	Context("move charts", func() {
+		It("shouldn't move charts to spaces with the same charts", func() {
+			_, err := client.MoveChart(source, db, target)
+			Expect(rest.ErrorConflict.Equal(err)).To(BeTrue())
+		})
+		It("shouldn't move charts to spaces which don't exist", func() {
+			_, err := client.MoveChart(source, web, "moves-nothing")
+			Expect(rest.ErrorNotFound.Equal(err)).To(BeTrue())
+		})
+		It("should move charts", func() {
+			_, err := client.MoveChart(source, web, target)
+			Expect(err).To(BeNil())
+			_, err = client.DownloadVersion(source, web, "1.0.0")
+			Expect(rest.ErrorNotFound.Equal(err)).To(BeTrue())
+			data, err := client.DownloadVersion(target, web, "1.0.0")
+			Expect(err).To(BeNil())
+			Expect(data).To(Equal(packages[web]))
+
+			history, err := client.FetchChartHistory(target, web, 0, 10)
+			Expect(err).To(BeNil())
+			Expect(history.Items).NotTo(BeEmpty())
+			event := history.Items[len(history.Items)-1]
+			Expect(event.Type).To(Equal(storage.EventTypeMove))
+			Expect(event.Source).To(Equal(source + "/" + web))
+		})
+	})
+
+	Context("rename spaces", func() {
+		It("shouldn't rename spaces to existing spaces", func() {
+			_, err := client.RenameSpace(source, target)
+			Expect(rest.ErrorConflict.Equal(err)).To(BeTrue())
+		})
+		It("should rename spaces with charts", func() {
+			_, err := client.RenameSpace(target, renamed)
+			Expect(err).To(BeNil())
+			_, err = client.FetchSpace(target)
+			Expect(rest.ErrorNotFound.Equal(err)).To(BeTrue())
+			charts, err := client.ListCharts(renamed, 0, 10)
+			Expect(err).To(BeNil())
+			Expect(charts.Items).To(Equal([]string{db, web}))
+			data, err := client.DownloadVersion(renamed, web, "1.0.0")
+			Expect(err).To(BeNil())
+			Expect(data).To(Equal(packages[web]))
+
+			history, err := client.FetchChartHistory(renamed, db, 0, 10)
+			Expect(err).To(BeNil())
+			Expect(history.Items).NotTo(BeEmpty())
+			event := history.Items[len(history.Items)-1]
+			Expect(event.Type).To(Equal(storage.EventTypeMove))
+			Expect(event.Source).To(Equal(target + "/" + db))
+		})
+	})
+
+	Context("delete spaces", func() {
+		It("should delete spaces", func() {
+			Expect(client.DeleteChart(source, db)).To(BeNil())
+			Expect(client.DeleteChart(renamed, db)).To(BeNil())
+			Expect(client.DeleteChart(renamed, web)).To(BeNil())
+			Expect(client.DeleteSpace(source)).To(BeNil())
+			Expect(client.DeleteSpace(renamed)).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,138 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package chart_test
+
+import (
+	"bytes"
+	"os"
+
+	"github.com/caicloud/helm-registry/pkg/rest"
+	"github.com/caicloud/helm-registry/pkg/rest/v1"
+	"github.com/caicloud/helm-registry/pkg/storage"
+	"github.com/caicloud/helm-registry/test/utils"
+	"github.com/ghodss/yaml"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/helm/pkg/chartutil"
+)
+
+var _ = Describe("Previews", func() {
+	const (
+		space = "previews"
+		web   = "web"
+		db    = "db"
+		site  = "site"
+	)
+	var (
+		endpoint = ""
+		client   *v1.Client
+		packages = map[string][]byte{}
+	)
+	// config creates an orchestration config of site with a version of db
+	config := func(dbVersion string) string {
+		return `{
+    "save": {"space": "` + space + `", "chart": "` + site + `", "version": "1.0.0", "description": "a preview"},
+    "configs": {
+        "package": {"independent": true, "space": "` + space + `", "chart": "` + web + `", "version": "1.0.0"},
+        "_config": {"replicas": 2},
+        "database": {
+            "package": {"independent": true, "space": "` + space + `", "chart": "` + db + `", "version": "` + dbVersion + `"},
+            "_config": {"port": 5432}
+        }
+    }
+}`
+	}
+	BeforeEach(func() {
+		By("getting registry host from env")
+		endpoint = os.Getenv(EnvEndpoint)
+		Expect(endpoint).NotTo(BeEmpty())
+		cli, err := v1.NewClient(endpoint)
+		Expect(err).To(BeNil())
+		client = cli
+	})
+
+	Context("upload packages", func() {
+		It("should upload packages", utils.Multicase([]string{web, db}, func(chart string) {
+			data, err := utils.Package(chart, "1.0.0", "replicas: 1\n", nil)
+			Expect(err).To(BeNil())
+			_, err = client.UploadChart(space, data)
+			Expect(err).To(BeNil())
+			packages[chart] = data
+		}))
+	})
+
+	Context("preview charts", func() {
+		It("should preview charts without storing them", func() {
+			preview, err := client.PreviewChart(space, config("1.0.0"))
+			Expect(err).To(BeNil())
+			Expect([]string{preview.Space, preview.Chart, preview.Version}).To(Equal([]string{space, site, "1.0.0"}))
+			Expect(preview.Accepted).To(BeTrue())
+			Expect(preview.Problems).To(BeEmpty())
+			Expect(preview.Digest).NotTo(BeEmpty())
+
+			tree := preview.Tree
+			Expect(tree).NotTo(BeNil())
+			Expect(tree.Package.Chart).To(Equal(web))
+			Expect(tree.Version).To(Equal("1.0.0"))
+			Expect(tree.Digest).To(Equal(storage.Digest(packages[web])))
+			Expect(tree.Dependencies).To(HaveLen(1))
+			Expect(tree.Dependencies[0].Name).To(Equal("database"))
+			Expect(tree.Dependencies[0].Package.Chart).To(Equal(db))
+			Expect(tree.Dependencies[0].Digest).To(Equal(storage.Digest(packages[db])))
+
+			Expect(preview.Values).To(ContainSubstring("replicas: 2"))
+			Expect(preview.Values).To(ContainSubstring("port: 5432"))
+
+			_, err = client.FetchVersionMetadata(space, site, "1.0.0")
+			Expect(rest.ErrorNotFound.Equal(err)).To(BeTrue())
+		})
+		It("should report missing packages", func() {
+			preview, err := client.PreviewChart(space, config("2.0.0"))
+			Expect(err).To(BeNil())
+			Expect(preview.Accepted).To(BeFalse())
+			Expect(preview.Digest).To(BeEmpty())
+			Expect(preview.Problems).To(HaveLen(1))
+			Expect(preview.Problems[0].Path).To(Equal("database"))
+			Expect(preview.Problems[0].Message).To(ContainSubstring(db))
+
+			_, err = client.PreviewChartArchive(space, config("2.0.0"))
+			Expect(err).NotTo(BeNil())
+		})
+		It("should preview chart packages", func() {
+			data, err := client.PreviewChartArchive(space, config("1.0.0"))
+			Expect(err).To(BeNil())
+			c, err := chartutil.LoadArchive(bytes.NewReader(data))
+			Expect(err).To(BeNil())
+			Expect(c.Metadata.Name).To(Equal(site))
+			Expect(c.Metadata.Description).To(Equal("a preview"))
+			Expect(c.Dependencies).To(HaveLen(1))
+			Expect(c.Dependencies[0].Metadata.Name).To(Equal("database"))
+		})
+		It("should create charts as they are previewed", func() {
+			preview, err := client.PreviewChart(space, config("1.0.0"))
+			Expect(err).To(BeNil())
+			_, err = client.CreateChart(space, config("1.0.0"))
+			Expect(err).To(BeNil())
+			// previews show merged values of the stored version
+			values, err := client.FetchVersionValues(space, site, "1.0.0")
+			Expect(err).To(BeNil())
+			merged, err := yaml.YAMLToJSON([]byte(preview.Values))
+			Expect(err).To(BeNil())
+			Expect(values).To(MatchJSON(merged))
+			record, err := client.FetchVersionRecord(space, site, "1.0.0")
+			Expect(err).To(BeNil())
+			Expect(record.Digest).To(Equal(preview.Digest))
+		})
+	})
+
+	Context("delete space", func() {
+		It("should delete space", func() {
+			for _, chart := range []string{site, web, db} {
+				Expect(client.DeleteChart(space, chart)).To(BeNil())
+			}
+			Expect(client.DeleteSpace(space)).To(BeNil())
+		})
+	})
+})
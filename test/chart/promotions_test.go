@@ -0,0 +1,115 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package chart_test
+
+import (
+	"net/http"
+	"os"
+
+	"github.com/caicloud/helm-registry/pkg/errors"
+	"github.com/caicloud/helm-registry/pkg/rest"
+	"github.com/caicloud/helm-registry/pkg/rest/v1"
+	"github.com/caicloud/helm-registry/pkg/storage"
+	"github.com/caicloud/helm-registry/test/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Promotions", func() {
+	const (
+		dev     = "promotions-dev"
+		staging = "promotions-staging"
+		prod    = "promotions-prod"
+		chart   = "web"
+	)
+	var (
+		endpoint = ""
+		client   *v1.Client
+		packages = map[string][]byte{}
+	)
+	BeforeEach(func() {
+		By("getting registry host from env")
+		endpoint = os.Getenv(EnvEndpoint)
+		Expect(endpoint).NotTo(BeEmpty())
+		cli, err := v1.NewClient(endpoint)
+		Expect(err).To(BeNil())
+		client = cli
+	})
+
+	Context("prepare spaces", func() {
+		It("should upload versions to the dev space", utils.Multicase([]string{"1.0.0", "1.1.0", "2.0.0"}, func(version string) {
+			files := map[string]string{"templates/svc.yaml": "kind: Service\n"}
+			if version == "2.0.0" {
+				// the template can't be parsed
+				files["templates/svc.yaml"] = "kind: {{ .Values.kind\n"
+			}
+			data, err := utils.Package(chart, version, "replicas: 1\n", files)
+			Expect(err).To(BeNil())
+			_, err = client.UploadChart(dev, data)
+			Expect(err).To(BeNil())
+			packages[version] = data
+		}))
+		It("should yank a version", func() {
+			_, err := client.SetVersionState(dev, chart, "1.1.0", storage.StateYanked, "broken")
+			Expect(err).To(BeNil())
+		})
+		It("should create target spaces", func() {
+			_, err := client.CreateSpace(staging)
+			Expect(err).To(BeNil())
+			properties := storage.NewSpaceProperties()
+			properties.Lint = storage.LintError
+			_, err = client.CreateSpaceWithProperties(prod, properties)
+			Expect(err).To(BeNil())
+		})
+	})
+
+	Context("promote versions", func() {
+		It("should promote versions which are not yanked", func() {
+			links, err := client.PromoteChart(dev, chart, staging, nil, true)
+			Expect(err).To(BeNil())
+			versions := []string{}
+			for _, link := range links {
+				versions = append(versions, link.Version)
+			}
+			Expect(versions).To(Equal([]string{"1.0.0", "2.0.0"}))
+			Expect(links[1].Lint).NotTo(BeEmpty())
+
+			data, err := client.DownloadVersion(staging, chart, "1.0.0")
+			Expect(err).To(BeNil())
+			Expect(data).To(Equal(packages["1.0.0"]))
+			_, err = client.DownloadVersion(staging, chart, "1.1.0")
+			Expect(rest.ErrorNotFound.Equal(err)).To(BeTrue())
+		})
+		It("should promote yanked versions explicitly", func() {
+			_, err := client.PromoteVersion(dev, chart, "1.1.0", staging, false)
+			Expect(err).To(BeNil())
+		})
+		It("should lint versions by the target space", func() {
+			_, err := client.PromoteVersion(dev, chart, "2.0.0", prod, false)
+			e, ok := err.(*errors.Error)
+			Expect(ok).To(BeTrue())
+			Expect(e.Code).To(Equal(http.StatusUnprocessableEntity))
+
+			// nothing is promoted if any version is rejected
+			_, err = client.PromoteChart(dev, chart, prod, []string{"1.0.0", "2.0.0"}, false)
+			Expect(err).NotTo(BeNil())
+			_, err = client.DownloadVersion(prod, chart, "1.0.0")
+			Expect(rest.ErrorNotFound.Equal(err)).To(BeTrue())
+
+			link, err := client.PromoteVersion(dev, chart, "1.0.0", prod, false)
+			Expect(err).To(BeNil())
+			Expect(link.Lint).NotTo(BeEmpty())
+		})
+	})
+
+	Context("delete spaces", func() {
+		It("should delete spaces", func() {
+			for _, space := range []string{dev, staging, prod} {
+				Expect(client.DeleteChart(space, chart)).To(BeNil())
+				Expect(client.DeleteSpace(space)).To(BeNil())
+			}
+		})
+	})
+})
@@ -0,0 +1,152 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package chart_test
+
+import (
+	"os"
+
+	"github.com/caicloud/helm-registry/pkg/rest"
+	"github.com/caicloud/helm-registry/pkg/rest/v1"
+	"github.com/caicloud/helm-registry/pkg/storage"
+	"github.com/caicloud/helm-registry/test/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Rebuilds", func() {
+	const (
+		space = "rebuilds"
+		web   = "web"
+		db    = "db"
+		site  = "site"
+	)
+	var (
+		endpoint = ""
+		client   *v1.Client
+	)
+	// databaseVersion returns the version of db which a version of site references
+	databaseVersion := func(version string) string {
+		record, err := client.FetchVersionRecord(space, site, version)
+		Expect(err).To(BeNil())
+		for _, reference := range record.References {
+			if reference.Path == "database" {
+				Expect(reference.Chart).To(Equal(db))
+				return reference.Version
+			}
+		}
+		Fail("no reference of database in " + version)
+		return ""
+	}
+	BeforeEach(func() {
+		By("getting registry host from env")
+		endpoint = os.Getenv(EnvEndpoint)
+		Expect(endpoint).NotTo(BeEmpty())
+		cli, err := v1.NewClient(endpoint)
+		Expect(err).To(BeNil())
+		client = cli
+	})
+
+	Context("create chart", func() {
+		It("should upload packages", func() {
+			for _, chart := range []string{web, db} {
+				data, err := utils.Package(chart, "1.0.0", "replicas: 1\n", nil)
+				Expect(err).To(BeNil())
+				_, err = client.UploadChart(space, data)
+				Expect(err).To(BeNil())
+			}
+			_, err := client.SetTag(space, db, "stable", "1.0.0")
+			Expect(err).To(BeNil())
+		})
+		It("should create chart by a config", func() {
+			config := `{
+    "save": {"space": "` + space + `", "chart": "` + site + `", "version": "1.0.0", "description": "a site"},
+    "configs": {
+        "package": {"independent": true, "space": "` + space + `", "chart": "` + web + `", "version": "1.0.0"},
+        "database": {
+            "package": {"independent": true, "space": "` + space + `", "chart": "` + db + `", "version": "stable"},
+            "_config": {"port": 5432}
+        }
+    }
+}`
+			_, err := client.CreateChart(space, config)
+			Expect(err).To(BeNil())
+			Expect(databaseVersion("1.0.0")).To(Equal("1.0.0"))
+		})
+	})
+
+	Context("fetch configs", func() {
+		It("should fetch configs of orchestrated versions", func() {
+			config, err := client.FetchOrchestrationConfig(space, site, "1.0.0")
+			Expect(err).To(BeNil())
+			Expect(config.Save.Chart).To(Equal(site))
+			Expect(config.Save.Version).To(Equal("1.0.0"))
+			Expect(config.Save.Desc).To(Equal("a site"))
+			database, ok := config.Configs["database"].(map[string]interface{})
+			Expect(ok).To(BeTrue())
+			Expect(database["package"]).To(HaveKeyWithValue("version", "stable"))
+			Expect(database["_config"]).To(Equal(map[string]interface{}{"port": 5432.0}))
+		})
+		It("shouldn't fetch configs of uploaded versions", func() {
+			_, err := client.FetchOrchestrationConfig(space, web, "1.0.0")
+			Expect(rest.ErrorNotFound.Equal(err)).To(BeTrue())
+		})
+	})
+
+	Context("rebuild versions", func() {
+		It("should upload new versions of packages", func() {
+			data, err := utils.Package(db, "1.1.0", "replicas: 1\n", nil)
+			Expect(err).To(BeNil())
+			_, err = client.UploadChart(space, data)
+			Expect(err).To(BeNil())
+			_, err = client.SetTag(space, db, "stable", "1.1.0")
+			Expect(err).To(BeNil())
+		})
+		It("should pin packages of rebuilt versions", func() {
+			link, err := client.RebuildVersion(space, site, "1.0.0", `{"version": "1.0.1"}`)
+			Expect(err).To(BeNil())
+			Expect([]string{link.Space, link.Chart, link.Version}).To(Equal([]string{space, site, "1.0.1"}))
+			Expect(databaseVersion("1.0.1")).To(Equal("1.0.0"))
+			metadata, err := client.FetchVersionMetadata(space, site, "1.0.1")
+			Expect(err).To(BeNil())
+			Expect(metadata.Description).To(Equal("a site"))
+			record, err := client.FetchVersionRecord(space, site, "1.0.1")
+			Expect(err).To(BeNil())
+			Expect(record.Source).To(Equal(storage.SourceOrchestration))
+		})
+		It("should upgrade packages by paths", func() {
+			_, err := client.RebuildVersion(space, site, "1.0.0", `{"version": "1.1.0", "upgrades": {"database": "1.1.0"}}`)
+			Expect(err).To(BeNil())
+			Expect(databaseVersion("1.1.0")).To(Equal("1.1.0"))
+			config, err := client.FetchOrchestrationConfig(space, site, "1.1.0")
+			Expect(err).To(BeNil())
+			Expect(config.Configs["database"].(map[string]interface{})["package"]).To(HaveKeyWithValue("version", "1.1.0"))
+		})
+		It("should upgrade packages to the latest versions", func() {
+			_, err := client.RebuildVersion(space, site, "1.0.0", `{"version": "1.2.0", "latest": true, "description": "latest"}`)
+			Expect(err).To(BeNil())
+			Expect(databaseVersion("1.2.0")).To(Equal("1.1.0"))
+			metadata, err := client.FetchVersionMetadata(space, site, "1.2.0")
+			Expect(err).To(BeNil())
+			Expect(metadata.Description).To(Equal("latest"))
+		})
+		It("shouldn't rebuild invalid versions", func() {
+			_, err := client.RebuildVersion(space, site, "1.0.0", `{"version": "1.1.0"}`)
+			Expect(rest.ErrorConflict.Equal(err)).To(BeTrue())
+			_, err = client.RebuildVersion(space, site, "1.0.0", `{"version": "1.3.0", "upgrades": {"cache": "1.0.0"}}`)
+			Expect(rest.ErrorBadRequest.Equal(err)).To(BeTrue())
+			_, err = client.RebuildVersion(space, web, "1.0.0", `{"version": "1.3.0"}`)
+			Expect(rest.ErrorNotFound.Equal(err)).To(BeTrue())
+		})
+	})
+
+	Context("delete space", func() {
+		It("should delete space", func() {
+			for _, chart := range []string{site, web, db} {
+				Expect(client.DeleteChart(space, chart)).To(BeNil())
+			}
+			Expect(client.DeleteSpace(space)).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,119 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package chart_test
+
+import (
+	"os"
+	"strings"
+
+	"github.com/caicloud/helm-registry/pkg/rest/v1"
+	"github.com/caicloud/helm-registry/pkg/storage"
+	"github.com/caicloud/helm-registry/test/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Records", func() {
+	const (
+		space = "records"
+		chart = "web"
+	)
+	var (
+		endpoint = ""
+		client   *v1.Client
+	)
+	BeforeEach(func() {
+		By("getting registry host from env")
+		endpoint = os.Getenv(EnvEndpoint)
+		Expect(endpoint).NotTo(BeEmpty())
+		cli, err := v1.NewClient(endpoint)
+		Expect(err).To(BeNil())
+		client = cli
+	})
+
+	Context("upload versions", func() {
+		It("should record the uploader of the header", func() {
+			data, err := utils.Package(chart, "1.0.0", "replicas: 1\n", nil)
+			Expect(err).To(BeNil())
+			api := v1.NewAPIUploadChart()
+			api.Space = space
+			api.ChartFile.Data = data
+			api.Uploader = "alice"
+			_, err = api.Convert(client.Do(api))
+			Expect(err).To(BeNil())
+
+			record, err := client.FetchVersionRecord(space, chart, "1.0.0")
+			Expect(err).To(BeNil())
+			Expect(record.Uploader).To(Equal("alice"))
+			Expect(record.Source).To(Equal(storage.SourceUpload))
+			Expect(record.Digest).To(Equal(storage.Digest(data)))
+			Expect(record.Size).To(Equal(int64(len(data))))
+			Expect(record.Created).To(Equal(record.Updated))
+		})
+		It("shouldn't take unverified users of basic auth as uploaders", func() {
+			cli, err := v1.NewClient(strings.Replace(endpoint, "://", "://bob:secret@", 1))
+			Expect(err).To(BeNil())
+			data, err := utils.Package(chart, "1.1.0", "replicas: 1\n", nil)
+			Expect(err).To(BeNil())
+			_, err = cli.UploadChart(space, data)
+			Expect(err).To(BeNil())
+
+			record, err := client.FetchVersionRecord(space, chart, "1.1.0")
+			Expect(err).To(BeNil())
+			Expect(record.Uploader).To(BeEmpty())
+		})
+		It("should keep the uploader and record the updater", func() {
+			data, err := utils.Package(chart, "1.0.0", "replicas: 2\n", nil)
+			Expect(err).To(BeNil())
+			api := v1.NewAPIUpdateVersion()
+			api.Space = space
+			api.Chart = chart
+			api.Version = "1.0.0"
+			api.ChartFile.Data = data
+			api.Uploader = "carol"
+			_, err = api.Convert(client.Do(api))
+			Expect(err).To(BeNil())
+
+			record, err := client.FetchVersionRecord(space, chart, "1.0.0")
+			Expect(err).To(BeNil())
+			Expect(record.Uploader).To(Equal("alice"))
+			Expect(record.Updater).To(Equal("carol"))
+			Expect(record.Revision).To(Equal(int64(2)))
+			Expect(record.Digest).To(Equal(storage.Digest(data)))
+			Expect(record.Updated.After(record.Created)).To(BeTrue())
+		})
+	})
+
+	Context("list versions", func() {
+		It("should list versions with records sorted by time", func() {
+			api := v1.NewAPIListVersionSummaries()
+			api.Space = space
+			api.Chart = chart
+			api.Limit = 10
+			api.Sort = "-updated"
+			result, err := api.Convert(client.Do(api))
+			Expect(err).To(BeNil())
+			versions := []string{}
+			for _, summary := range result.Items {
+				Expect(summary.Record).NotTo(BeNil())
+				versions = append(versions, summary.Version)
+			}
+			Expect(versions).To(Equal([]string{"1.0.0", "1.1.0"}))
+
+			api.Sort = "created"
+			result, err = api.Convert(client.Do(api))
+			Expect(err).To(BeNil())
+			Expect(result.Items).To(HaveLen(2))
+			Expect(result.Items[0].Version).To(Equal("1.0.0"))
+		})
+	})
+
+	Context("delete space", func() {
+		It("should delete space", func() {
+			Expect(client.DeleteChart(space, chart)).To(BeNil())
+			Expect(client.DeleteSpace(space)).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,93 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package chart_test
+
+import (
+	"os"
+
+	"github.com/caicloud/helm-registry/pkg/rest"
+	"github.com/caicloud/helm-registry/pkg/rest/v1"
+	"github.com/caicloud/helm-registry/test/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Requirements", func() {
+	const (
+		library = "requirements-library"
+		apps    = "requirements-apps"
+		other   = "requirements-other"
+		db      = "db"
+		web     = "web"
+	)
+	var (
+		endpoint = ""
+		client   *v1.Client
+	)
+	BeforeEach(func() {
+		By("getting registry host from env")
+		endpoint = os.Getenv(EnvEndpoint)
+		Expect(endpoint).NotTo(BeEmpty())
+		cli, err := v1.NewClient(endpoint)
+		Expect(err).To(BeNil())
+		client = cli
+	})
+
+	Context("upload charts", func() {
+		It("should upload required versions", utils.Multicase([]string{"1.0.0", "1.1.0", "2.0.0"}, func(version string) {
+			data, err := utils.Package(db, version, "port: 3306\n", nil)
+			Expect(err).To(BeNil())
+			_, err = client.UploadChart(library, data)
+			Expect(err).To(BeNil())
+		}))
+		It("should upload a chart with requirements", func() {
+			requirements := "dependencies:\n- name: db\n  version: ^1.0.0\n  repository: " +
+				endpoint + "/api/v1/spaces/" + library + "\n"
+			data, err := utils.Package(web, "1.0.0", "replicas: 1\n", map[string]string{"requirements.yaml": requirements})
+			Expect(err).To(BeNil())
+			_, err = client.UploadChart(apps, data)
+			Expect(err).To(BeNil())
+			_, err = client.CreateSpace(other)
+			Expect(err).To(BeNil())
+		})
+	})
+
+	Context("reference required versions", func() {
+		It("should list versions which require a chart", func() {
+			result, err := client.ListDependents(library, db, "", 0, 10)
+			Expect(err).To(BeNil())
+			Expect(result.Items).To(HaveLen(1))
+			dependent := result.Items[0]
+			Expect([]string{dependent.Space, dependent.Chart, dependent.Version}).To(Equal([]string{apps, web, "1.0.0"}))
+			Expect(dependent.Reference.Requirement).To(BeTrue())
+			Expect(dependent.Reference.Path).To(Equal(db))
+			Expect(dependent.Reference.Version).To(Equal("1.1.0"))
+		})
+		It("shouldn't delete required versions", func() {
+			Expect(rest.ErrorConflict.Equal(client.DeleteVersion(library, db, "1.1.0"))).To(BeTrue())
+			Expect(rest.ErrorConflict.Equal(client.DeleteChart(library, db))).To(BeTrue())
+			Expect(client.DeleteVersion(library, db, "2.0.0")).To(BeNil())
+		})
+		It("shouldn't move required charts even if forced", func() {
+			api := v1.NewAPIMoveChart()
+			api.Space = library
+			api.Chart = db
+			api.Target = other
+			api.Force = true
+			_, err := api.Convert(client.Do(api))
+			Expect(rest.ErrorConflict.Equal(err)).To(BeTrue())
+		})
+	})
+
+	Context("delete spaces", func() {
+		It("should delete spaces", func() {
+			Expect(client.DeleteChart(apps, web)).To(BeNil())
+			Expect(client.DeleteChart(library, db)).To(BeNil())
+			for _, space := range []string{apps, library, other} {
+				Expect(client.DeleteSpace(space)).To(BeNil())
+			}
+		})
+	})
+})
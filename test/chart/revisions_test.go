@@ -0,0 +1,124 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package chart_test
+
+import (
+	"os"
+
+	"github.com/caicloud/helm-registry/pkg/api/v1/types"
+	"github.com/caicloud/helm-registry/pkg/diff"
+	"github.com/caicloud/helm-registry/pkg/rest"
+	"github.com/caicloud/helm-registry/pkg/rest/v1"
+	"github.com/caicloud/helm-registry/pkg/storage"
+	"github.com/caicloud/helm-registry/test/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Revisions", func() {
+	const (
+		space   = "revisions"
+		web     = "web"
+		version = "1.0.0"
+	)
+	var (
+		endpoint = ""
+		client   *v1.Client
+		original []byte
+	)
+	BeforeEach(func() {
+		By("getting registry host from env")
+		endpoint = os.Getenv(EnvEndpoint)
+		Expect(endpoint).NotTo(BeEmpty())
+		cli, err := v1.NewClient(endpoint)
+		Expect(err).To(BeNil())
+		client = cli
+	})
+
+	Context("edit version", func() {
+		It("should upload version", func() {
+			data, err := utils.Package(web, version, "replicas: 1\n", nil)
+			Expect(err).To(BeNil())
+			_, err = client.UploadChart(space, data)
+			Expect(err).To(BeNil())
+			original = data
+		})
+		It("should keep revisions of edits", func() {
+			_, err := client.UpdateVersionValues(space, web, version, []byte(`{"replicas":2}`))
+			Expect(err).To(BeNil())
+			_, err = client.UpdateVersionValues(space, web, version, []byte(`{"replicas":3}`))
+			Expect(err).To(BeNil())
+		})
+	})
+
+	Context("browse revisions", func() {
+		It("should list revisions", func() {
+			result, err := client.ListRevisions(space, web, version, 0, 10)
+			Expect(err).To(BeNil())
+			Expect(result.Items).To(HaveLen(3))
+			for i, revision := range result.Items {
+				Expect(revision.Revision).To(Equal(int64(i + 1)))
+				Expect(revision.Current).To(Equal(i == 2))
+			}
+			Expect(result.Items[0].Digest).To(Equal(storage.Digest(original)))
+			Expect(result.Items[0].Size).To(Equal(int64(len(original))))
+		})
+		It("should download revisions", func() {
+			data, err := client.DownloadRevision(space, web, version, 1)
+			Expect(err).To(BeNil())
+			Expect(data).To(Equal(original))
+			_, err = client.DownloadRevision(space, web, version, 4)
+			Expect(rest.ErrorNotFound.Equal(err)).To(BeTrue())
+		})
+		It("should diff revisions", func() {
+			changes, err := client.DiffRevisions(space, web, version, 1, 2)
+			Expect(err).To(BeNil())
+			Expect(changes.Metadata).To(BeEmpty())
+			Expect(changes.Values).To(HaveLen(1))
+			Expect(changes.Values[0]).To(Equal(types.ValueChange{Path: "replicas", Type: diff.Changed, From: 1.0, To: 2.0}))
+
+			// revisions are compared with the current revision by default
+			changes, err = client.DiffRevisions(space, web, version, 1, -1)
+			Expect(err).To(BeNil())
+			Expect(changes.Values).To(HaveLen(1))
+			Expect(changes.Values[0].To).To(Equal(3.0))
+		})
+	})
+
+	Context("roll back", func() {
+		It("should roll back to a revision", func() {
+			revision, err := client.RollbackRevision(space, web, version, 1)
+			Expect(err).To(BeNil())
+			Expect(revision.Revision).To(Equal(int64(4)))
+			Expect(revision.Current).To(BeTrue())
+			Expect(revision.Digest).To(Equal(storage.Digest(original)))
+
+			data, err := client.DownloadVersion(space, web, version)
+			Expect(err).To(BeNil())
+			Expect(data).To(Equal(original))
+			record, err := client.FetchVersionRecord(space, web, version)
+			Expect(err).To(BeNil())
+			Expect(record.Revision).To(Equal(int64(4)))
+
+			history, err := client.FetchChartHistory(space, web, 0, 10)
+			Expect(err).To(BeNil())
+			Expect(history.Items).NotTo(BeEmpty())
+			event := history.Items[len(history.Items)-1]
+			Expect(event.Type).To(Equal(storage.EventTypeRollback))
+			Expect(event.Version).To(Equal(version))
+		})
+		It("shouldn't roll back to revisions which don't exist", func() {
+			_, err := client.RollbackRevision(space, web, version, 9)
+			Expect(rest.ErrorNotFound.Equal(err)).To(BeTrue())
+		})
+	})
+
+	Context("delete space", func() {
+		It("should delete space", func() {
+			Expect(client.DeleteChart(space, web)).To(BeNil())
+			Expect(client.DeleteSpace(space)).To(BeNil())
+		})
+	})
+})
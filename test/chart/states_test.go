@@ -0,0 +1,135 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package chart_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+
+	"github.com/caicloud/helm-registry/pkg/rest/v1"
+	"github.com/caicloud/helm-registry/pkg/storage"
+	"github.com/caicloud/helm-registry/test/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("States", func() {
+	const (
+		space = "states"
+		chart = "web"
+	)
+	var (
+		endpoint = ""
+		client   *v1.Client
+		packages = map[string][]byte{}
+	)
+	// get gets a resource of the chart
+	get := func(path string) *http.Response {
+		resp, err := http.Get(endpoint + "/api/v1/spaces/" + space + "/charts/" + chart + path)
+		Expect(err).To(BeNil())
+		return resp
+	}
+	BeforeEach(func() {
+		By("getting registry host from env")
+		endpoint = os.Getenv(EnvEndpoint)
+		Expect(endpoint).NotTo(BeEmpty())
+		cli, err := v1.NewClient(endpoint)
+		Expect(err).To(BeNil())
+		client = cli
+	})
+
+	Context("upload versions", func() {
+		It("should upload versions", utils.Multicase([]string{"1.0.0", "1.1.0", "1.2.0"}, func(version string) {
+			data, err := utils.Package(chart, version, "replicas: 1\n", nil)
+			Expect(err).To(BeNil())
+			_, err = client.UploadChart(space, data)
+			Expect(err).To(BeNil())
+			packages[version] = data
+		}))
+		It("should be active by default", func() {
+			state, err := client.FetchVersionState(space, chart, "1.0.0")
+			Expect(err).To(BeNil())
+			Expect(state.State).To(Equal(storage.StateActive))
+			Expect(state.Time).To(BeNil())
+		})
+	})
+
+	Context("set states", func() {
+		It("should set states", func() {
+			state, err := client.SetVersionState(space, chart, "1.1.0", storage.StateDeprecated, "use 1.2.0")
+			Expect(err).To(BeNil())
+			Expect(state.Reason).To(Equal("use 1.2.0"))
+			Expect(state.Time).NotTo(BeNil())
+			_, err = client.SetVersionState(space, chart, "1.2.0", storage.StateYanked, "broken")
+			Expect(err).To(BeNil())
+
+			state, err = client.FetchVersionState(space, chart, "1.2.0")
+			Expect(err).To(BeNil())
+			Expect(state.State).To(Equal(storage.StateYanked))
+			Expect(state.Reason).To(Equal("broken"))
+		})
+		It("shouldn't set invalid states", func() {
+			_, err := client.SetVersionState(space, chart, "1.0.0", "broken", "")
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Context("get versions by states", func() {
+		It("should download yanked versions by exact version", func() {
+			data, err := client.DownloadVersion(space, chart, "1.2.0")
+			Expect(err).To(BeNil())
+			Expect(data).To(Equal(packages["1.2.0"]))
+		})
+		It("should warn deprecated versions", func() {
+			resp := get("/versions/1.1.0")
+			resp.Body.Close()
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			Expect(resp.Header.Get("Warning")).To(ContainSubstring("deprecated: use 1.2.0"))
+			resp = get("/versions/1.0.0")
+			resp.Body.Close()
+			Expect(resp.Header.Get("Warning")).To(BeEmpty())
+		})
+		It("should exclude yanked versions from listings by default", func() {
+			result, err := client.ListVersions(space, chart, 0, 10)
+			Expect(err).To(BeNil())
+			Expect(result.Items).To(ConsistOf("1.0.0", "1.1.0"))
+
+			api := v1.NewAPIListVersions()
+			api.Space = space
+			api.Chart = chart
+			api.Limit = 10
+			api.Yanked = true
+			result, err = api.Convert(client.Do(api))
+			Expect(err).To(BeNil())
+			Expect(result.Items).To(ConsistOf("1.0.0", "1.1.0", "1.2.0"))
+		})
+		It("shouldn't take yanked versions as the latest", func() {
+			resp := get("/metadata/latest")
+			defer resp.Body.Close()
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			metadata := &storage.Metadata{}
+			Expect(json.NewDecoder(resp.Body).Decode(metadata)).To(BeNil())
+			Expect(metadata.Version).To(Equal("1.1.0"))
+			Expect(metadata.State.State).To(Equal(storage.StateDeprecated))
+		})
+		It("should take reactivated versions as the latest", func() {
+			_, err := client.SetVersionState(space, chart, "1.2.0", storage.StateActive, "")
+			Expect(err).To(BeNil())
+			resp := get("/metadata/latest")
+			defer resp.Body.Close()
+			metadata := &storage.Metadata{}
+			Expect(json.NewDecoder(resp.Body).Decode(metadata)).To(BeNil())
+			Expect(metadata.Version).To(Equal("1.2.0"))
+		})
+	})
+
+	Context("delete space", func() {
+		It("should delete space", func() {
+			Expect(client.DeleteChart(space, chart)).To(BeNil())
+			Expect(client.DeleteSpace(space)).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,141 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package chart_test
+
+import (
+	"os"
+
+	"github.com/caicloud/helm-registry/pkg/rest/v1"
+	"github.com/caicloud/helm-registry/pkg/storage"
+	"github.com/caicloud/helm-registry/test/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+var _ = Describe("Summaries", func() {
+	const (
+		space = "summaries"
+		web   = "web"
+		db    = "db"
+	)
+	var (
+		endpoint = ""
+		client   *v1.Client
+		packages = map[string][]byte{}
+	)
+	BeforeEach(func() {
+		By("getting registry host from env")
+		endpoint = os.Getenv(EnvEndpoint)
+		Expect(endpoint).NotTo(BeEmpty())
+		cli, err := v1.NewClient(endpoint)
+		Expect(err).To(BeNil())
+		client = cli
+	})
+
+	Context("upload charts", func() {
+		It("should create space", func() {
+			properties := storage.NewSpaceProperties()
+			properties.Description = "summaries of charts"
+			properties.Labels = map[string]string{"suite": space}
+			_, err := client.CreateSpaceWithProperties(space, properties)
+			Expect(err).To(BeNil())
+		})
+		It("should upload versions", utils.Multicase([]string{"1.0.0", "1.1.0", "2.0.0"}, func(version string) {
+			data, err := utils.Package(web, version, "replicas: 1\n", nil)
+			Expect(err).To(BeNil())
+			_, err = client.UploadChart(space, data)
+			Expect(err).To(BeNil())
+			packages[version] = data
+		}))
+		It("should upload other charts", func() {
+			data, err := utils.Package(db, "1.0.0", "port: 3306\n", nil)
+			Expect(err).To(BeNil())
+			_, err = client.UploadChart(space, data)
+			Expect(err).To(BeNil())
+			_, err = client.UpdateVersionMetadata(space, web, "1.1.0", &chart.Metadata{
+				Name:        web,
+				Version:     "1.1.0",
+				AppVersion:  "nginx-1.13",
+				Description: "a web server",
+			})
+			Expect(err).To(BeNil())
+			_, err = client.SetVersionState(space, web, "2.0.0", storage.StateYanked, "broken")
+			Expect(err).To(BeNil())
+		})
+	})
+
+	Context("list summaries", func() {
+		It("should list summaries of versions", func() {
+			result, err := client.ListVersionSummaries(space, web, 0, 10)
+			Expect(err).To(BeNil())
+			versions := []string{}
+			for _, summary := range result.Items {
+				versions = append(versions, summary.Version)
+			}
+			Expect(versions).To(ConsistOf("1.0.0", "1.1.0"))
+			for _, summary := range result.Items {
+				Expect(summary.State.State).To(Equal(storage.StateActive))
+				Expect(summary.Record).NotTo(BeNil())
+				if summary.Version == "1.1.0" {
+					Expect(summary.AppVersion).To(Equal("nginx-1.13"))
+					Expect(summary.Description).To(Equal("a web server"))
+					Expect(summary.Record.Revision).To(Equal(int64(2)))
+				} else {
+					Expect(summary.Record.Digest).To(Equal(storage.Digest(packages[summary.Version])))
+					Expect(summary.Record.Size).To(Equal(int64(len(packages[summary.Version]))))
+				}
+			}
+		})
+		It("should list summaries of yanked versions", func() {
+			api := v1.NewAPIListVersionSummaries()
+			api.Space = space
+			api.Chart = web
+			api.Limit = 10
+			api.Yanked = true
+			api.Sort = "-version"
+			result, err := api.Convert(client.Do(api))
+			Expect(err).To(BeNil())
+			Expect(result.Items).To(HaveLen(3))
+			Expect(result.Items[0].Version).To(Equal("2.0.0"))
+			Expect(result.Items[0].State.State).To(Equal(storage.StateYanked))
+		})
+		It("should list summaries of charts", func() {
+			result, err := client.ListChartSummaries(space, 0, 10)
+			Expect(err).To(BeNil())
+			Expect(result.Items).To(HaveLen(2))
+			summaries := map[string]*storage.ChartSummary{}
+			for _, summary := range result.Items {
+				summaries[summary.Name] = summary
+			}
+			Expect(summaries[web].Versions).To(Equal(3))
+			Expect(summaries[web].Latest).NotTo(BeNil())
+			Expect(summaries[web].Latest.Version).To(Equal("1.1.0"))
+			Expect(summaries[web].Latest.AppVersion).To(Equal("nginx-1.13"))
+			Expect(summaries[web].Updated.IsZero()).To(BeFalse())
+			Expect(summaries[db].Versions).To(Equal(1))
+			Expect(summaries[db].Latest.Version).To(Equal("1.0.0"))
+		})
+		It("should list summaries of spaces", func() {
+			api := v1.NewAPIListSpaceSummaries()
+			api.Limit = 10
+			api.Label = "suite=" + space
+			result, err := api.Convert(client.Do(api))
+			Expect(err).To(BeNil())
+			Expect(result.Items).To(HaveLen(1))
+			Expect(result.Items[0].Name).To(Equal(space))
+			Expect(result.Items[0].Description).To(Equal("summaries of charts"))
+			Expect(result.Items[0].Charts).To(Equal(2))
+		})
+	})
+
+	Context("delete space", func() {
+		It("should delete space", func() {
+			Expect(client.DeleteChart(space, web)).To(BeNil())
+			Expect(client.DeleteChart(space, db)).To(BeNil())
+			Expect(client.DeleteSpace(space)).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,143 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package chart_test
+
+import (
+	"os"
+
+	"github.com/caicloud/helm-registry/pkg/rest"
+	"github.com/caicloud/helm-registry/pkg/rest/v1"
+	"github.com/caicloud/helm-registry/pkg/storage"
+	"github.com/caicloud/helm-registry/test/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Tags", func() {
+	const (
+		space = "tags"
+		chart = "web"
+	)
+	var (
+		endpoint = ""
+		client   *v1.Client
+		packages = map[string][]byte{}
+	)
+	BeforeEach(func() {
+		By("getting registry host from env")
+		endpoint = os.Getenv(EnvEndpoint)
+		Expect(endpoint).NotTo(BeEmpty())
+		cli, err := v1.NewClient(endpoint)
+		Expect(err).To(BeNil())
+		client = cli
+	})
+
+	Context("upload versions", func() {
+		It("should upload versions", utils.Multicase([]string{"1.0.0", "1.1.0"}, func(version string) {
+			data, err := utils.Package(chart, version, "image: web:"+version+"\n", nil)
+			Expect(err).To(BeNil())
+			_, err = client.UploadChart(space, data)
+			Expect(err).To(BeNil())
+			packages[version] = data
+		}))
+	})
+
+	Context("manage tags", func() {
+		It("should set tags", func() {
+			tag, err := client.SetTag(space, chart, "stable", "1.0.0")
+			Expect(err).To(BeNil())
+			Expect(*tag).To(Equal(storage.Tag{Name: "stable", Version: "1.0.0"}))
+			// tags can point to the version of another tag
+			tag, err = client.SetTag(space, chart, "prod", "stable")
+			Expect(err).To(BeNil())
+			Expect(tag.Version).To(Equal("1.0.0"))
+			_, err = client.SetTag(space, chart, "canary", "1.1.0")
+			Expect(err).To(BeNil())
+
+			result, err := client.ListTags(space, chart, 0, 10)
+			Expect(err).To(BeNil())
+			Expect(result.Items).To(Equal([]*storage.Tag{
+				{Name: "canary", Version: "1.1.0"},
+				{Name: "prod", Version: "1.0.0"},
+				{Name: "stable", Version: "1.0.0"},
+			}))
+		})
+		It("shouldn't set tags to versions which don't exist", func() {
+			_, err := client.SetTag(space, chart, "stable", "2.0.0")
+			Expect(rest.ErrorNotFound.Equal(err)).To(BeTrue())
+			tag, err := client.FetchTag(space, chart, "stable")
+			Expect(err).To(BeNil())
+			Expect(tag.Version).To(Equal("1.0.0"))
+		})
+		It("should move tags and record them in history", func() {
+			_, err := client.SetTag(space, chart, "stable", "1.1.0")
+			Expect(err).To(BeNil())
+			history, err := client.FetchChartHistory(space, chart, 0, 10)
+			Expect(err).To(BeNil())
+			Expect(history.Items).NotTo(BeEmpty())
+			event := history.Items[len(history.Items)-1]
+			Expect(event.Type).To(Equal(storage.EventTypeTag))
+			Expect(event.Tag).To(Equal("stable"))
+			Expect(event.Version).To(Equal("1.1.0"))
+			Expect(event.Previous).To(Equal("1.0.0"))
+		})
+	})
+
+	Context("resolve tags", func() {
+		It("should download versions by tags", func() {
+			data, err := client.DownloadVersion(space, chart, "stable")
+			Expect(err).To(BeNil())
+			Expect(data).To(Equal(packages["1.1.0"]))
+			data, err = client.DownloadVersion(space, chart, "prod")
+			Expect(err).To(BeNil())
+			Expect(data).To(Equal(packages["1.0.0"]))
+		})
+		It("should fetch metadata and values by tags", func() {
+			metadata, err := client.FetchVersionMetadata(space, chart, "prod")
+			Expect(err).To(BeNil())
+			Expect(metadata.Version).To(Equal("1.0.0"))
+			values, err := client.FetchVersionValues(space, chart, "prod")
+			Expect(err).To(BeNil())
+			Expect(values).To(MatchJSON(`{"image":"web:1.0.0"}`))
+		})
+		It("should orchestrate packages by tags", func() {
+			config := `{
+    "save": {"space": "` + space + `", "chart": "site", "version": "1.0.0"},
+    "configs": {
+        "package": {"independent": true, "space": "` + space + `", "chart": "` + chart + `", "version": "prod"}
+    }
+}`
+			_, err := client.CreateChart(space, config)
+			Expect(err).To(BeNil())
+			record, err := client.FetchVersionRecord(space, "site", "1.0.0")
+			Expect(client.DeleteChart(space, "site")).To(BeNil())
+			Expect(err).To(BeNil())
+			Expect(record.References).To(HaveLen(1))
+			Expect(record.References[0].Version).To(Equal("1.0.0"))
+		})
+	})
+
+	Context("delete tags", func() {
+		It("should delete tags", func() {
+			Expect(client.DeleteTag(space, chart, "canary")).To(BeNil())
+			_, err := client.FetchTag(space, chart, "canary")
+			Expect(rest.ErrorNotFound.Equal(err)).To(BeTrue())
+			Expect(rest.ErrorNotFound.Equal(client.DeleteTag(space, chart, "canary"))).To(BeTrue())
+		})
+		It("should delete tags of deleted versions", func() {
+			Expect(client.DeleteVersion(space, chart, "1.0.0")).To(BeNil())
+			result, err := client.ListTags(space, chart, 0, 10)
+			Expect(err).To(BeNil())
+			Expect(result.Items).To(Equal([]*storage.Tag{{Name: "stable", Version: "1.1.0"}}))
+		})
+	})
+
+	Context("delete space", func() {
+		It("should delete space", func() {
+			Expect(client.DeleteChart(space, chart)).To(BeNil())
+			Expect(client.DeleteSpace(space)).To(BeNil())
+		})
+	})
+})
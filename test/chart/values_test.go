@@ -0,0 +1,93 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package chart_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/caicloud/helm-registry/pkg/errors"
+	"github.com/caicloud/helm-registry/pkg/patch"
+	"github.com/caicloud/helm-registry/pkg/rest/v1"
+	"github.com/caicloud/helm-registry/test/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Values", func() {
+	const (
+		space   = "values"
+		chart   = "web"
+		version = "1.0.0"
+		values  = "# replicas of web\nreplicas: 1 # count\nimage: web\n"
+	)
+	var (
+		endpoint = ""
+		client   *v1.Client
+	)
+	BeforeEach(func() {
+		By("getting registry host from env")
+		endpoint = os.Getenv(EnvEndpoint)
+		Expect(endpoint).NotTo(BeEmpty())
+		cli, err := v1.NewClient(endpoint)
+		Expect(err).To(BeNil())
+		client = cli
+	})
+
+	Context("upload chart", func() {
+		It("should upload chart with a subchart", func() {
+			db, err := utils.Package("db", "1.0.0", "port: 3306\n", nil)
+			Expect(err).To(BeNil())
+			data, err := utils.Package(chart, version, values, map[string]string{
+				"charts/db-1.0.0.tgz": string(db),
+			})
+			Expect(err).To(BeNil())
+			_, err = client.UploadChart(space, data)
+			Expect(err).To(BeNil())
+		})
+	})
+
+	Context("update values", func() {
+		It("should keep comments of values.yaml by updating values in json", func() {
+			data, err := client.FetchVersionValues(space, chart, version)
+			Expect(err).To(BeNil())
+			current := map[string]interface{}{}
+			Expect(json.Unmarshal(data, &current)).To(BeNil())
+			Expect(current).To(HaveKey("db"))
+			current["replicas"] = 3
+			data, err = json.Marshal(current)
+			Expect(err).To(BeNil())
+			_, err = client.UpdateVersionValues(space, chart, version, data)
+			Expect(err).To(BeNil())
+
+			raw, _, err := client.FetchVersionValuesYAML(space, chart, version)
+			Expect(err).To(BeNil())
+			Expect(string(raw)).To(Equal(strings.Replace(values, "replicas: 1", "replicas: 3", 1)))
+		})
+		It("should keep comments of values.yaml by patching values", func() {
+			_, _, err := client.PatchVersionValues(space, chart, version, patch.MergePatchType, []byte(`{"image":"nginx"}`), "")
+			Expect(err).To(BeNil())
+			raw, _, err := client.FetchVersionValuesYAML(space, chart, version)
+			Expect(err).To(BeNil())
+			Expect(string(raw)).To(Equal("# replicas of web\nreplicas: 3 # count\nimage: nginx\n"))
+		})
+		It("shouldn't remove defaults of subcharts", func() {
+			_, _, err := client.PatchVersionValues(space, chart, version, patch.MergePatchType, []byte(`{"db":{"port":null}}`), "")
+			Expect(err).NotTo(BeNil())
+			e, ok := err.(*errors.Error)
+			Expect(ok).To(BeTrue())
+			Expect(e.Code).To(Equal(http.StatusUnprocessableEntity))
+		})
+	})
+
+	Context("delete space", func() {
+		It("should delete space", func() {
+			Expect(client.DeleteChart(space, chart)).To(BeNil())
+			Expect(client.DeleteSpace(space)).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,124 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package space_test
+
+import (
+	"os"
+
+	"github.com/caicloud/helm-registry/pkg/rest"
+	"github.com/caicloud/helm-registry/pkg/rest/v1"
+	"github.com/caicloud/helm-registry/pkg/storage"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Properties", func() {
+	const (
+		team    = "properties-team"
+		private = "properties-private"
+	)
+	var (
+		endpoint = ""
+		client   *v1.Client
+	)
+	BeforeEach(func() {
+		By("getting registry host from env")
+		endpoint = os.Getenv(EnvEndpoint)
+		Expect(endpoint).NotTo(BeEmpty())
+		cli, err := v1.NewClient(endpoint)
+		Expect(err).To(BeNil())
+		client = cli
+	})
+
+	Context("create spaces with properties", func() {
+		It("should create spaces", func() {
+			properties := storage.NewSpaceProperties()
+			properties.Description = "charts of team x"
+			properties.Owners = []string{"alice", "team-x"}
+			properties.Labels = map[string]string{"team": "x", "env": "dev"}
+			_, err := client.CreateSpaceWithProperties(team, properties)
+			Expect(err).To(BeNil())
+
+			properties = storage.NewSpaceProperties()
+			properties.Labels = map[string]string{"team": "y"}
+			properties.Visibility = storage.VisibilityPrivate
+			_, err = client.CreateSpaceWithProperties(private, properties)
+			Expect(err).To(BeNil())
+		})
+		It("shouldn't create spaces with invalid properties", func() {
+			properties := storage.NewSpaceProperties()
+			properties.Visibility = "hidden"
+			_, err := client.CreateSpaceWithProperties("properties-invalid", properties)
+			Expect(rest.ErrorBadRequest.Equal(err)).To(BeTrue())
+		})
+		It("shouldn't create existing spaces", func() {
+			_, err := client.CreateSpace(team)
+			Expect(rest.ErrorConflict.Equal(err)).To(BeTrue())
+		})
+	})
+
+	Context("fetch and update properties", func() {
+		It("should fetch properties", func() {
+			space, err := client.FetchSpace(team)
+			Expect(err).To(BeNil())
+			Expect(space.Name).To(Equal(team))
+			Expect(space.Description).To(Equal("charts of team x"))
+			Expect(space.Owners).To(Equal([]string{"alice", "team-x"}))
+			Expect(space.Labels).To(Equal(map[string]string{"team": "x", "env": "dev"}))
+			Expect(space.Visibility).To(Equal(storage.VisibilityPublic))
+			Expect(space.Lint).To(Equal(storage.LintWarn))
+		})
+		It("should update properties", func() {
+			properties := storage.NewSpaceProperties()
+			properties.Description = "charts of team x"
+			properties.Labels = map[string]string{"team": "x", "env": "prod"}
+			properties.Lint = storage.LintError
+			space, err := client.UpdateSpace(team, properties)
+			Expect(err).To(BeNil())
+			Expect(space.Owners).To(BeEmpty())
+
+			space, err = client.FetchSpace(team)
+			Expect(err).To(BeNil())
+			Expect(space.Labels["env"]).To(Equal("prod"))
+			Expect(space.Lint).To(Equal(storage.LintError))
+		})
+		It("shouldn't fetch spaces which don't exist", func() {
+			_, err := client.FetchSpace("properties-nothing")
+			Expect(rest.ErrorNotFound.Equal(err)).To(BeTrue())
+		})
+	})
+
+	Context("filter spaces", func() {
+		It("should list spaces by labels", func() {
+			result, err := client.ListSpacesByLabels(map[string]string{"team": "x"}, 0, 10)
+			Expect(err).To(BeNil())
+			Expect(result.Items).To(Equal([]string{team}))
+
+			result, err = client.ListSpacesByLabels(map[string]string{"team": ""}, 0, 10)
+			Expect(err).To(BeNil())
+			Expect(result.Items).To(Equal([]string{private, team}))
+
+			result, err = client.ListSpacesByLabels(map[string]string{"team": "x", "env": "dev"}, 0, 10)
+			Expect(err).To(BeNil())
+			Expect(result.Items).To(BeEmpty())
+		})
+		It("should list spaces by visibility", func() {
+			api := v1.NewAPIListSpaces()
+			api.Limit = 10
+			api.Label = "team"
+			api.Visibility = string(storage.VisibilityPrivate)
+			result, err := api.Convert(client.Do(api))
+			Expect(err).To(BeNil())
+			Expect(result.Items).To(Equal([]string{private}))
+		})
+	})
+
+	Context("delete spaces", func() {
+		It("should delete spaces", func() {
+			Expect(client.DeleteSpace(team)).To(BeNil())
+			Expect(client.DeleteSpace(private)).To(BeNil())
+		})
+	})
+})
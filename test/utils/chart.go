@@ -0,0 +1,46 @@
+/*
+Copyright 2017 caicloud authors. All rights reserved.
+*/
+
+package utils
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/golang/protobuf/ptypes/any"
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+// Package creates a chart package with values and files. Files are indexed by paths
+// in the chart, and files in templates are templates.
+func Package(name, version, values string, files map[string]string) ([]byte, error) {
+	c := &chart.Chart{
+		Metadata: &chart.Metadata{
+			Name:        name,
+			Version:     version,
+			Description: "a chart for tests",
+			ApiVersion:  chartutil.ApiVersionV1,
+		},
+		Values: &chart.Config{Raw: values},
+	}
+	for path, content := range files {
+		if strings.HasPrefix(path, "templates/") {
+			c.Templates = append(c.Templates, &chart.Template{Name: path, Data: []byte(content)})
+			continue
+		}
+		c.Files = append(c.Files, &any.Any{TypeUrl: path, Value: []byte(content)})
+	}
+	dir, err := ioutil.TempDir("", "chart")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	path, err := chartutil.Save(c, dir)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadFile(path)
+}